@@ -0,0 +1,230 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/tech-arch1tect/kan-mcp/internal/logging"
+	"github.com/tech-arch1tect/kan-mcp/internal/storage"
+)
+
+// sensitiveParams are substrings of argument names whose values never reach
+// the audit log.
+var sensitiveParams = []string{"token", "password", "secret", "api_key", "apikey", "authorization", "credential"}
+
+// auditParams copies tool arguments for the audit log, leaving out user_id
+// and replacing the values of secret-looking keys at any depth.
+func auditParams(args map[string]interface{}) map[string]interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+
+	params := make(map[string]interface{}, len(args))
+	for key, value := range args {
+		if key == "user_id" {
+			continue
+		}
+		params[key] = redactValue(key, value)
+	}
+	if len(params) == 0 {
+		return nil
+	}
+	return params
+}
+
+func redactValue(key string, value interface{}) interface{} {
+	lowerKey := strings.ToLower(key)
+	for _, sensitive := range sensitiveParams {
+		if strings.Contains(lowerKey, sensitive) {
+			return "[REDACTED]"
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			redacted[k] = redactValue(k, item)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = redactValue("", item)
+		}
+		return redacted
+	}
+	return value
+}
+
+// toolErrorMessage is the failure reported to the client, from either the Go
+// error or the text of an error result.
+func toolErrorMessage(result *mcp.CallToolResult, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	if result == nil || !result.IsError {
+		return ""
+	}
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			return text.Text
+		}
+	}
+	return ""
+}
+
+// pruneAuditLog drops audit entries older than retention now and once a day
+// for as long as the server runs.
+func pruneAuditLog(auditStore *storage.AuditStore, retention time.Duration) {
+	if retention <= 0 {
+		return
+	}
+
+	for {
+		removed, err := auditStore.Prune(time.Now().Add(-retention))
+		if err != nil {
+			logging.Warnf("Failed to prune audit log: %v", err)
+		} else if removed > 0 {
+			logging.Infof("Pruned %d audit log entries older than %s", removed, retention)
+		}
+		time.Sleep(24 * time.Hour)
+	}
+}
+
+func setupAudit(fs *flag.FlagSet) func(env *commandEnv) {
+	userID := fs.String("user-id", "", "Only show calls made with this user ID")
+	requestID := fs.String("request-id", "", "Only show the call with this request ID")
+	tool := fs.String("tool", "", "Only show calls to this tool")
+	since := fs.Duration("since", 24*time.Hour, "How far back to show tool calls")
+	errorsOnly := fs.Bool("errors", false, "Only show failed calls")
+	limit := fs.Int("limit", 100, "Show at most this many of the most recent calls (0 for all)")
+	jsonOutput := fs.Bool("json", false, "Print matching entries as JSON lines")
+
+	return func(env *commandEnv) {
+		if *since <= 0 {
+			env.usageError("Since must be positive")
+		}
+		if *limit < 0 {
+			env.usageError("Limit cannot be negative")
+		}
+
+		auditStore, err := storage.NewAuditStore(env.cfg.Storage.DataDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to initialize audit store: %v\n", err)
+			os.Exit(1)
+		}
+
+		toolName := *tool
+		if toolName != "" && !strings.HasPrefix(toolName, "kanboard_") {
+			toolName = "kanboard_" + toolName
+		}
+
+		showAudit(auditStore, auditFilter{
+			userID:     *userID,
+			requestID:  *requestID,
+			tool:       toolName,
+			since:      time.Now().Add(-*since),
+			errorsOnly: *errorsOnly,
+			limit:      *limit,
+		}, *jsonOutput)
+	}
+}
+
+type auditFilter struct {
+	userID     string
+	requestID  string
+	tool       string
+	since      time.Time
+	errorsOnly bool
+	limit      int
+}
+
+func showAudit(auditStore *storage.AuditStore, filter auditFilter, jsonOutput bool) {
+	calls, err := auditStore.Calls(filter.since)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read audit log: %v\n", err)
+		os.Exit(1)
+	}
+
+	matched := calls[:0]
+	for _, call := range calls {
+		if filter.userID != "" && call.UserID != filter.userID {
+			continue
+		}
+		if filter.requestID != "" && call.RequestID != filter.requestID {
+			continue
+		}
+		if filter.tool != "" && call.Tool != filter.tool {
+			continue
+		}
+		if filter.errorsOnly && !call.Error {
+			continue
+		}
+		matched = append(matched, call)
+	}
+	if filter.limit > 0 && len(matched) > filter.limit {
+		matched = matched[len(matched)-filter.limit:]
+	}
+
+	if jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		for _, call := range matched {
+			if err := encoder.Encode(call); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to write entry: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		return
+	}
+
+	if len(matched) == 0 {
+		fmt.Printf("No matching tool calls since %s\n", filter.since.Format("2006-01-02 15:04:05"))
+		return
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tREQUEST ID\tUSER ID\tTOOL\tDURATION\tRESULT\tPARAMS")
+	for _, call := range matched {
+		userID := call.UserID
+		if userID == "" {
+			userID = "-"
+		}
+
+		result := "ok"
+		if call.Error {
+			result = "error"
+			if call.ErrorMessage != "" {
+				result += ": " + truncate(call.ErrorMessage, 60)
+			}
+		}
+
+		params := "-"
+		if len(call.Params) > 0 {
+			data, _ := json.Marshal(call.Params)
+			params = truncate(string(data), 80)
+		}
+
+		requestID := call.RequestID
+		if requestID == "" {
+			requestID = "-"
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%dms\t%s\t%s\n", call.Time.Local().Format("2006-01-02 15:04:05"), requestID, userID, strings.TrimPrefix(call.Tool, "kanboard_"), call.DurationMs, result, params)
+	}
+	tw.Flush()
+}
+
+func truncate(s string, n int) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	if runes := []rune(s); len(runes) > n {
+		return string(runes[:n-1]) + "…"
+	}
+	return s
+}
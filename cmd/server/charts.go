@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/tech-arch1tect/kan-mcp/internal/handlers"
+)
+
+// addChartResource serves the analytics charts as PNG resources, drawn from
+// fresh analytics on every read.
+func (s *KanboardMCPServer) addChartResource() {
+	template := mcp.NewResourceTemplate("kanboard://charts/{user_id}/{chart}{?time_range,project_ids,scope,bucket}", "Kanboard analytics chart",
+		mcp.WithTemplateDescription("PNG chart of the user's analytics. chart is 'burndown', 'velocity' or 'cumulative_flow'; time_range, project_ids (comma-separated), scope and bucket filter as in kanboard_analytics"),
+		mcp.WithTemplateMIMEType("image/png"),
+	)
+	s.server.AddResourceTemplate(template, s.readChart)
+}
+
+func (s *KanboardMCPServer) readChart(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	userID := resourceArgument(request, "user_id")
+	if err := s.authorizeResource(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	params := make(map[string]interface{})
+	for _, key := range []string{"time_range", "scope", "bucket"} {
+		if value := resourceArgument(request, key); value != "" {
+			params[key] = value
+		}
+	}
+	if value := resourceArgument(request, "project_ids"); value != "" {
+		params["project_ids"] = strings.Split(value, ",")
+	}
+
+	image, err := handlers.NewAnalyticsHandler(s.authManager, s.userConfig).Chart(ctx, userID, resourceArgument(request, "chart"), params)
+	if err != nil {
+		return nil, fmt.Errorf("chart failed: %w", err)
+	}
+
+	return []mcp.ResourceContents{
+		mcp.BlobResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "image/png",
+			Blob:     base64.StdEncoding.EncodeToString(image),
+		},
+	}, nil
+}
@@ -0,0 +1,807 @@
+package main
+
+import (
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/auth"
+	"github.com/tech-arch1tect/kan-mcp/internal/config"
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+	"github.com/tech-arch1tect/kan-mcp/internal/storage"
+	"github.com/tech-arch1tect/kan-mcp/internal/templates"
+)
+
+// command is a node in the kan-mcp command tree. Groups only have children;
+// leaves have setup, which registers the command's own flags and returns the
+// function that runs it. Configuration flags such as -data-dir are accepted
+// by every leaf and may appear anywhere on the command line.
+type command struct {
+	name     string
+	summary  string
+	args     string
+	hidden   bool
+	setup    func(fs *flag.FlagSet) func(env *commandEnv)
+	children []*command
+}
+
+// commandEnv is handed to a running leaf command.
+type commandEnv struct {
+	cfg   *config.Config
+	args  []string
+	usage func()
+
+	encryptionKey []byte
+	authManager   *auth.AuthManager
+}
+
+func (c *command) child(name string) *command {
+	for _, child := range c.children {
+		if child.name == name {
+			return child
+		}
+	}
+	return nil
+}
+
+func commandTree() *command {
+	user := &command{
+		name:    "user",
+		summary: "Manage registered users",
+		children: []*command{
+			{name: "register", summary: "Register a new user with Kanboard credentials", args: "-username <username> [-kanboard-url <url>] [-auth-mode user|app]", setup: setupRegister},
+			{name: "update", summary: "Change a user's Kanboard URL, username or token, keeping their user ID", args: "-user-id <user-id> [-kanboard-url <url>] [-username <username>] [-token]", setup: setupUpdate},
+			{name: "renew", summary: "Check a user's credentials still work and extend their expiry", args: "-user-id <user-id> [-token]", setup: setupRenew},
+			{name: "test", summary: "Call Kanboard with a user's token and report what it returns", args: "-user-id <user-id>", setup: setupTest},
+			{name: "list", summary: "List all registered users", setup: setupList},
+			{name: "show", summary: "Show details for a specific user", args: "-user-id <user-id>", setup: setupShow},
+			{name: "delete", summary: "Delete a user", args: "-user-id <user-id>", setup: setupDelete},
+			{name: "signing-secret", summary: "Create or remove the secret a user's HTTP requests must be signed with", args: "-user-id <user-id> [-remove]", setup: setupSigningSecret},
+			{name: "ical-token", summary: "Create or remove the token in a user's iCal feed URL", args: "-user-id <user-id> [-remove]", setup: setupCalendarToken},
+			{name: "notify", summary: "Opt a user in to daily overdue and due-today alerts, or out with -off", args: "-user-id <user-id> [-webhook-url <url>] [-email <address>] [-off]", setup: setupNotify},
+			{name: "export", summary: "Write every registration to an encrypted bundle", args: "-file <bundle> [-bundle-key <hex>]", setup: setupExport},
+			{name: "import", summary: "Load registrations from a bundle written by export", args: "-file <bundle> [-bundle-key <hex>] [-merge|-overwrite]", setup: setupImport},
+			{name: "manage", summary: "Full-screen user manager", setup: setupManage},
+			{name: "stats", summary: "Show tool calls, errors and last use per user", args: "[-window <duration>]", setup: setupStats},
+			{name: "usage", summary: "Show tool calls and response bytes per user per day against the quota", args: "[-days <n>] [-user-id <user-id>]", setup: setupUsage},
+		},
+	}
+	keygen := &command{name: "keygen", summary: "Generate a new encryption key", args: "[-env-file <path>]", setup: setupKeygen}
+	doctor := &command{name: "doctor", summary: "Check configuration, storage and Kanboard connectivity", setup: setupDoctor}
+	pseudonym := &command{name: "pseudonym", summary: "Show which Kanboard user a pseudonym in tool responses stands for", args: "<pseudonym>", setup: setupPseudonym}
+	audit := &command{name: "audit", summary: "Show the tool-call audit log", args: "[-since <duration>] [-user-id <user-id>] [-request-id <id>] [-tool <tool>] [-errors] [-limit <n>] [-json]", setup: setupAudit}
+	template := &command{
+		name:    "template",
+		summary: "Inspect the report templates",
+		children: []*command{
+			{name: "list", summary: "List the report templates and whether the data directory overrides them", setup: setupTemplateList},
+			{name: "show", summary: "Print a report template, to start an override from", args: "<name> [-default]", setup: setupTemplateShow},
+		},
+	}
+	events := &command{name: "events", summary: "Show events received from Kanboard webhooks", args: "[-since <duration>] [-project-id <id>] [-limit <n>] [-json]", setup: setupEvents}
+
+	root := &command{
+		name: os.Args[0],
+		children: []*command{
+			{name: "serve", summary: "Run the MCP server (the default when no command is given)", setup: setupServe},
+			user,
+			keygen,
+			doctor,
+			audit,
+			events,
+			template,
+			pseudonym,
+			{
+				name:    "completion",
+				summary: "Print a shell completion script",
+				children: []*command{
+					{name: "bash", summary: "Bash completion script", setup: setupCompletion(bashCompletion)},
+					{name: "zsh", summary: "Zsh completion script", setup: setupCompletion(zshCompletion)},
+					{name: "fish", summary: "Fish completion script", setup: setupCompletion(fishCompletion)},
+				},
+			},
+			{name: "help", summary: "Show help for a command", args: "[command...]", setup: setupHelp},
+			{name: "__complete", hidden: true, setup: setupComplete},
+		},
+	}
+
+	// "cli <command>" is the original spelling of the user commands.
+	legacy := &command{name: "cli", hidden: true, children: append([]*command{keygen, doctor, audit}, user.children...)}
+	root.children = append(root.children, legacy)
+
+	return root
+}
+
+func runCommand(args []string) {
+	root := commandTree()
+	known := allFlags(root)
+
+	node, path, rest, err := resolveCommand(root, known, args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n\n", err)
+		printGroupUsage(os.Stderr, node, path)
+		os.Exit(1)
+	}
+
+	if len(path) > 0 && path[0] == "cli" {
+		fmt.Fprintf(os.Stderr, "Note: '%s cli %s' is deprecated; use '%s'\n", os.Args[0], strings.Join(path[1:], " "), strings.Join(append([]string{os.Args[0]}, modernPath(path)...), " "))
+	}
+
+	if node == root {
+		if wantsHelp(rest) {
+			printGroupUsage(os.Stdout, root, nil)
+			fmt.Fprintf(os.Stdout, "\nServer options (flags override environment variables, which override the config file):\n")
+			printConfigFlags(os.Stdout)
+			return
+		}
+		node, path = root.child("serve"), []string{"serve"}
+	}
+
+	if node.setup == nil {
+		printGroupUsage(os.Stderr, node, path)
+		os.Exit(1)
+	}
+
+	if node.name == "__complete" {
+		node.setup(flag.NewFlagSet("", flag.ContinueOnError))(&commandEnv{args: rest})
+		return
+	}
+
+	commandPath := strings.Join(append([]string{os.Args[0]}, path...), " ")
+	fs := flag.NewFlagSet(commandPath, flag.ExitOnError)
+	run := node.setup(fs)
+	fs.Usage = func() {
+		printLeafUsage(fs.Output(), node, commandPath)
+	}
+
+	cfg, err := config.LoadConfig(fs, orderArgs(known, rest))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	run(&commandEnv{cfg: cfg, args: fs.Args(), usage: fs.Usage})
+}
+
+// resolveCommand walks the command words in args, skipping flags and their
+// values, and returns the deepest command reached along with the remaining
+// arguments.
+func resolveCommand(root *command, known *flag.FlagSet, args []string) (*command, []string, []string, error) {
+	node := root
+	var path, rest []string
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			return node, path, append(rest, args[i:]...), nil
+		case isFlagArg(arg):
+			rest = append(rest, arg)
+			if takesValue(known, arg) && i+1 < len(args) {
+				i++
+				rest = append(rest, args[i])
+			}
+		case node.setup == nil:
+			child := node.child(arg)
+			if child == nil || (child.hidden && node != root) {
+				return node, path, nil, fmt.Errorf("unknown command: %s", strings.Join(append(path, arg), " "))
+			}
+			node = child
+			path = append(path, arg)
+		default:
+			rest = append(rest, arg)
+		}
+	}
+
+	return node, path, rest, nil
+}
+
+// orderArgs moves flags ahead of positional arguments so they may be given
+// anywhere on the command line.
+func orderArgs(known *flag.FlagSet, args []string) []string {
+	var flags, positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			positional = append(positional, args[i+1:]...)
+			i = len(args)
+		case isFlagArg(arg):
+			flags = append(flags, arg)
+			if takesValue(known, arg) && i+1 < len(args) {
+				i++
+				flags = append(flags, args[i])
+			}
+		default:
+			positional = append(positional, arg)
+		}
+	}
+
+	if len(positional) == 0 {
+		return flags
+	}
+	return append(append(flags, "--"), positional...)
+}
+
+func isFlagArg(arg string) bool {
+	return len(arg) > 1 && arg[0] == '-'
+}
+
+func flagName(arg string) string {
+	name := strings.TrimLeft(arg, "-")
+	name, _, _ = strings.Cut(name, "=")
+	return name
+}
+
+func takesValue(known *flag.FlagSet, arg string) bool {
+	if strings.Contains(arg, "=") {
+		return false
+	}
+	f := known.Lookup(flagName(arg))
+	if f == nil {
+		return false
+	}
+	if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+		return false
+	}
+	return true
+}
+
+func wantsHelp(args []string) bool {
+	for _, arg := range args {
+		switch flagName(arg) {
+		case "h", "help":
+			if isFlagArg(arg) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// allFlags collects the configuration flags and every leaf's own flags, so
+// command words can be told apart from flag values before the leaf is known.
+func allFlags(root *command) *flag.FlagSet {
+	known := flag.NewFlagSet("", flag.ContinueOnError)
+	config.RegisterFlags(known)
+
+	var walk func(node *command)
+	walk = func(node *command) {
+		if node.setup != nil {
+			leaf := flag.NewFlagSet("", flag.ContinueOnError)
+			node.setup(leaf)
+			leaf.VisitAll(func(f *flag.Flag) {
+				if known.Lookup(f.Name) == nil {
+					known.Var(f.Value, f.Name, f.Usage)
+				}
+			})
+		}
+		for _, child := range node.children {
+			walk(child)
+		}
+	}
+	walk(root)
+
+	return known
+}
+
+func leafFlags(node *command) *flag.FlagSet {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	if node.setup != nil {
+		node.setup(fs)
+	}
+	return fs
+}
+
+func modernPath(path []string) []string {
+	if len(path) > 1 && path[0] == "cli" {
+		if path[1] == "keygen" || path[1] == "doctor" || path[1] == "audit" {
+			return path[1:]
+		}
+		return append([]string{"user"}, path[1:]...)
+	}
+	return path
+}
+
+func printGroupUsage(w io.Writer, node *command, path []string) {
+	prefix := strings.Join(append([]string{os.Args[0]}, path...), " ")
+	fmt.Fprintf(w, "Usage: %s <command> [options]\n\nCommands:\n", prefix)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	var walk func(node *command, words []string)
+	walk = func(node *command, words []string) {
+		for _, child := range node.children {
+			if child.hidden {
+				continue
+			}
+			childWords := append(append([]string{}, words...), child.name)
+			if child.setup != nil {
+				fmt.Fprintf(tw, "  %s\t%s\n", strings.Join(childWords, " "), child.summary)
+			}
+			walk(child, childWords)
+		}
+	}
+	walk(node, nil)
+	tw.Flush()
+
+	fmt.Fprintf(w, "\nRun '%s help <command>' or '<command> -help' for a command's options.\n", os.Args[0])
+}
+
+func printLeafUsage(w io.Writer, node *command, commandPath string) {
+	fmt.Fprintf(w, "Usage: %s %s\n\n%s\n", commandPath, strings.TrimSpace(node.args+" [options]"), node.summary)
+
+	if node.name == "serve" {
+		fmt.Fprintf(w, "\nOptions (flags override environment variables, which override the config file):\n")
+		printConfigFlags(w)
+		return
+	}
+
+	own := leafFlags(node)
+	hasFlags := false
+	own.VisitAll(func(*flag.Flag) { hasFlags = true })
+	if hasFlags {
+		fmt.Fprintf(w, "\nOptions:\n")
+		own.SetOutput(w)
+		own.PrintDefaults()
+	}
+
+	fmt.Fprintf(w, "\nConfiguration options such as -config and -data-dir are accepted by every command; run '%s serve -help' to list them.\n", os.Args[0])
+}
+
+func printConfigFlags(w io.Writer) {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	config.RegisterFlags(fs)
+	fs.SetOutput(w)
+	fs.PrintDefaults()
+}
+
+func (env *commandEnv) usageError(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n\n", args...)
+	env.usage()
+	os.Exit(1)
+}
+
+func (env *commandEnv) key() []byte {
+	if env.encryptionKey == nil {
+		encryptionKey, err := env.cfg.GetEncryptionKey()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to get encryption key: %v\n", err)
+			os.Exit(1)
+		}
+		env.encryptionKey = encryptionKey
+	}
+	return env.encryptionKey
+}
+
+func (env *commandEnv) auth() *auth.AuthManager {
+	if env.authManager == nil {
+		encryptionKey := env.key()
+
+		fileStore, err := storage.NewFileStore(env.cfg.Storage.DataDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to initialize storage: %v\n", err)
+			os.Exit(1)
+		}
+
+		authManager, err := auth.NewAuthManager(encryptionKey, fileStore)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to initialize auth manager: %v\n", err)
+			os.Exit(1)
+		}
+		authManager.SetCredentialLifetime(env.cfg.Security.CredentialLifetime)
+		env.authManager = authManager
+	}
+	return env.authManager
+}
+
+func setupServe(fs *flag.FlagSet) func(env *commandEnv) {
+	return func(env *commandEnv) {
+		serve(env.cfg)
+	}
+}
+
+func setupRegister(fs *flag.FlagSet) func(env *commandEnv) {
+	username := fs.String("username", "", "Kanboard username")
+	kanboardURL := fs.String("kanboard-url", "", "Kanboard URL (optional, uses default if not set)")
+	authMode := fs.String("auth-mode", models.AuthModeUser, "Authentication mode: 'user' (personal access token) or 'app' (shared application token)")
+
+	return func(env *commandEnv) {
+		if *username == "" {
+			env.usageError("Username is required for registration")
+		}
+		switch *authMode {
+		case models.AuthModeUser:
+			registerUser(env.auth(), env.cfg, *kanboardURL, *username)
+		case models.AuthModeApp:
+			registerAppUser(env.auth(), env.cfg, *kanboardURL, *username)
+		default:
+			env.usageError("Invalid auth mode: %s. Must be 'user' or 'app'", *authMode)
+		}
+	}
+}
+
+func setupUpdate(fs *flag.FlagSet) func(env *commandEnv) {
+	userID := fs.String("user-id", "", "User ID to update")
+	kanboardURL := fs.String("kanboard-url", "", "New Kanboard URL")
+	username := fs.String("username", "", "New Kanboard username")
+	newToken := fs.Bool("token", false, "Prompt for a new personal access token")
+
+	return func(env *commandEnv) {
+		if *userID == "" || (*kanboardURL == "" && *username == "" && !*newToken) {
+			env.usageError("User ID and at least one change are required for update operation")
+		}
+		updateUser(env.auth(), *userID, *kanboardURL, *username, *newToken)
+	}
+}
+
+func setupTest(fs *flag.FlagSet) func(env *commandEnv) {
+	userID := fs.String("user-id", "", "User ID to test")
+
+	return func(env *commandEnv) {
+		if *userID == "" {
+			env.usageError("User ID is required for test operation")
+		}
+		testUser(env.auth(), env.cfg, env.key(), *userID)
+	}
+}
+
+func setupRenew(fs *flag.FlagSet) func(env *commandEnv) {
+	userID := fs.String("user-id", "", "User ID to renew")
+	newToken := fs.Bool("token", false, "Prompt for a new personal access token before renewing")
+
+	return func(env *commandEnv) {
+		if *userID == "" {
+			env.usageError("User ID is required for renew operation")
+		}
+		if env.cfg.Security.CredentialLifetime <= 0 {
+			env.usageError("Credential expiry is not enabled; set CREDENTIAL_LIFETIME or -credential-lifetime")
+		}
+		renewUser(env.auth(), env.cfg, env.key(), *userID, *newToken)
+	}
+}
+
+func setupList(fs *flag.FlagSet) func(env *commandEnv) {
+	return func(env *commandEnv) {
+		listUsers(env.auth())
+	}
+}
+
+func setupShow(fs *flag.FlagSet) func(env *commandEnv) {
+	userID := fs.String("user-id", "", "User ID to show")
+
+	return func(env *commandEnv) {
+		if *userID == "" {
+			env.usageError("User ID is required for show operation")
+		}
+		showUser(env.auth(), *userID)
+	}
+}
+
+func setupDelete(fs *flag.FlagSet) func(env *commandEnv) {
+	userID := fs.String("user-id", "", "User ID to delete")
+
+	return func(env *commandEnv) {
+		if *userID == "" {
+			env.usageError("User ID is required for delete operation")
+		}
+		deleteUser(env.auth(), *userID)
+	}
+}
+
+func setupSigningSecret(fs *flag.FlagSet) func(env *commandEnv) {
+	userID := fs.String("user-id", "", "User ID to create the signing secret for")
+	remove := fs.Bool("remove", false, "Remove the signing secret so unsigned requests are accepted again")
+
+	return func(env *commandEnv) {
+		if *userID == "" {
+			env.usageError("User ID is required for signing-secret operation")
+		}
+		signingSecret(env.auth(), *userID, *remove)
+	}
+}
+
+func setupCalendarToken(fs *flag.FlagSet) func(env *commandEnv) {
+	userID := fs.String("user-id", "", "User ID to create the calendar token for")
+	remove := fs.Bool("remove", false, "Remove the calendar token, disabling the user's feed")
+
+	return func(env *commandEnv) {
+		if *userID == "" {
+			env.usageError("User ID is required for ical-token operation")
+		}
+		calendarToken(env.auth(), *userID, *remove)
+	}
+}
+
+func setupNotify(fs *flag.FlagSet) func(env *commandEnv) {
+	userID := fs.String("user-id", "", "User ID to change notifications for")
+	webhookURL := fs.String("webhook-url", "", "Post the user's alerts as JSON to this URL")
+	email := fs.String("email", "", "Email the user's alerts to this address")
+	off := fs.Bool("off", false, "Stop sending the user alerts")
+
+	return func(env *commandEnv) {
+		if *userID == "" {
+			env.usageError("User ID is required for notify operation")
+		}
+		if !*off && *webhookURL == "" && *email == "" {
+			env.usageError("A webhook URL or an email address is required, or -off")
+		}
+		notifications(env.auth(), *userID, *webhookURL, *email, *off)
+	}
+}
+
+func bundleFlags(fs *flag.FlagSet) (*string, func(env *commandEnv) []byte) {
+	bundleFile := fs.String("file", "", "Bundle file")
+	bundleKeyHex := fs.String("bundle-key", "", "64-character hex key protecting the bundle (default: the encryption key)")
+
+	return bundleFile, func(env *commandEnv) []byte {
+		if *bundleFile == "" {
+			env.usageError("A bundle file is required")
+		}
+		if *bundleKeyHex == "" {
+			return env.key()
+		}
+		bundleKey, err := hex.DecodeString(*bundleKeyHex)
+		if err != nil || len(bundleKey) != 32 {
+			env.usageError("Bundle key must be 32 bytes (64 hex characters)")
+		}
+		return bundleKey
+	}
+}
+
+func setupExport(fs *flag.FlagSet) func(env *commandEnv) {
+	bundleFile, bundleKey := bundleFlags(fs)
+
+	return func(env *commandEnv) {
+		key := bundleKey(env)
+		exportUsers(env.auth(), *bundleFile, key)
+	}
+}
+
+func setupImport(fs *flag.FlagSet) func(env *commandEnv) {
+	bundleFile, bundleKey := bundleFlags(fs)
+	merge := fs.Bool("merge", false, "Keep existing users whose IDs collide with the bundle")
+	overwrite := fs.Bool("overwrite", false, "Replace existing users whose IDs collide with the bundle")
+
+	return func(env *commandEnv) {
+		key := bundleKey(env)
+		if *merge && *overwrite {
+			env.usageError("-merge and -overwrite cannot be combined")
+		}
+		mode := auth.ImportModeFail
+		if *merge {
+			mode = auth.ImportModeMerge
+		} else if *overwrite {
+			mode = auth.ImportModeOverwrite
+		}
+		importUsers(env.auth(), *bundleFile, key, mode)
+	}
+}
+
+func setupManage(fs *flag.FlagSet) func(env *commandEnv) {
+	return func(env *commandEnv) {
+		manageUsers(env.auth(), env.cfg, env.key())
+	}
+}
+
+func setupStats(fs *flag.FlagSet) func(env *commandEnv) {
+	window := fs.Duration("window", 30*24*time.Hour, "How far back to count tool calls")
+
+	return func(env *commandEnv) {
+		if *window <= 0 {
+			env.usageError("Window must be positive")
+		}
+		auditStore, err := storage.NewAuditStore(env.cfg.Storage.DataDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to initialize audit store: %v\n", err)
+			os.Exit(1)
+		}
+		showStats(env.auth(), auditStore, *window)
+	}
+}
+
+func setupKeygen(fs *flag.FlagSet) func(env *commandEnv) {
+	envFile := fs.String("env-file", "", "Also append the key to this .env file")
+
+	return func(env *commandEnv) {
+		generateKey(env.cfg.Security.EncryptionKeyEnv, *envFile)
+	}
+}
+
+func setupDoctor(fs *flag.FlagSet) func(env *commandEnv) {
+	return func(env *commandEnv) {
+		runDoctor(env.cfg)
+	}
+}
+
+func setupPseudonym(fs *flag.FlagSet) func(env *commandEnv) {
+	return func(env *commandEnv) {
+		if len(env.args) != 1 {
+			env.usageError("Exactly one pseudonym is required")
+		}
+
+		pseudonyms, err := storage.NewPseudonymStore(env.cfg.Storage.DataDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load pseudonyms: %v\n", err)
+			os.Exit(1)
+		}
+
+		kanboardURL, kanboardUser, ok := pseudonyms.Resolve(env.args[0])
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Unknown pseudonym: %s\n", env.args[0])
+			os.Exit(1)
+		}
+
+		fmt.Printf("Kanboard URL: %s\n", kanboardURL)
+		if username, found := strings.CutPrefix(kanboardUser, "username:"); found {
+			fmt.Printf("Kanboard username: %s\n", username)
+		} else {
+			fmt.Printf("Kanboard user ID: %s\n", kanboardUser)
+		}
+	}
+}
+
+func setupTemplateList(fs *flag.FlagSet) func(env *commandEnv) {
+	return func(env *commandEnv) {
+		set := loadTemplates(env)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tSOURCE")
+		for _, name := range templates.Names {
+			source := "default"
+			if path, ok := set.Overridden(name); ok {
+				source = path
+			}
+			fmt.Fprintf(w, "%s\t%s\n", name, source)
+		}
+		w.Flush()
+	}
+}
+
+func setupTemplateShow(fs *flag.FlagSet) func(env *commandEnv) {
+	builtIn := fs.Bool("default", false, "Print the built-in template even when the data directory overrides it")
+
+	return func(env *commandEnv) {
+		if len(env.args) != 1 {
+			env.usageError("Exactly one template name is required: %s", strings.Join(templates.Names, ", "))
+		}
+		name := env.args[0]
+
+		source, err := templates.Default(name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		if path, ok := loadTemplates(env).Overridden(name); ok && !*builtIn {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to read %s: %v\n", path, err)
+				os.Exit(1)
+			}
+			source = string(data)
+		}
+		fmt.Print(source)
+	}
+}
+
+// loadTemplates parses the report templates the server would use, so broken
+// overrides are reported here too.
+func loadTemplates(env *commandEnv) *templates.Set {
+	set, err := templates.Load(filepath.Join(env.cfg.Storage.DataDir, "templates"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load templates: %v\n", err)
+		os.Exit(1)
+	}
+	return set
+}
+
+func setupHelp(fs *flag.FlagSet) func(env *commandEnv) {
+	return func(env *commandEnv) {
+		root := commandTree()
+		node, path, _, err := resolveCommand(root, flag.NewFlagSet("", flag.ContinueOnError), env.args)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n\n", err)
+		}
+		if node.setup == nil || node == root {
+			printGroupUsage(os.Stdout, node, path)
+			return
+		}
+		printLeafUsage(os.Stdout, node, strings.Join(append([]string{os.Args[0]}, path...), " "))
+	}
+}
+
+func setupCompletion(script string) func(fs *flag.FlagSet) func(env *commandEnv) {
+	return func(fs *flag.FlagSet) func(env *commandEnv) {
+		return func(env *commandEnv) {
+			name := filepath.Base(os.Args[0])
+			fmt.Print(strings.ReplaceAll(script, "{{name}}", name))
+		}
+	}
+}
+
+// setupComplete backs the completion scripts: given the words typed so far,
+// with the word being completed last, it prints one candidate per line.
+// Nothing is printed when a flag value is expected, so shells fall back to
+// file names.
+func setupComplete(fs *flag.FlagSet) func(env *commandEnv) {
+	return func(env *commandEnv) {
+		words := env.args
+		current := ""
+		if len(words) > 0 {
+			current, words = words[len(words)-1], words[:len(words)-1]
+		}
+
+		root := commandTree()
+		known := allFlags(root)
+
+		if len(words) > 0 && isFlagArg(words[len(words)-1]) && takesValue(known, words[len(words)-1]) {
+			return
+		}
+
+		node, _, _, err := resolveCommand(root, known, words)
+		if err != nil {
+			return
+		}
+
+		var candidates []string
+		if node.setup == nil && !strings.HasPrefix(current, "-") {
+			for _, child := range node.children {
+				if !child.hidden {
+					candidates = append(candidates, child.name)
+				}
+			}
+		} else {
+			flags := leafFlags(node)
+			if node == root || node.setup != nil {
+				config.RegisterFlags(flags)
+			}
+			flags.VisitAll(func(f *flag.Flag) {
+				if f.Name != "t" {
+					candidates = append(candidates, "-"+f.Name)
+				}
+			})
+		}
+
+		for _, candidate := range candidates {
+			if strings.HasPrefix(candidate, current) {
+				fmt.Println(candidate)
+			}
+		}
+	}
+}
+
+const bashCompletion = `# bash completion for {{name}}
+# Load with: source <({{name}} completion bash)
+_{{name}}_complete() {
+	local IFS=$'\n'
+	COMPREPLY=($("${COMP_WORDS[0]}" __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+}
+complete -o default -F _{{name}}_complete {{name}}
+`
+
+const zshCompletion = `#compdef {{name}}
+# Load with: source <({{name}} completion zsh)
+_{{name}}_complete() {
+	local -a candidates
+	candidates=("${(@f)$("${words[1]}" __complete "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+	if [[ -n "${candidates[1]}" ]]; then
+		compadd -- "${candidates[@]}"
+	else
+		_files
+	fi
+}
+compdef _{{name}}_complete {{name}}
+`
+
+const fishCompletion = `# fish completion for {{name}}
+# Load with: {{name}} completion fish | source
+function __{{name}}_complete
+	set -l tokens (commandline -opc)
+	$tokens[1] __complete $tokens[2..-1] (commandline -ct) 2>/dev/null
+end
+complete -c {{name}} -a '(__{{name}}_complete)'
+`
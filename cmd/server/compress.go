@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// compressor gzip- or deflate-encodes HTTP responses of at least minSize
+// bytes for clients that accept it. Event streams are passed through
+// untouched, since buffering them would hold back notifications.
+type compressor struct {
+	minSize int
+	next    http.Handler
+}
+
+func (c *compressor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+	if encoding == "" {
+		c.next.ServeHTTP(w, r)
+		return
+	}
+
+	w.Header().Add("Vary", "Accept-Encoding")
+	cw := &compressWriter{ResponseWriter: w, encoding: encoding, minSize: c.minSize}
+	defer cw.close()
+	c.next.ServeHTTP(cw, r)
+}
+
+// negotiateEncoding picks gzip or deflate from an Accept-Encoding header,
+// preferring gzip and honouring q=0 exclusions.
+func negotiateEncoding(header string) string {
+	accepted := make(map[string]bool)
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = parsed
+			}
+		}
+		accepted[name] = quality > 0
+	}
+
+	for _, encoding := range []string{"gzip", "deflate"} {
+		if accepted[encoding] {
+			return encoding
+		}
+	}
+	return ""
+}
+
+// compressWriter buffers a response until it reaches minSize, then switches
+// to compressing it. Responses that end smaller are sent as they are.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	minSize  int
+
+	status      int
+	buf         bytes.Buffer
+	encoder     io.WriteCloser
+	passThrough bool
+	wroteHeader bool
+}
+
+func (cw *compressWriter) WriteHeader(status int) {
+	if cw.status != 0 {
+		return
+	}
+	cw.status = status
+
+	header := cw.Header()
+	if header.Get("Content-Encoding") != "" || strings.HasPrefix(header.Get("Content-Type"), "text/event-stream") ||
+		status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified {
+		cw.passThrough = true
+		cw.sendHeader()
+	}
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if cw.status == 0 {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.passThrough {
+		return cw.ResponseWriter.Write(p)
+	}
+	if cw.encoder != nil {
+		return cw.encoder.Write(p)
+	}
+
+	cw.buf.Write(p)
+	if cw.buf.Len() >= cw.minSize {
+		if err := cw.startEncoding(); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush sends what has been written so far. A response still below minSize
+// is sent uncompressed from then on, since the client is waiting for it.
+func (cw *compressWriter) Flush() {
+	switch {
+	case cw.encoder != nil:
+		if flusher, ok := cw.encoder.(interface{ Flush() error }); ok {
+			flusher.Flush()
+		}
+	case !cw.passThrough:
+		if cw.status == 0 {
+			cw.status = http.StatusOK
+		}
+		cw.passThrough = true
+		cw.sendHeader()
+		cw.ResponseWriter.Write(cw.buf.Bytes())
+		cw.buf.Reset()
+	}
+
+	if flusher, ok := cw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (cw *compressWriter) startEncoding() error {
+	header := cw.Header()
+	header.Set("Content-Encoding", cw.encoding)
+	header.Del("Content-Length")
+	cw.sendHeader()
+
+	if cw.encoding == "gzip" {
+		cw.encoder = gzip.NewWriter(cw.ResponseWriter)
+	} else {
+		encoder, err := flate.NewWriter(cw.ResponseWriter, flate.DefaultCompression)
+		if err != nil {
+			return err
+		}
+		cw.encoder = encoder
+	}
+
+	_, err := cw.encoder.Write(cw.buf.Bytes())
+	cw.buf.Reset()
+	return err
+}
+
+func (cw *compressWriter) sendHeader() {
+	if cw.wroteHeader {
+		return
+	}
+	cw.wroteHeader = true
+	cw.ResponseWriter.WriteHeader(cw.status)
+}
+
+func (cw *compressWriter) close() {
+	switch {
+	case cw.encoder != nil:
+		cw.encoder.Close()
+	case !cw.passThrough && cw.status != 0:
+		cw.sendHeader()
+		cw.ResponseWriter.Write(cw.buf.Bytes())
+	}
+}
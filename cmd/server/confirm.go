@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/tech-arch1tect/kan-mcp/internal/config"
+	"github.com/tech-arch1tect/kan-mcp/internal/logging"
+)
+
+// previewFunc describes what a write tool would change without changing it.
+type previewFunc func(ctx context.Context, request mcp.CallToolRequest) (string, error)
+
+type pendingConfirmation struct {
+	userID   string
+	tool     string
+	argsHash string
+	expires  time.Time
+}
+
+// confirmations holds the tokens issued by previews of write tools the
+// operator wants confirmed. A token is good for one call by the same user
+// with the same arguments.
+type confirmations struct {
+	mu       sync.Mutex
+	settings config.ConfirmConfig
+	pending  map[string]pendingConfirmation
+}
+
+func newConfirmations(settings config.ConfirmConfig) *confirmations {
+	return &confirmations{
+		settings: settings,
+		pending:  make(map[string]pendingConfirmation),
+	}
+}
+
+// addWriteTool registers a tool that changes Kanboard data, unless the server
+// is read-only. When the operator requires confirmation for it, the tool
+// gains a confirmation_token parameter and a call without one only returns
+// preview's description and a token.
+func (s *KanboardMCPServer) addWriteTool(tool mcp.Tool, preview previewFunc, handler server.ToolHandlerFunc) {
+	if s.userConfig.ReadOnly {
+		logging.Debugf("Read-only mode: not registering %s", tool.Name)
+		return
+	}
+	if s.confirmations.settings.Required(tool.Name) {
+		if tool.InputSchema.Properties == nil {
+			tool.InputSchema.Properties = make(map[string]interface{})
+		}
+		tool.InputSchema.Properties["confirmation_token"] = map[string]interface{}{
+			"type":        "string",
+			"description": "Token returned by a previous call with the same arguments, confirming the previewed change should be made",
+		}
+		handler = s.confirmed(tool.Name, preview, handler)
+	}
+	s.server.AddTool(tool, s.recorded(tool.Name, handler))
+}
+
+func (s *KanboardMCPServer) confirmed(tool string, preview previewFunc, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := request.GetArguments()
+		userID, _ := args["user_id"].(string)
+		if userID == "" {
+			userID, _ = userIDFromContext(ctx)
+		}
+
+		argsHash, err := confirmationHash(args)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("%s failed: %v", tool, err)), nil
+		}
+
+		now := time.Now()
+		token, _ := args["confirmation_token"].(string)
+		if token != "" {
+			if err := s.confirmations.redeem(token, userID, tool, argsHash, now); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return handler(ctx, request)
+		}
+
+		description, err := preview(ctx, request)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("%s preview failed: %v", tool, err)), nil
+		}
+
+		token, err = s.confirmations.issue(userID, tool, argsHash, now)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("%s failed: %v", tool, err)), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("%s\n\nNothing has been changed yet. Show this to the user and, if they agree, call %s again with the same arguments plus confirmation_token %q within %s.", description, tool, token, s.confirmations.settings.TTL)), nil
+	}
+}
+
+func (c *confirmations) issue(userID, tool, argsHash string, now time.Time) (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", fmt.Errorf("failed to generate confirmation token: %w", err)
+	}
+	token := hex.EncodeToString(bytes)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, pending := range c.pending {
+		if now.After(pending.expires) {
+			delete(c.pending, key)
+		}
+	}
+
+	c.pending[token] = pendingConfirmation{
+		userID:   userID,
+		tool:     tool,
+		argsHash: argsHash,
+		expires:  now.Add(c.settings.TTL),
+	}
+	return token, nil
+}
+
+// redeem consumes token, whether or not it matches, so a token can never be
+// tried twice.
+func (c *confirmations) redeem(token, userID, tool, argsHash string, now time.Time) error {
+	c.mu.Lock()
+	pending, ok := c.pending[token]
+	delete(c.pending, token)
+	c.mu.Unlock()
+
+	switch {
+	case !ok || now.After(pending.expires):
+		return fmt.Errorf("confirmation token is unknown or has expired; call %s without confirmation_token for a new preview", tool)
+	case pending.userID != userID || pending.tool != tool || pending.argsHash != argsHash:
+		return fmt.Errorf("confirmation token was issued for a different call; call %s without confirmation_token to preview these arguments", tool)
+	}
+	return nil
+}
+
+// confirmationHash identifies a call's arguments apart from the token itself.
+// encoding/json sorts map keys, so equal arguments hash equally.
+func confirmationHash(args map[string]interface{}) (string, error) {
+	rest := make(map[string]interface{}, len(args))
+	for key, value := range args {
+		if key != "confirmation_token" {
+			rest[key] = value
+		}
+	}
+
+	data, err := json.Marshal(rest)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode arguments: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/config"
+)
+
+func TestConfirmationHash(t *testing.T) {
+	base := map[string]interface{}{"project_id": 3.0, "name": "Review"}
+	want, err := confirmationHash(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		args  map[string]interface{}
+		equal bool
+	}{
+		{"same arguments", map[string]interface{}{"name": "Review", "project_id": 3.0}, true},
+		{"token ignored", map[string]interface{}{"project_id": 3.0, "name": "Review", "confirmation_token": "abc"}, true},
+		{"different value", map[string]interface{}{"project_id": 3.0, "name": "QA"}, false},
+		{"extra argument", map[string]interface{}{"project_id": 3.0, "name": "Review", "position": 2.0}, false},
+		{"missing argument", map[string]interface{}{"project_id": 3.0}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := confirmationHash(tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (got == want) != tt.equal {
+				t.Errorf("hash equal = %t, want %t", got == want, tt.equal)
+			}
+		})
+	}
+}
+
+func TestConfirmationsRedeem(t *testing.T) {
+	const ttl = 5 * time.Minute
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		userID   string
+		tool     string
+		argsHash string
+		at       time.Time
+		ok       bool
+	}{
+		{"matching call", "alice", "kanboard_create_column", "hash1", now.Add(time.Minute), true},
+		{"at expiry", "alice", "kanboard_create_column", "hash1", now.Add(ttl), true},
+		{"expired", "alice", "kanboard_create_column", "hash1", now.Add(ttl + time.Second), false},
+		{"different user", "bob", "kanboard_create_column", "hash1", now, false},
+		{"different tool", "alice", "kanboard_delete_column", "hash1", now, false},
+		{"different arguments", "alice", "kanboard_create_column", "hash2", now, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newConfirmations(config.ConfirmConfig{Tools: []string{"*"}, TTL: ttl})
+			token, err := c.issue("alice", "kanboard_create_column", "hash1", now)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = c.redeem(token, tt.userID, tt.tool, tt.argsHash, tt.at)
+			if (err == nil) != tt.ok {
+				t.Fatalf("redeem error = %v, want ok %t", err, tt.ok)
+			}
+
+			// Tokens are single use, whether or not the first try matched.
+			if err := c.redeem(token, "alice", "kanboard_create_column", "hash1", now); err == nil {
+				t.Error("token redeemed twice")
+			}
+		})
+	}
+}
+
+func TestConfirmationsIssueDropsExpired(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	c := newConfirmations(config.ConfirmConfig{Tools: []string{"*"}, TTL: time.Minute})
+
+	old, err := c.issue("alice", "kanboard_create_column", "hash1", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fresh, err := c.issue("alice", "kanboard_create_column", "hash1", now.Add(2*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if old == fresh {
+		t.Fatal("issue returned the same token twice")
+	}
+	if _, ok := c.pending[old]; ok {
+		t.Error("expired token still pending after a later issue")
+	}
+	if _, ok := c.pending[fresh]; !ok {
+		t.Error("new token not pending")
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"expvar"
+	"log"
+	"net"
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/api"
+	"github.com/tech-arch1tect/kan-mcp/internal/logging"
+)
+
+// serveDebug exposes net/http/pprof and expvar on 127.0.0.1:port. It uses its
+// own mux so the profiling handlers are never reachable through the MCP
+// listener.
+func (s *KanboardMCPServer) serveDebug(port string) {
+	expvar.Publish("kanboard_transports", expvar.Func(func() interface{} { return api.TransportStats() }))
+	expvar.Publish("kanboard_breakers", expvar.Func(func() interface{} { return api.BreakerStats() }))
+	expvar.Publish("kanboard_cache", expvar.Func(func() interface{} { return api.CacheStats() }))
+	expvar.Publish("kanboard_work_pool", expvar.Func(func() interface{} { return s.userConfig.WorkPool.Stats() }))
+	expvar.Publish("user_id_lockout", expvar.Func(func() interface{} { return s.lockout.Stats() }))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+
+	addr := net.JoinHostPort("127.0.0.1", port)
+	log.Printf("Debug server (pprof, expvar) listening on %s", addr)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		logging.Errorf("Debug server stopped: %v", err)
+	}
+}
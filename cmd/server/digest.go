@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/tech-arch1tect/kan-mcp/internal/handlers"
+	"github.com/tech-arch1tect/kan-mcp/internal/templates"
+)
+
+// addDigestResource exposes each user's latest digests as MCP resources.
+// Like the tools, the resource takes the user ID as an argument, here as part
+// of its URI.
+func (s *KanboardMCPServer) addDigestResource() {
+	template := mcp.NewResourceTemplate("kanboard://digests/{user_id}/{period}{?format}", "Kanboard task digest",
+		mcp.WithTemplateDescription("The user's latest daily or weekly digest: overdue tasks, tasks completed in the period and upcoming deadlines. period is 'daily' or 'weekly'; format is 'json' (default) or 'text', rendered from the digest template"),
+		mcp.WithTemplateMIMEType("application/json"),
+	)
+	s.server.AddResourceTemplate(template, s.readDigest)
+}
+
+func (s *KanboardMCPServer) readDigest(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	userID := resourceArgument(request, "user_id")
+	period := resourceArgument(request, "period")
+	if period != handlers.DigestDaily && period != handlers.DigestWeekly {
+		return nil, fmt.Errorf("invalid digest period %q: must be daily or weekly", period)
+	}
+	format := resourceArgument(request, "format")
+	if format != "" && format != "json" && format != "text" {
+		return nil, fmt.Errorf("invalid format %q: must be json or text", format)
+	}
+
+	if err := s.authorizeResource(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	stored, ok := s.digests.Latest(userID, period)
+	if !ok {
+		return nil, fmt.Errorf("no %s digest has been generated for this user yet", period)
+	}
+
+	if format == "text" {
+		var digest handlers.Digest
+		if err := json.Unmarshal(stored.Digest, &digest); err != nil {
+			return nil, fmt.Errorf("failed to read stored digest: %w", err)
+		}
+		text, err := s.userConfig.Templates.Render(templates.Digest, &digest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render digest: %w", err)
+		}
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      request.Params.URI,
+				MIMEType: "text/plain",
+				Text:     text,
+			},
+		}, nil
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(stored.Digest),
+		},
+	}, nil
+}
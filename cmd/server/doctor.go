@@ -0,0 +1,263 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/api"
+	"github.com/tech-arch1tect/kan-mcp/internal/auth"
+	"github.com/tech-arch1tect/kan-mcp/internal/config"
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+	"github.com/tech-arch1tect/kan-mcp/internal/storage"
+)
+
+// doctorCheck is one line of the `doctor` checklist. A check with an
+// empty Hint passed; Skipped checks could not run because an earlier one
+// failed or the information needed is not configured.
+type doctorCheck struct {
+	Name    string
+	Detail  string
+	Hint    string
+	Skipped bool
+}
+
+func runDoctor(cfg *config.Config) {
+	var checks []doctorCheck
+	add := func(check doctorCheck) {
+		checks = append(checks, check)
+	}
+
+	encryptionKey, err := cfg.GetEncryptionKey()
+	if err != nil {
+		hint := fmt.Sprintf("generate one with `%s keygen` and export it as %s", os.Args[0], cfg.Security.EncryptionKeyEnv)
+		switch {
+		case os.Getenv(config.PassphraseEnv) != "":
+			hint = fmt.Sprintf("use a passphrase of at least 12 characters in %s on its own, and make sure the data directory is writable for key-derivation.json", config.PassphraseEnv)
+		case cfg.Security.EncryptionKeySource != "":
+			hint = fmt.Sprintf("check that %s holds a 64-character hex key and is readable from this host", cfg.Security.EncryptionKeySource)
+		}
+		add(doctorCheck{Name: "Encryption key", Detail: err.Error(), Hint: hint})
+	} else {
+		add(doctorCheck{Name: "Encryption key", Detail: "32 bytes"})
+	}
+
+	if encryptionKey == nil {
+		add(doctorCheck{Name: "Configuration", Detail: "needs a valid encryption key", Skipped: true})
+	} else if err := cfg.Validate(); err != nil {
+		add(doctorCheck{Name: "Configuration", Detail: err.Error(), Hint: fmt.Sprintf("run `%s -help` for every option and its environment variable", os.Args[0])})
+	} else {
+		add(doctorCheck{Name: "Configuration", Detail: "valid"})
+	}
+
+	add(checkDataDir(cfg.Storage.DataDir))
+
+	if encryptionKey != nil {
+		add(checkStoredTokens(cfg, encryptionKey))
+	} else {
+		add(doctorCheck{Name: "Stored tokens", Detail: "needs a valid encryption key", Skipped: true})
+	}
+
+	reachability, version := checkKanboard(cfg)
+	add(reachability)
+	add(version)
+
+	failed := 0
+	for _, check := range checks {
+		switch {
+		case check.Skipped:
+			fmt.Printf("- %s: skipped (%s)\n", check.Name, check.Detail)
+		case check.Hint != "":
+			failed++
+			fmt.Printf("✗ %s: %s\n", check.Name, check.Detail)
+			fmt.Printf("    → %s\n", check.Hint)
+		default:
+			fmt.Printf("✓ %s: %s\n", check.Name, check.Detail)
+		}
+	}
+
+	if failed > 0 {
+		fmt.Printf("\n%d check(s) failed\n", failed)
+		os.Exit(1)
+	}
+	fmt.Printf("\nAll checks passed\n")
+}
+
+func checkDataDir(dataDir string) doctorCheck {
+	check := doctorCheck{Name: "Data directory"}
+	if dataDir == "" {
+		check.Detail = "not set"
+		check.Hint = "set DATA_DIR or -data-dir"
+		return check
+	}
+
+	info, err := os.Stat(dataDir)
+	switch {
+	case os.IsNotExist(err):
+		check.Detail = fmt.Sprintf("%s does not exist yet", dataDir)
+		if parentErr := writable(filepath.Dir(dataDir)); parentErr != nil {
+			check.Hint = fmt.Sprintf("create it or make %s writable: %v", filepath.Dir(dataDir), parentErr)
+		}
+		return check
+	case err != nil:
+		check.Detail = err.Error()
+		check.Hint = "check the path and the permissions of its parent directories"
+		return check
+	case !info.IsDir():
+		check.Detail = fmt.Sprintf("%s is not a directory", dataDir)
+		check.Hint = "point DATA_DIR at a directory"
+		return check
+	}
+
+	if err := writable(dataDir); err != nil {
+		check.Detail = fmt.Sprintf("%s is not writable: %v", dataDir, err)
+		check.Hint = "grant the server's user write access to the data directory"
+		return check
+	}
+
+	if info.Mode().Perm()&0002 != 0 {
+		check.Detail = fmt.Sprintf("%s is world-writable (%s)", dataDir, info.Mode().Perm())
+		check.Hint = fmt.Sprintf("restrict it, e.g. chmod 700 %s", dataDir)
+		return check
+	}
+
+	check.Detail = fmt.Sprintf("%s (%s)", dataDir, info.Mode().Perm())
+	return check
+}
+
+func writable(dir string) error {
+	file, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		return err
+	}
+	file.Close()
+	return os.Remove(file.Name())
+}
+
+// checkStoredTokens confirms the configured key decrypts existing
+// registrations, which catches a key that was rotated or mistyped.
+func checkStoredTokens(cfg *config.Config, encryptionKey []byte) doctorCheck {
+	check := doctorCheck{Name: "Stored tokens"}
+
+	if _, err := os.Stat(filepath.Join(cfg.Storage.DataDir, "users")); err != nil {
+		check.Detail = "no users registered yet"
+		check.Skipped = true
+		return check
+	}
+
+	fileStore, err := storage.NewFileStore(cfg.Storage.DataDir)
+	if err != nil {
+		check.Detail = err.Error()
+		check.Hint = "fix the data directory first"
+		return check
+	}
+
+	authManager, err := auth.NewAuthManager(encryptionKey, fileStore)
+	if err != nil {
+		check.Detail = err.Error()
+		check.Hint = "fix the encryption key first"
+		return check
+	}
+	authManager.SetCredentialLifetime(cfg.Security.CredentialLifetime)
+
+	users, err := authManager.ListUsers()
+	if err != nil {
+		check.Detail = err.Error()
+		check.Hint = "check the permissions of the users directory"
+		return check
+	}
+
+	checked, undecryptable, expired := 0, 0, 0
+	for _, user := range users {
+		if expiry := authManager.CredentialExpiry(user); !expiry.IsZero() && time.Now().After(expiry) {
+			expired++
+		}
+		if user.AuthMode == models.AuthModeApp {
+			continue
+		}
+		checked++
+		if _, err := authManager.GetDecryptedToken(user); err != nil {
+			undecryptable++
+		}
+	}
+
+	if undecryptable > 0 {
+		check.Detail = fmt.Sprintf("%d of %d personal token(s) cannot be decrypted", undecryptable, checked)
+		check.Hint = fmt.Sprintf("%s differs from the key used at registration; restore the original key or re-register the affected users", cfg.Security.EncryptionKeyEnv)
+		return check
+	}
+
+	check.Detail = fmt.Sprintf("%d user(s), %d personal token(s) decrypt", len(users), checked)
+	if expired > 0 {
+		check.Detail += fmt.Sprintf(", %d registration(s) expired (renew with `%s user renew`)", expired, os.Args[0])
+	}
+	return check
+}
+
+// checkKanboard calls getVersion on the default Kanboard URL. Without the
+// application token the call is expected to be rejected, which still proves
+// the instance is reachable.
+func checkKanboard(cfg *config.Config) (doctorCheck, doctorCheck) {
+	reachability := doctorCheck{Name: "Kanboard reachable"}
+	version := doctorCheck{Name: "Kanboard API version"}
+
+	if cfg.Kanboard.DefaultURL == "" {
+		reachability.Detail = "DEFAULT_KANBOARD_URL is not set"
+		reachability.Hint = "set DEFAULT_KANBOARD_URL to your Kanboard base URL, e.g. https://kanboard.example.com"
+		version.Detail = "needs a Kanboard URL"
+		version.Skipped = true
+		return reachability, version
+	}
+
+	tlsConfig, err := cfg.GetKanboardTLSConfig()
+	if err != nil {
+		reachability.Detail = err.Error()
+		reachability.Hint = "check KANBOARD_CA_CERT, KANBOARD_CLIENT_CERT and KANBOARD_CLIENT_KEY"
+		version.Detail = "needs a working TLS configuration"
+		version.Skipped = true
+		return reachability, version
+	}
+
+	client := api.NewClient(cfg.Kanboard.DefaultURL, "", cfg.Kanboard.AppToken, api.Options{
+		AuthMode:   models.AuthModeApp,
+		AuthHeader: cfg.Kanboard.AuthHeader,
+		Timeout:    10 * time.Second,
+		TLSConfig:  tlsConfig,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	kanboardVersion, err := client.GetVersion(ctx)
+	latency := time.Since(start).Round(time.Millisecond)
+
+	switch {
+	case err == nil:
+		reachability.Detail = fmt.Sprintf("%s responded in %s", cfg.Kanboard.DefaultURL, latency)
+		version.Detail = kanboardVersion
+	case errors.Is(err, api.ErrUnauthorized) && cfg.Kanboard.AppToken == "":
+		reachability.Detail = fmt.Sprintf("%s responded in %s", cfg.Kanboard.DefaultURL, latency)
+		version.Detail = fmt.Sprintf("needs KANBOARD_APP_TOKEN; `%s user test -user-id <id>` checks a user's access instead", os.Args[0])
+		version.Skipped = true
+	case errors.Is(err, api.ErrUnauthorized):
+		reachability.Detail = fmt.Sprintf("%s responded in %s", cfg.Kanboard.DefaultURL, latency)
+		version.Detail = "the application token was rejected"
+		version.Hint = "copy the API token from Kanboard's Settings > API page into KANBOARD_APP_TOKEN"
+	case errors.Is(err, api.ErrNotFound):
+		reachability.Detail = fmt.Sprintf("%s has no JSON-RPC endpoint: %v", cfg.Kanboard.DefaultURL, err)
+		reachability.Hint = "use Kanboard's base URL, without /jsonrpc.php or a trailing path"
+		version.Detail = "needs a reachable Kanboard"
+		version.Skipped = true
+	default:
+		reachability.Detail = err.Error()
+		reachability.Hint = "check the URL, DNS, firewalls and any proxy between this host and Kanboard"
+		version.Detail = "needs a reachable Kanboard"
+		version.Skipped = true
+	}
+
+	return reachability, version
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/tech-arch1tect/kan-mcp/internal/api"
+	"github.com/tech-arch1tect/kan-mcp/internal/auth"
+	"github.com/tech-arch1tect/kan-mcp/internal/handlers"
+	"github.com/tech-arch1tect/kan-mcp/internal/workpool"
+)
+
+var notFoundHints = map[string]string{
+	"overview":             "One of the user's projects could not be read; it may have been deleted or archived while the overview was running. Retry the call.",
+	"tasks":                "Check that every ID in project_ids refers to an existing project the user is a member of, or omit project_ids to search all projects.",
+	"priorities":           "Check that every ID in project_ids refers to an existing project the user is a member of, or omit project_ids to analyse all projects.",
+	"analytics":            "Check that every ID in project_ids refers to an existing project the user is a member of, or omit project_ids to analyse all projects.",
+	"focus":                "Check that every ID in project_ids refers to an existing project the user is a member of, or omit project_ids to use all projects.",
+	"standup":              "Check that every ID in project_ids refers to an existing project the user is a member of, or omit project_ids to use all projects.",
+	"manage_categories":    "Check that task_id refers to an existing task; find task IDs with kanboard_tasks.",
+	"manage_members":       "Check the Kanboard username or user ID; kanboard_overview lists the users in each project.",
+	"add_external_link":    "Check that task_id refers to an existing task; find task IDs with kanboard_tasks.",
+	"log_time":             "Check that task_id refers to an existing task; find task IDs with kanboard_tasks.",
+	"duplicate_task":       "Check that task_id refers to an existing task and project_id to an existing project; kanboard_overview lists the user's projects.",
+	"move_task_to_project": "Check that task_id refers to an existing task and project_id to an existing project; kanboard_overview lists the user's projects.",
+	"set_recurrence":       "Check that task_id refers to an existing task; find task IDs with kanboard_tasks.",
+}
+
+func toolError(tool string, err error) *mcp.CallToolResult {
+	var hint string
+
+	switch {
+	case errors.Is(err, auth.ErrCredentialsExpired):
+		hint = "The registration has passed the credential lifetime set by the operator. Ask the operator to check the token and renew it with: ./kan-mcp user renew -user-id <id>"
+	case errors.Is(err, api.ErrUnauthorized):
+		hint = "Kanboard rejected the stored credentials. The personal access token may have been revoked or the user lacks access; ask the operator to store a new token with: ./kan-mcp user update -user-id <id> -token"
+	case errors.Is(err, api.ErrNotFound):
+		hint = notFoundHints[tool]
+	case errors.Is(err, api.ErrRateLimited):
+		hint = "Kanboard is rate limiting requests. Wait a minute before retrying, and narrow the request with project_ids if possible."
+	case errors.Is(err, api.ErrServer):
+		hint = "Kanboard returned a server error. This is not caused by the request parameters; retry later or ask the Kanboard administrator to check the server logs."
+	case errors.Is(err, api.ErrUnavailable):
+		hint = "The Kanboard instance could not be reached. Retry later; calls are suspended briefly after repeated failures."
+	case errors.Is(err, workpool.ErrBusy):
+		hint = "The server is busy with other requests. Retry in a few seconds, and narrow the request with project_ids if possible."
+	case errors.Is(err, handlers.ErrPermission):
+		hint = "Kanboard would not let the user make this change either. Ask a project manager to make it or to change the user's project role."
+	case errors.Is(err, api.ErrRejected):
+		hint = "Kanboard gives no reason for refusing a change; check names are unique and the referenced items still exist, then retry."
+	case errors.Is(err, api.ErrReadOnly):
+		hint = "The operator runs this server in read-only mode, so it cannot change Kanboard data. Make the change in Kanboard directly."
+	}
+
+	if hint == "" {
+		return mcp.NewToolResultError(fmt.Sprintf("%s failed: %v", tool, err))
+	}
+	return mcp.NewToolResultError(fmt.Sprintf("%s failed: %v. %s", tool, err, hint))
+}
+
+// addRequestID appends the call's request ID to an error result so users can
+// quote it when reporting the failure.
+func addRequestID(result *mcp.CallToolResult, requestID string) {
+	if result == nil || !result.IsError {
+		return
+	}
+	for i, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			text.Text = fmt.Sprintf("%s (request ID: %s)", text.Text, requestID)
+			result.Content[i] = text
+			return
+		}
+	}
+}
+
+// validRequestID accepts caller-supplied X-Request-ID values that are safe to
+// log and forward to Kanboard.
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > 64 {
+		return false
+	}
+	for _, r := range requestID {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return false
+		}
+	}
+	return true
+}
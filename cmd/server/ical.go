@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/auth"
+	"github.com/tech-arch1tect/kan-mcp/internal/handlers"
+	"github.com/tech-arch1tect/kan-mcp/internal/logging"
+)
+
+// icalFeed serves each user's task due dates on /ical/{token}.ics, where the
+// token comes from `user ical-token`. Calendar applications cannot send
+// headers or sign requests, so the token in the path is the only credential;
+// unknown tokens count towards the client's lockout. Add ?start_dates=true
+// for events on start dates too.
+type icalFeed struct {
+	authManager *auth.AuthManager
+	feed        *handlers.CalendarFeed
+	limiter     *toolLimiter
+	lockout     *lockout
+}
+
+func (f *icalFeed) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/ical/"), ".ics")
+	if !ok || token == "" || strings.Contains(token, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	includeStart := false
+	if value := r.URL.Query().Get("start_dates"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			http.Error(w, "start_dates must be true or false", http.StatusBadRequest)
+			return
+		}
+		includeStart = parsed
+	}
+
+	user, err := f.authManager.UserByCalendarToken(token)
+	if err != nil {
+		if !errors.Is(err, auth.ErrUnknownCalendarToken) {
+			logging.Warnf("Calendar feed lookup failed: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		if addr, ok := clientAddrFromContext(r.Context()); ok {
+			f.lockout.fail(addr, time.Now())
+		}
+		http.NotFound(w, r)
+		return
+	}
+
+	if err := f.limiter.allow(user.UserID, time.Now()); err != nil {
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	}
+
+	data, err := f.feed.Render(r.Context(), user.UserID, includeStart, time.Now())
+	if err != nil {
+		if errors.Is(err, auth.ErrCredentialsExpired) {
+			http.Error(w, "Credentials expired", http.StatusForbidden)
+			return
+		}
+		logging.Warnf("Calendar feed for user %s failed: %v", logging.UserID(user.UserID), err)
+		http.Error(w, "Failed to load tasks from Kanboard", http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	if r.Method == http.MethodHead {
+		return
+	}
+	w.Write(data)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/logging"
+)
+
+// ipFilter refuses HTTP requests from addresses outside the allowlist or
+// inside the denylist before they reach the MCP handler.
+type ipFilter struct {
+	allowed           []netip.Prefix
+	denied            []netip.Prefix
+	trustForwardedFor bool
+	next              http.Handler
+}
+
+func (f *ipFilter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	addr, ok := requestAddr(r, f.trustForwardedFor)
+	if !ok || !f.permitted(addr) {
+		logging.Warnf("Refused HTTP request from %s (remote %s)", addr, r.RemoteAddr)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	f.next.ServeHTTP(w, r)
+}
+
+// requestAddr returns the connecting address, or with trustForwardedFor the
+// last X-Forwarded-For entry: the one appended by our own proxy. Earlier
+// entries come from the client and can be forged.
+func requestAddr(r *http.Request, trustForwardedFor bool) (netip.Addr, bool) {
+	if trustForwardedFor {
+		if header := r.Header.Values("X-Forwarded-For"); len(header) > 0 {
+			entries := strings.Split(header[len(header)-1], ",")
+			addr, err := netip.ParseAddr(strings.TrimSpace(entries[len(entries)-1]))
+			return addr.Unmap(), err == nil
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	return addr.Unmap(), err == nil
+}
+
+func (f *ipFilter) permitted(addr netip.Addr) bool {
+	for _, prefix := range f.denied {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	if len(f.allowed) == 0 {
+		return true
+	}
+	for _, prefix := range f.allowed {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
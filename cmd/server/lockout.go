@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/config"
+	"github.com/tech-arch1tect/kan-mcp/internal/logging"
+)
+
+type clientAddrKey struct{}
+
+// clientAddrFromContext returns the address an HTTP request came from. ok is
+// false outside the HTTP transport.
+func clientAddrFromContext(ctx context.Context) (addr netip.Addr, ok bool) {
+	addr, ok = ctx.Value(clientAddrKey{}).(netip.Addr)
+	return addr, ok
+}
+
+type lockoutState struct {
+	failures    int
+	lastFailure time.Time
+	bannedUntil time.Time
+}
+
+// lockout bans HTTP clients that keep calling tools with unknown user IDs or
+// sending webhooks with the wrong token, so neither can be guessed by brute
+// force.
+type lockout struct {
+	mu       sync.Mutex
+	settings config.LockoutConfig
+	clients  map[netip.Addr]*lockoutState
+	bans     int
+}
+
+func newLockout(settings config.LockoutConfig) *lockout {
+	return &lockout{
+		settings: settings,
+		clients:  make(map[netip.Addr]*lockoutState),
+	}
+}
+
+// guard refuses requests from banned addresses with 429 and records the
+// client address in the request context for the tool handlers.
+func (l *lockout) guard(trustForwardedFor bool, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		addr, ok := requestAddr(r, trustForwardedFor)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if wait := l.banned(addr, time.Now()); wait > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(wait.Seconds())+1))
+			http.Error(w, "Too many invalid user IDs; retry later", http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), clientAddrKey{}, addr)))
+	})
+}
+
+// banned returns how much longer addr is banned for.
+func (l *lockout) banned(addr netip.Addr, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	state, ok := l.clients[addr]
+	if !ok {
+		return 0
+	}
+	return state.bannedUntil.Sub(now)
+}
+
+// fail records an unknown user ID or webhook token from addr, banning it once
+// it reaches the threshold.
+func (l *lockout) fail(addr netip.Addr, now time.Time) {
+	if l.settings.Threshold <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.clients) >= 1000 {
+		l.sweep(now)
+	}
+
+	state, ok := l.clients[addr]
+	if !ok || now.Sub(state.lastFailure) > l.settings.MaxBan {
+		state = &lockoutState{}
+		l.clients[addr] = state
+	}
+	state.failures++
+	state.lastFailure = now
+
+	if state.failures < l.settings.Threshold {
+		return
+	}
+
+	ban := l.settings.MaxBan
+	if shift := state.failures - l.settings.Threshold; shift < 32 && l.settings.Ban<<shift < ban {
+		ban = l.settings.Ban << shift
+	}
+	state.bannedUntil = now.Add(ban)
+	l.bans++
+
+	logging.Warnf("Banned %s for %s after %d unknown user IDs or webhook tokens", addr, ban, state.failures)
+	if ban == l.settings.MaxBan {
+		logging.Errorf("Sustained guessing from %s: %d unknown user IDs or webhook tokens, banned for the maximum of %s", addr, state.failures, ban)
+	}
+}
+
+// sweep drops addresses that are neither banned nor within the window in
+// which their failures still count.
+func (l *lockout) sweep(now time.Time) {
+	for addr, state := range l.clients {
+		if now.After(state.bannedUntil) && now.Sub(state.lastFailure) > l.settings.MaxBan {
+			delete(l.clients, addr)
+		}
+	}
+}
+
+// Stats reports current and total bans for expvar.
+func (l *lockout) Stats() map[string]int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	active := 0
+	for _, state := range l.clients {
+		if state.bannedUntil.After(now) {
+			active++
+		}
+	}
+	return map[string]int{"active_bans": active, "total_bans": l.bans}
+}
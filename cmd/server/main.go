@@ -2,22 +2,40 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"context"
-	"flag"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"syscall"
+	"text/tabwriter"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/tech-arch1tect/kan-mcp/internal/api"
 	"github.com/tech-arch1tect/kan-mcp/internal/auth"
 	"github.com/tech-arch1tect/kan-mcp/internal/config"
 	"github.com/tech-arch1tect/kan-mcp/internal/handlers"
+	"github.com/tech-arch1tect/kan-mcp/internal/logging"
+	"github.com/tech-arch1tect/kan-mcp/internal/mailer"
 	"github.com/tech-arch1tect/kan-mcp/internal/models"
 	"github.com/tech-arch1tect/kan-mcp/internal/storage"
+	"github.com/tech-arch1tect/kan-mcp/internal/templates"
+	"github.com/tech-arch1tect/kan-mcp/internal/tracing"
+	"github.com/tech-arch1tect/kan-mcp/internal/workpool"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
 	"golang.org/x/term"
 )
 
@@ -35,18 +53,26 @@ func userIDFromContext(ctx context.Context) (string, error) {
 	return userID, nil
 }
 
+const serverVersion = "1.0.0"
+
 type KanboardMCPServer struct {
 	server      *server.MCPServer
 	authManager *auth.AuthManager
 	userConfig  *models.UserConfig
+	auditStore  *storage.AuditStore
+	quotas      *quotaTracker
+	limiter     *toolLimiter
+	lockout     *lockout
+	taskSyncer  *handlers.TaskSyncer
+	digests     *storage.DigestStore
+	digester    *handlers.DigestScheduler
+	notifier    *handlers.Notifier
+
+	confirmations     *confirmations
+	requireSignatures bool
 }
 
-func NewKanboardMCPServer() (*KanboardMCPServer, error) {
-
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		return nil, fmt.Errorf("failed to load configuration: %w", err)
-	}
+func NewKanboardMCPServer(cfg *config.Config) (*KanboardMCPServer, error) {
 
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("configuration validation failed: %w", err)
@@ -57,6 +83,10 @@ func NewKanboardMCPServer() (*KanboardMCPServer, error) {
 		return nil, fmt.Errorf("failed to get encryption key: %w", err)
 	}
 
+	if cfg.Kanboard.TLS.InsecureSkipVerify {
+		log.Println("WARNING: TLS certificate verification for Kanboard is disabled (KANBOARD_INSECURE_SKIP_VERIFY)")
+	}
+
 	fileStore, err := storage.NewFileStore(cfg.Storage.DataDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize file store: %w", err)
@@ -66,15 +96,89 @@ func NewKanboardMCPServer() (*KanboardMCPServer, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize auth manager: %w", err)
 	}
+	authManager.SetCredentialLifetime(cfg.Security.CredentialLifetime)
 
-	userConfig := &models.UserConfig{
-		DefaultKanboardURL: cfg.Kanboard.DefaultURL,
-		EncryptionKey:      encryptionKey,
+	userConfig, err := newUserConfig(cfg, encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	auditStore, err := storage.NewAuditStore(cfg.Storage.DataDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize audit store: %w", err)
+	}
+
+	quotas, err := newQuotaTracker(cfg.Quota, userConfig.DefaultLocation, auditStore)
+	if err != nil {
+		return nil, err
+	}
+
+	var taskSyncer *handlers.TaskSyncer
+	if cfg.TaskSync.Enabled {
+		taskStore, err := storage.NewTaskStore(cfg.Storage.DataDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize task store: %w", err)
+		}
+		userConfig.TaskSnapshot = taskStore
+		userConfig.TaskSnapshotMaxAge = 2 * cfg.TaskSync.Interval
+		taskSyncer = handlers.NewTaskSyncer(authManager, userConfig, taskStore, cfg.TaskSync.Interval)
+	}
+
+	digestPeriods, err := cfg.Digest.GetPeriods()
+	if err != nil {
+		return nil, err
+	}
+	var digests *storage.DigestStore
+	var digester *handlers.DigestScheduler
+	if len(digestPeriods) > 0 {
+		digests, err = storage.NewDigestStore(cfg.Storage.DataDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize digest store: %w", err)
+		}
+		hour, minute, weekday, err := cfg.Digest.GetSchedule()
+		if err != nil {
+			return nil, err
+		}
+		digester = handlers.NewDigestScheduler(authManager, userConfig, digests, handlers.DigestSchedule{
+			Periods:       digestPeriods,
+			Hour:          hour,
+			Minute:        minute,
+			Weekday:       weekday,
+			WebhookURL:    cfg.Digest.WebhookURL,
+			WebhookSecret: cfg.Digest.WebhookSecret,
+		})
+	}
+
+	var notifier *handlers.Notifier
+	if cfg.Notify.Enabled {
+		hour, minute, err := cfg.Notify.GetTime()
+		if err != nil {
+			return nil, err
+		}
+		// A nil *mailer.Mailer must not become a non-nil handlers.Mailer.
+		var sender handlers.Mailer
+		if cfg.Notify.SMTP.Host != "" {
+			sender, err = mailer.New(mailer.Settings{
+				Host:     cfg.Notify.SMTP.Host,
+				Port:     cfg.Notify.SMTP.Port,
+				Username: cfg.Notify.SMTP.Username,
+				Password: cfg.Notify.SMTP.Password,
+				From:     cfg.Notify.SMTP.From,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to initialize mailer: %w", err)
+			}
+		}
+		notifier = handlers.NewNotifier(authManager, userConfig, sender, handlers.NotificationSchedule{
+			Hour:          hour,
+			Minute:        minute,
+			WebhookSecret: cfg.Notify.WebhookSecret,
+		})
 	}
 
 	mcpServer := server.NewMCPServer(
 		"Kanboard MCP Server",
-		"1.0.0",
+		serverVersion,
 		server.WithToolCapabilities(true),
 	)
 
@@ -82,13 +186,105 @@ func NewKanboardMCPServer() (*KanboardMCPServer, error) {
 		server:      mcpServer,
 		authManager: authManager,
 		userConfig:  userConfig,
+		auditStore:  auditStore,
+		quotas:      quotas,
+		limiter:     newToolLimiter(cfg.ToolRateLimit),
+		lockout:     newLockout(cfg.Lockout),
+		taskSyncer:  taskSyncer,
+		digests:     digests,
+		digester:    digester,
+		notifier:    notifier,
+
+		confirmations:     newConfirmations(cfg.Confirm),
+		requireSignatures: cfg.Server.RequireSignatures,
 	}
 
 	kanboardServer.addTools()
+	kanboardServer.addWriteTools()
+	kanboardServer.addChartResource()
+	if digests != nil {
+		kanboardServer.addDigestResource()
+	}
 
 	return kanboardServer, nil
 }
 
+// newUserConfig resolves the settings handlers need to talk to Kanboard.
+func newUserConfig(cfg *config.Config, encryptionKey []byte) (*models.UserConfig, error) {
+	tlsConfig, err := cfg.GetKanboardTLSConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build Kanboard TLS configuration: %w", err)
+	}
+
+	workCalendar, err := cfg.GetCalendar()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build working-day calendar: %w", err)
+	}
+
+	defaultLocation, err := cfg.GetTimezone()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load default timezone: %w", err)
+	}
+
+	userConfig := &models.UserConfig{
+		DefaultKanboardURL: cfg.Kanboard.DefaultURL,
+		EncryptionKey:      encryptionKey,
+		KanboardAppToken:   cfg.Kanboard.AppToken,
+		KanboardAuthHeader: cfg.Kanboard.AuthHeader,
+		KanboardTimeout:    cfg.Kanboard.Timeout,
+		KanboardTLS:        tlsConfig,
+		KanboardRetry: models.RetrySettings{
+			MaxRetries: cfg.Kanboard.Retry.MaxRetries,
+			BaseDelay:  cfg.Kanboard.Retry.BaseDelay,
+			MaxDelay:   cfg.Kanboard.Retry.MaxDelay,
+		},
+		KanboardRateLimit: models.RateLimitSettings{
+			RequestsPerSecond: cfg.Kanboard.RateLimit.RequestsPerSecond,
+			Burst:             cfg.Kanboard.RateLimit.Burst,
+		},
+		KanboardCacheTTLs: map[string]time.Duration{
+			"getMyProjects":    cfg.Kanboard.Cache.ProjectsTTL,
+			"getProjectById":   cfg.Kanboard.Cache.ProjectsTTL,
+			"getColumns":       cfg.Kanboard.Cache.ColumnsTTL,
+			"getAllSwimlanes":  cfg.Kanboard.Cache.SwimlanesTTL,
+			"getProjectUsers":  cfg.Kanboard.Cache.UsersTTL,
+			"getAllCategories": cfg.Kanboard.Cache.CategoriesTTL,
+			"getTagsByProject": cfg.Kanboard.Cache.CategoriesTTL,
+		},
+		OverviewCacheTTL: cfg.Kanboard.Cache.OverviewTTL,
+		KanboardBreaker: models.BreakerSettings{
+			FailureThreshold: cfg.Kanboard.Breaker.FailureThreshold,
+			OpenDuration:     cfg.Kanboard.Breaker.OpenDuration,
+		},
+		KanboardTransport: models.TransportSettings{
+			MaxIdleConns:        cfg.Kanboard.Transport.MaxIdleConns,
+			MaxIdleConnsPerHost: cfg.Kanboard.Transport.MaxIdleConnsPerHost,
+			IdleConnTimeout:     cfg.Kanboard.Transport.IdleConnTimeout,
+		},
+		KanboardSlowCall: cfg.Kanboard.SlowCalls.Threshold,
+		ReadOnly:         cfg.Server.ReadOnly,
+		Calendar:         workCalendar,
+		DefaultLocation:  defaultLocation,
+		WorkPool:         workpool.New(cfg.Kanboard.WorkerPool.Workers, cfg.Kanboard.WorkerPool.QueueSize, cfg.Kanboard.WorkerPool.QueueTimeout),
+	}
+
+	reportTemplates, err := templates.Load(filepath.Join(cfg.Storage.DataDir, "templates"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load report templates: %w", err)
+	}
+	userConfig.Templates = reportTemplates
+
+	if cfg.Security.PseudonymizeUsers {
+		pseudonyms, err := storage.NewPseudonymStore(cfg.Storage.DataDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load pseudonyms: %w", err)
+		}
+		userConfig.Pseudonyms = pseudonyms
+	}
+
+	return userConfig, nil
+}
+
 func (s *KanboardMCPServer) addTools() {
 
 	overviewTool := mcp.NewTool("kanboard_overview",
@@ -103,8 +299,35 @@ func (s *KanboardMCPServer) addTools() {
 		mcp.WithBoolean("include_inactive_projects",
 			mcp.Description("Include inactive/archived projects (default: false)"),
 		),
+		mcp.WithString("project_ids",
+			mcp.Description("Optional: comma-separated list of project IDs to include"),
+		),
+		mcp.WithString("project_filter",
+			mcp.Description("Optional: only projects whose name or identifier contains this text, or matches it as a glob such as 'ops-*' (case-insensitive)"),
+		),
+		mcp.WithBoolean("include_swimlane_counts",
+			mcp.Description("Break task counts down by swimlane and column (default: false)"),
+		),
+		mcp.WithBoolean("include_due_counts",
+			mcp.Description("Include each project's overdue_count and due_this_week_count (default: false)"),
+		),
+		mcp.WithBoolean("force_refresh",
+			mcp.Description("Ignore cached results and reload everything from Kanboard (default: false)"),
+		),
+		mcp.WithBoolean("compact",
+			mcp.Description("Return only project IDs, names, column names and per-column task counts (default: false)"),
+		),
+		mcp.WithString("sort_by",
+			mcp.Description("Order projects by 'name', 'last_activity' (most recent first) or 'overdue_count' (most overdue first) (default: name)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of projects per page (default: all, max: 100)"),
+		),
+		mcp.WithString("cursor",
+			mcp.Description("Optional: next_cursor from a previous response to fetch the following page of projects. Other parameters must match the original call"),
+		),
 	)
-	s.server.AddTool(overviewTool, s.handleOverview)
+	s.server.AddTool(overviewTool, s.recorded(overviewTool.Name, s.handleOverview))
 
 	tasksTool := mcp.NewTool("kanboard_tasks",
 		mcp.WithDescription("Get detailed task information for priority analysis and workload management"),
@@ -122,10 +345,10 @@ func (s *KanboardMCPServer) addTools() {
 			mcp.Description("Filter tasks by status: 'active', 'completed', or 'all' (default: active)"),
 		),
 		mcp.WithString("due_date_start",
-			mcp.Description("Optional: filter by due date start (YYYY-MM-DD format)"),
+			mcp.Description("Optional: filter by due date start, as YYYY-MM-DD or a phrase such as 'today', 'friday', 'in 2 weeks' or 'next week' (a range phrase starts on its first day)"),
 		),
 		mcp.WithString("due_date_end",
-			mcp.Description("Optional: filter by due date end (YYYY-MM-DD format)"),
+			mcp.Description("Optional: filter by due date end, as YYYY-MM-DD or a phrase such as 'tomorrow', 'end of month' or 'next week' (a range phrase ends on its last day)"),
 		),
 		mcp.WithBoolean("include_overdue",
 			mcp.Description("Include overdue tasks (default: false)"),
@@ -133,17 +356,53 @@ func (s *KanboardMCPServer) addTools() {
 		mcp.WithBoolean("include_time_tracking",
 			mcp.Description("Include time tracking information (default: true)"),
 		),
+		mcp.WithBoolean("include_comments",
+			mcp.Description("Include each task's comment count and a preview of its latest comment (default: false)"),
+		),
+		mcp.WithBoolean("include_subtasks",
+			mcp.Description("Include a subtask progress rollup (total, done, in_progress) per task (default: true)"),
+		),
+		mcp.WithString("column",
+			mcp.Description("Optional: only tasks in this column (name or ID)"),
+		),
+		mcp.WithString("swimlane",
+			mcp.Description("Optional: only tasks in this swimlane (name or ID)"),
+		),
+		mcp.WithString("category",
+			mcp.Description("Optional: only tasks in this category (name or ID)"),
+		),
+		mcp.WithString("color",
+			mcp.Description("Optional: only tasks with this colour ID, e.g. 'red' or 'green'"),
+		),
+		mcp.WithString("tag",
+			mcp.Description("Optional: only tasks carrying this tag (name or ID)"),
+		),
+		mcp.WithString("query",
+			mcp.Description("Optional: case-insensitive text to look for in task titles and descriptions"),
+		),
+		mcp.WithBoolean("query_regex",
+			mcp.Description("Treat query as a regular expression (default: false)"),
+		),
 		mcp.WithString("sort_by",
-			mcp.Description("Sort tasks by: 'due_date', 'priority', or 'created' (default: due_date)"),
+			mcp.Description("Comma-separated sort keys applied in order: 'due_date' (soonest first), 'priority' (highest first), 'created' and 'modified' (newest first), 'title'. Prefix a key with '-' to reverse it, e.g. 'priority,-created' (default: due_date)"),
 		),
 		mcp.WithNumber("limit",
-			mcp.Description("Maximum number of tasks to return (default: 20, max: 100, or 200 in summary mode)"),
+			mcp.Description("Maximum number of tasks per page (default: 20, max: 100, or 200 in summary mode)"),
+		),
+		mcp.WithString("cursor",
+			mcp.Description("Optional: next_cursor from a previous response to fetch the following page. Other filters must match the original call"),
 		),
 		mcp.WithBoolean("summary_mode",
 			mcp.Description("Return lightweight task summaries instead of full details (default: true)"),
 		),
+		mcp.WithString("export_format",
+			mcp.Description("Optional: 'csv' or 'xlsx' to return every matching task (up to 5000, ignoring limit and cursor) as a spreadsheet file attachment instead of JSON"),
+		),
+		mcp.WithString("view",
+			mcp.Description("Optional: name of a view saved with kanboard_save_view whose filters to use. Other parameters given alongside it override the view's"),
+		),
 	)
-	s.server.AddTool(tasksTool, s.handleTasks)
+	s.server.AddTool(tasksTool, s.recorded(tasksTool.Name, s.handleTasks))
 
 	prioritiesTool := mcp.NewTool("kanboard_priorities",
 		mcp.WithDescription("Analyse workload and provide priority recommendations"),
@@ -155,13 +414,25 @@ func (s *KanboardMCPServer) addTools() {
 			mcp.Description("Optional: comma-separated list of project IDs to filter by"),
 		),
 		mcp.WithString("time_horizon",
-			mcp.Description("Time horizon for analysis: 'today', 'week', or 'month' (default: week)"),
+			mcp.Description("Time horizon for analysis: 'today', 'week', 'month', or a date phrase such as 'friday', 'end of month' or 'in 10 days' (default: week)"),
 		),
 		mcp.WithBoolean("include_recommendations",
 			mcp.Description("Include priority recommendations (default: true)"),
 		),
+		mcp.WithBoolean("include_undated",
+			mcp.Description("Include tasks without a due date in workload and bottleneck analysis; dated tasks count only when due within time_horizon (default: true)"),
+		),
+		mcp.WithString("output",
+			mcp.Description("Output mode: 'analysis' for workload analysis or 'matrix' for an Eisenhower urgent/important matrix (default: analysis)"),
+		),
+		mcp.WithNumber("matrix_limit",
+			mcp.Description("Maximum tasks listed per matrix quadrant (default: 5)"),
+		),
+		mcp.WithString("important_categories",
+			mcp.Description("Optional: comma-separated category names that mark a task as important in matrix output"),
+		),
 	)
-	s.server.AddTool(prioritiesTool, s.handlePriorities)
+	s.server.AddTool(prioritiesTool, s.recorded(prioritiesTool.Name, s.handlePriorities))
 
 	analyticsTool := mcp.NewTool("kanboard_analytics",
 		mcp.WithDescription("Perform historical data analysis and trend identification"),
@@ -175,14 +446,101 @@ func (s *KanboardMCPServer) addTools() {
 		mcp.WithString("time_range",
 			mcp.Description("Time range for analysis: '7_days', '30_days', '90_days', '6_months', '1_year' (default: 30_days)"),
 		),
+		mcp.WithString("scope",
+			mcp.Description("Optional: limit analysis to one slice of work, e.g. 'swimlane:Sprint 12' or 'tag:release-2.0' (a bare value is treated as a swimlane name)"),
+		),
+		mcp.WithString("bucket",
+			mcp.Description("Period bucket for trends and velocity: 'day', 'week' (ISO week), or 'month' (default: derived from time_range)"),
+		),
 		mcp.WithString("analysis_types",
-			mcp.Description("Comma-separated analysis types: 'completion_trends', 'cycle_time', 'velocity', 'task_aging', 'burndown', 'project_health' (default: all)"),
+			mcp.Description("Comma-separated analysis types: 'completion_trends', 'cycle_time', 'lead_time', 'velocity', 'task_aging', 'burndown', 'cumulative_flow', 'project_health', 'forecast', 'wip_limits' (default: all except burndown, cumulative_flow, project_health, forecast and wip_limits)"),
 		),
 		mcp.WithString("group_by",
 			mcp.Description("Group results by: 'project', 'user', 'time' (default: project)"),
 		),
+		mcp.WithString("export_format",
+			mcp.Description("Optional: 'csv' for one CSV file attachment per analysis, or 'xlsx' for a workbook with a sheet per analysis, instead of JSON"),
+		),
+		mcp.WithBoolean("render_charts",
+			mcp.Description("Also return PNG charts for the burndown, velocity and cumulative_flow analyses among analysis_types (default: false)"),
+		),
+	)
+	s.server.AddTool(analyticsTool, s.recorded(analyticsTool.Name, s.handleAnalytics))
+
+	focusTool := mcp.NewTool("kanboard_focus",
+		mcp.WithDescription("List the few tasks the user should work on today: overdue items, items blocking others, and items due today, each with a one-line reason"),
+		mcp.WithString("user_id",
+			mcp.Description("User ID for authentication"),
+			mcp.Required(),
+		),
+		mcp.WithString("project_ids",
+			mcp.Description("Optional: comma-separated list of project IDs to filter by"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of focus items to return (default: 5)"),
+		),
+	)
+	s.server.AddTool(focusTool, s.recorded(focusTool.Name, s.handleFocus))
+
+	standupTool := mcp.NewTool("kanboard_standup",
+		mcp.WithDescription("Write the user's standup report: tasks completed since the start of the previous working day, open tasks started, overdue or due today, and tasks blocked by other open tasks"),
+		mcp.WithString("user_id",
+			mcp.Description("User ID for authentication"),
+			mcp.Required(),
+		),
+		mcp.WithString("project_ids",
+			mcp.Description("Optional: comma-separated list of project IDs to filter by"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Output format: 'text' for the report rendered from the deployment's standup template, or 'json' for the underlying data (default: text)"),
+		),
+	)
+	s.server.AddTool(standupTool, s.recorded(standupTool.Name, s.handleStandup))
+
+	saveViewTool := mcp.NewTool("kanboard_save_view",
+		mcp.WithDescription("Save a named set of kanboard_tasks filters, such as 'my sprint board', so it can be re-run later with kanboard_tasks' view parameter. Saving under an existing name replaces that view. Call with only user_id to list saved views"),
+		mcp.WithString("user_id",
+			mcp.Description("User ID for authentication"),
+			mcp.Required(),
+		),
+		mcp.WithString("name",
+			mcp.Description("Name of the view to save or delete; matched case-insensitively"),
+		),
+		mcp.WithString("project_ids",
+			mcp.Description("Optional: comma-separated list of project IDs to filter by"),
+		),
+		mcp.WithString("assignee_ids",
+			mcp.Description("Optional: comma-separated list of assignee user IDs to filter by"),
+		),
+		mcp.WithString("status_filter",
+			mcp.Description("Optional: 'active', 'completed', or 'all'"),
+		),
+		mcp.WithString("sort_by",
+			mcp.Description("Optional: sort keys as for kanboard_tasks, e.g. 'priority,-created'"),
+		),
+		mcp.WithString("due_date_start",
+			mcp.Description("Optional: due date start as YYYY-MM-DD or a phrase such as 'this week', resolved each time the view is run"),
+		),
+		mcp.WithString("due_date_end",
+			mcp.Description("Optional: due date end as YYYY-MM-DD or a phrase such as 'end of month', resolved each time the view is run"),
+		),
+		mcp.WithBoolean("delete",
+			mcp.Description("Delete the named view instead of saving it (default: false)"),
+		),
 	)
-	s.server.AddTool(analyticsTool, s.handleAnalytics)
+	s.server.AddTool(saveViewTool, s.recorded(saveViewTool.Name, s.handleSaveView))
+
+	statusTool := mcp.NewTool("kanboard_server_status",
+		mcp.WithDescription("Explain why Kanboard tools may be failing: reachability, latency and circuit-breaker state of each Kanboard instance, cache freshness, and recent tool-call error counts including the caller's own recent errors"),
+		mcp.WithString("user_id",
+			mcp.Description("User ID for authentication"),
+			mcp.Required(),
+		),
+		mcp.WithString("window",
+			mcp.Description("Optional: how far back to count tool calls, as a duration such as 30m or 24h (default: 1h, max: 168h)"),
+		),
+	)
+	s.server.AddTool(statusTool, s.recorded(statusTool.Name, s.handleServerStatus))
 }
 
 func (s *KanboardMCPServer) handleOverview(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -191,7 +549,7 @@ func (s *KanboardMCPServer) handleOverview(ctx context.Context, request mcp.Call
 
 	userID, ok := args["user_id"].(string)
 	if !ok || userID == "" {
-		return mcp.NewToolResultError("Missing required parameter: user_id. Please ask the user for their User ID and include it in the tool call. Users can find their User ID by running: ./kan-mcp cli list"), nil
+		return mcp.NewToolResultError("Missing required parameter: user_id. Please ask the user for their User ID and include it in the tool call. Users can find their User ID by running: ./kan-mcp user list"), nil
 	}
 
 	params := make(map[string]interface{})
@@ -204,11 +562,43 @@ func (s *KanboardMCPServer) handleOverview(ctx context.Context, request mcp.Call
 		params["include_inactive_projects"] = val
 	}
 
+	if val, ok := args["compact"]; ok {
+		params["compact"] = val
+	}
+
+	if val, ok := args["include_swimlane_counts"]; ok {
+		params["include_swimlane_counts"] = val
+	}
+
+	if val, ok := args["project_ids"]; ok {
+		if str, ok := val.(string); ok && str != "" {
+			params["project_ids"] = strings.Split(str, ",")
+		}
+	}
+
+	if val, ok := args["project_filter"]; ok {
+		params["project_filter"] = val
+	}
+
+	if val, ok := args["force_refresh"]; ok {
+		params["force_refresh"] = val
+	}
+
+	if val, ok := args["include_due_counts"]; ok {
+		params["include_due_counts"] = val
+	}
+
+	for _, key := range []string{"sort_by", "limit", "cursor"} {
+		if val, ok := args[key]; ok {
+			params[key] = val
+		}
+	}
+
 	overviewHandler := handlers.NewOverviewHandler(s.authManager, s.userConfig)
 
-	response, err := overviewHandler.Handle(params, userID)
+	response, err := overviewHandler.Handle(ctx, params, userID)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("overview failed: %v", err)), nil
+		return toolError("overview", err), nil
 	}
 
 	if len(response.Content) > 0 {
@@ -224,7 +614,7 @@ func (s *KanboardMCPServer) handleTasks(ctx context.Context, request mcp.CallToo
 
 	userID, ok := args["user_id"].(string)
 	if !ok || userID == "" {
-		return mcp.NewToolResultError("Missing required parameter: user_id. Please ask the user for their User ID and include it in the tool call. Users can find their User ID by running: ./kan-mcp cli list"), nil
+		return mcp.NewToolResultError("Missing required parameter: user_id. Please ask the user for their User ID and include it in the tool call. Users can find their User ID by running: ./kan-mcp user list"), nil
 	}
 
 	params := make(map[string]interface{})
@@ -270,6 +660,12 @@ func (s *KanboardMCPServer) handleTasks(ctx context.Context, request mcp.CallToo
 		params["include_time_tracking"] = val
 	}
 
+	for _, key := range []string{"column", "swimlane", "category", "color", "tag", "query", "query_regex", "include_subtasks", "include_comments"} {
+		if val, ok := args[key]; ok {
+			params[key] = val
+		}
+	}
+
 	if val, ok := args["sort_by"]; ok {
 		params["sort_by"] = val
 	}
@@ -282,18 +678,26 @@ func (s *KanboardMCPServer) handleTasks(ctx context.Context, request mcp.CallToo
 		params["summary_mode"] = val
 	}
 
-	tasksHandler := handlers.NewTasksHandler(s.authManager, s.userConfig)
+	if val, ok := args["cursor"]; ok {
+		params["cursor"] = val
+	}
 
-	response, err := tasksHandler.Handle(params, userID)
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("tasks failed: %v", err)), nil
+	if val, ok := args["export_format"]; ok {
+		params["export_format"] = val
 	}
 
-	if len(response.Content) > 0 {
-		return mcp.NewToolResultText(response.Content[0].Text), nil
+	if val, ok := args["view"]; ok {
+		params["view"] = val
 	}
 
-	return mcp.NewToolResultText("{}"), nil
+	tasksHandler := handlers.NewTasksHandler(s.authManager, s.userConfig)
+
+	response, err := tasksHandler.Handle(ctx, params, userID)
+	if err != nil {
+		return toolError("tasks", err), nil
+	}
+
+	return toolResult(response), nil
 }
 
 func (s *KanboardMCPServer) handlePriorities(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -302,7 +706,7 @@ func (s *KanboardMCPServer) handlePriorities(ctx context.Context, request mcp.Ca
 
 	userID, ok := args["user_id"].(string)
 	if !ok || userID == "" {
-		return mcp.NewToolResultError("Missing required parameter: user_id. Please ask the user for their User ID and include it in the tool call. Users can find their User ID by running: ./kan-mcp cli list"), nil
+		return mcp.NewToolResultError("Missing required parameter: user_id. Please ask the user for their User ID and include it in the tool call. Users can find their User ID by running: ./kan-mcp user list"), nil
 	}
 
 	params := make(map[string]interface{})
@@ -321,11 +725,29 @@ func (s *KanboardMCPServer) handlePriorities(ctx context.Context, request mcp.Ca
 		params["include_recommendations"] = val
 	}
 
+	if val, ok := args["include_undated"]; ok {
+		params["include_undated"] = val
+	}
+
+	if val, ok := args["output"]; ok {
+		params["output"] = val
+	}
+
+	if val, ok := args["matrix_limit"]; ok {
+		params["matrix_limit"] = val
+	}
+
+	if val, ok := args["important_categories"]; ok {
+		if str, ok := val.(string); ok && str != "" {
+			params["important_categories"] = strings.Split(str, ",")
+		}
+	}
+
 	prioritiesHandler := handlers.NewPrioritiesHandler(s.authManager, s.userConfig)
 
-	response, err := prioritiesHandler.Handle(params, userID)
+	response, err := prioritiesHandler.Handle(ctx, params, userID)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("priorities failed: %v", err)), nil
+		return toolError("priorities", err), nil
 	}
 
 	if len(response.Content) > 0 {
@@ -341,7 +763,7 @@ func (s *KanboardMCPServer) handleAnalytics(ctx context.Context, request mcp.Cal
 
 	userID, ok := args["user_id"].(string)
 	if !ok || userID == "" {
-		return mcp.NewToolResultError("Missing required parameter: user_id. Please ask the user for their User ID and include it in the tool call. Users can find their User ID by running: ./kan-mcp cli list"), nil
+		return mcp.NewToolResultError("Missing required parameter: user_id. Please ask the user for their User ID and include it in the tool call. Users can find their User ID by running: ./kan-mcp user list"), nil
 	}
 
 	params := make(map[string]interface{})
@@ -356,6 +778,14 @@ func (s *KanboardMCPServer) handleAnalytics(ctx context.Context, request mcp.Cal
 		params["time_range"] = val
 	}
 
+	if val, ok := args["bucket"]; ok {
+		params["bucket"] = val
+	}
+
+	if val, ok := args["scope"]; ok {
+		params["scope"] = val
+	}
+
 	if val, ok := args["analysis_types"]; ok {
 		if str, ok := val.(string); ok && str != "" {
 			params["analysis_types"] = strings.Split(str, ",")
@@ -366,11 +796,145 @@ func (s *KanboardMCPServer) handleAnalytics(ctx context.Context, request mcp.Cal
 		params["group_by"] = val
 	}
 
+	if val, ok := args["export_format"]; ok {
+		params["export_format"] = val
+	}
+
+	if val, ok := args["render_charts"]; ok {
+		params["render_charts"] = val
+	}
+
 	analyticsHandler := handlers.NewAnalyticsHandler(s.authManager, s.userConfig)
 
-	response, err := analyticsHandler.Handle(params, userID)
+	response, err := analyticsHandler.Handle(ctx, params, userID)
+	if err != nil {
+		return toolError("analytics", err), nil
+	}
+
+	return toolResult(response), nil
+}
+
+// toolResult converts a handler response to a tool result, passing exported
+// files on as embedded resources and charts as images.
+func toolResult(response *models.MCPResponse) *mcp.CallToolResult {
+	if len(response.Content) == 0 {
+		return mcp.NewToolResultText("{}")
+	}
+
+	result := &mcp.CallToolResult{}
+	for _, content := range response.Content {
+		switch content.Type {
+		case "resource":
+			result.Content = append(result.Content, mcp.NewEmbeddedResource(mcp.BlobResourceContents{
+				URI:      "kanboard://exports/" + content.Name,
+				MIMEType: content.MimeType,
+				Blob:     content.Data,
+			}))
+		case "image":
+			result.Content = append(result.Content, mcp.NewImageContent(content.Data, content.MimeType))
+		default:
+			result.Content = append(result.Content, mcp.NewTextContent(content.Text))
+		}
+	}
+	return result
+}
+
+// recorded wraps a tool handler so every call is written to the audit store.
+func (s *KanboardMCPServer) recorded(tool string, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		requestID := logging.RequestID(ctx)
+		if requestID == "" {
+			requestID = logging.NewRequestID()
+			ctx = logging.WithRequestID(ctx, requestID)
+		}
+
+		args := request.GetArguments()
+		userID, _ := args["user_id"].(string)
+		if userID == "" {
+			userID, _ = userIDFromContext(ctx)
+		}
+
+		if addr, ok := clientAddrFromContext(ctx); ok && userID != "" {
+			if _, lookupErr := s.authManager.GetUser(userID); lookupErr != nil {
+				s.lockout.fail(addr, time.Now())
+			}
+		}
+
+		ctx, span := tracing.Start(ctx, "tool "+tool, attribute.String("mcp.tool", tool), attribute.String("request.id", requestID))
+		start := time.Now()
+
+		// The status tool is exempt from quotas so an agent can explain the
+		// refusal, but not from the rate limit since it probes every instance.
+		var result *mcp.CallToolResult
+		var err error
+		if signErr := s.checkSignedUser(ctx, userID); signErr != nil {
+			result = mcp.NewToolResultError(signErr.Error())
+		} else if limitErr := s.limiter.allow(userID, start); limitErr != nil {
+			result = mcp.NewToolResultError(limitErr.Error())
+		} else {
+			if quotaErr := s.quotas.check(userID, start); quotaErr != nil && tool != "kanboard_server_status" {
+				result = mcp.NewToolResultError(quotaErr.Error())
+			} else {
+				result, err = handler(ctx, request)
+				s.quotas.add(userID, responseBytes(result), start)
+			}
+		}
+
+		if err == nil && result != nil && result.IsError {
+			span.SetStatus(codes.Error, "tool returned an error result")
+		}
+		tracing.End(span, err)
+		duration := time.Since(start)
+
+		call := models.ToolCall{
+			Time:         start,
+			UserID:       userID,
+			Tool:         tool,
+			Params:       auditParams(args),
+			Error:        err != nil || (result != nil && result.IsError),
+			ErrorMessage: toolErrorMessage(result, err),
+			RequestID:    requestID,
+			Bytes:        responseBytes(result),
+			DurationMs:   duration.Milliseconds(),
+		}
+		if recordErr := s.auditStore.Record(call); recordErr != nil {
+			logging.WarnfContext(ctx, "Failed to record %s call: %v", tool, recordErr)
+		}
+
+		if call.Error {
+			logging.WarnfContext(ctx, "%s for user %s failed after %s: %s", tool, logging.UserID(userID), duration, call.ErrorMessage)
+			if err != nil {
+				err = fmt.Errorf("%w (request ID: %s)", err, requestID)
+			}
+			addRequestID(result, requestID)
+		} else {
+			logging.DebugfContext(ctx, "%s completed in %s", tool, duration)
+		}
+
+		return result, err
+	}
+}
+
+func (s *KanboardMCPServer) handleServerStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+
+	args := request.GetArguments()
+
+	userID, ok := args["user_id"].(string)
+	if !ok || userID == "" {
+		return mcp.NewToolResultError("Missing required parameter: user_id. Please ask the user for their User ID and include it in the tool call. Users can find their User ID by running: ./kan-mcp user list"), nil
+	}
+
+	params := make(map[string]interface{})
+
+	if val, ok := args["window"]; ok {
+		params["window"] = val
+	}
+
+	statusHandler := handlers.NewStatusHandler(s.authManager, s.userConfig, s.auditStore)
+
+	response, err := statusHandler.Handle(ctx, params, userID)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("analytics failed: %v", err)), nil
+		return toolError("server_status", err), nil
 	}
 
 	if len(response.Content) > 0 {
@@ -380,54 +944,209 @@ func (s *KanboardMCPServer) handleAnalytics(ctx context.Context, request mcp.Cal
 	return mcp.NewToolResultText("{}"), nil
 }
 
+// checkSignedUser stops a request signed by one user from acting as another
+// user who is required to sign their own requests.
+func (s *KanboardMCPServer) checkSignedUser(ctx context.Context, userID string) error {
+	signedUserID, ok := signedUserFromContext(ctx)
+	if !ok || userID == "" || signedUserID == userID {
+		return nil
+	}
+	if !signingRequired(s.authManager, s.requireSignatures, userID) {
+		return nil
+	}
+	return fmt.Errorf("request is not signed for user %s; sign it with that user's secret and send their ID in X-User-ID", userID)
+}
+
 func (s *KanboardMCPServer) extractUserIDFromRequest(ctx context.Context, r *http.Request) context.Context {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(r.Header))
+
+	if requestID := r.Header.Get("X-Request-ID"); validRequestID(requestID) {
+		ctx = logging.WithRequestID(ctx, requestID)
+	}
 
+	source := "header"
 	userID := r.Header.Get("X-User-ID")
 	if userID == "" {
+		source = "query"
 		userID = r.URL.Query().Get("user_id")
 	}
 
-	log.Printf("Extracted User ID: %s (from header: %s, from query: %s)",
-		userID, r.Header.Get("X-User-ID"), r.URL.Query().Get("user_id"))
-
 	if userID != "" {
+		logging.DebugfContext(ctx, "HTTP request for user %s (from %s)", logging.UserID(userID), source)
 		return withUserID(ctx, userID)
 	}
 
 	return ctx
 }
 
-func main() {
-	var (
-		transport   = flag.String("t", "stdio", "Transport type (stdio or http)")
-		cliCommand  = flag.String("cmd", "", "CLI command (register, list, delete, show)")
-		userID      = flag.String("user-id", "", "User ID for show/delete operations")
-		kanboardURL = flag.String("kanboard-url", "", "Kanboard URL (optional, uses default if not set)")
-		username    = flag.String("username", "", "Kanboard username")
-	)
-	flag.StringVar(transport, "transport", "stdio", "Transport type (stdio or http)")
-	flag.Parse()
+func (s *KanboardMCPServer) handleFocus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+
+	args := request.GetArguments()
 
-	if len(os.Args) > 1 && os.Args[1] == "cli" {
+	userID, ok := args["user_id"].(string)
+	if !ok || userID == "" {
+		return mcp.NewToolResultError("Missing required parameter: user_id. Please ask the user for their User ID and include it in the tool call. Users can find their User ID by running: ./kan-mcp user list"), nil
+	}
 
-		if len(os.Args) > 2 {
-			*cliCommand = os.Args[2]
+	params := make(map[string]interface{})
 
-			flag.CommandLine.Parse(os.Args[3:])
+	if val, ok := args["project_ids"]; ok {
+		if str, ok := val.(string); ok && str != "" {
+			params["project_ids"] = strings.Split(str, ",")
 		}
-		runCLI(*cliCommand, *userID, *kanboardURL, *username)
-		return
 	}
 
-	log.Println("Starting Kanboard MCP Server...")
+	if val, ok := args["limit"]; ok {
+		params["limit"] = val
+	}
+
+	focusHandler := handlers.NewFocusHandler(s.authManager, s.userConfig)
 
-	kanboardServer, err := NewKanboardMCPServer()
+	response, err := focusHandler.Handle(ctx, params, userID)
 	if err != nil {
-		log.Fatalf("Failed to create server: %v", err)
+		return toolError("focus", err), nil
 	}
 
-	switch *transport {
-	case "stdio":
+	if len(response.Content) > 0 {
+		return mcp.NewToolResultText(response.Content[0].Text), nil
+	}
+
+	return mcp.NewToolResultText("{}"), nil
+}
+
+func (s *KanboardMCPServer) handleStandup(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+
+	args := request.GetArguments()
+
+	userID, ok := args["user_id"].(string)
+	if !ok || userID == "" {
+		return mcp.NewToolResultError("Missing required parameter: user_id. Please ask the user for their User ID and include it in the tool call. Users can find their User ID by running: ./kan-mcp user list"), nil
+	}
+
+	params := make(map[string]interface{})
+
+	if val, ok := args["project_ids"]; ok {
+		if str, ok := val.(string); ok && str != "" {
+			params["project_ids"] = strings.Split(str, ",")
+		}
+	}
+
+	if val, ok := args["format"]; ok {
+		if str, ok := val.(string); ok && str != "" {
+			params["format"] = str
+		}
+	}
+
+	standupHandler := handlers.NewStandupHandler(s.authManager, s.userConfig)
+
+	response, err := standupHandler.Handle(ctx, params, userID)
+	if err != nil {
+		return toolError("standup", err), nil
+	}
+
+	if len(response.Content) > 0 {
+		return mcp.NewToolResultText(response.Content[0].Text), nil
+	}
+
+	return mcp.NewToolResultText(""), nil
+}
+
+func (s *KanboardMCPServer) handleSaveView(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+
+	args := request.GetArguments()
+
+	userID, ok := args["user_id"].(string)
+	if !ok || userID == "" {
+		return mcp.NewToolResultError("Missing required parameter: user_id. Please ask the user for their User ID and include it in the tool call. Users can find their User ID by running: ./kan-mcp user list"), nil
+	}
+
+	params := make(map[string]interface{})
+
+	for _, key := range []string{"project_ids", "assignee_ids"} {
+		if val, ok := args[key]; ok {
+			if str, ok := val.(string); ok && str != "" {
+				params[key] = strings.Split(str, ",")
+			}
+		}
+	}
+
+	for _, key := range []string{"name", "status_filter", "sort_by", "due_date_start", "due_date_end", "delete"} {
+		if val, ok := args[key]; ok {
+			params[key] = val
+		}
+	}
+
+	viewsHandler := handlers.NewViewsHandler(s.authManager, s.userConfig)
+
+	response, err := viewsHandler.Handle(ctx, params, userID)
+	if err != nil {
+		return toolError("save_view", err), nil
+	}
+
+	return toolResult(response), nil
+}
+
+func main() {
+	runCommand(os.Args[1:])
+}
+
+func serve(cfg *config.Config) {
+	if level, err := logging.ParseLevel(cfg.Log.Level); err == nil {
+		logging.SetLevel(level)
+	}
+	if mode, err := logging.ParseUserIDMode(cfg.Log.UserIDs); err == nil {
+		logging.SetUserIDMode(mode)
+	}
+
+	log.Println("Starting Kanboard MCP Server...")
+
+	shutdownTracing, err := tracing.Setup(context.Background(), serverVersion)
+	if err != nil {
+		log.Fatalf("Failed to set up tracing: %v", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			log.Printf("Failed to flush traces: %v", err)
+		}
+	}()
+
+	kanboardServer, err := NewKanboardMCPServer(cfg)
+	if err != nil {
+		log.Fatalf("Failed to create server: %v", err)
+	}
+	if cfg.Server.ReadOnly {
+		log.Println("Read-only mode: tools that change Kanboard data are disabled")
+	}
+
+	go pruneAuditLog(kanboardServer.auditStore, cfg.Audit.Retention)
+	go logSlowCallSummaries(cfg.Kanboard.SlowCalls.SummaryInterval)
+	if kanboardServer.taskSyncer != nil {
+		go kanboardServer.taskSyncer.Run(context.Background())
+	}
+	if kanboardServer.digester != nil {
+		go kanboardServer.digester.Run(context.Background())
+	}
+	if kanboardServer.notifier != nil {
+		go kanboardServer.notifier.Run(context.Background())
+	}
+	if cfg.Kanboard.Cache.WarmInterval > 0 {
+		warmer := handlers.NewCacheWarmer(kanboardServer.authManager, kanboardServer.userConfig, kanboardServer.auditStore, cfg.Kanboard.Cache.WarmInterval, cfg.Kanboard.Cache.WarmActiveWithin)
+		go warmer.Run(context.Background())
+	}
+	if cfg.Security.EncryptionKeySource != "" && cfg.Security.EncryptionKeyRefresh > 0 {
+		go kanboardServer.watchEncryptionKey(cfg)
+	}
+	if cfg.Server.DebugPort != "" {
+		go kanboardServer.serveDebug(cfg.Server.DebugPort)
+	}
+
+	switch cfg.Server.Transport {
+	case "stdio":
+		if cfg.Webhook.Secret != "" {
+			log.Println("Kanboard webhooks are only accepted with the HTTP transport")
+		}
 		if err := server.ServeStdio(kanboardServer.server); err != nil {
 			log.Fatalf("Server error: %v", err)
 		}
@@ -435,75 +1154,173 @@ func main() {
 		httpServer := server.NewStreamableHTTPServer(kanboardServer.server,
 			server.WithHTTPContextFunc(kanboardServer.extractUserIDFromRequest),
 		)
-		log.Printf("HTTP server listening on :8080")
-		if err := httpServer.Start(":8080"); err != nil {
+		mux := http.NewServeMux()
+		mux.Handle("/mcp", newSignatureVerifier(kanboardServer.authManager, cfg.Server.RequireSignatures, cfg.Server.SignatureMaxAge, httpServer))
+		mux.Handle("/ical/", &icalFeed{
+			authManager: kanboardServer.authManager,
+			feed:        handlers.NewCalendarFeed(kanboardServer.authManager, kanboardServer.userConfig),
+			limiter:     kanboardServer.limiter,
+			lockout:     kanboardServer.lockout,
+		})
+
+		if cfg.Webhook.Secret != "" {
+			events, err := storage.NewEventStore(cfg.Storage.DataDir)
+			if err != nil {
+				log.Fatalf("Failed to initialize webhook event store: %v", err)
+			}
+			instance := cfg.Webhook.Instance
+			if instance == "" {
+				instance = cfg.Kanboard.DefaultURL
+			}
+			mux.Handle("/webhooks/kanboard", &webhookReceiver{secret: cfg.Webhook.Secret, instance: instance, events: events, lockout: kanboardServer.lockout})
+			go pruneWebhookEvents(events, cfg.Webhook.Retention)
+			log.Printf("Accepting Kanboard webhooks from %s on /webhooks/kanboard", instance)
+		}
+
+		var handler http.Handler = mux
+		if cfg.Server.CompressMinSize > 0 {
+			handler = &compressor{minSize: cfg.Server.CompressMinSize, next: handler}
+		}
+		handler = kanboardServer.lockout.guard(cfg.Server.TrustForwardedFor, handler)
+		allowed, denied, err := cfg.GetIPFilter()
+		if err != nil {
+			log.Fatalf("Failed to parse IP filter: %v", err)
+		}
+		if len(allowed) > 0 || len(denied) > 0 {
+			handler = &ipFilter{allowed: allowed, denied: denied, trustForwardedFor: cfg.Server.TrustForwardedFor, next: handler}
+			log.Printf("HTTP access restricted to %d allowed and %d denied ranges", len(allowed), len(denied))
+		}
+
+		addr := net.JoinHostPort(cfg.Server.Host, cfg.Server.Port)
+		log.Printf("HTTP server listening on %s", addr)
+		if err := http.ListenAndServe(addr, handler); err != nil {
 			log.Fatalf("Server error: %v", err)
 		}
 	default:
-		log.Fatalf("Invalid transport type: %s. Must be 'stdio' or 'http'", *transport)
+		log.Fatalf("Invalid transport type: %s. Must be 'stdio' or 'http'", cfg.Server.Transport)
 	}
 }
 
-func runCLI(command, userID, kanboardURL, username string) {
+// logSlowCallSummaries logs the slowest Kanboard methods seen in each
+// interval, so operators can tell a slow Kanboard from a slow kan-mcp.
+func logSlowCallSummaries(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
 
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
-		os.Exit(1)
+	for range time.Tick(interval) {
+		stats := api.TakeSlowCallStats()
+		if len(stats) == 0 {
+			continue
+		}
+
+		var parts []string
+		for _, entry := range stats[:min(len(stats), 5)] {
+			part := fmt.Sprintf("%s %dx avg %s max %s", entry.Method, entry.Count, (entry.Total / time.Duration(entry.Count)).Round(time.Millisecond), entry.Max.Round(time.Millisecond))
+			if entry.MaxProjectID > 0 {
+				part += fmt.Sprintf(" (project %d)", entry.MaxProjectID)
+			}
+			parts = append(parts, part)
+		}
+		logging.Infof("Slowest Kanboard methods in the last %s: %s", interval, strings.Join(parts, "; "))
 	}
+}
 
-	encryptionKey, err := cfg.GetEncryptionKey()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to get encryption key: %v\n", err)
+// watchEncryptionKey re-reads the key source and, when the key has changed,
+// re-encrypts the stored credentials with the new one.
+func (s *KanboardMCPServer) watchEncryptionKey(cfg *config.Config) {
+	current := s.userConfig.EncryptionKey
+	for range time.Tick(cfg.Security.EncryptionKeyRefresh) {
+		key, err := cfg.GetEncryptionKey()
+		if err != nil {
+			logging.Warnf("Failed to refresh encryption key: %v", err)
+			continue
+		}
+		if bytes.Equal(key, current) {
+			continue
+		}
+
+		// current only advances once every registration is re-encrypted, so
+		// the next tick retries the ones that failed.
+		rotated, err := s.authManager.RotateKey(key)
+		if err != nil {
+			logging.Errorf("Encryption key changed but credentials could not all be re-encrypted (%d user(s) done); retrying in %s: %v", rotated, cfg.Security.EncryptionKeyRefresh, err)
+			continue
+		}
+		logging.Infof("Encryption key changed; re-encrypted credentials of %d user(s)", rotated)
+		current = key
+	}
+}
+
+func generateKey(envName, envFile string) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to generate key: %v\n", err)
 		os.Exit(1)
 	}
+	keyHex := hex.EncodeToString(key)
 
-	fileStore, err := storage.NewFileStore(cfg.Storage.DataDir)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to initialize storage: %v\n", err)
+	if envFile == "" {
+		fmt.Println(keyHex)
+		return
+	}
+
+	existing, err := os.ReadFile(envFile)
+	if err != nil && !os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "Failed to read %s: %v\n", envFile, err)
 		os.Exit(1)
 	}
+	for _, line := range strings.Split(string(existing), "\n") {
+		line = strings.TrimPrefix(strings.TrimSpace(line), "export ")
+		if strings.HasPrefix(line, envName+"=") {
+			fmt.Fprintf(os.Stderr, "%s already sets %s; refusing to replace a key that may protect existing registrations\n", envFile, envName)
+			os.Exit(1)
+		}
+	}
 
-	authManager, err := auth.NewAuthManager(encryptionKey, fileStore)
+	file, err := os.OpenFile(envFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to initialize auth manager: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Failed to open %s: %v\n", envFile, err)
 		os.Exit(1)
 	}
+	defer file.Close()
 
-	switch command {
-	case "register":
-		if username == "" {
-			fmt.Fprintf(os.Stderr, "Username is required for registration\n")
-			fmt.Fprintf(os.Stderr, "Usage: %s cli register -username <username> [-kanboard-url <url>]\n", os.Args[0])
-			os.Exit(1)
-		}
-		registerUser(authManager, cfg, kanboardURL, username)
-	case "list":
-		listUsers(authManager)
-	case "delete":
-		if userID == "" {
-			fmt.Fprintf(os.Stderr, "User ID is required for delete operation\n")
-			fmt.Fprintf(os.Stderr, "Usage: %s cli delete -user-id <user-id>\n", os.Args[0])
-			os.Exit(1)
-		}
-		deleteUser(authManager, userID)
-	case "show":
-		if userID == "" {
-			fmt.Fprintf(os.Stderr, "User ID is required for show operation\n")
-			fmt.Fprintf(os.Stderr, "Usage: %s cli show -user-id <user-id>\n", os.Args[0])
-			os.Exit(1)
-		}
-		showUser(authManager, userID)
-	default:
-		fmt.Fprintf(os.Stderr, "Unknown command: %s\n", command)
-		fmt.Fprintf(os.Stderr, "Available commands: register, list, delete, show\n")
+	snippet := fmt.Sprintf("%s=%s\n", envName, keyHex)
+	if len(existing) > 0 && !strings.HasSuffix(string(existing), "\n") {
+		snippet = "\n" + snippet
+	}
+	if _, err := file.WriteString(snippet); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write %s: %v\n", envFile, err)
 		os.Exit(1)
 	}
+
+	fmt.Printf("✓ Wrote %s to %s\n", envName, envFile)
+	fmt.Printf("  Keep a copy somewhere safe: registered tokens cannot be decrypted without it\n")
 }
 
 func registerUser(authManager *auth.AuthManager, cfg *config.Config, kanboardURL, username string) {
 	fmt.Printf("Registering user: %s\n", username)
 
+	token := readToken()
+
+	if kanboardURL == "" {
+		kanboardURL = cfg.Kanboard.DefaultURL
+	}
+
+	user, err := authManager.RegisterUser(kanboardURL, username, token)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Registration failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✓ User registered successfully!\n")
+	fmt.Printf("  User ID: %s\n", user.UserID)
+	fmt.Printf("  Kanboard URL: %s\n", user.KanboardURL)
+	fmt.Printf("  Username: %s\n", user.KanboardUsername)
+	fmt.Printf("  Created: %s\n", user.CreatedAt.Format("2006-01-02 15:04:05"))
+}
+
+func readToken() string {
 	fmt.Print("Enter Kanboard Personal Access Token: ")
 	tokenBytes, err := term.ReadPassword(int(syscall.Stdin))
 	if err != nil {
@@ -518,11 +1335,21 @@ func registerUser(authManager *auth.AuthManager, cfg *config.Config, kanboardURL
 		os.Exit(1)
 	}
 
+	return token
+}
+
+func registerAppUser(authManager *auth.AuthManager, cfg *config.Config, kanboardURL, username string) {
+	fmt.Printf("Registering user: %s (application token auth)\n", username)
+
+	if cfg.Kanboard.AppToken == "" {
+		fmt.Fprintf(os.Stderr, "Warning: KANBOARD_APP_TOKEN is not set; requests for this user will fail until it is configured\n")
+	}
+
 	if kanboardURL == "" {
 		kanboardURL = cfg.Kanboard.DefaultURL
 	}
 
-	user, err := authManager.RegisterUser(kanboardURL, username, token)
+	user, err := authManager.RegisterAppUser(kanboardURL, username)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Registration failed: %v\n", err)
 		os.Exit(1)
@@ -532,9 +1359,150 @@ func registerUser(authManager *auth.AuthManager, cfg *config.Config, kanboardURL
 	fmt.Printf("  User ID: %s\n", user.UserID)
 	fmt.Printf("  Kanboard URL: %s\n", user.KanboardURL)
 	fmt.Printf("  Username: %s\n", user.KanboardUsername)
+	fmt.Printf("  Auth Mode: application token\n")
 	fmt.Printf("  Created: %s\n", user.CreatedAt.Format("2006-01-02 15:04:05"))
 }
 
+func updateUser(authManager *auth.AuthManager, userID, kanboardURL, username string, promptToken bool) {
+	fmt.Printf("Updating user: %s\n", userID)
+
+	var token string
+	if promptToken {
+		token = readToken()
+	}
+
+	user, err := authManager.UpdateUser(userID, kanboardURL, username, token)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Update failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✓ User updated successfully!\n")
+	fmt.Printf("  User ID: %s\n", user.UserID)
+	fmt.Printf("  Kanboard URL: %s\n", user.KanboardURL)
+	fmt.Printf("  Username: %s\n", user.KanboardUsername)
+	if promptToken {
+		fmt.Printf("  Token: [UPDATED]\n")
+	}
+}
+
+// renewUser checks the user's credentials against Kanboard, or a new token
+// that is stored only once Kanboard accepts it, and only then extends the
+// registration.
+func renewUser(authManager *auth.AuthManager, cfg *config.Config, encryptionKey []byte, userID string, promptToken bool) {
+	userConfig, err := newUserConfig(cfg, encryptionKey)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Renewing user: %s\n", userID)
+
+	var token string
+	var report *handlers.ConnectionReport
+	if promptToken {
+		token = readToken()
+		report, err = handlers.CheckToken(context.Background(), authManager, userConfig, userID, token)
+	} else {
+		report, err = handlers.CheckConnection(context.Background(), authManager, userConfig, userID)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Renewal failed, Kanboard did not accept the credentials: %v\n", err)
+		if !promptToken {
+			fmt.Fprintf(os.Stderr, "Store a new token and renew with: %s user renew -user-id %s -token\n", os.Args[0], userID)
+		}
+		os.Exit(1)
+	}
+
+	if promptToken {
+		if _, err := authManager.UpdateUser(userID, "", "", token); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to store new token: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	user, err := authManager.RenewUser(userID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Renewal failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✓ Credentials for %s (Kanboard user %s) renewed\n", user.KanboardUsername, report.KanboardUser.Username)
+	fmt.Printf("  Expires: %s\n", user.ExpiresAt.Format("2006-01-02 15:04:05"))
+}
+
+func testUser(authManager *auth.AuthManager, cfg *config.Config, encryptionKey []byte, userID string) {
+	userConfig, err := newUserConfig(cfg, encryptionKey)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	report, err := handlers.CheckConnection(context.Background(), authManager, userConfig, userID)
+	if report != nil {
+		fmt.Printf("Testing user: %s\n", report.UserID)
+		fmt.Printf("  Kanboard URL: %s\n", report.KanboardURL)
+		fmt.Printf("  Username: %s\n", report.Username)
+		fmt.Printf("  Auth Mode: %s\n", report.AuthMode)
+		if report.GetMeLatency > 0 {
+			fmt.Printf("  getMe: %s\n", report.GetMeLatency.Round(time.Millisecond))
+		}
+		if me := report.KanboardUser; me != nil {
+			fmt.Printf("  Kanboard User: %s (ID %d, %s, role %s)\n", me.Username, me.ID, me.Name, me.Role)
+		}
+		if report.ProjectsLatency > 0 {
+			fmt.Printf("  getMyProjects: %s\n", report.ProjectsLatency.Round(time.Millisecond))
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "✗ Test failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("  Projects: %d\n", report.ProjectCount)
+	fmt.Printf("✓ Connection OK\n")
+}
+
+func exportUsers(authManager *auth.AuthManager, file string, bundleKey []byte) {
+	bundle, count, err := authManager.ExportUsers(bundleKey)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Export failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(file, []byte(bundle), 0600); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write bundle: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✓ Exported %d user(s) to %s\n", count, file)
+}
+
+func importUsers(authManager *auth.AuthManager, file string, bundleKey []byte, mode string) {
+	bundle, err := os.ReadFile(file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read bundle: %v\n", err)
+		os.Exit(1)
+	}
+
+	result, err := authManager.ImportUsers(string(bundle), bundleKey, mode)
+	if err != nil {
+		if result != nil && len(result.Imported)+len(result.Overwritten) > 0 {
+			fmt.Fprintf(os.Stderr, "Imported %d and overwrote %d user(s) before stopping\n", len(result.Imported), len(result.Overwritten))
+		}
+		fmt.Fprintf(os.Stderr, "Import failed: %v\n", err)
+		if errors.Is(err, auth.ErrUserIDCollision) {
+			fmt.Fprintf(os.Stderr, "Use -merge to keep existing users or -overwrite to replace them\n")
+		}
+		os.Exit(1)
+	}
+
+	fmt.Printf("✓ Import complete\n")
+	fmt.Printf("  Imported: %d\n", len(result.Imported))
+	fmt.Printf("  Overwritten: %d\n", len(result.Overwritten))
+	fmt.Printf("  Skipped (already registered): %d\n", len(result.Skipped))
+}
+
 func listUsers(authManager *auth.AuthManager) {
 	users, err := authManager.ListUsers()
 	if err != nil {
@@ -556,10 +1524,93 @@ func listUsers(authManager *auth.AuthManager) {
 		fmt.Printf("Username: %s\n", user.KanboardUsername)
 		fmt.Printf("Created: %s\n", user.CreatedAt.Format("2006-01-02 15:04:05"))
 		fmt.Printf("Last Used: %s\n", user.LastUsed.Format("2006-01-02 15:04:05"))
+		if expiry := authManager.CredentialExpiry(user); !expiry.IsZero() {
+			fmt.Printf("Expires: %s\n", formatExpiry(expiry))
+		}
 		fmt.Println(strings.Repeat("-", 80))
 	}
 }
 
+type userStats struct {
+	userID   string
+	username string
+	calls    int
+	errors   int
+	lastCall time.Time
+	tools    map[string]int
+}
+
+func showStats(authManager *auth.AuthManager, auditStore *storage.AuditStore, window time.Duration) {
+	since := time.Now().Add(-window)
+
+	calls, err := auditStore.Calls(since)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read usage: %v\n", err)
+		os.Exit(1)
+	}
+
+	users, err := authManager.ListUsers()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to list users: %v\n", err)
+		os.Exit(1)
+	}
+
+	stats := make(map[string]*userStats)
+	for _, user := range users {
+		stats[user.UserID] = &userStats{userID: user.UserID, username: user.KanboardUsername, tools: map[string]int{}}
+	}
+	for _, call := range calls {
+		entry, ok := stats[call.UserID]
+		if !ok {
+			entry = &userStats{userID: call.UserID, username: "(not registered)", tools: map[string]int{}}
+			if call.UserID == "" {
+				entry.userID = "-"
+			}
+			stats[call.UserID] = entry
+		}
+		entry.calls++
+		if call.Error {
+			entry.errors++
+		}
+		if call.Time.After(entry.lastCall) {
+			entry.lastCall = call.Time
+		}
+		entry.tools[strings.TrimPrefix(call.Tool, "kanboard_")]++
+	}
+
+	rows := make([]*userStats, 0, len(stats))
+	for _, entry := range stats {
+		rows = append(rows, entry)
+	}
+	slices.SortFunc(rows, func(a, b *userStats) int {
+		if a.calls != b.calls {
+			return b.calls - a.calls
+		}
+		return strings.Compare(a.username, b.username)
+	})
+
+	fmt.Printf("Tool calls since %s (%d total)\n\n", since.Format("2006-01-02 15:04:05"), len(calls))
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "USER ID\tUSERNAME\tCALLS\tERRORS\tERROR RATE\tLAST CALL\tTOOLS")
+	for _, entry := range rows {
+		errorRate, lastCall := "-", "never"
+		if entry.calls > 0 {
+			errorRate = fmt.Sprintf("%.1f%%", float64(entry.errors)*100/float64(entry.calls))
+			lastCall = entry.lastCall.Format("2006-01-02 15:04:05")
+		}
+
+		tools := make([]string, 0, len(entry.tools))
+		for tool, count := range entry.tools {
+			tools = append(tools, fmt.Sprintf("%s:%d", tool, count))
+		}
+		slices.Sort(tools)
+
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%s\t%s\t%s\n", entry.userID, entry.username, entry.calls, entry.errors, errorRate, lastCall, strings.Join(tools, " "))
+	}
+	tw.Flush()
+}
+
 func deleteUser(authManager *auth.AuthManager, userID string) {
 
 	fmt.Printf("Are you sure you want to delete user %s? (y/N): ", userID)
@@ -584,8 +1635,94 @@ func deleteUser(authManager *auth.AuthManager, userID string) {
 	fmt.Printf("✓ User %s deleted successfully\n", userID)
 }
 
+func signingSecret(authManager *auth.AuthManager, userID string, remove bool) {
+	if remove {
+		if err := authManager.RemoveSigningSecret(userID); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to remove signing secret: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Signing secret removed; unsigned requests for %s are accepted again\n", userID)
+		return
+	}
+
+	secret, err := authManager.CreateSigningSecret(userID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create signing secret: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✓ Signing secret created for %s\n", userID)
+	fmt.Printf("  Secret: %s\n", secret)
+	fmt.Printf("\nStore it with the client now; it cannot be shown again. HTTP requests for this\n")
+	fmt.Printf("user must now send X-User-ID, X-Signature-Timestamp (Unix seconds),\n")
+	fmt.Printf("X-Signature-Nonce and X-Signature: hex HMAC-SHA256 of\n")
+	fmt.Printf("\"<timestamp>\\n<nonce>\\n<body>\" keyed with the secret.\n")
+}
+
+func calendarToken(authManager *auth.AuthManager, userID string, remove bool) {
+	if remove {
+		if err := authManager.RemoveCalendarToken(userID); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to remove calendar token: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Calendar feed disabled for %s\n", userID)
+		return
+	}
+
+	token, err := authManager.CreateCalendarToken(userID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create calendar token: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✓ Calendar token created for %s\n", userID)
+	fmt.Printf("  Feed: /ical/%s.ics\n", token)
+	fmt.Printf("\nSubscribe to the feed at this server's HTTP address; the URL cannot be shown\n")
+	fmt.Printf("again and any previous one stops working. Add ?start_dates=true for events\n")
+	fmt.Printf("on task start dates as well as due dates.\n")
+}
+
+func notifications(authManager *auth.AuthManager, userID, webhookURL, email string, off bool) {
+	if off {
+		if err := authManager.RemoveNotifications(userID); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to disable notifications: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Notifications disabled for %s\n", userID)
+		return
+	}
+
+	if err := authManager.SetNotifications(userID, webhookURL, email); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to enable notifications: %v\n", err)
+		os.Exit(1)
+	}
+
+	user, err := authManager.GetUser(userID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "User not found: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✓ Notifications enabled for %s\n", userID)
+	if user.Notifications.WebhookURL != "" {
+		fmt.Printf("  Webhook: %s\n", user.Notifications.WebhookURL)
+	}
+	if user.Notifications.Email != "" {
+		fmt.Printf("  Email: %s\n", user.Notifications.Email)
+	}
+	fmt.Printf("\nAlerts are sent once a day when the server runs with NOTIFICATIONS=true.\n")
+}
+
+func formatExpiry(expiry time.Time) string {
+	remaining := time.Until(expiry)
+	if remaining <= 0 {
+		return expiry.Format("2006-01-02 15:04:05") + " (expired)"
+	}
+	return fmt.Sprintf("%s (in %d days)", expiry.Format("2006-01-02 15:04:05"), int(remaining.Hours()/24))
+}
+
 func showUser(authManager *auth.AuthManager, userID string) {
-	user, err := authManager.AuthenticateUser(userID)
+	user, err := authManager.GetUser(userID)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "User not found: %v\n", err)
 		os.Exit(1)
@@ -597,5 +1734,29 @@ func showUser(authManager *auth.AuthManager, userID string) {
 	fmt.Printf("  Username: %s\n", user.KanboardUsername)
 	fmt.Printf("  Created: %s\n", user.CreatedAt.Format("2006-01-02 15:04:05"))
 	fmt.Printf("  Last Used: %s\n", user.LastUsed.Format("2006-01-02 15:04:05"))
-	fmt.Printf("  Token: [ENCRYPTED]\n")
+	if expiry := authManager.CredentialExpiry(user); !expiry.IsZero() {
+		fmt.Printf("  Expires: %s\n", formatExpiry(expiry))
+	}
+	if user.AuthMode == models.AuthModeApp {
+		fmt.Printf("  Token: [SHARED APPLICATION TOKEN]\n")
+	} else {
+		fmt.Printf("  Token: [ENCRYPTED]\n")
+	}
+	if user.SigningSecret != "" {
+		fmt.Printf("  Request Signing: required\n")
+	}
+	if user.CalendarTokenHash != "" {
+		fmt.Printf("  Calendar Feed: enabled\n")
+	}
+	if user.Notifications != nil {
+		if user.Notifications.WebhookURL != "" {
+			fmt.Printf("  Notification Webhook: %s\n", user.Notifications.WebhookURL)
+		}
+		if user.Notifications.Email != "" {
+			fmt.Printf("  Notification Email: %s\n", user.Notifications.Email)
+		}
+		if !user.Notifications.LastSent.IsZero() {
+			fmt.Printf("  Last Notified: %s\n", user.Notifications.LastSent.Format("2006-01-02 15:04:05"))
+		}
+	}
 }
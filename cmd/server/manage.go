@@ -0,0 +1,408 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/auth"
+	"github.com/tech-arch1tect/kan-mcp/internal/config"
+	"github.com/tech-arch1tect/kan-mcp/internal/handlers"
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+	"golang.org/x/term"
+)
+
+// manageUI is the full-screen user manager behind `user manage`. It drives the
+// terminal directly in raw mode: one screen is redrawn after every key press.
+type manageUI struct {
+	authManager *auth.AuthManager
+	cfg         *config.Config
+	userConfig  *models.UserConfig
+	in          *bufio.Reader
+	out         io.Writer
+
+	users    []*models.User
+	search   string
+	selected int
+	offset   int
+	showing  bool
+	status   string
+}
+
+// Special keys are negative so they never collide with typed runes.
+const (
+	keyUp = -(iota + 1)
+	keyDown
+	keyEnter
+	keyEscape
+	keyBackspace
+)
+
+func manageUsers(authManager *auth.AuthManager, cfg *config.Config, encryptionKey []byte) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		fmt.Fprintf(os.Stderr, "user manage needs an interactive terminal\n")
+		os.Exit(1)
+	}
+
+	userConfig, err := newUserConfig(cfg, encryptionKey)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to switch terminal to raw mode: %v\n", err)
+		os.Exit(1)
+	}
+
+	ui := &manageUI{
+		authManager: authManager,
+		cfg:         cfg,
+		userConfig:  userConfig,
+		in:          bufio.NewReader(os.Stdin),
+		out:         os.Stdout,
+	}
+
+	fmt.Fprint(ui.out, "\x1b[?1049h\x1b[?25l")
+	defer func() {
+		fmt.Fprint(ui.out, "\x1b[?25h\x1b[?1049l")
+		term.Restore(fd, state)
+	}()
+
+	ui.reload()
+	ui.run()
+}
+
+func (ui *manageUI) run() {
+	for {
+		ui.render()
+
+		key, err := ui.readKey()
+		if err != nil {
+			return
+		}
+
+		if ui.showing {
+			ui.showing = false
+			continue
+		}
+
+		visible := ui.visibleUsers()
+		switch key {
+		case 'q', 3:
+			return
+		case keyUp, 'k':
+			ui.selected = max(ui.selected-1, 0)
+		case keyDown, 'j':
+			ui.selected = min(ui.selected+1, max(len(visible)-1, 0))
+		case keyEnter:
+			if len(visible) > 0 {
+				ui.showing = true
+			}
+		case '/':
+			if search, ok := ui.prompt("Search: ", ui.search, false); ok {
+				ui.search = search
+				ui.selected = 0
+			}
+		case keyEscape:
+			ui.search = ""
+			ui.selected = 0
+		case 'r':
+			ui.register()
+		case 't':
+			if len(visible) > 0 {
+				ui.rotate(visible[ui.selected])
+			}
+		case 'd':
+			if len(visible) > 0 {
+				ui.remove(visible[ui.selected])
+			}
+		}
+	}
+}
+
+func (ui *manageUI) reload() {
+	users, err := ui.authManager.ListUsers()
+	if err != nil {
+		ui.status = fmt.Sprintf("Failed to list users: %v", err)
+		return
+	}
+
+	slices.SortFunc(users, func(a, b *models.User) int {
+		if c := strings.Compare(strings.ToLower(a.KanboardUsername), strings.ToLower(b.KanboardUsername)); c != 0 {
+			return c
+		}
+		return strings.Compare(a.UserID, b.UserID)
+	})
+	ui.users = users
+	ui.selected = min(ui.selected, max(len(ui.visibleUsers())-1, 0))
+}
+
+func (ui *manageUI) visibleUsers() []*models.User {
+	if ui.search == "" {
+		return ui.users
+	}
+
+	needle := strings.ToLower(ui.search)
+	var visible []*models.User
+	for _, user := range ui.users {
+		haystack := strings.ToLower(user.UserID + " " + user.KanboardUsername + " " + user.KanboardURL)
+		if strings.Contains(haystack, needle) {
+			visible = append(visible, user)
+		}
+	}
+	return visible
+}
+
+func (ui *manageUI) register() {
+	username, ok := ui.prompt("Kanboard username: ", "", false)
+	if !ok || username == "" {
+		return
+	}
+
+	kanboardURL, ok := ui.prompt(fmt.Sprintf("Kanboard URL [%s]: ", ui.cfg.Kanboard.DefaultURL), "", false)
+	if !ok {
+		return
+	}
+	if kanboardURL == "" {
+		kanboardURL = ui.cfg.Kanboard.DefaultURL
+	}
+
+	mode, ok := ui.prompt("Auth mode (user/app) [user]: ", "", false)
+	if !ok {
+		return
+	}
+	if mode == "" {
+		mode = models.AuthModeUser
+	}
+
+	var user *models.User
+	var err error
+	switch mode {
+	case models.AuthModeUser:
+		token, ok := ui.prompt("Personal access token: ", "", true)
+		if !ok || token == "" {
+			ui.status = "Registration cancelled"
+			return
+		}
+		user, err = ui.authManager.RegisterUser(kanboardURL, username, token)
+	case models.AuthModeApp:
+		user, err = ui.authManager.RegisterAppUser(kanboardURL, username)
+	default:
+		ui.status = fmt.Sprintf("Invalid auth mode: %s", mode)
+		return
+	}
+	if err != nil {
+		ui.status = fmt.Sprintf("Registration failed: %v", err)
+		return
+	}
+
+	ui.status = fmt.Sprintf("Registered %s as %s", user.KanboardUsername, user.UserID)
+	ui.reload()
+}
+
+func (ui *manageUI) rotate(user *models.User) {
+	if user.AuthMode == models.AuthModeApp {
+		ui.status = fmt.Sprintf("%s uses the shared application token; there is nothing to rotate", user.KanboardUsername)
+		return
+	}
+
+	token, ok := ui.prompt(fmt.Sprintf("New token for %s: ", user.KanboardUsername), "", true)
+	if !ok || token == "" {
+		ui.status = "Rotation cancelled"
+		return
+	}
+
+	// The old token stays in place unless Kanboard accepts the new one.
+	ui.status = fmt.Sprintf("Checking new token for %s...", user.KanboardUsername)
+	ui.render()
+	if _, err := handlers.CheckToken(context.Background(), ui.authManager, ui.userConfig, user.UserID, token); err != nil {
+		ui.status = fmt.Sprintf("Rotation failed, Kanboard did not accept the token: %v", err)
+		return
+	}
+
+	if _, err := ui.authManager.UpdateUser(user.UserID, "", "", token); err != nil {
+		ui.status = fmt.Sprintf("Rotation failed: %v", err)
+		return
+	}
+
+	ui.status = fmt.Sprintf("Rotated token for %s", user.KanboardUsername)
+	ui.reload()
+}
+
+func (ui *manageUI) remove(user *models.User) {
+	answer, ok := ui.prompt(fmt.Sprintf("Delete %s (%s)? (y/N): ", user.KanboardUsername, user.UserID), "", false)
+	if !ok || (answer != "y" && answer != "yes") {
+		ui.status = "Deletion cancelled"
+		return
+	}
+
+	if err := ui.authManager.DeleteUser(user.UserID); err != nil {
+		ui.status = fmt.Sprintf("Failed to delete user: %v", err)
+		return
+	}
+
+	ui.status = fmt.Sprintf("Deleted %s", user.UserID)
+	ui.reload()
+}
+
+// prompt reads a line on the bottom row. Escape cancels; secret input is
+// echoed as asterisks.
+func (ui *manageUI) prompt(label, initial string, secret bool) (string, bool) {
+	value := []rune(initial)
+	for {
+		ui.render()
+
+		shown := string(value)
+		if secret {
+			shown = strings.Repeat("*", len(value))
+		}
+		_, height := ui.size()
+		fmt.Fprintf(ui.out, "\x1b[%d;1H\x1b[2K%s%s\x1b[?25h", height, label, shown)
+
+		key, err := ui.readKey()
+		fmt.Fprint(ui.out, "\x1b[?25l")
+		if err != nil {
+			return "", false
+		}
+
+		switch key {
+		case keyEnter:
+			return strings.TrimSpace(string(value)), true
+		case keyEscape, 3:
+			return "", false
+		case keyBackspace:
+			if len(value) > 0 {
+				value = value[:len(value)-1]
+			}
+		case keyUp, keyDown:
+		default:
+			if key >= 32 {
+				value = append(value, rune(key))
+			}
+		}
+	}
+}
+
+func (ui *manageUI) readKey() (int, error) {
+	r, _, err := ui.in.ReadRune()
+	if err != nil {
+		return 0, err
+	}
+
+	switch r {
+	case '\r', '\n':
+		return keyEnter, nil
+	case 127, 8:
+		return keyBackspace, nil
+	case 27:
+		if ui.in.Buffered() == 0 {
+			return keyEscape, nil
+		}
+		next, _, _ := ui.in.ReadRune()
+		if next != '[' && next != 'O' {
+			return keyEscape, nil
+		}
+		final, _, _ := ui.in.ReadRune()
+		switch final {
+		case 'A':
+			return keyUp, nil
+		case 'B':
+			return keyDown, nil
+		}
+		return keyEscape, nil
+	}
+
+	return int(r), nil
+}
+
+func (ui *manageUI) size() (int, int) {
+	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || height < 8 {
+		return 80, 24
+	}
+	return width, height
+}
+
+func (ui *manageUI) render() {
+	width, height := ui.size()
+	var b strings.Builder
+	line := func(format string, args ...interface{}) {
+		text := fmt.Sprintf(format, args...)
+		if runes := []rune(text); len(runes) > width {
+			text = string(runes[:width])
+		}
+		b.WriteString(text + "\x1b[0m\r\n")
+	}
+
+	b.WriteString("\x1b[H\x1b[2J")
+
+	visible := ui.visibleUsers()
+	title := fmt.Sprintf("kan-mcp users (%d)", len(ui.users))
+	if ui.search != "" {
+		title += fmt.Sprintf(" - search %q: %d match(es)", ui.search, len(visible))
+	}
+	line("\x1b[1m%s", title)
+	line("%s", strings.Repeat("-", width))
+
+	if ui.showing && len(visible) > 0 {
+		ui.renderDetail(line, visible[ui.selected])
+	} else {
+		rows := height - 5
+		if ui.selected < ui.offset {
+			ui.offset = ui.selected
+		}
+		if ui.selected >= ui.offset+rows {
+			ui.offset = ui.selected - rows + 1
+		}
+
+		if len(visible) == 0 {
+			line("No users")
+		}
+		for i := ui.offset; i < len(visible) && i < ui.offset+rows; i++ {
+			user := visible[i]
+			style := ""
+			if i == ui.selected {
+				style = "\x1b[7m"
+			}
+			mode := user.AuthMode
+			if mode == "" {
+				mode = models.AuthModeUser
+			}
+			line("%s %-20s %-32s %-4s %s", style, user.KanboardUsername, user.UserID, mode, user.KanboardURL)
+		}
+	}
+
+	fmt.Fprint(ui.out, b.String())
+	fmt.Fprintf(ui.out, "\x1b[%d;1H\x1b[2K%s", height-1, ui.status)
+	fmt.Fprintf(ui.out, "\x1b[%d;1H\x1b[2K\x1b[2m%s\x1b[0m", height, "↑/↓ move  enter details  / search  esc clear  r register  t rotate token  d delete  q quit")
+}
+
+func (ui *manageUI) renderDetail(line func(string, ...interface{}), user *models.User) {
+	kanboardURL := user.KanboardURL
+	if kanboardURL == "" {
+		kanboardURL = ui.cfg.Kanboard.DefaultURL + " (default)"
+	}
+
+	line("User ID:      %s", user.UserID)
+	line("Username:     %s", user.KanboardUsername)
+	line("Kanboard URL: %s", kanboardURL)
+	if user.AuthMode == models.AuthModeApp {
+		line("Token:        [SHARED APPLICATION TOKEN]")
+	} else {
+		line("Token:        [ENCRYPTED]")
+	}
+	line("Created:      %s", user.CreatedAt.Format("2006-01-02 15:04:05"))
+	line("Last Used:    %s", user.LastUsed.Format("2006-01-02 15:04:05"))
+	if expiry := ui.authManager.CredentialExpiry(user); !expiry.IsZero() {
+		line("Expires:      %s", formatExpiry(expiry))
+	}
+	line("")
+	line("Press any key to return")
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/tech-arch1tect/kan-mcp/internal/auth"
+	"github.com/tech-arch1tect/kan-mcp/internal/config"
+	"github.com/tech-arch1tect/kan-mcp/internal/storage"
+)
+
+// quotaTracker counts each user's tool calls and response bytes for the
+// current day and refuses calls once a configured daily quota is used up.
+type quotaTracker struct {
+	mu       sync.Mutex
+	quotas   config.QuotaConfig
+	location *time.Location
+	day      time.Time
+	usage    map[string]*dailyUsage
+}
+
+type dailyUsage struct {
+	calls int
+	bytes int
+}
+
+// newQuotaTracker starts from today's entries in the audit log so a restart
+// does not reset anyone's usage.
+func newQuotaTracker(quotas config.QuotaConfig, location *time.Location, auditStore *storage.AuditStore) (*quotaTracker, error) {
+	tracker := &quotaTracker{
+		quotas:   quotas,
+		location: location,
+		day:      startOfDay(time.Now(), location),
+		usage:    make(map[string]*dailyUsage),
+	}
+
+	calls, err := auditStore.Calls(tracker.day)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read today's tool calls: %w", err)
+	}
+	for _, call := range calls {
+		tracker.add(call.UserID, call.Bytes, call.Time)
+	}
+
+	return tracker, nil
+}
+
+func startOfDay(t time.Time, location *time.Location) time.Time {
+	local := t.In(location)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, location)
+}
+
+// rollover starts a new day once midnight has passed. Callers hold mu.
+func (q *quotaTracker) rollover(now time.Time) {
+	if day := startOfDay(now, q.location); day.After(q.day) {
+		q.day = day
+		q.usage = make(map[string]*dailyUsage)
+	}
+}
+
+func (q *quotaTracker) add(userID string, bytes int, now time.Time) {
+	if userID == "" {
+		return
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.rollover(now)
+	if now.Before(q.day) {
+		return
+	}
+
+	usage, ok := q.usage[userID]
+	if !ok {
+		usage = &dailyUsage{}
+		q.usage[userID] = usage
+	}
+	usage.calls++
+	usage.bytes += bytes
+}
+
+// check returns the error to show when userID has used up a daily quota.
+func (q *quotaTracker) check(userID string, now time.Time) error {
+	limits := q.quotas.Limits(userID)
+	if userID == "" || (limits.DailyCalls == 0 && limits.DailyBytes == 0) {
+		return nil
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.rollover(now)
+	usage, ok := q.usage[userID]
+	if !ok {
+		return nil
+	}
+
+	resetsAt := q.day.AddDate(0, 0, 1).Format("15:04 MST")
+	if limits.DailyCalls > 0 && usage.calls >= limits.DailyCalls {
+		return fmt.Errorf("quota exceeded: %d of %d daily tool calls used, resets at %s", usage.calls, limits.DailyCalls, resetsAt)
+	}
+	if limits.DailyBytes > 0 && usage.bytes >= limits.DailyBytes {
+		return fmt.Errorf("quota exceeded: %s of %s daily response data used, resets at %s", formatBytes(usage.bytes), formatBytes(limits.DailyBytes), resetsAt)
+	}
+	return nil
+}
+
+// responseBytes is the size of the text, exported files and charts returned
+// to the client.
+func responseBytes(result *mcp.CallToolResult) int {
+	if result == nil {
+		return 0
+	}
+
+	total := 0
+	for _, content := range result.Content {
+		switch content := content.(type) {
+		case mcp.TextContent:
+			total += len(content.Text)
+		case mcp.ImageContent:
+			total += len(content.Data)
+		case mcp.EmbeddedResource:
+			if blob, ok := content.Resource.(mcp.BlobResourceContents); ok {
+				total += len(blob.Blob)
+			}
+		}
+	}
+	return total
+}
+
+func formatBytes(n int) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
+
+func setupUsage(fs *flag.FlagSet) func(env *commandEnv) {
+	days := fs.Int("days", 7, "Number of days to show, including today")
+	userID := fs.String("user-id", "", "Only show this user")
+
+	return func(env *commandEnv) {
+		if *days <= 0 {
+			env.usageError("Days must be positive")
+		}
+
+		location, err := env.cfg.GetTimezone()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+
+		auditStore, err := storage.NewAuditStore(env.cfg.Storage.DataDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to initialize audit store: %v\n", err)
+			os.Exit(1)
+		}
+
+		showUsage(env.auth(), auditStore, env.cfg.Quota, location, *days, *userID)
+	}
+}
+
+// showUsage prints calls and response bytes per user per day next to the
+// quota that applies to each user.
+func showUsage(authManager *auth.AuthManager, auditStore *storage.AuditStore, quotas config.QuotaConfig, location *time.Location, days int, userID string) {
+	since := startOfDay(time.Now(), location).AddDate(0, 0, -(days - 1))
+
+	calls, err := auditStore.Calls(since)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read usage: %v\n", err)
+		os.Exit(1)
+	}
+
+	users, err := authManager.ListUsers()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to list users: %v\n", err)
+		os.Exit(1)
+	}
+	usernames := make(map[string]string, len(users))
+	for _, user := range users {
+		usernames[user.UserID] = user.KanboardUsername
+	}
+
+	type key struct{ date, userID string }
+	usage := make(map[key]*dailyUsage)
+	for _, call := range calls {
+		if call.UserID == "" || (userID != "" && call.UserID != userID) {
+			continue
+		}
+		k := key{date: call.Time.In(location).Format("2006-01-02"), userID: call.UserID}
+		entry, ok := usage[k]
+		if !ok {
+			entry = &dailyUsage{}
+			usage[k] = entry
+		}
+		entry.calls++
+		entry.bytes += call.Bytes
+	}
+
+	if len(usage) == 0 {
+		fmt.Printf("No tool calls since %s\n", since.Format("2006-01-02"))
+		return
+	}
+
+	keys := make([]key, 0, len(usage))
+	for k := range usage {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b key) int {
+		if c := strings.Compare(b.date, a.date); c != 0 {
+			return c
+		}
+		return usage[b].calls - usage[a].calls
+	})
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "DATE\tUSER ID\tUSERNAME\tCALLS\tBYTES")
+	for _, k := range keys {
+		entry := usage[k]
+		username, ok := usernames[k.userID]
+		if !ok {
+			username = "(not registered)"
+		}
+
+		limits := quotas.Limits(k.userID)
+		calls := fmt.Sprint(entry.calls)
+		if limits.DailyCalls > 0 {
+			calls += fmt.Sprintf(" / %d", limits.DailyCalls)
+		}
+		bytes := formatBytes(entry.bytes)
+		if limits.DailyBytes > 0 {
+			bytes += " / " + formatBytes(limits.DailyBytes)
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", k.date, k.userID, username, calls, bytes)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/config"
+	"golang.org/x/time/rate"
+)
+
+const toolLimiterSweepThreshold = 1000
+
+// toolLimiter is a token bucket per user ID in front of every tool, so one
+// runaway agent loop cannot monopolise the server or its user's Kanboard.
+type toolLimiter struct {
+	mu       sync.Mutex
+	settings config.ToolRateLimitConfig
+	limiters map[string]*rate.Limiter
+}
+
+func newToolLimiter(settings config.ToolRateLimitConfig) *toolLimiter {
+	return &toolLimiter{
+		settings: settings,
+		limiters: make(map[string]*rate.Limiter),
+	}
+}
+
+// allow takes a token for userID, or returns the error to show when the
+// bucket is empty. Calls are rejected rather than delayed so the client sees
+// the limit instead of a hanging request.
+func (tl *toolLimiter) allow(userID string, now time.Time) error {
+	limits := tl.settings.Limits(userID)
+	if userID == "" || limits.RequestsPerSecond <= 0 {
+		return nil
+	}
+
+	limiter := tl.limiterFor(userID, limits, now)
+	reservation := limiter.ReserveN(now, 1)
+	if !reservation.OK() {
+		return fmt.Errorf("rate limit exceeded: at most %g tool calls per second are allowed", limits.RequestsPerSecond)
+	}
+
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		seconds := math.Ceil(delay.Seconds())
+		return fmt.Errorf("rate limit exceeded: at most %g tool calls per second (bursts of %d) are allowed, retry in %.0fs", limits.RequestsPerSecond, max(limits.Burst, 1), seconds)
+	}
+
+	return nil
+}
+
+func (tl *toolLimiter) limiterFor(userID string, limits config.ToolRateLimit, now time.Time) *rate.Limiter {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	if limiter, ok := tl.limiters[userID]; ok {
+		return limiter
+	}
+
+	// Buckets that have refilled completely carry no state worth keeping.
+	if len(tl.limiters) >= toolLimiterSweepThreshold {
+		for id, limiter := range tl.limiters {
+			if limiter.TokensAt(now) >= float64(limiter.Burst()) {
+				delete(tl.limiters, id)
+			}
+		}
+	}
+
+	limiter := rate.NewLimiter(rate.Limit(limits.RequestsPerSecond), max(limits.Burst, 1))
+	tl.limiters[userID] = limiter
+	return limiter
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// authorizeResource checks the user ID a resource URI names the way tool
+// calls are checked: unknown IDs count towards the client's lockout, signed
+// requests must be signed by that user, and reads share the user's tool rate
+// limit.
+func (s *KanboardMCPServer) authorizeResource(ctx context.Context, userID string) error {
+	if _, err := s.authManager.GetUser(userID); err != nil {
+		if addr, ok := clientAddrFromContext(ctx); ok {
+			s.lockout.fail(addr, time.Now())
+		}
+		return fmt.Errorf("authentication failed: unknown user ID")
+	}
+	if err := s.checkSignedUser(ctx, userID); err != nil {
+		return err
+	}
+	if _, err := s.authManager.AuthenticateUser(userID); err != nil {
+		return fmt.Errorf("authentication failed: %w", err)
+	}
+	return s.limiter.allow(userID, time.Now())
+}
+
+// resourceArgument returns a variable matched from a resource template URI.
+func resourceArgument(request mcp.ReadResourceRequest, name string) string {
+	switch value := request.Params.Arguments[name].(type) {
+	case string:
+		return value
+	case []string:
+		if len(value) > 0 {
+			return value[0]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/auth"
+	"github.com/tech-arch1tect/kan-mcp/internal/logging"
+)
+
+const (
+	maxSignedBodyBytes = 10 << 20
+	maxNonceLength     = 128
+)
+
+type signedUserKey struct{}
+
+// signedUserFromContext returns the user whose secret signed the HTTP request,
+// or "" for an unsigned one. ok is false outside the HTTP transport.
+func signedUserFromContext(ctx context.Context) (userID string, ok bool) {
+	userID, ok = ctx.Value(signedUserKey{}).(string)
+	return userID, ok
+}
+
+// signatureVerifier checks HMAC-SHA256 signatures on HTTP requests before
+// they reach the MCP server. Requests for users with a signing secret must be
+// signed with it; with require set, every request must be signed.
+type signatureVerifier struct {
+	authManager *auth.AuthManager
+	require     bool
+	maxAge      time.Duration
+	next        http.Handler
+
+	mu     sync.Mutex
+	nonces map[string]time.Time
+}
+
+func newSignatureVerifier(authManager *auth.AuthManager, require bool, maxAge time.Duration, next http.Handler) *signatureVerifier {
+	return &signatureVerifier{
+		authManager: authManager,
+		require:     require,
+		maxAge:      maxAge,
+		next:        next,
+		nonces:      make(map[string]time.Time),
+	}
+}
+
+func (v *signatureVerifier) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.Header.Get("X-User-ID")
+	if userID == "" {
+		userID = r.URL.Query().Get("user_id")
+	}
+
+	signedUserID, err := v.verify(r, userID)
+	if err != nil {
+		logging.Warnf("Rejected HTTP request for user %s from %s: %v", logging.UserID(userID), r.RemoteAddr, err)
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), signedUserKey{}, signedUserID)
+	v.next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// verify returns the user ID the request is signed for, or "" when an
+// unsigned request is acceptable.
+func (v *signatureVerifier) verify(r *http.Request, userID string) (string, error) {
+	signature := r.Header.Get("X-Signature")
+	if signature == "" {
+		if v.require {
+			return "", fmt.Errorf("request signature required")
+		}
+		if userID != "" && signingRequired(v.authManager, false, userID) {
+			return "", fmt.Errorf("requests for this user must be signed")
+		}
+		return "", nil
+	}
+
+	if userID == "" {
+		return "", fmt.Errorf("signed requests must carry X-User-ID")
+	}
+
+	user, err := v.authManager.GetUser(userID)
+	if err != nil || user.SigningSecret == "" {
+		return "", fmt.Errorf("invalid request signature")
+	}
+
+	timestamp := r.Header.Get("X-Signature-Timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid X-Signature-Timestamp")
+	}
+	signedAt := time.Unix(seconds, 0)
+	if age := time.Since(signedAt); age > v.maxAge || age < -v.maxAge {
+		return "", fmt.Errorf("request signature expired; check the client clock")
+	}
+
+	nonce := r.Header.Get("X-Signature-Nonce")
+	if nonce == "" || len(nonce) > maxNonceLength {
+		return "", fmt.Errorf("invalid X-Signature-Nonce")
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, maxSignedBodyBytes))
+	if err != nil {
+		return "", fmt.Errorf("failed to read request body: %w", err)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	secret, err := v.authManager.GetSigningSecret(user)
+	if err != nil {
+		return "", fmt.Errorf("invalid request signature")
+	}
+	expected := auth.SignRequest(secret, timestamp, nonce, body)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return "", fmt.Errorf("invalid request signature")
+	}
+
+	// Nonces are only recorded once the signature checks out, so forged
+	// requests cannot use up a client's nonces.
+	if !v.useNonce(userID+":"+nonce, signedAt.Add(v.maxAge)) {
+		return "", fmt.Errorf("request nonce already used")
+	}
+
+	return userID, nil
+}
+
+func signingRequired(authManager *auth.AuthManager, require bool, userID string) bool {
+	if require {
+		return true
+	}
+	user, err := authManager.GetUser(userID)
+	return err == nil && user.SigningSecret != ""
+}
+
+// useNonce records key until expires, reporting false if it was already
+// seen. A nonce can only be replayed with a timestamp older than maxAge, so
+// entries are dropped once their signature would have expired anyway.
+func (v *signatureVerifier) useNonce(key string, expires time.Time) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	now := time.Now()
+	if seen, ok := v.nonces[key]; ok && seen.After(now) {
+		return false
+	}
+
+	if len(v.nonces) >= 1000 {
+		for k, seen := range v.nonces {
+			if !seen.After(now) {
+				delete(v.nonces, k)
+			}
+		}
+	}
+
+	v.nonces[key] = expires
+	return true
+}
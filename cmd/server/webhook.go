@@ -0,0 +1,204 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/api"
+	"github.com/tech-arch1tect/kan-mcp/internal/logging"
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+	"github.com/tech-arch1tect/kan-mcp/internal/storage"
+)
+
+const maxWebhookBody = 1 << 20
+
+// webhookReceiver accepts the webhooks one Kanboard instance sends when tasks,
+// comments and the like change. It drops what is cached for the affected
+// project, so the next tool call sees the change without waiting for the
+// cache to expire, and records the event. Kanboard authenticates by
+// appending its webhook token to the URL as the token query parameter; wrong
+// tokens count towards the client's lockout.
+type webhookReceiver struct {
+	secret   string
+	instance string
+	events   *storage.EventStore
+	lockout  *lockout
+}
+
+type webhookPayload struct {
+	EventName   string `json:"event_name"`
+	EventAuthor string `json:"event_author"`
+	EventData   struct {
+		ProjectID models.KanboardString `json:"project_id"`
+		TaskID    models.KanboardString `json:"task_id"`
+		Task      struct {
+			ID        models.KanboardString `json:"id"`
+			ProjectID models.KanboardString `json:"project_id"`
+			Title     models.KanboardString `json:"title"`
+		} `json:"task"`
+	} `json:"event_data"`
+}
+
+func (wr *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token := r.URL.Query().Get("token")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(wr.secret)) != 1 {
+		if addr, ok := clientAddrFromContext(r.Context()); ok {
+			wr.lockout.fail(addr, time.Now())
+		}
+		logging.Warnf("Rejected Kanboard webhook from %s: invalid token", r.RemoteAddr)
+		http.Error(w, "Invalid webhook token", http.StatusUnauthorized)
+		return
+	}
+
+	var payload webhookPayload
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxWebhookBody)).Decode(&payload); err != nil {
+		http.Error(w, "Invalid webhook payload", http.StatusBadRequest)
+		return
+	}
+	if payload.EventName == "" {
+		http.Error(w, "Missing event_name", http.StatusBadRequest)
+		return
+	}
+
+	event := models.KanboardEvent{
+		Time:        time.Now(),
+		KanboardURL: wr.instance,
+		Name:        payload.EventName,
+		ProjectID:   firstID(payload.EventData.ProjectID, payload.EventData.Task.ProjectID),
+		TaskID:      firstID(payload.EventData.TaskID, payload.EventData.Task.ID),
+		TaskTitle:   string(payload.EventData.Task.Title),
+		Author:      payload.EventAuthor,
+	}
+
+	// Events without a project, such as user changes, may affect any
+	// project, so they drop everything cached for the instance.
+	api.InvalidateCachedProject(wr.instance, event.ProjectID)
+	logging.Debugf("Kanboard webhook %s for project %d: cache invalidated", event.Name, event.ProjectID)
+
+	if err := wr.events.Record(event); err != nil {
+		logging.Warnf("Failed to record Kanboard webhook event: %v", err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// firstID returns the first of ids that parses as a positive integer, or
+// zero.
+func firstID(ids ...models.KanboardString) int {
+	for _, id := range ids {
+		if n, err := strconv.Atoi(string(id)); err == nil && n > 0 {
+			return n
+		}
+	}
+	return 0
+}
+
+// pruneWebhookEvents drops webhook events older than retention now and once
+// a day for as long as the server runs.
+func pruneWebhookEvents(events *storage.EventStore, retention time.Duration) {
+	if retention <= 0 {
+		return
+	}
+
+	for {
+		removed, err := events.Prune(time.Now().Add(-retention))
+		if err != nil {
+			logging.Warnf("Failed to prune webhook events: %v", err)
+		} else if removed > 0 {
+			logging.Infof("Pruned %d webhook events older than %s", removed, retention)
+		}
+		time.Sleep(24 * time.Hour)
+	}
+}
+
+func setupEvents(fs *flag.FlagSet) func(env *commandEnv) {
+	since := fs.Duration("since", 24*time.Hour, "How far back to show events")
+	projectID := fs.Int("project-id", 0, "Only show events for this project")
+	limit := fs.Int("limit", 100, "Show at most this many of the most recent events (0 for all)")
+	jsonOutput := fs.Bool("json", false, "Print matching events as JSON lines")
+
+	return func(env *commandEnv) {
+		if *since <= 0 {
+			env.usageError("Since must be positive")
+		}
+		if *limit < 0 {
+			env.usageError("Limit cannot be negative")
+		}
+
+		eventStore, err := storage.NewEventStore(env.cfg.Storage.DataDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to initialize event store: %v\n", err)
+			os.Exit(1)
+		}
+
+		showEvents(eventStore, time.Now().Add(-*since), *projectID, *limit, *jsonOutput)
+	}
+}
+
+func showEvents(eventStore *storage.EventStore, since time.Time, projectID, limit int, jsonOutput bool) {
+	events, err := eventStore.Events(since)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read event log: %v\n", err)
+		os.Exit(1)
+	}
+
+	matched := events[:0]
+	for _, event := range events {
+		if projectID != 0 && event.ProjectID != projectID {
+			continue
+		}
+		matched = append(matched, event)
+	}
+	if limit > 0 && len(matched) > limit {
+		matched = matched[len(matched)-limit:]
+	}
+
+	if jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		for _, event := range matched {
+			if err := encoder.Encode(event); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to write event: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		return
+	}
+
+	if len(matched) == 0 {
+		fmt.Printf("No matching webhook events since %s\n", since.Format("2006-01-02 15:04:05"))
+		return
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tEVENT\tPROJECT\tTASK\tAUTHOR")
+	for _, event := range matched {
+		project, task, author := "-", "-", "-"
+		if event.ProjectID != 0 {
+			project = strconv.Itoa(event.ProjectID)
+		}
+		if event.TaskID != 0 {
+			task = "#" + strconv.Itoa(event.TaskID)
+			if event.TaskTitle != "" {
+				task += " " + truncate(event.TaskTitle, 50)
+			}
+		}
+		if event.Author != "" {
+			author = event.Author
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", event.Time.Local().Format("2006-01-02 15:04:05"), event.Name, project, task, author)
+	}
+	tw.Flush()
+}
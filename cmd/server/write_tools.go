@@ -0,0 +1,293 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/tech-arch1tect/kan-mcp/internal/handlers"
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+)
+
+// writeHandler is a handler behind a tool that changes Kanboard data.
+// Preview describes the change without making it, for confirmations.
+type writeHandler interface {
+	Preview(ctx context.Context, params map[string]interface{}, userID string) (string, error)
+	Handle(ctx context.Context, params map[string]interface{}, userID string) (*models.MCPResponse, error)
+}
+
+// addWriteTools registers the tools that change Kanboard data. addWriteTool
+// leaves them out in read-only mode.
+func (s *KanboardMCPServer) addWriteTools() {
+	swimlaneTool := mcp.NewTool("kanboard_manage_swimlanes",
+		mcp.WithDescription("Create, rename, reorder, enable or disable a project's swimlanes. Needs the project manager role. Returns the project's swimlanes after the change"),
+		mcp.WithString("user_id",
+			mcp.Description("User ID for authentication"),
+			mcp.Required(),
+		),
+		mcp.WithNumber("project_id",
+			mcp.Description("ID of the project whose swimlanes to change"),
+			mcp.Required(),
+		),
+		mcp.WithString("action",
+			mcp.Description("'create', 'rename' (name and/or description), 'move' (to position), 'enable' or 'disable'"),
+			mcp.Required(),
+		),
+		mcp.WithString("swimlane",
+			mcp.Description("Swimlane to change, by name or ID (not used by create)"),
+		),
+		mcp.WithString("name",
+			mcp.Description("Name for create, or the new name for rename"),
+		),
+		mcp.WithString("description",
+			mcp.Description("Optional: description for create or rename"),
+		),
+		mcp.WithNumber("position",
+			mcp.Description("Position counting from 1 at the top of the board; required for move, optional for create (default: bottom)"),
+		),
+	)
+	s.addWriteHandler(swimlaneTool, "manage_swimlanes", handlers.NewSwimlaneHandler(s.authManager, s.userConfig))
+
+	columnTool := mcp.NewTool("kanboard_manage_columns",
+		mcp.WithDescription("Create, rename or reorder a project's columns, or set a column's WIP limit. Needs the project manager role. Returns the project's columns after the change"),
+		mcp.WithString("user_id",
+			mcp.Description("User ID for authentication"),
+			mcp.Required(),
+		),
+		mcp.WithNumber("project_id",
+			mcp.Description("ID of the project whose columns to change"),
+			mcp.Required(),
+		),
+		mcp.WithString("action",
+			mcp.Description("'create', 'rename' (title and/or description), 'move' (to position) or 'set_limit' (to task_limit)"),
+			mcp.Required(),
+		),
+		mcp.WithString("column",
+			mcp.Description("Column to change, by title or ID (not used by create)"),
+		),
+		mcp.WithString("title",
+			mcp.Description("Title for create, or the new title for rename"),
+		),
+		mcp.WithString("description",
+			mcp.Description("Optional: description for create or rename"),
+		),
+		mcp.WithNumber("position",
+			mcp.Description("Position counting from 1 at the left of the board; required for move, optional for create (default: rightmost)"),
+		),
+		mcp.WithNumber("task_limit",
+			mcp.Description("WIP limit: the most open tasks the column should hold, 0 for no limit; required for set_limit, optional for create"),
+		),
+	)
+	s.addWriteHandler(columnTool, "manage_columns", handlers.NewColumnHandler(s.authManager, s.userConfig))
+
+	categoryTool := mcp.NewTool("kanboard_manage_categories",
+		mcp.WithDescription("Create, rename or delete a project's categories (e.g. bug, feature, chore), or put a task in a category. Changing categories needs the project manager role; assigning one to a task needs member rights. Returns the project's categories after the change"),
+		mcp.WithString("user_id",
+			mcp.Description("User ID for authentication"),
+			mcp.Required(),
+		),
+		mcp.WithString("action",
+			mcp.Description("'create', 'rename' (name and/or color), 'delete', or 'assign' (put task_id in category)"),
+			mcp.Required(),
+		),
+		mcp.WithNumber("project_id",
+			mcp.Description("ID of the project whose categories to change; for assign it defaults to the task's project"),
+		),
+		mcp.WithString("category",
+			mcp.Description("Category to change or assign, by name or ID; for assign, 'none' removes the task's category"),
+		),
+		mcp.WithString("name",
+			mcp.Description("Name for create, or the new name for rename"),
+		),
+		mcp.WithString("color",
+			mcp.Description("Optional: colour ID for create or rename, e.g. 'red', 'blue' or 'green'"),
+		),
+		mcp.WithNumber("task_id",
+			mcp.Description("Task to put in the category; required for assign"),
+		),
+	)
+	s.addWriteHandler(categoryTool, "manage_categories", handlers.NewCategoryHandler(s.authManager, s.userConfig))
+
+	memberTool := mcp.NewTool("kanboard_manage_members",
+		mcp.WithDescription("Add a user to a project, remove them, or change their project role. Needs the project manager role; the project's last manager cannot be removed or demoted. Returns the project's members and roles after the change"),
+		mcp.WithString("user_id",
+			mcp.Description("User ID for authentication"),
+			mcp.Required(),
+		),
+		mcp.WithNumber("project_id",
+			mcp.Description("ID of the project whose members to change"),
+			mcp.Required(),
+		),
+		mcp.WithString("action",
+			mcp.Description("'add', 'remove' or 'set_role'"),
+			mcp.Required(),
+		),
+		mcp.WithString("user",
+			mcp.Description("Kanboard user to add, remove or change, by username or numeric ID. Users who are not members yet may have to be given by ID"),
+			mcp.Required(),
+		),
+		mcp.WithString("role",
+			mcp.Description("'manager', 'member' or 'viewer'; required for set_role, optional for add (default: member)"),
+		),
+	)
+	s.addWriteHandler(memberTool, "manage_members", handlers.NewMembershipHandler(s.authManager, s.userConfig))
+
+	linkTool := mcp.NewTool("kanboard_add_external_link",
+		mcp.WithDescription("Attach a URL, such as a pull request, ticket or document, to a task. Needs member rights in the task's project. Returns the task's external links after the change"),
+		mcp.WithString("user_id",
+			mcp.Description("User ID for authentication"),
+			mcp.Required(),
+		),
+		mcp.WithNumber("task_id",
+			mcp.Description("Task to attach the link to"),
+			mcp.Required(),
+		),
+		mcp.WithString("url",
+			mcp.Description("Absolute http or https URL to attach"),
+			mcp.Required(),
+		),
+		mcp.WithString("title",
+			mcp.Description("Optional: title to show for the link, e.g. 'PR #42: Fix login'. Kanboard uses the page title or file name when omitted"),
+		),
+		mcp.WithString("type",
+			mcp.Description("Optional: 'weblink', 'attachment' for a file to download, or 'auto' to let Kanboard decide from the URL (default: auto)"),
+		),
+	)
+	s.addWriteHandler(linkTool, "add_external_link", handlers.NewExternalLinkHandler(s.authManager, s.userConfig))
+
+	timeTool := mcp.NewTool("kanboard_log_time",
+		mcp.WithDescription("Record hours spent on a task, or on one of its subtasks. The hours are added to the time already logged; a negative value corrects an earlier entry. Needs member rights in the task's project. Returns the task's time spent and estimate afterwards"),
+		mcp.WithString("user_id",
+			mcp.Description("User ID for authentication"),
+			mcp.Required(),
+		),
+		mcp.WithNumber("task_id",
+			mcp.Description("Task the time was spent on"),
+			mcp.Required(),
+		),
+		mcp.WithNumber("hours",
+			mcp.Description("Hours to add, e.g. 1.5; negative to remove hours logged by mistake"),
+			mcp.Required(),
+		),
+		mcp.WithString("subtask",
+			mcp.Description("Optional: subtask the time was spent on, by title or ID. Kanboard adds subtask time up into the task's"),
+		),
+	)
+	s.addWriteHandler(timeTool, "log_time", handlers.NewTimeLogHandler(s.authManager, s.userConfig))
+
+	duplicateTool := mcp.NewTool("kanboard_duplicate_task",
+		append([]mcp.ToolOption{
+			mcp.WithDescription("Copy a task, with its subtasks and tags, to a project; the original stays where it is. Needs member rights in both projects. Returns the new task"),
+		}, taskTransferOptions()...)...,
+	)
+	s.addWriteHandler(duplicateTool, "duplicate_task", handlers.NewTaskTransferHandler(s.authManager, s.userConfig, false))
+
+	moveTool := mcp.NewTool("kanboard_move_task_to_project",
+		append([]mcp.ToolOption{
+			mcp.WithDescription("Move a task to another project, e.g. from a triage project to a delivery project. Needs member rights in both projects. Returns the task in its new project"),
+		}, taskTransferOptions()...)...,
+	)
+	s.addWriteHandler(moveTool, "move_task_to_project", handlers.NewTaskTransferHandler(s.authManager, s.userConfig, true))
+
+	recurrenceTool := mcp.NewTool("kanboard_set_recurrence",
+		mcp.WithDescription("Make a task recur, change how it recurs, or stop it recurring. A recurring task gets a copy with a new due date when its trigger happens. Settings left out keep their current values. Needs member rights in the task's project. kanboard_tasks shows each task's current recurrence. Returns the task's recurrence after the change"),
+		mcp.WithString("user_id",
+			mcp.Description("User ID for authentication"),
+			mcp.Required(),
+		),
+		mcp.WithNumber("task_id",
+			mcp.Description("Task whose recurrence to set"),
+			mcp.Required(),
+		),
+		mcp.WithBoolean("disable",
+			mcp.Description("Optional: set to true to stop the task recurring"),
+		),
+		mcp.WithString("trigger",
+			mcp.Description("Optional: when the copy is created: 'closed', 'last_column' (moved to the last column) or 'first_column' (moved out of the first column; Kanboard's default)"),
+		),
+		mcp.WithNumber("factor",
+			mcp.Description("Optional: how many timeframes after the base date the copy is due, e.g. 2 with 'weeks' for fortnightly (default: the current factor, or 1 for a task that does not recur yet)"),
+		),
+		mcp.WithString("timeframe",
+			mcp.Description("Optional: 'days', 'weeks', 'months' or 'years' (default: days)"),
+		),
+		mcp.WithString("basedate",
+			mcp.Description("Optional: date the copy's due date counts from: 'due_date' (this task's due date; default) or 'action_date' (when the trigger happens)"),
+		),
+	)
+	s.addWriteHandler(recurrenceTool, "set_recurrence", handlers.NewRecurrenceHandler(s.authManager, s.userConfig))
+}
+
+// taskTransferOptions are the parameters shared by the tools that copy and
+// move tasks between projects.
+func taskTransferOptions() []mcp.ToolOption {
+	return []mcp.ToolOption{
+		mcp.WithString("user_id",
+			mcp.Description("User ID for authentication"),
+			mcp.Required(),
+		),
+		mcp.WithNumber("task_id",
+			mcp.Description("Task to copy or move"),
+			mcp.Required(),
+		),
+		mcp.WithNumber("project_id",
+			mcp.Description("ID of the destination project"),
+			mcp.Required(),
+		),
+		mcp.WithString("column",
+			mcp.Description("Optional: destination column by title or ID (default: the column with the task's current column title, else the first column)"),
+		),
+		mcp.WithString("swimlane",
+			mcp.Description("Optional: destination swimlane by name or ID (default: the swimlane with the task's current swimlane name, else the first active swimlane)"),
+		),
+		mcp.WithString("category",
+			mcp.Description("Optional: destination category by name or ID, or 'none' (default: the category with the task's current category name, else none)"),
+		),
+		mcp.WithString("assignee",
+			mcp.Description("Optional: member of the destination project to assign, by username or ID, or 'none' (default: the current assignee if they are a member there, else unassigned)"),
+		),
+	}
+}
+
+// addWriteHandler registers tool with handler, passing the tool's arguments
+// other than user_id and confirmation_token on as params.
+func (s *KanboardMCPServer) addWriteHandler(tool mcp.Tool, name string, handler writeHandler) {
+	preview := func(ctx context.Context, request mcp.CallToolRequest) (string, error) {
+		userID, params, err := writeArguments(request)
+		if err != nil {
+			return "", err
+		}
+		return handler.Preview(ctx, params, userID)
+	}
+
+	s.addWriteTool(tool, preview, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		userID, params, err := writeArguments(request)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		response, err := handler.Handle(ctx, params, userID)
+		if err != nil {
+			return toolError(name, err), nil
+		}
+
+		return toolResult(response), nil
+	})
+}
+
+func writeArguments(request mcp.CallToolRequest) (string, map[string]interface{}, error) {
+	args := request.GetArguments()
+
+	userID, ok := args["user_id"].(string)
+	if !ok || userID == "" {
+		return "", nil, fmt.Errorf("Missing required parameter: user_id. Please ask the user for their User ID and include it in the tool call. Users can find their User ID by running: ./kan-mcp user list")
+	}
+
+	params := make(map[string]interface{}, len(args))
+	for key, val := range args {
+		if key != "user_id" && key != "confirmation_token" {
+			params[key] = val
+		}
+	}
+	return userID, params, nil
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// getMemberProjectsRaw emulates getMyProjects for application-token clients,
+// which have no user session: it lists every project and keeps those where
+// the impersonated user is a member.
+func (c *Client) getMemberProjectsRaw(ctx context.Context) (json.RawMessage, error) {
+	me, err := c.GetMe(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve user %s: %w", c.username, err)
+	}
+	if me.ID == 0 {
+		return nil, fmt.Errorf("user %s not found in Kanboard", c.username)
+	}
+
+	resp, err := c.makeRequest(ctx, "getAllProjects", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []map[string]interface{}
+	if err := c.unmarshalResult(resp.Result, &projects); err != nil {
+		return nil, err
+	}
+
+	if len(projects) == 0 {
+		return json.RawMessage("[]"), nil
+	}
+
+	calls := make([]BatchCall, len(projects))
+	for i, project := range projects {
+		calls[i] = BatchCall{
+			Method: "getProjectUsers",
+			Params: map[string]interface{}{"project_id": rawID(project["id"])},
+		}
+	}
+
+	responses, err := c.makeBatchRequest(ctx, calls)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check project membership: %w", err)
+	}
+
+	memberProjects := make([]map[string]interface{}, 0, len(projects))
+	for i, project := range projects {
+		users, err := c.parseProjectUsers(responses[i].Result)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, user := range users {
+			if user.ID == me.ID {
+				memberProjects = append(memberProjects, project)
+				break
+			}
+		}
+	}
+
+	data, err := json.Marshal(memberProjects)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal projects: %w", err)
+	}
+
+	return json.RawMessage(data), nil
+}
+
+func rawID(value interface{}) int {
+	switch id := value.(type) {
+	case float64:
+		return int(id)
+	case string:
+		parsed, _ := strconv.Atoi(id)
+		return parsed
+	}
+	return 0
+}
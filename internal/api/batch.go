@@ -0,0 +1,200 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+)
+
+type BatchCall struct {
+	Method string
+	Params interface{}
+}
+
+// makeBatchRequest sends all calls in a single JSON-RPC batch and returns
+// the responses in the same order as the calls.
+func (c *Client) makeBatchRequest(ctx context.Context, calls []BatchCall) ([]models.JSONRPCResponse, error) {
+	if len(calls) == 0 {
+		return nil, nil
+	}
+
+	ordered := make([]models.JSONRPCResponse, len(calls))
+	found := make([]bool, len(calls))
+	cached := make([]bool, len(calls))
+
+	var requests []models.JSONRPCRequest
+	var methods []string
+	var projectIDs []int
+	idempotent := true
+
+	for i, call := range calls {
+		if err := c.checkWritable(call.Method); err != nil {
+			return nil, err
+		}
+		if result, ok := c.cachedResult(call.Method, call.Params); ok {
+			ordered[i] = models.JSONRPCResponse{JSONRpc: "2.0", ID: i + 1, Result: result}
+			found[i] = true
+			cached[i] = true
+			continue
+		}
+
+		requests = append(requests, models.JSONRPCRequest{
+			JSONRpc: "2.0",
+			Method:  call.Method,
+			ID:      i + 1,
+			Params:  call.Params,
+		})
+		methods = append(methods, call.Method)
+		if projectID := projectIDFromParams(call.Params); projectID > 0 && !slices.Contains(projectIDs, projectID) {
+			projectIDs = append(projectIDs, projectID)
+		}
+		if !isIdempotent(call.Method) {
+			idempotent = false
+		}
+	}
+
+	if len(requests) > 0 {
+		jsonData, err := json.Marshal(requests)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal batch request: %w", err)
+		}
+
+		label := "batch[" + strings.Join(methods, ",") + "]"
+		start := time.Now()
+		body, err := c.send(ctx, label, idempotent, jsonData)
+		c.observeCall(ctx, label, projectIDs, time.Since(start))
+		if err != nil {
+			return nil, err
+		}
+
+		var batchResp []models.JSONRPCResponse
+		if err := json.Unmarshal(body, &batchResp); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal batch response: %w", err)
+		}
+
+		for _, resp := range batchResp {
+			index := resp.ID - 1
+			if index < 0 || index >= len(calls) || found[index] {
+				continue
+			}
+			ordered[index] = resp
+			found[index] = true
+		}
+	}
+
+	for i, ok := range found {
+		if !ok {
+			return nil, fmt.Errorf("batch response missing result for %s", calls[i].Method)
+		}
+		if ordered[i].Error != nil {
+			return nil, jsonRPCError(calls[i].Method, ordered[i].Error.Code, ordered[i].Error.Message)
+		}
+	}
+
+	for i, call := range calls {
+		if cached[i] {
+			continue
+		}
+		if isIdempotent(call.Method) {
+			c.storeResult(call.Method, call.Params, ordered[i].Result)
+		} else if projectID := projectIDFromParams(call.Params); projectID > 0 {
+			c.InvalidateProject(projectID)
+		}
+	}
+
+	return ordered, nil
+}
+
+// PrefetchProjectStructure loads a project's details, columns, swimlanes,
+// members, categories and tags into the response cache in one round trip.
+func (c *Client) PrefetchProjectStructure(ctx context.Context, projectID int) error {
+	params := map[string]interface{}{"project_id": projectID}
+
+	_, err := c.makeBatchRequest(ctx, []BatchCall{
+		{Method: "getProjectById", Params: params},
+		{Method: "getColumns", Params: params},
+		{Method: "getAllSwimlanes", Params: params},
+		{Method: "getProjectUsers", Params: params},
+		{Method: "getAllCategories", Params: params},
+		{Method: "getTagsByProject", Params: params},
+	})
+	return err
+}
+
+// BoardParts selects which of a board's optional parts GetProjectBoard
+// fetches alongside its open tasks and columns.
+type BoardParts struct {
+	Swimlanes  bool
+	Users      bool
+	Categories bool
+}
+
+// AllBoardParts fetches every part of a board.
+var AllBoardParts = BoardParts{Swimlanes: true, Users: true, Categories: true}
+
+// GetProjectBoard fetches a project's open tasks and columns together with
+// the parts of its board selected by parts in a single round trip.
+func (c *Client) GetProjectBoard(ctx context.Context, projectID int, parts BoardParts) (*models.ProjectBoard, error) {
+	params := map[string]interface{}{"project_id": projectID}
+
+	calls := []BatchCall{
+		{Method: "getAllTasks", Params: map[string]interface{}{"project_id": projectID, "status_id": TaskStatusOpen}},
+		{Method: "getColumns", Params: params},
+	}
+	if parts.Swimlanes {
+		calls = append(calls, BatchCall{Method: "getAllSwimlanes", Params: params})
+	}
+	if parts.Users {
+		calls = append(calls, BatchCall{Method: "getProjectUsers", Params: params})
+	}
+	if parts.Categories {
+		calls = append(calls, BatchCall{Method: "getAllCategories", Params: params})
+	}
+
+	responses, err := c.makeBatchRequest(ctx, calls)
+	if err != nil {
+		return nil, err
+	}
+
+	var board models.ProjectBoard
+
+	if err := c.unmarshalResult(responses[0].Result, &board.Tasks); err != nil {
+		return nil, fmt.Errorf("failed to parse tasks: %w", err)
+	}
+
+	if err := c.unmarshalResult(responses[1].Result, &board.Columns); err != nil {
+		return nil, fmt.Errorf("failed to parse columns: %w", err)
+	}
+
+	next := 2
+	if parts.Swimlanes {
+		if err := c.unmarshalResult(responses[next].Result, &board.Swimlanes); err != nil {
+			return nil, fmt.Errorf("failed to parse swimlanes: %w", err)
+		}
+		next++
+	}
+
+	if parts.Users {
+		users, err := c.parseProjectUsers(responses[next].Result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse project users: %w", err)
+		}
+		board.Users = users
+		next++
+	}
+
+	if parts.Categories {
+		if categories, ok := responses[next].Result.([]interface{}); ok {
+			if err := c.unmarshalResult(categories, &board.Categories); err != nil {
+				return nil, fmt.Errorf("failed to parse categories: %w", err)
+			}
+		}
+	}
+
+	return &board, nil
+}
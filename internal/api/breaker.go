@@ -0,0 +1,171 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+type BreakerSettings struct {
+	FailureThreshold int
+	OpenDuration     time.Duration
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+type circuitBreaker struct {
+	mu                  sync.Mutex
+	settings            BreakerSettings
+	state               breakerState
+	consecutiveFailures int
+	unavailableSince    time.Time
+	openedAt            time.Time
+}
+
+var (
+	breakersMu sync.Mutex
+	breakers   = make(map[string]*circuitBreaker)
+)
+
+// breakerFor returns the circuit breaker shared by every client talking to
+// the same Kanboard instance.
+func breakerFor(baseURL string, settings BreakerSettings) *circuitBreaker {
+	if settings.FailureThreshold <= 0 {
+		return nil
+	}
+
+	if settings.OpenDuration <= 0 {
+		settings.OpenDuration = 30 * time.Second
+	}
+
+	key := InstanceKey(baseURL)
+
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+
+	breaker, exists := breakers[key]
+	if !exists {
+		breaker = &circuitBreaker{}
+		breakers[key] = breaker
+	}
+
+	breaker.mu.Lock()
+	breaker.settings = settings
+	breaker.mu.Unlock()
+
+	return breaker
+}
+
+// allow reports whether a request may be sent. Once the open period has
+// elapsed a single trial request is let through; its outcome decides whether
+// the breaker closes again or stays open for another period.
+func (b *circuitBreaker) allow(instance string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) < b.settings.OpenDuration {
+			return b.unavailableError(instance)
+		}
+		b.state = breakerHalfOpen
+		return nil
+	case breakerHalfOpen:
+		return b.unavailableError(instance)
+	default:
+		return nil
+	}
+}
+
+func (b *circuitBreaker) record(ctx context.Context, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if ctx.Err() != nil {
+		if b.state == breakerHalfOpen {
+			b.state = breakerOpen
+		}
+		return
+	}
+
+	var transient *retryableError
+	if err == nil || !errors.As(err, &transient) {
+		b.state = breakerClosed
+		b.consecutiveFailures = 0
+		b.unavailableSince = time.Time{}
+		return
+	}
+
+	if b.consecutiveFailures == 0 {
+		b.unavailableSince = time.Now()
+	}
+	b.consecutiveFailures++
+
+	if b.state == breakerHalfOpen || b.consecutiveFailures >= b.settings.FailureThreshold {
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+	}
+}
+
+func (b *circuitBreaker) unavailableError(instance string) error {
+	retryAt := b.openedAt.Add(b.settings.OpenDuration)
+	return &Error{
+		Kind: ErrUnavailable,
+		Message: fmt.Sprintf("Kanboard instance %s unavailable since %s after %d consecutive failures; next attempt after %s",
+			instance,
+			b.unavailableSince.Format(time.RFC3339),
+			b.consecutiveFailures,
+			retryAt.Format(time.RFC3339)),
+	}
+}
+
+// BreakerStatus describes the circuit breaker for one Kanboard instance.
+type BreakerStatus struct {
+	Instance            string    `json:"instance"`
+	State               string    `json:"state"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	UnavailableSince    time.Time `json:"unavailable_since"`
+	RetryAt             time.Time `json:"retry_at"`
+}
+
+// BreakerStats reports the state ("closed", "open" or "half_open") of every
+// circuit breaker, per instance.
+func BreakerStats() []BreakerStatus {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+
+	stats := make([]BreakerStatus, 0, len(breakers))
+	for instance, breaker := range breakers {
+		breaker.mu.Lock()
+		status := BreakerStatus{
+			Instance:            instance,
+			State:               "closed",
+			ConsecutiveFailures: breaker.consecutiveFailures,
+			UnavailableSince:    breaker.unavailableSince,
+		}
+		switch breaker.state {
+		case breakerOpen:
+			status.State = "open"
+			status.RetryAt = breaker.openedAt.Add(breaker.settings.OpenDuration)
+		case breakerHalfOpen:
+			status.State = "half_open"
+		}
+		breaker.mu.Unlock()
+		stats = append(stats, status)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Instance < stats[j].Instance
+	})
+
+	return stats
+}
@@ -0,0 +1,224 @@
+package api
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheTTLs maps read-only JSON-RPC method names to how long their results
+// may be served from memory. Methods without an entry are never cached.
+type CacheTTLs map[string]time.Duration
+
+type cacheEntry struct {
+	result    interface{}
+	stored    time.Time
+	expires   time.Time
+	instance  string
+	username  string
+	projectID int
+}
+
+type responseCache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+const cacheSweepThreshold = 1000
+
+var sharedCache = &responseCache{entries: make(map[string]cacheEntry)}
+
+func (rc *responseCache) get(key string) (interface{}, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	entry, ok := rc.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	if time.Now().After(entry.expires) {
+		delete(rc.entries, key)
+		return nil, false
+	}
+
+	return entry.result, true
+}
+
+func (rc *responseCache) set(key string, entry cacheEntry) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if len(rc.entries) >= cacheSweepThreshold {
+		now := time.Now()
+		for k, e := range rc.entries {
+			if now.After(e.expires) {
+				delete(rc.entries, k)
+			}
+		}
+	}
+
+	entry.stored = time.Now()
+	rc.entries[key] = entry
+}
+
+// CacheInstanceStats describes the live cached entries for one instance.
+type CacheInstanceStats struct {
+	Instance  string        `json:"instance"`
+	Entries   int           `json:"entries"`
+	OldestAge time.Duration `json:"oldest_age"`
+}
+
+// CacheStats reports how many unexpired entries are cached per instance and
+// how old the oldest of them is.
+func CacheStats() []CacheInstanceStats {
+	sharedCache.mu.Lock()
+	defer sharedCache.mu.Unlock()
+
+	now := time.Now()
+	byInstance := make(map[string]*CacheInstanceStats)
+	for _, entry := range sharedCache.entries {
+		if now.After(entry.expires) {
+			continue
+		}
+		stats, exists := byInstance[entry.instance]
+		if !exists {
+			stats = &CacheInstanceStats{Instance: entry.instance}
+			byInstance[entry.instance] = stats
+		}
+		stats.Entries++
+		stats.OldestAge = max(stats.OldestAge, now.Sub(entry.stored))
+	}
+
+	stats := make([]CacheInstanceStats, 0, len(byInstance))
+	for _, entry := range byInstance {
+		stats = append(stats, *entry)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Instance < stats[j].Instance
+	})
+
+	return stats
+}
+
+// invalidateUser drops every cached entry one user has on an instance.
+func (rc *responseCache) invalidateUser(instance, username string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	for k, e := range rc.entries {
+		if e.instance == instance && e.username == username {
+			delete(rc.entries, k)
+		}
+	}
+}
+
+// invalidate drops cached entries for an instance. A projectID of zero drops
+// everything for the instance; otherwise only that project's entries and
+// the project lists, which embed project metadata, are removed.
+func (rc *responseCache) invalidate(instance string, projectID int) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	for k, e := range rc.entries {
+		if e.instance != instance {
+			continue
+		}
+		if projectID == 0 || e.projectID == projectID || e.projectID == 0 {
+			delete(rc.entries, k)
+		}
+	}
+}
+
+func (c *Client) cacheTTL(method string) time.Duration {
+	if c.cacheTTLs == nil {
+		return 0
+	}
+	return c.cacheTTLs[method]
+}
+
+func (c *Client) cacheKey(method string, params interface{}) string {
+	paramsJSON, _ := json.Marshal(params)
+	return strings.Join([]string{InstanceKey(c.baseURL), c.username, method, string(paramsJSON)}, "|")
+}
+
+func (c *Client) cachedResult(method string, params interface{}) (interface{}, bool) {
+	if c.cacheTTL(method) <= 0 {
+		return nil, false
+	}
+	return sharedCache.get(c.cacheKey(method, params))
+}
+
+func (c *Client) storeResult(method string, params interface{}, result interface{}) {
+	ttl := c.cacheTTL(method)
+	if ttl <= 0 {
+		return
+	}
+
+	sharedCache.set(c.cacheKey(method, params), cacheEntry{
+		result:    result,
+		expires:   time.Now().Add(ttl),
+		instance:  InstanceKey(c.baseURL),
+		username:  c.username,
+		projectID: projectIDFromParams(params),
+	})
+}
+
+// CachedValue returns a value stored with StoreValue for this client's
+// instance and user.
+func (c *Client) CachedValue(key string) (interface{}, bool) {
+	return sharedCache.get(c.cacheKey(key, nil))
+}
+
+// StoreValue caches a result assembled from several calls, such as a whole
+// tool response. It spans projects, so any project invalidation on the
+// instance drops it.
+func (c *Client) StoreValue(key string, value interface{}, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	sharedCache.set(c.cacheKey(key, nil), cacheEntry{
+		result:   value,
+		expires:  time.Now().Add(ttl),
+		instance: InstanceKey(c.baseURL),
+		username: c.username,
+	})
+}
+
+// InvalidateUserCache discards everything cached for this client's user, so
+// the next reads go to Kanboard.
+func (c *Client) InvalidateUserCache() {
+	sharedCache.invalidateUser(InstanceKey(c.baseURL), c.username)
+}
+
+// InvalidateProject discards cached reads for a project on this client's
+// Kanboard instance. Write operations call it after mutating a project.
+func (c *Client) InvalidateProject(projectID int) {
+	InvalidateCachedProject(c.baseURL, projectID)
+}
+
+// InvalidateCachedProject discards every user's cached reads for a project on
+// a Kanboard instance, or everything cached for the instance when projectID
+// is zero. It is for changes made outside this server, such as those
+// reported by Kanboard webhooks.
+func InvalidateCachedProject(baseURL string, projectID int) {
+	sharedCache.invalidate(InstanceKey(baseURL), projectID)
+}
+
+func projectIDFromParams(params interface{}) int {
+	values, ok := params.(map[string]interface{})
+	if !ok {
+		return 0
+	}
+
+	switch id := values["project_id"].(type) {
+	case int:
+		return id
+	case float64:
+		return int(id)
+	}
+	return 0
+}
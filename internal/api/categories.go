@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+)
+
+func (c *Client) GetCategories(ctx context.Context, projectID int) ([]models.Category, error) {
+	resp, err := c.makeRequest(ctx, "getAllCategories", map[string]interface{}{
+		"project_id": projectID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var categories []models.Category
+	if err := c.unmarshalResult(resp.Result, &categories); err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+// CreateCategory adds a category to a project and returns its ID. An empty
+// colorID leaves the colour unset.
+func (c *Client) CreateCategory(ctx context.Context, projectID int, name, colorID string) (int, error) {
+	params := map[string]interface{}{
+		"project_id": projectID,
+		"name":       name,
+	}
+	if colorID != "" {
+		params["color_id"] = colorID
+	}
+	return c.create(ctx, "createCategory", params)
+}
+
+// UpdateCategory renames a category. An empty colorID leaves the colour
+// unchanged.
+func (c *Client) UpdateCategory(ctx context.Context, projectID, categoryID int, name, colorID string) error {
+	params := map[string]interface{}{
+		"id":   categoryID,
+		"name": name,
+	}
+	if colorID != "" {
+		params["color_id"] = colorID
+	}
+	if _, err := c.mutate(ctx, "updateCategory", params); err != nil {
+		return err
+	}
+
+	c.InvalidateProject(projectID)
+	return nil
+}
+
+// RemoveCategory deletes a category. Kanboard clears it from the project's
+// tasks.
+func (c *Client) RemoveCategory(ctx context.Context, projectID, categoryID int) error {
+	if _, err := c.mutate(ctx, "removeCategory", map[string]interface{}{
+		"category_id": categoryID,
+	}); err != nil {
+		return err
+	}
+
+	c.InvalidateProject(projectID)
+	return nil
+}
+
+// SetTaskCategory puts a task in a category, or in none when categoryID is
+// zero.
+func (c *Client) SetTaskCategory(ctx context.Context, projectID, taskID, categoryID int) error {
+	if _, err := c.mutate(ctx, "updateTask", map[string]interface{}{
+		"id":          taskID,
+		"category_id": categoryID,
+	}); err != nil {
+		return err
+	}
+
+	c.InvalidateProject(projectID)
+	return nil
+}
@@ -2,36 +2,115 @@ package api
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptrace"
 	"strconv"
 	"time"
 
+	"github.com/tech-arch1tect/kan-mcp/internal/logging"
 	"github.com/tech-arch1tect/kan-mcp/internal/models"
+	"github.com/tech-arch1tect/kan-mcp/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
+)
+
+const (
+	AuthHeaderAuthorization = "authorization"
+	AuthHeaderXAPIAuth      = "x-api-auth"
 )
 
 type Client struct {
 	baseURL    string
 	username   string
 	token      string
+	authMode   string
+	authHeader string
 	httpClient *http.Client
+	transport  *sharedTransport
+	retry      RetryPolicy
+	limiter    *rate.Limiter
+	cacheTTLs  CacheTTLs
+	breaker    *circuitBreaker
+	readOnly   bool
+
+	slowCallThreshold time.Duration
+}
+
+type Options struct {
+	// AuthMode selects between a user's personal access token (the default)
+	// and Kanboard's application API token, in which case requests are sent
+	// as the "jsonrpc" user and scoped to username by the client.
+	AuthMode string
+	// AuthHeader chooses the standard Authorization header or Kanboard's
+	// X-API-Auth header for setups where a proxy strips Authorization.
+	AuthHeader string
+	Timeout    time.Duration
+	TLSConfig  *tls.Config
+	Retry      RetryPolicy
+	RateLimit  RateLimit
+	Cache      CacheTTLs
+	Breaker    BreakerSettings
+	Transport  TransportSettings
+	// SlowCallThreshold logs requests that take at least this long; zero
+	// disables slow-call logging.
+	SlowCallThreshold time.Duration
+	// ReadOnly refuses every method that is not a get or search call.
+	ReadOnly bool
 }
 
-func NewClient(baseURL, username, token string) *Client {
+func NewClient(baseURL, username, token string, opts Options) *Client {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+
+	transport := transportFor(baseURL, opts.TLSConfig, opts.Transport)
+
+	httpClient := &http.Client{
+		Timeout:   timeout,
+		Transport: transport.transport,
+	}
+
 	return &Client{
-		baseURL:  baseURL,
-		username: username,
-		token:    token,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		baseURL:    baseURL,
+		username:   username,
+		token:      token,
+		authMode:   opts.AuthMode,
+		authHeader: opts.AuthHeader,
+		httpClient: httpClient,
+		transport:  transport,
+		retry:      opts.Retry,
+		limiter:    limiterFor(baseURL, opts.RateLimit),
+		cacheTTLs:  opts.Cache,
+		breaker:    breakerFor(baseURL, opts.Breaker),
+		readOnly:   opts.ReadOnly,
+
+		slowCallThreshold: opts.SlowCallThreshold,
 	}
 }
 
-func (c *Client) makeRequest(method string, params interface{}) (*models.JSONRPCResponse, error) {
+// BaseURL returns the Kanboard instance the client talks to.
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+func (c *Client) makeRequest(ctx context.Context, method string, params interface{}) (*models.JSONRPCResponse, error) {
+	if err := c.checkWritable(method); err != nil {
+		return nil, err
+	}
+
+	if result, ok := c.cachedResult(method, params); ok {
+		trace.SpanFromContext(ctx).AddEvent("kanboard cache hit", trace.WithAttributes(attribute.String("rpc.method", method)))
+		return &models.JSONRPCResponse{JSONRpc: "2.0", ID: 1, Result: result}, nil
+	}
+
 	req := &models.JSONRPCRequest{
 		JSONRpc: "2.0",
 		Method:  method,
@@ -44,44 +123,163 @@ func (c *Client) makeRequest(method string, params interface{}) (*models.JSONRPC
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequest("POST", c.baseURL+"/jsonrpc.php", bytes.NewBuffer(jsonData))
+	var projectIDs []int
+	if projectID := projectIDFromParams(params); projectID > 0 {
+		projectIDs = []int{projectID}
+	}
+
+	start := time.Now()
+	body, err := c.send(ctx, method, isIdempotent(method), jsonData)
+	c.observeCall(ctx, method, projectIDs, time.Since(start))
+	if err != nil {
+		return nil, err
+	}
+
+	var jsonRPCResp models.JSONRPCResponse
+	if err := json.Unmarshal(body, &jsonRPCResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	if jsonRPCResp.Error != nil {
+		return nil, jsonRPCError(method, jsonRPCResp.Error.Code, jsonRPCResp.Error.Message)
+	}
+
+	if isIdempotent(method) {
+		c.storeResult(method, params, jsonRPCResp.Result)
+	} else if projectID := projectIDFromParams(params); projectID > 0 {
+		c.InvalidateProject(projectID)
+	}
+
+	return &jsonRPCResp, nil
+}
+
+// checkWritable refuses mutating methods on a read-only client before
+// anything is sent to Kanboard.
+func (c *Client) checkWritable(method string) error {
+	if c.readOnly && !isIdempotent(method) {
+		return &Error{Kind: ErrReadOnly, Method: method, Message: "the server is in read-only mode"}
+	}
+	return nil
+}
+
+// send posts a JSON-RPC payload and returns the raw response body, retrying
+// transient failures when the payload is safe to repeat.
+func (c *Client) send(ctx context.Context, label string, idempotent bool, payload []byte) (body []byte, err error) {
+	ctx, span := tracing.Start(ctx, "kanboard "+label,
+		attribute.String("rpc.system", "jsonrpc"),
+		attribute.String("rpc.method", label),
+		attribute.String("kanboard.instance", InstanceKey(c.baseURL)),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	if c.breaker == nil {
+		return c.sendWithRetry(ctx, label, idempotent, payload)
+	}
+
+	if err := c.breaker.allow(InstanceKey(c.baseURL)); err != nil {
+		return nil, err
+	}
+
+	body, err = c.sendWithRetry(ctx, label, idempotent, payload)
+	c.breaker.record(ctx, err)
+	return body, err
+}
+
+func (c *Client) sendWithRetry(ctx context.Context, label string, idempotent bool, payload []byte) ([]byte, error) {
+	maxAttempts := 1
+	if idempotent {
+		maxAttempts += c.retry.MaxRetries
+	}
+
+	for attempt := 1; ; attempt++ {
+		body, err := c.doRequest(ctx, payload)
+		if err == nil {
+			if attempt > 1 {
+				logging.DebugfContext(ctx, "kanboard %s succeeded after %d retries", label, attempt-1)
+			}
+			return body, nil
+		}
+
+		if attempt >= maxAttempts || !isRetryable(err) || ctx.Err() != nil {
+			if attempt > 1 {
+				logging.DebugfContext(ctx, "kanboard %s failed after %d retries: %v", label, attempt-1, err)
+			}
+			return nil, err
+		}
+
+		delay := c.retry.backoff(attempt)
+		logging.DebugfContext(ctx, "kanboard %s attempt %d failed (%v), retrying in %s", label, attempt, err, delay)
+
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (c *Client) doRequest(ctx context.Context, jsonData []byte) ([]byte, error) {
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("rate limit wait failed: %w", err)
+		}
+	}
+
+	traceCtx := httptrace.WithClientTrace(ctx, c.transport.clientTrace)
+	httpReq, err := http.NewRequestWithContext(traceCtx, "POST", c.baseURL+"/jsonrpc.php", bytes.NewReader(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
 	}
 
-	auth := base64.StdEncoding.EncodeToString([]byte(c.username + ":" + c.token))
-	httpReq.Header.Set("Authorization", "Basic "+auth)
+	c.setAuthHeader(httpReq)
 	httpReq.Header.Set("Content-Type", "application/json")
+	if requestID := logging.RequestID(ctx); requestID != "" {
+		httpReq.Header.Set("X-Request-ID", requestID)
+	}
 
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make HTTP request: %w", err)
+		return nil, &retryableError{err: &Error{Kind: ErrUnavailable, Message: "failed to make HTTP request", Err: err}}
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP error: %s", resp.Status)
+		io.Copy(io.Discard, resp.Body)
+		err := httpStatusError(resp.StatusCode, resp.Status)
+		if isRetryableStatus(resp.StatusCode) {
+			return nil, &retryableError{err: err}
+		}
+		return nil, err
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, &retryableError{err: fmt.Errorf("failed to read response body: %w", err)}
 	}
 
-	var jsonRPCResp models.JSONRPCResponse
-	if err := json.Unmarshal(body, &jsonRPCResp); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	return body, nil
+}
+
+func (c *Client) setAuthHeader(req *http.Request) {
+	username := c.username
+	if c.isAppMode() {
+		username = "jsonrpc"
 	}
 
-	if jsonRPCResp.Error != nil {
-		return nil, fmt.Errorf("JSON-RPC error: %s", jsonRPCResp.Error.Message)
+	credentials := base64.StdEncoding.EncodeToString([]byte(username + ":" + c.token))
+
+	if c.authHeader == AuthHeaderXAPIAuth {
+		req.Header.Set("X-API-Auth", credentials)
+		return
 	}
 
-	return &jsonRPCResp, nil
+	req.Header.Set("Authorization", "Basic "+credentials)
+}
+
+func (c *Client) isAppMode() bool {
+	return c.authMode == models.AuthModeApp
 }
 
-func (c *Client) makeRawRequest(method string, params interface{}) (json.RawMessage, error) {
-	resp, err := c.makeRequest(method, params)
+func (c *Client) makeRawRequest(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
+	resp, err := c.makeRequest(ctx, method, params)
 	if err != nil {
 		return nil, err
 	}
@@ -94,25 +292,46 @@ func (c *Client) makeRawRequest(method string, params interface{}) (json.RawMess
 	return json.RawMessage(data), nil
 }
 
-func (c *Client) GetMyProjectsRaw() (json.RawMessage, error) {
-	return c.makeRawRequest("getMyProjects", nil)
+func (c *Client) GetMyProjectsRaw(ctx context.Context) (json.RawMessage, error) {
+	if c.isAppMode() {
+		return c.getMemberProjectsRaw(ctx)
+	}
+	return c.makeRawRequest(ctx, "getMyProjects", nil)
+}
+
+func (c *Client) GetProjectByIDRaw(ctx context.Context, projectID int) (map[string]interface{}, error) {
+	resp, err := c.makeRequest(ctx, "getProjectById", map[string]interface{}{"project_id": projectID})
+	if err != nil {
+		return nil, err
+	}
+
+	var project map[string]interface{}
+	if err := c.unmarshalResult(resp.Result, &project); err != nil {
+		return nil, err
+	}
+
+	return project, nil
 }
 
-func (c *Client) GetProjectUsers(projectID int) ([]models.KanboardUser, error) {
-	resp, err := c.makeRequest("getProjectUsers", map[string]interface{}{"project_id": projectID})
+func (c *Client) GetProjectUsers(ctx context.Context, projectID int) ([]models.KanboardUser, error) {
+	resp, err := c.makeRequest(ctx, "getProjectUsers", map[string]interface{}{"project_id": projectID})
 	if err != nil {
 		return nil, err
 	}
 
+	return c.parseProjectUsers(resp.Result)
+}
+
+func (c *Client) parseProjectUsers(result interface{}) ([]models.KanboardUser, error) {
 	var users []models.KanboardUser
 
-	if err := c.unmarshalResult(resp.Result, &users); err != nil {
+	if err := c.unmarshalResult(result, &users); err != nil {
 
 		var userMap map[string]string
-		if mapErr := c.unmarshalResult(resp.Result, &userMap); mapErr != nil {
+		if mapErr := c.unmarshalResult(result, &userMap); mapErr != nil {
 
 			var interfaceMap map[string]interface{}
-			if interfaceErr := c.unmarshalResult(resp.Result, &interfaceMap); interfaceErr != nil {
+			if interfaceErr := c.unmarshalResult(result, &interfaceMap); interfaceErr != nil {
 				return nil, fmt.Errorf("failed to unmarshal as array: %w, as string map: %w, as interface map: %w", err, mapErr, interfaceErr)
 			}
 
@@ -161,8 +380,8 @@ func (c *Client) GetProjectUsers(projectID int) ([]models.KanboardUser, error) {
 	return users, nil
 }
 
-func (c *Client) GetTasksByProject(projectID int) ([]models.Task, error) {
-	resp, err := c.makeRequest("getAllTasks", map[string]interface{}{"project_id": projectID})
+func (c *Client) GetTasksByProject(ctx context.Context, projectID int) ([]models.Task, error) {
+	resp, err := c.makeRequest(ctx, "getAllTasks", map[string]interface{}{"project_id": projectID})
 	if err != nil {
 		return nil, err
 	}
@@ -175,8 +394,8 @@ func (c *Client) GetTasksByProject(projectID int) ([]models.Task, error) {
 	return tasks, nil
 }
 
-func (c *Client) GetColumns(projectID int) ([]models.Column, error) {
-	resp, err := c.makeRequest("getColumns", map[string]interface{}{"project_id": projectID})
+func (c *Client) GetColumns(ctx context.Context, projectID int) ([]models.Column, error) {
+	resp, err := c.makeRequest(ctx, "getColumns", map[string]interface{}{"project_id": projectID})
 	if err != nil {
 		return nil, err
 	}
@@ -189,8 +408,8 @@ func (c *Client) GetColumns(projectID int) ([]models.Column, error) {
 	return columns, nil
 }
 
-func (c *Client) GetSwimlanes(projectID int) ([]models.Swimlane, error) {
-	resp, err := c.makeRequest("getAllSwimlanes", map[string]interface{}{"project_id": projectID})
+func (c *Client) GetSwimlanes(ctx context.Context, projectID int) ([]models.Swimlane, error) {
+	resp, err := c.makeRequest(ctx, "getAllSwimlanes", map[string]interface{}{"project_id": projectID})
 	if err != nil {
 		return nil, err
 	}
@@ -203,8 +422,13 @@ func (c *Client) GetSwimlanes(projectID int) ([]models.Swimlane, error) {
 	return swimlanes, nil
 }
 
-func (c *Client) GetMe() (*models.KanboardUser, error) {
-	resp, err := c.makeRequest("getMe", nil)
+func (c *Client) GetMe(ctx context.Context) (*models.KanboardUser, error) {
+	method, params := "getMe", interface{}(nil)
+	if c.isAppMode() {
+		method, params = "getUserByName", map[string]interface{}{"username": c.username}
+	}
+
+	resp, err := c.makeRequest(ctx, method, params)
 	if err != nil {
 		return nil, err
 	}
@@ -217,6 +441,20 @@ func (c *Client) GetMe() (*models.KanboardUser, error) {
 	return &user, nil
 }
 
+func (c *Client) GetVersion(ctx context.Context) (string, error) {
+	resp, err := c.makeRequest(ctx, "getVersion", nil)
+	if err != nil {
+		return "", err
+	}
+
+	var version string
+	if err := c.unmarshalResult(resp.Result, &version); err != nil {
+		return "", err
+	}
+
+	return version, nil
+}
+
 func (c *Client) unmarshalResult(result interface{}, target interface{}) error {
 	data, err := json.Marshal(result)
 	if err != nil {
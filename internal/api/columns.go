@@ -0,0 +1,46 @@
+package api
+
+import (
+	"context"
+)
+
+// AddColumn creates a column at the right of a project's board and returns
+// its ID. A taskLimit of zero means no WIP limit.
+func (c *Client) AddColumn(ctx context.Context, projectID int, title string, taskLimit int, description string) (int, error) {
+	return c.create(ctx, "addColumn", map[string]interface{}{
+		"project_id":  projectID,
+		"title":       title,
+		"task_limit":  taskLimit,
+		"description": description,
+	})
+}
+
+// UpdateColumn sets a column's title, WIP limit and description. Kanboard
+// resets whichever of them is left out, so all three are always sent.
+func (c *Client) UpdateColumn(ctx context.Context, projectID, columnID int, title string, taskLimit int, description string) error {
+	_, err := c.mutate(ctx, "updateColumn", map[string]interface{}{
+		"column_id":   columnID,
+		"title":       title,
+		"task_limit":  taskLimit,
+		"description": description,
+	})
+	if err != nil {
+		return err
+	}
+
+	// updateColumn is keyed by column alone, so the project's cached reads
+	// are not dropped automatically.
+	c.InvalidateProject(projectID)
+	return nil
+}
+
+// ChangeColumnPosition moves a column to position, counting from 1 at the
+// left of the board.
+func (c *Client) ChangeColumnPosition(ctx context.Context, projectID, columnID, position int) error {
+	_, err := c.mutate(ctx, "changeColumnPosition", map[string]interface{}{
+		"project_id": projectID,
+		"column_id":  columnID,
+		"position":   position,
+	})
+	return err
+}
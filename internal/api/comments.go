@@ -0,0 +1,50 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+)
+
+const commentsBatchSize = 50
+
+// GetComments fetches the comments of many tasks, batching the
+// getAllComments calls so each round trip covers up to commentsBatchSize
+// tasks.
+func (c *Client) GetComments(ctx context.Context, taskIDs []int) (map[int][]models.Comment, error) {
+	comments := make(map[int][]models.Comment, len(taskIDs))
+
+	for start := 0; start < len(taskIDs); start += commentsBatchSize {
+		end := min(start+commentsBatchSize, len(taskIDs))
+
+		calls := make([]BatchCall, 0, end-start)
+		for _, taskID := range taskIDs[start:end] {
+			calls = append(calls, BatchCall{
+				Method: "getAllComments",
+				Params: map[string]interface{}{"task_id": taskID},
+			})
+		}
+
+		responses, err := c.makeBatchRequest(ctx, calls)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, resp := range responses {
+			taskID := taskIDs[start+i]
+			result, ok := resp.Result.([]interface{})
+			if !ok {
+				continue
+			}
+
+			var taskComments []models.Comment
+			if err := c.unmarshalResult(result, &taskComments); err != nil {
+				return nil, fmt.Errorf("failed to parse comments for task %d: %w", taskID, err)
+			}
+			comments[taskID] = taskComments
+		}
+	}
+
+	return comments, nil
+}
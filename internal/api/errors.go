@@ -0,0 +1,90 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrNotFound     = errors.New("not found")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrServer       = errors.New("server error")
+	ErrUnavailable  = errors.New("unavailable")
+	ErrReadOnly     = errors.New("read-only")
+	ErrRejected     = errors.New("rejected")
+)
+
+// Error describes a failed Kanboard call. Kind is one of the sentinel errors
+// above so callers can branch with errors.Is without parsing messages.
+type Error struct {
+	Kind       error
+	Method     string
+	StatusCode int
+	Code       int
+	Message    string
+	Err        error
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.Err != nil:
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	case e.Method != "":
+		return fmt.Sprintf("%s (%s)", e.Message, e.Method)
+	default:
+		return e.Message
+	}
+}
+
+func (e *Error) Is(target error) bool {
+	return e.Kind != nil && target == e.Kind
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func httpStatusError(status int, statusText string) *Error {
+	err := &Error{
+		StatusCode: status,
+		Message:    fmt.Sprintf("HTTP error: %s", statusText),
+	}
+
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		err.Kind = ErrUnauthorized
+	case status == http.StatusNotFound:
+		err.Kind = ErrNotFound
+	case status == http.StatusTooManyRequests:
+		err.Kind = ErrRateLimited
+	case status >= 500:
+		err.Kind = ErrServer
+	}
+
+	return err
+}
+
+// jsonRPCError classifies an error object returned by Kanboard. Kanboard
+// reports permission failures with HTTP-style codes inside the envelope.
+func jsonRPCError(method string, code int, message string) *Error {
+	err := &Error{
+		Method:  method,
+		Code:    code,
+		Message: fmt.Sprintf("JSON-RPC error: %s", message),
+	}
+
+	switch code {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		err.Kind = ErrUnauthorized
+	case http.StatusNotFound, -32601:
+		err.Kind = ErrNotFound
+	case http.StatusTooManyRequests:
+		err.Kind = ErrRateLimited
+	case -32603:
+		err.Kind = ErrServer
+	}
+
+	return err
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+)
+
+const linksBatchSize = 50
+
+// GetTaskLinks fetches the internal links of many tasks, batching the
+// getAllTaskLinks calls so each round trip covers up to linksBatchSize tasks.
+func (c *Client) GetTaskLinks(ctx context.Context, taskIDs []int) (map[int][]models.TaskLink, error) {
+	links := make(map[int][]models.TaskLink, len(taskIDs))
+
+	for start := 0; start < len(taskIDs); start += linksBatchSize {
+		end := min(start+linksBatchSize, len(taskIDs))
+
+		calls := make([]BatchCall, 0, end-start)
+		for _, taskID := range taskIDs[start:end] {
+			calls = append(calls, BatchCall{
+				Method: "getAllTaskLinks",
+				Params: map[string]interface{}{"task_id": taskID},
+			})
+		}
+
+		responses, err := c.makeBatchRequest(ctx, calls)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, resp := range responses {
+			taskID := taskIDs[start+i]
+			result, ok := resp.Result.([]interface{})
+			if !ok {
+				continue
+			}
+
+			var taskLinks []models.TaskLink
+			if err := c.unmarshalResult(result, &taskLinks); err != nil {
+				return nil, fmt.Errorf("failed to parse links for task %d: %w", taskID, err)
+			}
+			links[taskID] = taskLinks
+		}
+	}
+
+	return links, nil
+}
+
+func (c *Client) GetExternalTaskLinks(ctx context.Context, taskID int) ([]models.ExternalLink, error) {
+	resp, err := c.makeRequest(ctx, "getExternalTaskLinks", map[string]interface{}{
+		"task_id": taskID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var links []models.ExternalLink
+	if result, ok := resp.Result.([]interface{}); ok {
+		if err := c.unmarshalResult(result, &links); err != nil {
+			return nil, err
+		}
+	}
+
+	return links, nil
+}
+
+// CreateExternalTaskLink attaches url to a task and returns the link's ID.
+// linkType "auto" lets Kanboard choose between a web link and an attachment,
+// and an empty title lets it fill one in.
+func (c *Client) CreateExternalTaskLink(ctx context.Context, taskID int, url, dependency, linkType, title string) (int, error) {
+	params := map[string]interface{}{
+		"task_id":    taskID,
+		"url":        url,
+		"dependency": dependency,
+		"type":       linkType,
+	}
+	if title != "" {
+		params["title"] = title
+	}
+	return c.create(ctx, "createExternalTaskLink", params)
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+)
+
+// GetUser looks a Kanboard user up by ID.
+func (c *Client) GetUser(ctx context.Context, userID int) (*models.KanboardUser, error) {
+	return c.getUser(ctx, "getUser", map[string]interface{}{"user_id": userID}, fmt.Sprintf("user %d not found", userID))
+}
+
+// GetUserByName looks a Kanboard user up by username.
+func (c *Client) GetUserByName(ctx context.Context, username string) (*models.KanboardUser, error) {
+	return c.getUser(ctx, "getUserByName", map[string]interface{}{"username": username}, fmt.Sprintf("no user named %q", username))
+}
+
+func (c *Client) getUser(ctx context.Context, method string, params map[string]interface{}, notFound string) (*models.KanboardUser, error) {
+	resp, err := c.makeRequest(ctx, method, params)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Result == nil {
+		return nil, &Error{Kind: ErrNotFound, Method: method, Message: notFound}
+	}
+	if ok, isBool := resp.Result.(bool); isBool && !ok {
+		return nil, &Error{Kind: ErrNotFound, Method: method, Message: notFound}
+	}
+
+	var user models.KanboardUser
+	if err := c.unmarshalResult(resp.Result, &user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// AddProjectUser makes a user a member of a project with the given role,
+// such as project-member.
+func (c *Client) AddProjectUser(ctx context.Context, projectID, userID int, role string) error {
+	_, err := c.mutate(ctx, "addProjectUser", map[string]interface{}{
+		"project_id": projectID,
+		"user_id":    userID,
+		"role":       role,
+	})
+	return err
+}
+
+// RemoveProjectUser takes a user's direct membership of a project away. Roles
+// the user has through groups are left alone.
+func (c *Client) RemoveProjectUser(ctx context.Context, projectID, userID int) error {
+	_, err := c.mutate(ctx, "removeProjectUser", map[string]interface{}{
+		"project_id": projectID,
+		"user_id":    userID,
+	})
+	return err
+}
+
+// ChangeProjectUserRole changes the role of a user who is already a direct
+// member of a project.
+func (c *Client) ChangeProjectUserRole(ctx context.Context, projectID, userID int, role string) error {
+	_, err := c.mutate(ctx, "changeProjectUserRole", map[string]interface{}{
+		"project_id": projectID,
+		"user_id":    userID,
+		"role":       role,
+	})
+	return err
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"net/url"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+type RateLimit struct {
+	RequestsPerSecond float64
+	Burst             int
+}
+
+var (
+	limitersMu sync.Mutex
+	limiters   = make(map[string]*rate.Limiter)
+)
+
+// limiterFor returns the token bucket shared by every client talking to the
+// same Kanboard instance, so concurrent tool calls are throttled together.
+func limiterFor(baseURL string, limit RateLimit) *rate.Limiter {
+	if limit.RequestsPerSecond <= 0 {
+		return nil
+	}
+
+	burst := limit.Burst
+	if burst <= 0 {
+		burst = 1
+	}
+
+	key := InstanceKey(baseURL)
+
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+
+	limiter, exists := limiters[key]
+	if !exists {
+		limiter = rate.NewLimiter(rate.Limit(limit.RequestsPerSecond), burst)
+		limiters[key] = limiter
+		return limiter
+	}
+
+	if limiter.Limit() != rate.Limit(limit.RequestsPerSecond) {
+		limiter.SetLimit(rate.Limit(limit.RequestsPerSecond))
+	}
+	if limiter.Burst() != burst {
+		limiter.SetBurst(burst)
+	}
+
+	return limiter
+}
+
+// InstanceKey identifies the Kanboard instance behind a URL; rate limits,
+// circuit breakers, transports and the cache are shared per key.
+func InstanceKey(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Host == "" {
+		return baseURL
+	}
+	return u.Scheme + "://" + u.Host
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+)
+
+const rolesBatchSize = 50
+
+// GetProjectUserRoles looks up one user's role in many projects. Projects
+// where the user has no direct role map to an empty string.
+func (c *Client) GetProjectUserRoles(ctx context.Context, projectIDs []int, userID int) (map[int]string, error) {
+	pairs := make([]projectUser, len(projectIDs))
+	for i, projectID := range projectIDs {
+		pairs[i] = projectUser{projectID: projectID, userID: userID}
+	}
+	found, err := c.projectUserRoles(ctx, pairs)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make(map[int]string, len(projectIDs))
+	for i, projectID := range projectIDs {
+		roles[projectID] = found[i]
+	}
+	return roles, nil
+}
+
+// GetProjectMemberRoles looks up many users' roles in one project. Users with
+// no role map to an empty string.
+func (c *Client) GetProjectMemberRoles(ctx context.Context, projectID int, userIDs []int) (map[int]string, error) {
+	pairs := make([]projectUser, len(userIDs))
+	for i, userID := range userIDs {
+		pairs[i] = projectUser{projectID: projectID, userID: userID}
+	}
+	found, err := c.projectUserRoles(ctx, pairs)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make(map[int]string, len(userIDs))
+	for i, userID := range userIDs {
+		roles[userID] = found[i]
+	}
+	return roles, nil
+}
+
+type projectUser struct {
+	projectID int
+	userID    int
+}
+
+// projectUserRoles batches a getProjectUserRole call for each pair and
+// returns the roles in the same order.
+func (c *Client) projectUserRoles(ctx context.Context, pairs []projectUser) ([]string, error) {
+	roles := make([]string, len(pairs))
+
+	for start := 0; start < len(pairs); start += rolesBatchSize {
+		end := min(start+rolesBatchSize, len(pairs))
+
+		calls := make([]BatchCall, 0, end-start)
+		for _, pair := range pairs[start:end] {
+			calls = append(calls, BatchCall{
+				Method: "getProjectUserRole",
+				Params: map[string]interface{}{"project_id": pair.projectID, "user_id": pair.userID},
+			})
+		}
+
+		responses, err := c.makeBatchRequest(ctx, calls)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, resp := range responses {
+			role, _ := resp.Result.(string)
+			roles[start+i] = role
+		}
+	}
+
+	return roles, nil
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/logging"
+)
+
+// SlowCallStats aggregates the JSON-RPC requests for one method (or batch of
+// methods) that exceeded the slow-call threshold since the last summary.
+type SlowCallStats struct {
+	Method       string
+	Count        int
+	Total        time.Duration
+	Max          time.Duration
+	MaxProjectID int
+}
+
+var (
+	slowCallsMu sync.Mutex
+	slowCalls   = make(map[string]*SlowCallStats)
+)
+
+// observeCall logs a request that took longer than the client's slow-call
+// threshold and adds it to the summary returned by TakeSlowCallStats. The
+// elapsed time includes rate-limit waits and retries.
+func (c *Client) observeCall(ctx context.Context, method string, projectIDs []int, elapsed time.Duration) {
+	if c.slowCallThreshold <= 0 || elapsed < c.slowCallThreshold {
+		return
+	}
+
+	var projects string
+	if len(projectIDs) > 0 {
+		ids := make([]string, len(projectIDs))
+		for i, id := range projectIDs {
+			ids[i] = fmt.Sprint(id)
+		}
+		projects = " (project " + strings.Join(ids, ",") + ")"
+	}
+	logging.WarnfContext(ctx, "Slow Kanboard call: %s on %s%s took %s", method, InstanceKey(c.baseURL), projects, elapsed.Round(time.Millisecond))
+
+	slowCallsMu.Lock()
+	defer slowCallsMu.Unlock()
+
+	stats, exists := slowCalls[method]
+	if !exists {
+		stats = &SlowCallStats{Method: method}
+		slowCalls[method] = stats
+	}
+	stats.Count++
+	stats.Total += elapsed
+	if elapsed > stats.Max {
+		stats.Max = elapsed
+		stats.MaxProjectID = 0
+		if len(projectIDs) == 1 {
+			stats.MaxProjectID = projectIDs[0]
+		}
+	}
+}
+
+// TakeSlowCallStats returns the slow calls recorded since the previous call,
+// slowest method first, and starts a new summary period.
+func TakeSlowCallStats() []SlowCallStats {
+	slowCallsMu.Lock()
+	taken := slowCalls
+	slowCalls = make(map[string]*SlowCallStats)
+	slowCallsMu.Unlock()
+
+	stats := make([]SlowCallStats, 0, len(taken))
+	for _, entry := range taken {
+		stats = append(stats, *entry)
+	}
+	slices.SortFunc(stats, func(a, b SlowCallStats) int {
+		if c := cmp.Compare(b.Max, a.Max); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Method, b.Method)
+	})
+	return stats
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+)
+
+const subtasksBatchSize = 50
+
+// GetSubtasks fetches the subtasks of many tasks, batching the
+// getAllSubtasks calls so each round trip covers up to subtasksBatchSize
+// tasks.
+func (c *Client) GetSubtasks(ctx context.Context, taskIDs []int) (map[int][]models.Subtask, error) {
+	subtasks := make(map[int][]models.Subtask, len(taskIDs))
+
+	for start := 0; start < len(taskIDs); start += subtasksBatchSize {
+		end := min(start+subtasksBatchSize, len(taskIDs))
+
+		calls := make([]BatchCall, 0, end-start)
+		for _, taskID := range taskIDs[start:end] {
+			calls = append(calls, BatchCall{
+				Method: "getAllSubtasks",
+				Params: map[string]interface{}{"task_id": taskID},
+			})
+		}
+
+		responses, err := c.makeBatchRequest(ctx, calls)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, resp := range responses {
+			taskID := taskIDs[start+i]
+			result, ok := resp.Result.([]interface{})
+			if !ok {
+				continue
+			}
+
+			var taskSubtasks []models.Subtask
+			if err := c.unmarshalResult(result, &taskSubtasks); err != nil {
+				return nil, fmt.Errorf("failed to parse subtasks for task %d: %w", taskID, err)
+			}
+			subtasks[taskID] = taskSubtasks
+		}
+	}
+
+	return subtasks, nil
+}
+
+// SetSubtaskTimeSpent sets the hours spent on a subtask. Kanboard then
+// recalculates the task's time spent from its subtasks.
+func (c *Client) SetSubtaskTimeSpent(ctx context.Context, projectID, taskID, subtaskID int, hours float64) error {
+	if _, err := c.mutate(ctx, "updateSubtask", map[string]interface{}{
+		"id":         subtaskID,
+		"task_id":    taskID,
+		"time_spent": hours,
+	}); err != nil {
+		return err
+	}
+
+	c.InvalidateProject(projectID)
+	return nil
+}
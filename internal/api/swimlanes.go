@@ -0,0 +1,57 @@
+package api
+
+import (
+	"context"
+)
+
+// AddSwimlane creates a swimlane at the bottom of a project's board and
+// returns its ID.
+func (c *Client) AddSwimlane(ctx context.Context, projectID int, name, description string) (int, error) {
+	params := map[string]interface{}{
+		"project_id": projectID,
+		"name":       name,
+	}
+	if description != "" {
+		params["description"] = description
+	}
+	return c.create(ctx, "addSwimlane", params)
+}
+
+// UpdateSwimlane renames a swimlane. A nil description leaves it unchanged.
+func (c *Client) UpdateSwimlane(ctx context.Context, projectID, swimlaneID int, name string, description *string) error {
+	params := map[string]interface{}{
+		"project_id":  projectID,
+		"swimlane_id": swimlaneID,
+		"name":        name,
+	}
+	if description != nil {
+		params["description"] = *description
+	}
+	_, err := c.mutate(ctx, "updateSwimlane", params)
+	return err
+}
+
+// ChangeSwimlanePosition moves a swimlane to position, counting from 1 at
+// the top of the board.
+func (c *Client) ChangeSwimlanePosition(ctx context.Context, projectID, swimlaneID, position int) error {
+	_, err := c.mutate(ctx, "changeSwimlanePosition", map[string]interface{}{
+		"project_id":  projectID,
+		"swimlane_id": swimlaneID,
+		"position":    position,
+	})
+	return err
+}
+
+// SetSwimlaneActive enables or disables a swimlane. Tasks in a disabled
+// swimlane are hidden from the board.
+func (c *Client) SetSwimlaneActive(ctx context.Context, projectID, swimlaneID int, active bool) error {
+	method := "disableSwimlane"
+	if active {
+		method = "enableSwimlane"
+	}
+	_, err := c.mutate(ctx, method, map[string]interface{}{
+		"project_id":  projectID,
+		"swimlane_id": swimlaneID,
+	})
+	return err
+}
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"context"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+)
+
+func (c *Client) GetTagsByProject(ctx context.Context, projectID int) ([]models.Tag, error) {
+	resp, err := c.makeRequest(ctx, "getTagsByProject", map[string]interface{}{
+		"project_id": projectID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []models.Tag
+	if err := c.unmarshalResult(resp.Result, &tags); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
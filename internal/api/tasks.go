@@ -0,0 +1,225 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+)
+
+const (
+	TaskStatusClosed = 0
+	TaskStatusOpen   = 1
+)
+
+const defaultTaskWindow = 30 * 24 * time.Hour
+
+// TaskQuery selects which closed tasks StreamClosedTasks fetches. Closed
+// tasks last modified before ModifiedSince are skipped; when it is set they
+// are fetched with one search and delivered in Window-sized
+// date_modification slices, each task in exactly one.
+type TaskQuery struct {
+	ModifiedSince time.Time
+	Window        time.Duration
+}
+
+func (c *Client) GetTasksByStatus(ctx context.Context, projectID, statusID int) ([]models.Task, error) {
+	resp, err := c.makeRequest(ctx, "getAllTasks", map[string]interface{}{
+		"project_id": projectID,
+		"status_id":  statusID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []models.Task
+	if err := c.unmarshalResult(resp.Result, &tasks); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// GetTask fetches one task. Kanboard answers null for unknown tasks, which
+// is reported as ErrNotFound.
+func (c *Client) GetTask(ctx context.Context, taskID int) (*models.Task, error) {
+	resp, err := c.makeRequest(ctx, "getTask", map[string]interface{}{
+		"task_id": taskID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Result == nil {
+		return nil, &Error{Kind: ErrNotFound, Method: "getTask", Message: fmt.Sprintf("task %d not found", taskID)}
+	}
+
+	var task models.Task
+	if err := c.unmarshalResult(resp.Result, &task); err != nil {
+		return nil, err
+	}
+
+	return &task, nil
+}
+
+// SetTaskTimeSpent sets the hours spent on a task.
+func (c *Client) SetTaskTimeSpent(ctx context.Context, projectID, taskID int, hours float64) error {
+	if _, err := c.mutate(ctx, "updateTask", map[string]interface{}{
+		"id":         taskID,
+		"time_spent": hours,
+	}); err != nil {
+		return err
+	}
+
+	c.InvalidateProject(projectID)
+	return nil
+}
+
+// TaskPlacement says where a task copied or moved to another project goes.
+type TaskPlacement struct {
+	ProjectID  int
+	ColumnID   int
+	SwimlaneID int
+	CategoryID int
+	OwnerID    int
+}
+
+func (p TaskPlacement) params(taskID int) map[string]interface{} {
+	return map[string]interface{}{
+		"task_id":     taskID,
+		"project_id":  p.ProjectID,
+		"column_id":   p.ColumnID,
+		"swimlane_id": p.SwimlaneID,
+		"category_id": p.CategoryID,
+		"owner_id":    p.OwnerID,
+	}
+}
+
+// DuplicateTaskToProject copies a task to the placement and returns the new
+// task's ID.
+func (c *Client) DuplicateTaskToProject(ctx context.Context, taskID int, placement TaskPlacement) (int, error) {
+	return c.create(ctx, "duplicateTaskToProject", placement.params(taskID))
+}
+
+// MoveTaskToProject moves a task from sourceProjectID to the placement.
+func (c *Client) MoveTaskToProject(ctx context.Context, taskID, sourceProjectID int, placement TaskPlacement) error {
+	if _, err := c.mutate(ctx, "moveTaskToProject", placement.params(taskID)); err != nil {
+		return err
+	}
+
+	c.InvalidateProject(sourceProjectID)
+	return nil
+}
+
+// TaskRecurrence is a task's recurrence settings, using the
+// models.Recurrence* values.
+type TaskRecurrence struct {
+	Status    int
+	Trigger   int
+	Factor    int
+	Timeframe int
+	Basedate  int
+}
+
+func (c *Client) SetTaskRecurrence(ctx context.Context, projectID, taskID int, recurrence TaskRecurrence) error {
+	if _, err := c.mutate(ctx, "updateTask", map[string]interface{}{
+		"id":                   taskID,
+		"recurrence_status":    recurrence.Status,
+		"recurrence_trigger":   recurrence.Trigger,
+		"recurrence_factor":    recurrence.Factor,
+		"recurrence_timeframe": recurrence.Timeframe,
+		"recurrence_basedate":  recurrence.Basedate,
+	}); err != nil {
+		return err
+	}
+
+	c.InvalidateProject(projectID)
+	return nil
+}
+
+func (c *Client) SearchTasks(ctx context.Context, projectID int, query string) ([]models.Task, error) {
+	resp, err := c.makeRequest(ctx, "searchTasks", map[string]interface{}{
+		"project_id": projectID,
+		"query":      query,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []models.Task
+	if err := c.unmarshalResult(resp.Result, &tasks); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// TaskIDsWithTag returns the IDs of a project's tasks, open or closed, that
+// carry the given tag.
+func (c *Client) TaskIDsWithTag(ctx context.Context, projectID int, tag string) (map[int]bool, error) {
+	tasks, err := c.SearchTasks(ctx, projectID, fmt.Sprintf("tag:%q", strings.ReplaceAll(tag, `"`, "")))
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[int]bool, len(tasks))
+	for _, task := range tasks {
+		ids[task.ID] = true
+	}
+
+	return ids, nil
+}
+
+// StreamClosedTasks delivers a project's closed tasks to fn one chunk at a
+// time, so callers can reduce each chunk before the next one is handed over.
+func (c *Client) StreamClosedTasks(ctx context.Context, projectID int, query TaskQuery, fn func([]models.Task) error) error {
+	if query.ModifiedSince.IsZero() {
+		tasks, err := c.GetTasksByStatus(ctx, projectID, TaskStatusClosed)
+		if err != nil {
+			return err
+		}
+		return fn(tasks)
+	}
+
+	// Kanboard ORs repeated attributes in a search, so it cannot bound a
+	// window on both sides. One search from ModifiedSince, a day early to
+	// allow for Kanboard's timezone, fetches each task once; the tasks are
+	// then handed to fn a window at a time.
+	found, err := c.SearchTasks(ctx, projectID, fmt.Sprintf("status:closed modified:>=%s", query.ModifiedSince.AddDate(0, 0, -1).Format("2006-01-02")))
+	if err != nil {
+		return fmt.Errorf("failed to fetch closed tasks modified since %s: %w", query.ModifiedSince.Format("2006-01-02"), err)
+	}
+
+	tasks := found[:0]
+	for _, task := range found {
+		if !task.DateModified.Time.Before(query.ModifiedSince) {
+			tasks = append(tasks, task)
+		}
+	}
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].DateModified.Time.Before(tasks[j].DateModified.Time)
+	})
+
+	window := query.Window
+	if window <= 0 {
+		window = defaultTaskWindow
+	}
+	for len(tasks) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		windowEnd := query.ModifiedSince.Add(window * (tasks[0].DateModified.Time.Sub(query.ModifiedSince)/window + 1))
+		n := sort.Search(len(tasks), func(i int) bool {
+			return !tasks[i].DateModified.Time.Before(windowEnd)
+		})
+		if err := fn(tasks[:n:n]); err != nil {
+			return err
+		}
+		tasks = tasks[n:]
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+)
+
+// closedTasksKanboard answers searchTasks the way Kanboard does for
+// "status:closed modified:>=DATE", counting every task it sends back.
+type closedTasksKanboard struct {
+	modified []int64
+	searches int
+	returned int
+}
+
+var modifiedSince = regexp.MustCompile(`modified:>=(\d{4}-\d{2}-\d{2})`)
+
+func (kb *closedTasksKanboard) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     int    `json:"id"`
+		Method string `json:"method"`
+		Params struct {
+			Query string `json:"query"`
+		} `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Method != "searchTasks" {
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+		return
+	}
+	kb.searches++
+
+	var from int64
+	if match := modifiedSince.FindStringSubmatch(req.Params.Query); match != nil {
+		date, _ := time.Parse("2006-01-02", match[1])
+		from = date.Unix()
+	}
+	tasks := []map[string]interface{}{}
+	for i, modified := range kb.modified {
+		if modified >= from {
+			tasks = append(tasks, map[string]interface{}{"id": i + 1, "is_active": 0, "date_modification": modified})
+		}
+	}
+	kb.returned += len(tasks)
+
+	json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": tasks})
+}
+
+func TestStreamClosedTasksFetchesEachTaskOnce(t *testing.T) {
+	day := 24 * time.Hour
+	since := time.Now().UTC().Truncate(day).Add(-90 * day)
+
+	kb := &closedTasksKanboard{}
+	// Tasks every other day from well before since, so some fall in the day
+	// of slack the search allows and must be dropped.
+	for modified := since.Add(-10 * day); modified.Before(time.Now()); modified = modified.Add(2 * day) {
+		kb.modified = append(kb.modified, modified.Add(time.Hour).Unix())
+	}
+	server := httptest.NewServer(kb)
+	defer server.Close()
+
+	client := NewClient(server.URL, "user1", "token", Options{})
+	query := TaskQuery{ModifiedSince: since, Window: 7 * day}
+
+	seen := make(map[int]int)
+	chunks := 0
+	err := client.StreamClosedTasks(context.Background(), 1, query, func(tasks []models.Task) error {
+		chunks++
+		if len(tasks) == 0 {
+			t.Error("empty chunk delivered")
+			return nil
+		}
+		window := tasks[0].DateModified.Time.Sub(since) / query.Window
+		for _, task := range tasks {
+			seen[task.ID]++
+			if task.DateModified.Time.Before(since) {
+				t.Errorf("task %d modified %s, before %s", task.ID, task.DateModified.Time, since)
+			}
+			if task.DateModified.Time.Sub(since)/query.Window != window {
+				t.Errorf("task %d is in the wrong window", task.ID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := 0
+	for _, modified := range kb.modified {
+		if modified >= since.Unix() {
+			want++
+		}
+	}
+	if len(seen) != want {
+		t.Errorf("delivered %d tasks, want %d", len(seen), want)
+	}
+	for id, count := range seen {
+		if count != 1 {
+			t.Errorf("task %d delivered %d times", id, count)
+		}
+	}
+	if kb.searches != 1 {
+		t.Errorf("%d searches, want 1", kb.searches)
+	}
+	// Only the day of slack before since may be fetched on top of the tasks
+	// delivered.
+	if kb.returned > want+1 {
+		t.Errorf("Kanboard returned %d tasks across all windows for %d wanted", kb.returned, want)
+	}
+	if chunks < 12 {
+		t.Errorf("%d chunks, want one per week", chunks)
+	}
+}
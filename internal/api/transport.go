@@ -0,0 +1,102 @@
+package api
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type TransportSettings struct {
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+}
+
+type ConnectionStats struct {
+	Instance          string `json:"instance"`
+	NewConnections    int64  `json:"new_connections"`
+	ReusedConnections int64  `json:"reused_connections"`
+}
+
+type sharedTransport struct {
+	transport   *http.Transport
+	clientTrace *httptrace.ClientTrace
+	newConns    atomic.Int64
+	reused      atomic.Int64
+}
+
+var (
+	transportsMu sync.Mutex
+	transports   = make(map[string]*sharedTransport)
+)
+
+// transportFor returns the transport shared by every client talking to the
+// same Kanboard instance so that keep-alive connections are reused across
+// tool calls instead of being rebuilt for each short-lived Client.
+func transportFor(baseURL string, tlsConfig *tls.Config, settings TransportSettings) *sharedTransport {
+	key := InstanceKey(baseURL)
+
+	transportsMu.Lock()
+	defer transportsMu.Unlock()
+
+	if shared, exists := transports[key]; exists {
+		return shared
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig.Clone()
+	}
+	if settings.MaxIdleConns > 0 {
+		transport.MaxIdleConns = settings.MaxIdleConns
+	}
+	if settings.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = settings.MaxIdleConnsPerHost
+	}
+	if settings.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = settings.IdleConnTimeout
+	}
+
+	shared := &sharedTransport{transport: transport}
+	shared.clientTrace = shared.newTrace()
+	transports[key] = shared
+	return shared
+}
+
+func (st *sharedTransport) newTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				st.reused.Add(1)
+			} else {
+				st.newConns.Add(1)
+			}
+		},
+	}
+}
+
+// TransportStats reports how many Kanboard connections were opened versus
+// reused, per instance.
+func TransportStats() []ConnectionStats {
+	transportsMu.Lock()
+	defer transportsMu.Unlock()
+
+	stats := make([]ConnectionStats, 0, len(transports))
+	for instance, shared := range transports {
+		stats = append(stats, ConnectionStats{
+			Instance:          instance,
+			NewConnections:    shared.newConns.Load(),
+			ReusedConnections: shared.reused.Load(),
+		})
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Instance < stats[j].Instance
+	})
+
+	return stats
+}
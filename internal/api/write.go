@@ -0,0 +1,36 @@
+package api
+
+import (
+	"context"
+)
+
+// mutate calls a Kanboard method that changes data. Kanboard reports a
+// refused change, such as a duplicate name, by returning false rather than
+// an error, so that is turned into ErrRejected. The result is returned for
+// methods that answer with the ID of what they created.
+func (c *Client) mutate(ctx context.Context, method string, params map[string]interface{}) (interface{}, error) {
+	resp, err := c.makeRequest(ctx, method, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok, isBool := resp.Result.(bool); resp.Result == nil || (isBool && !ok) {
+		return nil, &Error{Kind: ErrRejected, Method: method, Message: "Kanboard refused the change"}
+	}
+
+	return resp.Result, nil
+}
+
+// create calls a Kanboard method that answers with the ID of what it made.
+func (c *Client) create(ctx context.Context, method string, params map[string]interface{}) (int, error) {
+	result, err := c.mutate(ctx, method, params)
+	if err != nil {
+		return 0, err
+	}
+
+	id := rawID(result)
+	if id <= 0 {
+		return 0, &Error{Kind: ErrRejected, Method: method, Message: "Kanboard refused the change"}
+	}
+	return id, nil
+}
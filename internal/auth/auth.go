@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/tech-arch1tect/kan-mcp/internal/models"
@@ -11,8 +12,12 @@ import (
 )
 
 type AuthManager struct {
-	encryptor *encryption.Encryptor
-	userStore UserStore
+	mu                 sync.RWMutex
+	encryptor          *encryption.Encryptor
+	key                []byte
+	previous           []*encryption.Encryptor
+	userStore          UserStore
+	credentialLifetime time.Duration
 }
 
 type UserStore interface {
@@ -30,6 +35,7 @@ func NewAuthManager(encryptionKey []byte, userStore UserStore) (*AuthManager, er
 
 	return &AuthManager{
 		encryptor: encryptor,
+		key:       encryptionKey,
 		userStore: userStore,
 	}, nil
 }
@@ -41,7 +47,7 @@ func (a *AuthManager) RegisterUser(kanboardURL, kanboardUsername, kanboardToken
 		return nil, fmt.Errorf("failed to generate user ID: %w", err)
 	}
 
-	encryptedToken, err := a.encryptor.Encrypt(kanboardToken)
+	encryptedToken, err := a.encrypt(kanboardToken)
 	if err != nil {
 		return nil, fmt.Errorf("failed to encrypt token: %w", err)
 	}
@@ -53,6 +59,33 @@ func (a *AuthManager) RegisterUser(kanboardURL, kanboardUsername, kanboardToken
 		KanboardToken:    encryptedToken,
 		CreatedAt:        time.Now(),
 		LastUsed:         time.Now(),
+		ExpiresAt:        a.newExpiry(),
+	}
+
+	if err := a.userStore.SaveUser(user); err != nil {
+		return nil, fmt.Errorf("failed to save user: %w", err)
+	}
+
+	return user, nil
+}
+
+// RegisterAppUser registers a user who is served through the shared
+// application API token instead of a personal token of their own.
+func (a *AuthManager) RegisterAppUser(kanboardURL, kanboardUsername string) (*models.User, error) {
+
+	userID, err := a.generateUserID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate user ID: %w", err)
+	}
+
+	user := &models.User{
+		UserID:           userID,
+		KanboardURL:      kanboardURL,
+		KanboardUsername: kanboardUsername,
+		AuthMode:         models.AuthModeApp,
+		CreatedAt:        time.Now(),
+		LastUsed:         time.Now(),
+		ExpiresAt:        a.newExpiry(),
 	}
 
 	if err := a.userStore.SaveUser(user); err != nil {
@@ -68,6 +101,10 @@ func (a *AuthManager) AuthenticateUser(userID string) (*models.User, error) {
 		return nil, fmt.Errorf("user not found: %w", err)
 	}
 
+	if err := a.checkExpiry(user); err != nil {
+		return nil, err
+	}
+
 	user.LastUsed = time.Now()
 	if err := a.userStore.SaveUser(user); err != nil {
 		return nil, fmt.Errorf("failed to update user: %w", err)
@@ -76,8 +113,49 @@ func (a *AuthManager) AuthenticateUser(userID string) (*models.User, error) {
 	return user, nil
 }
 
+// UpdateUser changes an existing registration in place, keeping its user ID.
+// Empty arguments leave the corresponding field unchanged.
+func (a *AuthManager) UpdateUser(userID, kanboardURL, kanboardUsername, kanboardToken string) (*models.User, error) {
+	user, err := a.userStore.GetUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	if kanboardURL != "" {
+		user.KanboardURL = kanboardURL
+	}
+	if kanboardUsername != "" {
+		user.KanboardUsername = kanboardUsername
+	}
+	if kanboardToken != "" {
+		if user.AuthMode == models.AuthModeApp {
+			return nil, fmt.Errorf("user is registered for application token auth and has no personal token")
+		}
+		encryptedToken, err := a.encrypt(kanboardToken)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt token: %w", err)
+		}
+		user.KanboardToken = encryptedToken
+	}
+
+	if err := a.userStore.SaveUser(user); err != nil {
+		return nil, fmt.Errorf("failed to save user: %w", err)
+	}
+
+	return user, nil
+}
+
+// GetUser looks up a registration without recording it as used.
+func (a *AuthManager) GetUser(userID string) (*models.User, error) {
+	user, err := a.userStore.GetUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+	return user, nil
+}
+
 func (a *AuthManager) GetDecryptedToken(user *models.User) (string, error) {
-	token, err := a.encryptor.Decrypt(user.KanboardToken)
+	token, err := a.decrypt(user.KanboardToken)
 	if err != nil {
 		return "", fmt.Errorf("failed to decrypt token: %w", err)
 	}
@@ -0,0 +1,181 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+	"github.com/tech-arch1tect/kan-mcp/pkg/encryption"
+)
+
+const bundleHeader = "kan-mcp-users-v1\n"
+
+// ErrUserIDCollision is returned by ImportUsers in ImportModeFail when the
+// bundle contains user IDs that are already registered.
+var ErrUserIDCollision = errors.New("user IDs already registered")
+
+const (
+	// ImportModeFail refuses to import a bundle containing any user ID that
+	// is already registered.
+	ImportModeFail = "fail"
+	// ImportModeMerge keeps existing registrations and imports the rest.
+	ImportModeMerge = "merge"
+	// ImportModeOverwrite replaces existing registrations with the bundle's.
+	ImportModeOverwrite = "overwrite"
+)
+
+type ImportResult struct {
+	Imported    []string
+	Skipped     []string
+	Overwritten []string
+}
+
+// ExportUsers serialises every registration into a bundle encrypted with
+// bundleKey. Tokens are decrypted first so the bundle can be imported on a
+// host with a different encryption key.
+func (a *AuthManager) ExportUsers(bundleKey []byte) (string, int, error) {
+	bundleEncryptor, err := encryption.NewEncryptor(bundleKey)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid bundle key: %w", err)
+	}
+
+	users, err := a.userStore.ListUsers()
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	exported := make([]models.User, 0, len(users))
+	for _, user := range users {
+		record := *user
+		if record.AuthMode != models.AuthModeApp {
+			token, err := a.decrypt(user.KanboardToken)
+			if err != nil {
+				return "", 0, fmt.Errorf("failed to decrypt token for user %s: %w", user.UserID, err)
+			}
+			record.KanboardToken = token
+		}
+		if record.SigningSecret != "" {
+			secret, err := a.decrypt(user.SigningSecret)
+			if err != nil {
+				return "", 0, fmt.Errorf("failed to decrypt signing secret for user %s: %w", user.UserID, err)
+			}
+			record.SigningSecret = secret
+		}
+		exported = append(exported, record)
+	}
+
+	data, err := json.Marshal(exported)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to marshal users: %w", err)
+	}
+
+	sealed, err := bundleEncryptor.Encrypt(string(data))
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to encrypt bundle: %w", err)
+	}
+
+	return bundleHeader + sealed + "\n", len(exported), nil
+}
+
+// ImportUsers stores the registrations from a bundle produced by ExportUsers,
+// re-encrypting tokens with this manager's key. In ImportModeFail nothing is
+// written when any user ID already exists.
+func (a *AuthManager) ImportUsers(bundle string, bundleKey []byte, mode string) (*ImportResult, error) {
+	bundleEncryptor, err := encryption.NewEncryptor(bundleKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bundle key: %w", err)
+	}
+
+	sealed, ok := strings.CutPrefix(bundle, bundleHeader)
+	if !ok {
+		return nil, fmt.Errorf("not a kan-mcp user bundle")
+	}
+
+	data, err := bundleEncryptor.Decrypt(strings.TrimSpace(sealed))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt bundle (wrong bundle key?): %w", err)
+	}
+
+	var users []models.User
+	if err := json.Unmarshal([]byte(data), &users); err != nil {
+		return nil, fmt.Errorf("failed to parse bundle: %w", err)
+	}
+
+	for _, user := range users {
+		if !validUserID(user.UserID) {
+			return nil, fmt.Errorf("bundle contains an invalid user ID %q", user.UserID)
+		}
+	}
+
+	existing, err := a.userStore.ListUsers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	registered := make(map[string]bool, len(existing))
+	for _, user := range existing {
+		registered[user.UserID] = true
+	}
+
+	if mode == ImportModeFail {
+		var collisions []string
+		for _, user := range users {
+			if registered[user.UserID] {
+				collisions = append(collisions, user.UserID)
+			}
+		}
+		if len(collisions) > 0 {
+			return nil, fmt.Errorf("%w: %s", ErrUserIDCollision, strings.Join(collisions, ", "))
+		}
+	}
+
+	result := &ImportResult{}
+	for _, user := range users {
+		if registered[user.UserID] && mode == ImportModeMerge {
+			result.Skipped = append(result.Skipped, user.UserID)
+			continue
+		}
+
+		if user.AuthMode != models.AuthModeApp {
+			encryptedToken, err := a.encrypt(user.KanboardToken)
+			if err != nil {
+				return result, fmt.Errorf("failed to encrypt token for user %s: %w", user.UserID, err)
+			}
+			user.KanboardToken = encryptedToken
+		}
+		if user.SigningSecret != "" {
+			encryptedSecret, err := a.encrypt(user.SigningSecret)
+			if err != nil {
+				return result, fmt.Errorf("failed to encrypt signing secret for user %s: %w", user.UserID, err)
+			}
+			user.SigningSecret = encryptedSecret
+		}
+
+		if err := a.userStore.SaveUser(&user); err != nil {
+			return result, fmt.Errorf("failed to save user %s: %w", user.UserID, err)
+		}
+
+		if registered[user.UserID] {
+			result.Overwritten = append(result.Overwritten, user.UserID)
+		} else {
+			result.Imported = append(result.Imported, user.UserID)
+		}
+	}
+
+	return result, nil
+}
+
+// validUserID accepts the hex IDs generateUserID produces, which also keeps
+// imported IDs safe to use as file names.
+func validUserID(userID string) bool {
+	if userID == "" {
+		return false
+	}
+	for _, r := range userID {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return false
+		}
+	}
+	return true
+}
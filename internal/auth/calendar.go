@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+)
+
+// ErrUnknownCalendarToken is returned by UserByCalendarToken when no user has
+// the token.
+var ErrUnknownCalendarToken = errors.New("unknown calendar token")
+
+// CreateCalendarToken generates a new token for userID's iCal feed, replacing
+// any existing one, and returns it in plain text. Only its hash is stored, so
+// this is the only time it can be shown. The token is separate from the user
+// ID because feed URLs are handed to calendar services.
+func (a *AuthManager) CreateCalendarToken(userID string) (string, error) {
+	user, err := a.userStore.GetUser(userID)
+	if err != nil {
+		return "", fmt.Errorf("user not found: %w", err)
+	}
+
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", fmt.Errorf("failed to generate calendar token: %w", err)
+	}
+	token := hex.EncodeToString(bytes)
+
+	user.CalendarTokenHash = hashCalendarToken(token)
+	if err := a.userStore.SaveUser(user); err != nil {
+		return "", fmt.Errorf("failed to save user: %w", err)
+	}
+
+	return token, nil
+}
+
+// RemoveCalendarToken disables userID's iCal feed.
+func (a *AuthManager) RemoveCalendarToken(userID string) error {
+	user, err := a.userStore.GetUser(userID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+
+	user.CalendarTokenHash = ""
+	if err := a.userStore.SaveUser(user); err != nil {
+		return fmt.Errorf("failed to save user: %w", err)
+	}
+
+	return nil
+}
+
+// UserByCalendarToken returns the user whose iCal feed token is token.
+func (a *AuthManager) UserByCalendarToken(token string) (*models.User, error) {
+	users, err := a.userStore.ListUsers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	hash := []byte(hashCalendarToken(token))
+	for _, user := range users {
+		if user.CalendarTokenHash != "" && subtle.ConstantTimeCompare(hash, []byte(user.CalendarTokenHash)) == 1 {
+			return user, nil
+		}
+	}
+	return nil, ErrUnknownCalendarToken
+}
+
+func hashCalendarToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
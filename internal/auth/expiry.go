@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+)
+
+// ErrCredentialsExpired is returned by AuthenticateUser for registrations
+// past their expiry; `user renew` extends them.
+var ErrCredentialsExpired = errors.New("credentials expired")
+
+// SetCredentialLifetime makes registrations expire lifetime after they are
+// created or renewed. Zero disables expiry.
+func (a *AuthManager) SetCredentialLifetime(lifetime time.Duration) {
+	a.credentialLifetime = lifetime
+}
+
+// CredentialExpiry returns when user's registration expires, or the zero
+// time if it never does. Registrations from before expiry was enabled are
+// dated from their creation.
+func (a *AuthManager) CredentialExpiry(user *models.User) time.Time {
+	if a.credentialLifetime <= 0 {
+		return time.Time{}
+	}
+	if !user.ExpiresAt.IsZero() {
+		return user.ExpiresAt
+	}
+	return user.CreatedAt.Add(a.credentialLifetime)
+}
+
+func (a *AuthManager) checkExpiry(user *models.User) error {
+	expiry := a.CredentialExpiry(user)
+	if !expiry.IsZero() && time.Now().After(expiry) {
+		return fmt.Errorf("%w on %s", ErrCredentialsExpired, expiry.Format("2006-01-02"))
+	}
+	return nil
+}
+
+// RenewUser extends a registration by the credential lifetime from now.
+// Callers are expected to have checked the credentials still work.
+func (a *AuthManager) RenewUser(userID string) (*models.User, error) {
+	if a.credentialLifetime <= 0 {
+		return nil, fmt.Errorf("credential expiry is not enabled")
+	}
+
+	user, err := a.userStore.GetUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	user.ExpiresAt = time.Now().Add(a.credentialLifetime)
+	if err := a.userStore.SaveUser(user); err != nil {
+		return nil, fmt.Errorf("failed to save user: %w", err)
+	}
+
+	return user, nil
+}
+
+func (a *AuthManager) newExpiry() time.Time {
+	if a.credentialLifetime <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(a.credentialLifetime)
+}
@@ -0,0 +1,80 @@
+package auth
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+)
+
+// SetNotifications opts userID in to alerts sent to webhookURL, email or
+// both, replacing any previous destinations.
+func (a *AuthManager) SetNotifications(userID, webhookURL, email string) error {
+	if webhookURL == "" && email == "" {
+		return fmt.Errorf("a webhook URL or an email address is required")
+	}
+	if webhookURL != "" {
+		if u, err := url.Parse(webhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid webhook URL %q", webhookURL)
+		}
+	}
+	if email != "" {
+		address, err := mail.ParseAddress(email)
+		if err != nil {
+			return fmt.Errorf("invalid email address %q: %w", email, err)
+		}
+		email = address.Address
+	}
+
+	user, err := a.userStore.GetUser(userID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+
+	settings := &models.NotificationSettings{WebhookURL: webhookURL, Email: email}
+	if user.Notifications != nil {
+		settings.LastSent = user.Notifications.LastSent
+	}
+	user.Notifications = settings
+	if err := a.userStore.SaveUser(user); err != nil {
+		return fmt.Errorf("failed to save user: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveNotifications opts userID out of alerts.
+func (a *AuthManager) RemoveNotifications(userID string) error {
+	user, err := a.userStore.GetUser(userID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+
+	user.Notifications = nil
+	if err := a.userStore.SaveUser(user); err != nil {
+		return fmt.Errorf("failed to save user: %w", err)
+	}
+
+	return nil
+}
+
+// MarkNotified records that userID's alerts were delivered at sentAt. Users
+// who have opted out in the meantime are left alone.
+func (a *AuthManager) MarkNotified(userID string, sentAt time.Time) error {
+	user, err := a.userStore.GetUser(userID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+	if user.Notifications == nil {
+		return nil
+	}
+
+	user.Notifications.LastSent = sentAt
+	if err := a.userStore.SaveUser(user); err != nil {
+		return fmt.Errorf("failed to save user: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+	"github.com/tech-arch1tect/kan-mcp/pkg/encryption"
+)
+
+func (a *AuthManager) encrypt(plaintext string) (string, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.encryptor.Encrypt(plaintext)
+}
+
+// decrypt falls back to the keys in use before the last rotation, for
+// records the rotation could not rewrite.
+func (a *AuthManager) decrypt(ciphertext string) (string, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	plaintext, err := a.encryptor.Decrypt(ciphertext)
+	if err != nil {
+		for _, previous := range a.previous {
+			if fallback, fallbackErr := previous.Decrypt(ciphertext); fallbackErr == nil {
+				return fallback, nil
+			}
+		}
+	}
+	return plaintext, err
+}
+
+// RotateKey re-encrypts every stored token and signing secret with newKey and
+// switches to it, returning how many registrations were rewritten. Records
+// that already decrypt with newKey, for example because another server
+// sharing the data directory rotated them first, are left alone. Calling it
+// again with the same key retries the records that failed. Every earlier key
+// is kept until a rotation rewrites all records, so rotating again before a
+// retry succeeds still reads records written with a key two or more
+// rotations back.
+func (a *AuthManager) RotateKey(newKey []byte) (int, error) {
+	next, err := encryption.NewEncryptor(newKey)
+	if err != nil {
+		return 0, fmt.Errorf("invalid encryption key: %w", err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	users, err := a.userStore.ListUsers()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	rotated := 0
+	var errs []error
+	for _, user := range users {
+		changed, err := a.reencrypt(user, next)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("user %s: %w", user.UserID, err))
+			continue
+		}
+		if !changed {
+			continue
+		}
+		if err := a.userStore.SaveUser(user); err != nil {
+			errs = append(errs, fmt.Errorf("failed to save user %s: %w", user.UserID, err))
+			continue
+		}
+		rotated++
+	}
+
+	if !bytes.Equal(newKey, a.key) {
+		a.previous = append([]*encryption.Encryptor{a.encryptor}, a.previous...)
+		a.encryptor = next
+		a.key = newKey
+	}
+	// Once every record is rewritten only the key just replaced is still
+	// needed, for saves that raced the rotation.
+	if len(errs) == 0 && len(a.previous) > 1 {
+		a.previous = a.previous[:1]
+	}
+
+	return rotated, errors.Join(errs...)
+}
+
+// reencrypt rewrites user's secrets from the current key to next. The keys
+// before that are tried too, since a save racing the last rotation can write
+// back a record it had read earlier, and a failed rotation leaves records
+// behind. Callers hold mu.
+func (a *AuthManager) reencrypt(user *models.User, next *encryption.Encryptor) (bool, error) {
+	changed := false
+	for _, field := range []*string{&user.KanboardToken, &user.SigningSecret} {
+		if *field == "" || (field == &user.KanboardToken && user.AuthMode == models.AuthModeApp) {
+			continue
+		}
+		if _, err := next.Decrypt(*field); err == nil {
+			continue
+		}
+
+		plaintext, err := a.encryptor.Decrypt(*field)
+		for _, previous := range a.previous {
+			if err == nil {
+				break
+			}
+			plaintext, err = previous.Decrypt(*field)
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to decrypt with any previous key: %w", err)
+		}
+		ciphertext, err := next.Encrypt(plaintext)
+		if err != nil {
+			return false, fmt.Errorf("failed to encrypt with the new key: %w", err)
+		}
+		*field = ciphertext
+		changed = true
+	}
+	return changed, nil
+}
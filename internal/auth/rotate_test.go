@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+	"github.com/tech-arch1tect/kan-mcp/internal/storage"
+)
+
+// flakyStore fails to save the users listed in failing.
+type flakyStore struct {
+	UserStore
+	failing map[string]bool
+}
+
+func (s *flakyStore) SaveUser(user *models.User) error {
+	if s.failing[user.UserID] {
+		return errors.New("disk full")
+	}
+	return s.UserStore.SaveUser(user)
+}
+
+func TestRotateKeyAfterPartialFailure(t *testing.T) {
+	fileStore, err := storage.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := &flakyStore{UserStore: fileStore, failing: map[string]bool{}}
+
+	key1 := bytes.Repeat([]byte{1}, 32)
+	key2 := bytes.Repeat([]byte{2}, 32)
+	key3 := bytes.Repeat([]byte{3}, 32)
+
+	a, err := NewAuthManager(key1, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice, err := a.RegisterUser("http://kanboard", "alice", "alice-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := a.RegisterUser("http://kanboard", "bob", "bob-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Bob's record stays under key1 when the first rotation fails to save it.
+	store.failing[bob.UserID] = true
+	if rotated, err := a.RotateKey(key2); err == nil || rotated != 1 {
+		t.Fatalf("RotateKey(key2) = %d, %v; want 1 and an error", rotated, err)
+	}
+
+	store.failing[bob.UserID] = false
+	if rotated, err := a.RotateKey(key3); err != nil || rotated != 2 {
+		t.Fatalf("RotateKey(key3) = %d, %v; want 2, nil", rotated, err)
+	}
+
+	want := map[string]string{alice.UserID: "alice-token", bob.UserID: "bob-token"}
+	for userID, token := range want {
+		user, err := a.GetUser(userID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := a.GetDecryptedToken(user)
+		if err != nil {
+			t.Fatalf("user %s: %v", userID, err)
+		}
+		if got != token {
+			t.Errorf("user %s token = %q, want %q", userID, got, token)
+		}
+	}
+
+	// Every record is under key3 now, so only key2 is kept for racing saves.
+	if len(a.previous) != 1 {
+		t.Errorf("%d previous keys kept after a full rotation, want 1", len(a.previous))
+	}
+}
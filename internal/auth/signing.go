@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+)
+
+// CreateSigningSecret generates a new HMAC secret for userID, replacing any
+// existing one, and returns it in plain text. Only the encrypted form is
+// stored, so this is the only time it can be shown.
+func (a *AuthManager) CreateSigningSecret(userID string) (string, error) {
+	user, err := a.userStore.GetUser(userID)
+	if err != nil {
+		return "", fmt.Errorf("user not found: %w", err)
+	}
+
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", fmt.Errorf("failed to generate signing secret: %w", err)
+	}
+	secret := hex.EncodeToString(bytes)
+
+	encryptedSecret, err := a.encrypt(secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt signing secret: %w", err)
+	}
+	user.SigningSecret = encryptedSecret
+
+	if err := a.userStore.SaveUser(user); err != nil {
+		return "", fmt.Errorf("failed to save user: %w", err)
+	}
+
+	return secret, nil
+}
+
+// RemoveSigningSecret stops requiring signed requests for userID.
+func (a *AuthManager) RemoveSigningSecret(userID string) error {
+	user, err := a.userStore.GetUser(userID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+
+	user.SigningSecret = ""
+	if err := a.userStore.SaveUser(user); err != nil {
+		return fmt.Errorf("failed to save user: %w", err)
+	}
+
+	return nil
+}
+
+func (a *AuthManager) GetSigningSecret(user *models.User) (string, error) {
+	secret, err := a.decrypt(user.SigningSecret)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt signing secret: %w", err)
+	}
+	return secret, nil
+}
+
+// SignRequest returns the hex HMAC-SHA256 of timestamp, nonce and body, each
+// separated by a newline, as sent in the X-Signature header.
+func SignRequest(secret, timestamp, nonce string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + nonce + "\n"))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+)
+
+const (
+	maxViews        = 50
+	maxViewNameSize = 64
+)
+
+// SaveView stores view for userID, replacing any view with the same name.
+// Names are matched case-insensitively.
+func (a *AuthManager) SaveView(userID string, view models.SavedView) error {
+	view.Name = strings.TrimSpace(view.Name)
+	if view.Name == "" {
+		return fmt.Errorf("a view name is required")
+	}
+	if len(view.Name) > maxViewNameSize {
+		return fmt.Errorf("view name is longer than %d characters", maxViewNameSize)
+	}
+
+	user, err := a.userStore.GetUser(userID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+
+	view.UpdatedAt = time.Now()
+	if i := viewIndex(user.Views, view.Name); i >= 0 {
+		user.Views[i] = view
+	} else {
+		if len(user.Views) >= maxViews {
+			return fmt.Errorf("user already has %d saved views; delete one first", maxViews)
+		}
+		user.Views = append(user.Views, view)
+	}
+
+	if err := a.userStore.SaveUser(user); err != nil {
+		return fmt.Errorf("failed to save user: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteView removes userID's view called name.
+func (a *AuthManager) DeleteView(userID, name string) error {
+	user, err := a.userStore.GetUser(userID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+
+	i := viewIndex(user.Views, name)
+	if i < 0 {
+		return unknownViewError(user.Views, name)
+	}
+	user.Views = append(user.Views[:i], user.Views[i+1:]...)
+	if err := a.userStore.SaveUser(user); err != nil {
+		return fmt.Errorf("failed to save user: %w", err)
+	}
+
+	return nil
+}
+
+// View returns userID's view called name.
+func (a *AuthManager) View(userID, name string) (*models.SavedView, error) {
+	user, err := a.userStore.GetUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	i := viewIndex(user.Views, name)
+	if i < 0 {
+		return nil, unknownViewError(user.Views, name)
+	}
+	view := user.Views[i]
+	return &view, nil
+}
+
+// ListViews returns userID's views in the order they were first saved.
+func (a *AuthManager) ListViews(userID string) ([]models.SavedView, error) {
+	user, err := a.userStore.GetUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+	return user.Views, nil
+}
+
+func viewIndex(views []models.SavedView, name string) int {
+	name = strings.TrimSpace(name)
+	for i, view := range views {
+		if strings.EqualFold(view.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+func unknownViewError(views []models.SavedView, name string) error {
+	if len(views) == 0 {
+		return fmt.Errorf("no saved view named %q; the user has no saved views", name)
+	}
+	names := make([]string, len(views))
+	for i, view := range views {
+		names[i] = view.Name
+	}
+	return fmt.Errorf("no saved view named %q; saved views are: %s", name, strings.Join(names, ", "))
+}
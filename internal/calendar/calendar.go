@@ -0,0 +1,132 @@
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Calendar knows which days are working days. A nil Calendar treats Monday
+// to Friday as working days with no holidays.
+type Calendar struct {
+	workDays [7]bool
+	holidays map[string]bool
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+var defaultCalendar = &Calendar{
+	workDays: [7]bool{false, true, true, true, true, true, false},
+	holidays: map[string]bool{},
+}
+
+// New builds a calendar from a comma-separated list of weekday names such as
+// "mon,tue,wed,thu,fri" and a list of YYYY-MM-DD holiday dates.
+func New(workDays string, holidays []string) (*Calendar, error) {
+	c := &Calendar{holidays: make(map[string]bool)}
+
+	for _, name := range strings.Split(workDays, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if len(name) > 3 {
+			name = name[:3]
+		}
+		day, ok := weekdayNames[name]
+		if !ok {
+			return nil, fmt.Errorf("invalid work day %q", name)
+		}
+		c.workDays[day] = true
+	}
+
+	hasWorkDay := false
+	for _, working := range c.workDays {
+		hasWorkDay = hasWorkDay || working
+	}
+	if !hasWorkDay {
+		return nil, fmt.Errorf("at least one work day is required")
+	}
+
+	for _, holiday := range holidays {
+		holiday = strings.TrimSpace(holiday)
+		if holiday == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", holiday); err != nil {
+			return nil, fmt.Errorf("invalid holiday %q: expected YYYY-MM-DD", holiday)
+		}
+		c.holidays[holiday] = true
+	}
+
+	return c, nil
+}
+
+// ReadHolidays reads one YYYY-MM-DD date per line; blank lines and lines
+// starting with # are ignored, as is anything after the date.
+func ReadHolidays(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open holidays file: %w", err)
+	}
+	defer file.Close()
+
+	var holidays []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		holidays = append(holidays, strings.Fields(line)[0])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read holidays file: %w", err)
+	}
+
+	return holidays, nil
+}
+
+func (c *Calendar) IsWorkDay(t time.Time) bool {
+	if c == nil {
+		c = defaultCalendar
+	}
+	return c.workDays[t.Weekday()] && !c.holidays[t.Format("2006-01-02")]
+}
+
+// WorkDaysUntil counts the working days after from's date up to and including
+// to's date, so a task due Monday is one working day away on Friday. The
+// result is negative when to is before from.
+func (c *Calendar) WorkDaysUntil(from, to time.Time) int {
+	start := dateOf(from)
+	end := dateOf(to.In(from.Location()))
+
+	sign := 1
+	if end.Before(start) {
+		start, end = end, start
+		sign = -1
+	}
+
+	days := 0
+	for day := start.AddDate(0, 0, 1); !day.After(end); day = day.AddDate(0, 0, 1) {
+		if c.IsWorkDay(day) {
+			days++
+		}
+	}
+
+	return sign * days
+}
+
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
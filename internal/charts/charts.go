@@ -0,0 +1,339 @@
+// Package charts renders simple line, bar and stacked-area charts as PNG
+// images, enough to show analytics trends to people who read pictures
+// rather than JSON.
+package charts
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"strconv"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+type Kind int
+
+const (
+	// Line draws each series as a line.
+	Line Kind = iota
+	// Bar draws the series side by side in each slot.
+	Bar
+	// StackedArea stacks the series on top of each other, the first at the
+	// bottom.
+	StackedArea
+)
+
+// Series is one line, set of bars or band of a chart, with a value per
+// label.
+type Series struct {
+	Name   string
+	Values []float64
+	Dashed bool
+}
+
+type Chart struct {
+	Kind   Kind
+	Title  string
+	Labels []string
+	Series []Series
+}
+
+const (
+	width        = 900
+	height       = 480
+	marginLeft   = 64
+	marginRight  = 24
+	marginTop    = 48
+	marginBottom = 84
+)
+
+var palette = []color.RGBA{
+	{0x1f, 0x77, 0xb4, 0xff},
+	{0xff, 0x7f, 0x0e, 0xff},
+	{0x2c, 0xa0, 0x2c, 0xff},
+	{0xd6, 0x27, 0x28, 0xff},
+	{0x94, 0x67, 0xbd, 0xff},
+	{0x8c, 0x56, 0x4b, 0xff},
+}
+
+var (
+	textColor = color.RGBA{0x33, 0x33, 0x33, 0xff}
+	axisColor = color.RGBA{0x99, 0x99, 0x99, 0xff}
+	gridColor = color.RGBA{0xe6, 0xe6, 0xe6, 0xff}
+)
+
+var (
+	fontOnce sync.Once
+	goFont   *opentype.Font
+	fontErr  error
+)
+
+// PNG renders the chart. Every series must have a value per label.
+func (c Chart) PNG() ([]byte, error) {
+	if len(c.Labels) == 0 {
+		return nil, fmt.Errorf("chart %q has no data", c.Title)
+	}
+	for _, s := range c.Series {
+		if len(s.Values) != len(c.Labels) {
+			return nil, fmt.Errorf("chart %q: series %q has %d values for %d labels", c.Title, s.Name, len(s.Values), len(c.Labels))
+		}
+	}
+
+	fontOnce.Do(func() {
+		goFont, fontErr = opentype.Parse(goregular.TTF)
+	})
+	if fontErr != nil {
+		return nil, fmt.Errorf("failed to load chart font: %w", fontErr)
+	}
+	face, err := opentype.NewFace(goFont, &opentype.FaceOptions{Size: 12, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart font: %w", err)
+	}
+	defer face.Close()
+	titleFace, err := opentype.NewFace(goFont, &opentype.FaceOptions{Size: 16, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart font: %w", err)
+	}
+	defer titleFace.Close()
+
+	cv := &canvas{img: image.NewRGBA(image.Rect(0, 0, width, height))}
+	draw.Draw(cv.img, cv.img.Bounds(), image.White, image.Point{}, draw.Src)
+
+	plot := rect{marginLeft, marginTop, width - marginRight, height - marginBottom}
+	maxValue := c.maxValue()
+	top, step := niceScale(maxValue)
+
+	// Horizontal grid lines with their values on the left.
+	for v := 0.0; v <= top+step/2; v += step {
+		y := plot.y1 - float32(v/top)*(plot.y1-plot.y0)
+		cv.line(plot.x0, y, plot.x1, y, 1, gridColor)
+		cv.text(face, formatValue(v), plot.x0-8, y+4, alignRight)
+	}
+	cv.line(plot.x0, plot.y0, plot.x0, plot.y1, 1, axisColor)
+	cv.line(plot.x0, plot.y1, plot.x1, plot.y1, 1, axisColor)
+
+	slot := (plot.x1 - plot.x0) / float32(len(c.Labels))
+	x := func(i int) float32 {
+		if c.Kind == Bar || len(c.Labels) == 1 {
+			return plot.x0 + slot*(float32(i)+0.5)
+		}
+		return plot.x0 + (plot.x1-plot.x0)*float32(i)/float32(len(c.Labels)-1)
+	}
+	y := func(v float64) float32 {
+		return plot.y1 - float32(v/top)*(plot.y1-plot.y0)
+	}
+
+	// Only every labelStep-th label is drawn so they do not overlap.
+	widest := 0
+	for _, label := range c.Labels {
+		widest = max(widest, font.MeasureString(face, label).Ceil())
+	}
+	labelStep := int(math.Ceil(float64(widest+12) / float64((plot.x1-plot.x0)/float32(len(c.Labels)))))
+	labelStep = max(labelStep, 1)
+	for i := 0; i < len(c.Labels); i += labelStep {
+		cv.line(x(i), plot.y1, x(i), plot.y1+4, 1, axisColor)
+		cv.text(face, c.Labels[i], x(i), plot.y1+18, alignCenter)
+	}
+
+	switch c.Kind {
+	case Line:
+		for si, s := range c.Series {
+			col := palette[si%len(palette)]
+			if len(s.Values) == 1 {
+				cv.fill([]point{{x(0) - 3, y(s.Values[0]) - 3}, {x(0) + 3, y(s.Values[0]) - 3}, {x(0) + 3, y(s.Values[0]) + 3}, {x(0) - 3, y(s.Values[0]) + 3}}, col)
+				continue
+			}
+			for i := 1; i < len(s.Values); i++ {
+				if s.Dashed {
+					cv.dashedLine(x(i-1), y(s.Values[i-1]), x(i), y(s.Values[i]), 2, col)
+				} else {
+					cv.line(x(i-1), y(s.Values[i-1]), x(i), y(s.Values[i]), 2.5, col)
+				}
+			}
+		}
+	case Bar:
+		groupWidth := slot * 0.8
+		barWidth := groupWidth / float32(len(c.Series))
+		for si, s := range c.Series {
+			col := palette[si%len(palette)]
+			for i, v := range s.Values {
+				if v <= 0 {
+					continue
+				}
+				x0 := x(i) - groupWidth/2 + barWidth*float32(si)
+				cv.fill([]point{{x0, y(v)}, {x0 + barWidth, y(v)}, {x0 + barWidth, plot.y1}, {x0, plot.y1}}, col)
+			}
+		}
+	case StackedArea:
+		lower := make([]float64, len(c.Labels))
+		for si, s := range c.Series {
+			col := palette[si%len(palette)]
+			upper := make([]float64, len(lower))
+			var band []point
+			for i := range lower {
+				upper[i] = lower[i] + s.Values[i]
+				band = append(band, point{x(i), y(upper[i])})
+			}
+			for i := len(lower) - 1; i >= 0; i-- {
+				band = append(band, point{x(i), y(lower[i])})
+			}
+			if len(lower) == 1 {
+				band = []point{{x(0) - 4, y(upper[0])}, {x(0) + 4, y(upper[0])}, {x(0) + 4, y(lower[0])}, {x(0) - 4, y(lower[0])}}
+			}
+			cv.fill(band, col)
+			lower = upper
+		}
+	}
+
+	cv.text(titleFace, c.Title, width/2, 30, alignCenter)
+
+	// Legend along the bottom.
+	legendWidth := float32(0)
+	for _, s := range c.Series {
+		legendWidth += 18 + float32(font.MeasureString(face, s.Name).Ceil()) + 20
+	}
+	lx := (width - legendWidth) / 2
+	ly := float32(height - 28)
+	for si, s := range c.Series {
+		col := palette[si%len(palette)]
+		cv.fill([]point{{lx, ly - 10}, {lx + 12, ly - 10}, {lx + 12, ly + 2}, {lx, ly + 2}}, col)
+		cv.text(face, s.Name, lx+18, ly, alignLeft)
+		lx += 18 + float32(font.MeasureString(face, s.Name).Ceil()) + 20
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, cv.img); err != nil {
+		return nil, fmt.Errorf("failed to encode chart: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (c Chart) maxValue() float64 {
+	maxValue := 0.0
+	if c.Kind == StackedArea {
+		for i := range c.Labels {
+			total := 0.0
+			for _, s := range c.Series {
+				total += s.Values[i]
+			}
+			maxValue = max(maxValue, total)
+		}
+		return maxValue
+	}
+	for _, s := range c.Series {
+		for _, v := range s.Values {
+			maxValue = max(maxValue, v)
+		}
+	}
+	return maxValue
+}
+
+// niceScale returns an axis maximum of at least maxValue and a tick step of
+// 1, 2 or 5 times a power of ten giving about five ticks.
+func niceScale(maxValue float64) (top, step float64) {
+	if maxValue <= 0 {
+		return 1, 1
+	}
+	raw := maxValue / 5
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+	step = 10 * magnitude
+	for _, m := range []float64{1, 2, 5} {
+		if m*magnitude >= raw {
+			step = m * magnitude
+			break
+		}
+	}
+	return math.Ceil(maxValue/step) * step, step
+}
+
+func formatValue(v float64) string {
+	if v == math.Trunc(v) {
+		return strconv.FormatFloat(v, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+type rect struct {
+	x0, y0, x1, y1 float32
+}
+
+type point struct {
+	x, y float32
+}
+
+type alignment int
+
+const (
+	alignLeft alignment = iota
+	alignCenter
+	alignRight
+)
+
+type canvas struct {
+	img *image.RGBA
+}
+
+// fill paints the polygon through points, anti-aliased.
+func (cv *canvas) fill(points []point, col color.Color) {
+	if len(points) < 3 {
+		return
+	}
+	r := vector.NewRasterizer(width, height)
+	r.MoveTo(points[0].x, points[0].y)
+	for _, p := range points[1:] {
+		r.LineTo(p.x, p.y)
+	}
+	r.ClosePath()
+	r.Draw(cv.img, cv.img.Bounds(), image.NewUniform(col), image.Point{})
+}
+
+func (cv *canvas) line(x0, y0, x1, y1, lineWidth float32, col color.Color) {
+	dx, dy := x1-x0, y1-y0
+	length := float32(math.Hypot(float64(dx), float64(dy)))
+	if length == 0 {
+		return
+	}
+	// Offset both ends half the width either side of the line, and extend
+	// them by as much so consecutive segments join without gaps.
+	nx, ny := -dy/length*lineWidth/2, dx/length*lineWidth/2
+	ex, ey := dx/length*lineWidth/2, dy/length*lineWidth/2
+	cv.fill([]point{
+		{x0 - ex + nx, y0 - ey + ny},
+		{x1 + ex + nx, y1 + ey + ny},
+		{x1 + ex - nx, y1 + ey - ny},
+		{x0 - ex - nx, y0 - ey - ny},
+	}, col)
+}
+
+func (cv *canvas) dashedLine(x0, y0, x1, y1, lineWidth float32, col color.Color) {
+	const dash, gap = 8, 6
+	length := float32(math.Hypot(float64(x1-x0), float64(y1-y0)))
+	for start := float32(0); start < length; start += dash + gap {
+		end := min(start+dash, length)
+		cv.line(x0+(x1-x0)*start/length, y0+(y1-y0)*start/length, x0+(x1-x0)*end/length, y0+(y1-y0)*end/length, lineWidth, col)
+	}
+}
+
+// text draws s with its baseline at y, aligned on x.
+func (cv *canvas) text(face font.Face, s string, x, y float32, align alignment) {
+	d := &font.Drawer{Dst: cv.img, Src: image.NewUniform(textColor), Face: face}
+	switch align {
+	case alignCenter:
+		w := float32(d.MeasureString(s).Ceil())
+		x = min(max(x-w/2, 2), width-w-2)
+	case alignRight:
+		x -= float32(d.MeasureString(s).Ceil())
+	}
+	d.Dot = fixed.P(int(x), int(y))
+	d.DrawString(s)
+}
@@ -1,67 +1,755 @@
 package config
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/hex"
+	"flag"
 	"fmt"
+	"net/mail"
+	"net/netip"
+	"net/url"
 	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/calendar"
+	"github.com/tech-arch1tect/kan-mcp/internal/keysource"
+	"github.com/tech-arch1tect/kan-mcp/internal/logging"
+	"github.com/tech-arch1tect/kan-mcp/pkg/encryption"
 )
 
+const ConfigFileEnv = "KAN_MCP_CONFIG"
+
+// PassphraseEnv holds a passphrase the encryption key is derived from, as an
+// alternative to a raw hex key.
+const PassphraseEnv = "ENCRYPTION_PASSPHRASE"
+
+const minPassphraseLength = 12
+
 type Config struct {
+	Log      LogConfig      `yaml:"log"`
 	Server   ServerConfig   `yaml:"server"`
 	Kanboard KanboardConfig `yaml:"kanboard"`
 	Security SecurityConfig `yaml:"security"`
 	Storage  StorageConfig  `yaml:"storage"`
+	Calendar CalendarConfig `yaml:"calendar"`
+	Audit    AuditConfig    `yaml:"audit"`
+	Quota    QuotaConfig    `yaml:"quota"`
+	// ToolRateLimit limits inbound tool calls per user ID.
+	ToolRateLimit ToolRateLimitConfig `yaml:"tool_rate_limit"`
+	Confirm       ConfirmConfig       `yaml:"confirm"`
+	Lockout       LockoutConfig       `yaml:"lockout"`
+	TaskSync      TaskSyncConfig      `yaml:"task_sync"`
+	Webhook       WebhookConfig       `yaml:"webhook"`
+	Digest        DigestConfig        `yaml:"digest"`
+	Notify        NotifyConfig        `yaml:"notify"`
+}
+
+type LogConfig struct {
+	Level string `yaml:"level"`
+	// UserIDs is how user IDs appear in logs: "hash" (the default), "plain"
+	// or "omit".
+	UserIDs string `yaml:"user_ids"`
 }
 
 type ServerConfig struct {
-	Port string `yaml:"port"`
-	Host string `yaml:"host"`
+	Transport string `yaml:"transport"`
+	Port      string `yaml:"port"`
+	Host      string `yaml:"host"`
+	// DebugPort, when set, serves pprof and expvar on 127.0.0.1 only.
+	DebugPort string `yaml:"debug_port"`
+	// AllowedIPs and DeniedIPs are addresses or CIDRs the HTTP transport
+	// accepts and refuses; an empty allowlist admits everyone not denied.
+	AllowedIPs []string `yaml:"allowed_ips"`
+	DeniedIPs  []string `yaml:"denied_ips"`
+	// TrustForwardedFor takes the client address from X-Forwarded-For, for
+	// deployments behind a reverse proxy.
+	TrustForwardedFor bool `yaml:"trust_forwarded_for"`
+	// RequireSignatures rejects unsigned HTTP requests even for users
+	// without a signing secret. SignatureMaxAge bounds the clock skew
+	// accepted on signed requests and how long nonces are remembered.
+	RequireSignatures bool          `yaml:"require_signatures"`
+	SignatureMaxAge   time.Duration `yaml:"signature_max_age"`
+	// ReadOnly leaves out every tool that changes Kanboard data and makes
+	// the Kanboard client refuse mutating API calls.
+	ReadOnly bool `yaml:"read_only"`
+	// CompressMinSize is the smallest HTTP response, in bytes, compressed
+	// for clients that accept gzip or deflate; 0 disables compression.
+	CompressMinSize int `yaml:"compress_min_size"`
 }
 
 type KanboardConfig struct {
-	DefaultURL string        `yaml:"default_url"`
-	Timeout    time.Duration `yaml:"timeout"`
+	DefaultURL string           `yaml:"default_url"`
+	AppToken   string           `yaml:"app_token"`
+	AuthHeader string           `yaml:"auth_header"`
+	Timeout    time.Duration    `yaml:"timeout"`
+	TLS        TLSConfig        `yaml:"tls"`
+	Retry      RetryConfig      `yaml:"retry"`
+	RateLimit  RateLimitConfig  `yaml:"rate_limit"`
+	Cache      CacheConfig      `yaml:"cache"`
+	Breaker    BreakerConfig    `yaml:"circuit_breaker"`
+	Transport  TransportConfig  `yaml:"transport"`
+	WorkerPool WorkerPoolConfig `yaml:"worker_pool"`
+	SlowCalls  SlowCallConfig   `yaml:"slow_calls"`
+}
+
+// SlowCallConfig controls logging of Kanboard requests that take at least
+// Threshold, and how often the slowest methods are summarised.
+type SlowCallConfig struct {
+	Threshold       time.Duration `yaml:"threshold"`
+	SummaryInterval time.Duration `yaml:"summary_interval"`
+}
+
+// WorkerPoolConfig bounds how many per-project jobs run concurrently across
+// all tool calls, how many may wait for a worker and for how long.
+type WorkerPoolConfig struct {
+	Workers      int           `yaml:"workers"`
+	QueueSize    int           `yaml:"queue_size"`
+	QueueTimeout time.Duration `yaml:"queue_timeout"`
+}
+
+type TransportConfig struct {
+	MaxIdleConns        int           `yaml:"max_idle_conns"`
+	MaxIdleConnsPerHost int           `yaml:"max_idle_conns_per_host"`
+	IdleConnTimeout     time.Duration `yaml:"idle_conn_timeout"`
+}
+
+type BreakerConfig struct {
+	FailureThreshold int           `yaml:"failure_threshold"`
+	OpenDuration     time.Duration `yaml:"open_duration"`
+}
+
+type CacheConfig struct {
+	ProjectsTTL  time.Duration `yaml:"projects_ttl"`
+	ColumnsTTL   time.Duration `yaml:"columns_ttl"`
+	SwimlanesTTL time.Duration `yaml:"swimlanes_ttl"`
+	UsersTTL     time.Duration `yaml:"users_ttl"`
+	// CategoriesTTL covers project categories and tags.
+	CategoriesTTL time.Duration `yaml:"categories_ttl"`
+	OverviewTTL   time.Duration `yaml:"overview_ttl"`
+	// WarmInterval, when positive, is how often the project lists and board
+	// structure of users who called a tool within WarmActiveWithin are
+	// fetched ahead of their next call.
+	WarmInterval     time.Duration `yaml:"warm_interval"`
+	WarmActiveWithin time.Duration `yaml:"warm_active_within"`
+}
+
+type RateLimitConfig struct {
+	RequestsPerSecond float64 `yaml:"requests_per_second"`
+	Burst             int     `yaml:"burst"`
+}
+
+type RetryConfig struct {
+	MaxRetries int           `yaml:"max_retries"`
+	BaseDelay  time.Duration `yaml:"base_delay"`
+	MaxDelay   time.Duration `yaml:"max_delay"`
+}
+
+type TLSConfig struct {
+	CACertFile         string `yaml:"ca_cert_file"`
+	ClientCertFile     string `yaml:"client_cert_file"`
+	ClientKeyFile      string `yaml:"client_key_file"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
 }
 
 type SecurityConfig struct {
 	EncryptionKeyEnv string `yaml:"encryption_key_env"`
+	// EncryptionKeySource reads the key from a file, Vault or AWS Secrets
+	// Manager instead of EncryptionKeyEnv; see keysource.Fetch. The server
+	// re-reads it every EncryptionKeyRefresh to pick up rotations.
+	EncryptionKeySource  string        `yaml:"encryption_key_source"`
+	EncryptionKeyRefresh time.Duration `yaml:"encryption_key_refresh"`
+	// CredentialLifetime makes registrations expire this long after they
+	// are created or renewed. Zero means they never expire.
+	CredentialLifetime time.Duration `yaml:"credential_lifetime"`
+	// PseudonymizeUsers replaces Kanboard usernames and names in tool
+	// responses with stable pseudonyms kept in the data directory.
+	PseudonymizeUsers bool `yaml:"pseudonymize_users"`
 }
 
 type StorageConfig struct {
 	DataDir string `yaml:"data_dir"`
 }
 
-func LoadConfig() (*Config, error) {
-	config := &Config{
+// AuditConfig controls how long tool calls are kept in the audit log. A zero
+// retention keeps them forever.
+type AuditConfig struct {
+	Retention time.Duration `yaml:"retention"`
+}
+
+// QuotaConfig caps the tool calls and response bytes each user may use per
+// day, with days starting at midnight in the default timezone. Zero means
+// unlimited. An entry in Users replaces both limits for that user ID.
+type QuotaConfig struct {
+	DailyCalls int                    `yaml:"daily_calls"`
+	DailyBytes int                    `yaml:"daily_bytes"`
+	Users      map[string]QuotaLimits `yaml:"users"`
+}
+
+type QuotaLimits struct {
+	DailyCalls int `yaml:"daily_calls"`
+	DailyBytes int `yaml:"daily_bytes"`
+}
+
+// Limits returns the quota that applies to userID.
+func (q QuotaConfig) Limits(userID string) QuotaLimits {
+	if limits, ok := q.Users[userID]; ok {
+		return limits
+	}
+	return QuotaLimits{DailyCalls: q.DailyCalls, DailyBytes: q.DailyBytes}
+}
+
+// ToolRateLimitConfig is the token bucket each user's tool calls are drawn
+// from. An entry in Users replaces both settings for that user ID; a rate of
+// zero disables the limit.
+type ToolRateLimitConfig struct {
+	RequestsPerSecond float64                  `yaml:"requests_per_second"`
+	Burst             int                      `yaml:"burst"`
+	Users             map[string]ToolRateLimit `yaml:"users"`
+}
+
+type ToolRateLimit struct {
+	RequestsPerSecond float64 `yaml:"requests_per_second"`
+	Burst             int     `yaml:"burst"`
+}
+
+// LockoutConfig bans HTTP clients that keep sending unknown user IDs. Once an
+// address reaches Threshold failures it is banned for Ban, doubling with each
+// further failure up to MaxBan; failures are forgotten after MaxBan without
+// one. A zero threshold disables the lockout.
+type LockoutConfig struct {
+	Threshold int           `yaml:"threshold"`
+	Ban       time.Duration `yaml:"ban"`
+	MaxBan    time.Duration `yaml:"max_ban"`
+}
+
+// TaskSyncConfig enables the background sync of each user's closed tasks
+// into a local snapshot, refreshed every Interval.
+type TaskSyncConfig struct {
+	Enabled  bool          `yaml:"enabled"`
+	Interval time.Duration `yaml:"interval"`
+}
+
+// WebhookConfig enables the Kanboard webhook endpoint of the HTTP transport.
+// Secret is the webhook token from Kanboard's settings, and Instance the
+// Kanboard URL sending the webhooks, by default DefaultURL. Received events
+// are kept for Retention; zero keeps them forever.
+type WebhookConfig struct {
+	Secret    string        `yaml:"secret"`
+	Instance  string        `yaml:"instance"`
+	Retention time.Duration `yaml:"retention"`
+}
+
+// DigestConfig schedules per-user digests: "daily" ones at Time every day
+// and "weekly" ones at Time on Weekday, in the default timezone. Digests
+// are kept for retrieval as MCP resources and, when WebhookURL is set,
+// posted there, signed with WebhookSecret if one is given. No Periods
+// disables digests.
+type DigestConfig struct {
+	Periods       []string `yaml:"periods"`
+	Time          string   `yaml:"time"`
+	Weekday       string   `yaml:"weekday"`
+	WebhookURL    string   `yaml:"webhook_url"`
+	WebhookSecret string   `yaml:"webhook_secret"`
+}
+
+// GetPeriods returns the configured digest periods, lower-cased and without
+// duplicates.
+func (d DigestConfig) GetPeriods() ([]string, error) {
+	var periods []string
+	for _, period := range d.Periods {
+		period = strings.ToLower(strings.TrimSpace(period))
+		if period != "daily" && period != "weekly" {
+			return nil, fmt.Errorf("invalid digest period %q: must be daily or weekly", period)
+		}
+		if !slices.Contains(periods, period) {
+			periods = append(periods, period)
+		}
+	}
+	return periods, nil
+}
+
+// GetSchedule returns the time of day digests are generated at, as hours and
+// minutes, and the weekday of weekly digests.
+func (d DigestConfig) GetSchedule() (hour, minute int, weekday time.Weekday, err error) {
+	at, err := time.Parse("15:04", d.Time)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid digest time %q: use HH:MM", d.Time)
+	}
+
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		name := strings.ToLower(day.String())
+		if strings.EqualFold(d.Weekday, name) || strings.EqualFold(d.Weekday, name[:3]) {
+			return at.Hour(), at.Minute(), day, nil
+		}
+	}
+	return 0, 0, 0, fmt.Errorf("invalid digest weekday %q", d.Weekday)
+}
+
+// NotifyConfig schedules overdue and due-today alerts for the users who have
+// opted in with `user notify`. Alerts go out once a day at Time, in the
+// default timezone, to each user's webhook, signed with WebhookSecret if one
+// is given, and by email through SMTP.
+type NotifyConfig struct {
+	Enabled       bool       `yaml:"enabled"`
+	Time          string     `yaml:"time"`
+	WebhookSecret string     `yaml:"webhook_secret"`
+	SMTP          SMTPConfig `yaml:"smtp"`
+}
+
+// SMTPConfig is the mail server alerts are sent through. Port 465 uses
+// implicit TLS; other ports upgrade with STARTTLS when the server offers it,
+// which is required before authenticating with Username and Password. No
+// Host disables email.
+type SMTPConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
+}
+
+// GetTime returns the time of day alerts are sent at, as hours and minutes.
+func (n NotifyConfig) GetTime() (hour, minute int, err error) {
+	at, err := time.Parse("15:04", n.Time)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid notification time %q: use HH:MM", n.Time)
+	}
+	return at.Hour(), at.Minute(), nil
+}
+
+// Limits returns the rate limit that applies to userID.
+func (r ToolRateLimitConfig) Limits(userID string) ToolRateLimit {
+	if limits, ok := r.Users[userID]; ok {
+		return limits
+	}
+	return ToolRateLimit{RequestsPerSecond: r.RequestsPerSecond, Burst: r.Burst}
+}
+
+// ConfirmConfig lists the write tools whose calls must be confirmed: the
+// first call only returns a preview and a token, and a second call with the
+// token makes the change. "*" covers every write tool.
+type ConfirmConfig struct {
+	Tools []string      `yaml:"tools"`
+	TTL   time.Duration `yaml:"ttl"`
+}
+
+// Required reports whether calls to tool need a confirmation token.
+func (c ConfirmConfig) Required(tool string) bool {
+	for _, name := range c.Tools {
+		name = strings.TrimSpace(name)
+		if name == "*" || name == tool || "kanboard_"+name == tool {
+			return true
+		}
+	}
+	return false
+}
+
+type CalendarConfig struct {
+	WorkDays     string   `yaml:"work_days"`
+	Holidays     []string `yaml:"holidays"`
+	HolidaysFile string   `yaml:"holidays_file"`
+	Timezone     string   `yaml:"timezone"`
+}
+
+func defaultConfig() *Config {
+	return &Config{
+		Log: LogConfig{
+			Level:   "info",
+			UserIDs: logging.UserIDsHash,
+		},
 		Server: ServerConfig{
-			Port: getEnvOrDefault("MCP_PORT", "8080"),
-			Host: getEnvOrDefault("MCP_HOST", "0.0.0.0"),
+			Transport:       "stdio",
+			Port:            "8080",
+			Host:            "0.0.0.0",
+			SignatureMaxAge: 5 * time.Minute,
+			CompressMinSize: 1024,
 		},
 		Kanboard: KanboardConfig{
-			DefaultURL: getEnvOrDefault("DEFAULT_KANBOARD_URL", ""),
+			AuthHeader: "authorization",
 			Timeout:    30 * time.Second,
+			Retry: RetryConfig{
+				MaxRetries: 2,
+				BaseDelay:  500 * time.Millisecond,
+				MaxDelay:   5 * time.Second,
+			},
+			RateLimit: RateLimitConfig{
+				RequestsPerSecond: 10,
+				Burst:             20,
+			},
+			Cache: CacheConfig{
+				ProjectsTTL:      time.Minute,
+				ColumnsTTL:       5 * time.Minute,
+				SwimlanesTTL:     5 * time.Minute,
+				UsersTTL:         5 * time.Minute,
+				CategoriesTTL:    5 * time.Minute,
+				OverviewTTL:      time.Minute,
+				WarmActiveWithin: 24 * time.Hour,
+			},
+			Breaker: BreakerConfig{
+				FailureThreshold: 5,
+				OpenDuration:     30 * time.Second,
+			},
+			Transport: TransportConfig{
+				MaxIdleConns:        100,
+				MaxIdleConnsPerHost: 32,
+				IdleConnTimeout:     90 * time.Second,
+			},
+			WorkerPool: WorkerPoolConfig{
+				Workers:      8,
+				QueueSize:    1000,
+				QueueTimeout: 30 * time.Second,
+			},
+			SlowCalls: SlowCallConfig{
+				Threshold:       2 * time.Second,
+				SummaryInterval: 15 * time.Minute,
+			},
 		},
 		Security: SecurityConfig{
-			EncryptionKeyEnv: "ENCRYPTION_KEY",
+			EncryptionKeyEnv:     "ENCRYPTION_KEY",
+			EncryptionKeyRefresh: time.Hour,
 		},
 		Storage: StorageConfig{
-			DataDir: getEnvOrDefault("DATA_DIR", "./data"),
+			DataDir: "./data",
+		},
+		Calendar: CalendarConfig{
+			WorkDays: "mon,tue,wed,thu,fri",
+		},
+		Audit: AuditConfig{
+			Retention: 90 * 24 * time.Hour,
+		},
+		ToolRateLimit: ToolRateLimitConfig{
+			RequestsPerSecond: 5,
+			Burst:             20,
+		},
+		Lockout: LockoutConfig{
+			Threshold: 10,
+			Ban:       time.Minute,
+			MaxBan:    time.Hour,
+		},
+		Confirm: ConfirmConfig{
+			TTL: 5 * time.Minute,
+		},
+		TaskSync: TaskSyncConfig{
+			Interval: 15 * time.Minute,
+		},
+		Webhook: WebhookConfig{
+			Retention: 30 * 24 * time.Hour,
+		},
+		Digest: DigestConfig{
+			Time:    "08:00",
+			Weekday: "mon",
+		},
+		Notify: NotifyConfig{
+			Time: "08:00",
+			SMTP: SMTPConfig{
+				Port: 587,
+			},
 		},
 	}
+}
 
-	if timeoutStr := os.Getenv("KANBOARD_TIMEOUT"); timeoutStr != "" {
-		if timeout, err := time.ParseDuration(timeoutStr); err == nil {
-			config.Kanboard.Timeout = timeout
+// LoadConfig builds the configuration from defaults, an optional YAML file,
+// environment variables and finally the flags in args, each source overriding
+// the previous one. Flags are registered on fs so callers can add their own
+// before calling it.
+func LoadConfig(fs *flag.FlagSet, args []string) (*Config, error) {
+	config := defaultConfig()
+
+	configFile := configFileFromArgs(args)
+	if configFile == "" {
+		configFile = os.Getenv(ConfigFileEnv)
+	}
+
+	if configFile != "" {
+		if err := config.loadFile(configFile); err != nil {
+			return nil, err
 		}
 	}
 
+	if err := config.applyEnv(); err != nil {
+		return nil, err
+	}
+
+	config.bindFlags(fs, configFile)
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
 	return config, nil
 }
 
+func (c *Config) applyEnv() error {
+	setStringFromEnv(&c.Log.Level, "LOG_LEVEL")
+	setStringFromEnv(&c.Log.UserIDs, "LOG_USER_IDS")
+	setStringFromEnv(&c.Server.Transport, "MCP_TRANSPORT")
+	setStringFromEnv(&c.Server.Port, "MCP_PORT")
+	setStringFromEnv(&c.Server.Host, "MCP_HOST")
+	setStringFromEnv(&c.Server.DebugPort, "MCP_DEBUG_PORT")
+	setStringFromEnv(&c.Kanboard.DefaultURL, "DEFAULT_KANBOARD_URL")
+	setStringFromEnv(&c.Kanboard.AppToken, "KANBOARD_APP_TOKEN")
+	setStringFromEnv(&c.Kanboard.AuthHeader, "KANBOARD_AUTH_HEADER")
+	setStringFromEnv(&c.Kanboard.TLS.CACertFile, "KANBOARD_CA_CERT")
+	setStringFromEnv(&c.Kanboard.TLS.ClientCertFile, "KANBOARD_CLIENT_CERT")
+	setStringFromEnv(&c.Kanboard.TLS.ClientKeyFile, "KANBOARD_CLIENT_KEY")
+	setStringFromEnv(&c.Security.EncryptionKeyEnv, "ENCRYPTION_KEY_ENV")
+	setStringFromEnv(&c.Security.EncryptionKeySource, "ENCRYPTION_KEY_SOURCE")
+	if value := os.Getenv("ENCRYPTION_KEY_FILE"); value != "" {
+		c.Security.EncryptionKeySource = "file:" + value
+	}
+	setStringFromEnv(&c.Storage.DataDir, "DATA_DIR")
+	setStringFromEnv(&c.Calendar.WorkDays, "WORK_DAYS")
+	setStringFromEnv(&c.Calendar.HolidaysFile, "HOLIDAYS_FILE")
+	setStringFromEnv(&c.Calendar.Timezone, "DEFAULT_TIMEZONE")
+
+	if value := os.Getenv("HOLIDAYS"); value != "" {
+		c.Calendar.Holidays = strings.Split(value, ",")
+	}
+
+	if value := os.Getenv("CONFIRM_TOOLS"); value != "" {
+		c.Confirm.Tools = strings.Split(value, ",")
+	}
+
+	if value := os.Getenv("MCP_ALLOWED_IPS"); value != "" {
+		c.Server.AllowedIPs = strings.Split(value, ",")
+	}
+
+	if value := os.Getenv("MCP_DENIED_IPS"); value != "" {
+		c.Server.DeniedIPs = strings.Split(value, ",")
+	}
+
+	if err := setBoolFromEnv(&c.Server.TrustForwardedFor, "MCP_TRUST_FORWARDED_FOR"); err != nil {
+		return err
+	}
+
+	if err := setBoolFromEnv(&c.Server.RequireSignatures, "MCP_REQUIRE_SIGNATURES"); err != nil {
+		return err
+	}
+
+	if err := setBoolFromEnv(&c.Server.ReadOnly, "READ_ONLY"); err != nil {
+		return err
+	}
+
+	if err := setDurationFromEnv(&c.Security.CredentialLifetime, "CREDENTIAL_LIFETIME"); err != nil {
+		return err
+	}
+
+	if err := setBoolFromEnv(&c.Security.PseudonymizeUsers, "PSEUDONYMIZE_USERS"); err != nil {
+		return err
+	}
+
+	if err := setDurationFromEnv(&c.Confirm.TTL, "CONFIRM_TTL"); err != nil {
+		return err
+	}
+
+	if err := setDurationFromEnv(&c.Security.EncryptionKeyRefresh, "ENCRYPTION_KEY_REFRESH"); err != nil {
+		return err
+	}
+
+	if err := setDurationFromEnv(&c.Server.SignatureMaxAge, "MCP_SIGNATURE_MAX_AGE"); err != nil {
+		return err
+	}
+
+	if err := setIntFromEnv(&c.Server.CompressMinSize, "MCP_COMPRESS_MIN_SIZE"); err != nil {
+		return err
+	}
+
+	if err := setDurationFromEnv(&c.Kanboard.Timeout, "KANBOARD_TIMEOUT"); err != nil {
+		return err
+	}
+
+	if err := setBoolFromEnv(&c.Kanboard.TLS.InsecureSkipVerify, "KANBOARD_INSECURE_SKIP_VERIFY"); err != nil {
+		return err
+	}
+
+	if err := setIntFromEnv(&c.Kanboard.Retry.MaxRetries, "KANBOARD_MAX_RETRIES"); err != nil {
+		return err
+	}
+
+	if err := setDurationFromEnv(&c.Kanboard.Retry.BaseDelay, "KANBOARD_RETRY_BASE_DELAY"); err != nil {
+		return err
+	}
+
+	if err := setDurationFromEnv(&c.Kanboard.Retry.MaxDelay, "KANBOARD_RETRY_MAX_DELAY"); err != nil {
+		return err
+	}
+
+	if err := setFloatFromEnv(&c.Kanboard.RateLimit.RequestsPerSecond, "KANBOARD_RATE_LIMIT_RPS"); err != nil {
+		return err
+	}
+
+	if err := setIntFromEnv(&c.Kanboard.RateLimit.Burst, "KANBOARD_RATE_LIMIT_BURST"); err != nil {
+		return err
+	}
+
+	if err := setDurationFromEnv(&c.Kanboard.Cache.ProjectsTTL, "KANBOARD_CACHE_PROJECTS_TTL"); err != nil {
+		return err
+	}
+
+	if err := setDurationFromEnv(&c.Kanboard.Cache.ColumnsTTL, "KANBOARD_CACHE_COLUMNS_TTL"); err != nil {
+		return err
+	}
+
+	if err := setDurationFromEnv(&c.Kanboard.Cache.SwimlanesTTL, "KANBOARD_CACHE_SWIMLANES_TTL"); err != nil {
+		return err
+	}
+
+	if err := setDurationFromEnv(&c.Kanboard.Cache.UsersTTL, "KANBOARD_CACHE_USERS_TTL"); err != nil {
+		return err
+	}
+
+	if err := setDurationFromEnv(&c.Kanboard.Cache.CategoriesTTL, "KANBOARD_CACHE_CATEGORIES_TTL"); err != nil {
+		return err
+	}
+
+	if err := setDurationFromEnv(&c.Kanboard.Cache.OverviewTTL, "KANBOARD_CACHE_OVERVIEW_TTL"); err != nil {
+		return err
+	}
+
+	if err := setDurationFromEnv(&c.Kanboard.Cache.WarmInterval, "KANBOARD_CACHE_WARM_INTERVAL"); err != nil {
+		return err
+	}
+
+	if err := setDurationFromEnv(&c.Kanboard.Cache.WarmActiveWithin, "KANBOARD_CACHE_WARM_ACTIVE_WITHIN"); err != nil {
+		return err
+	}
+
+	if err := setIntFromEnv(&c.Kanboard.Breaker.FailureThreshold, "KANBOARD_BREAKER_THRESHOLD"); err != nil {
+		return err
+	}
+
+	if err := setDurationFromEnv(&c.Kanboard.Breaker.OpenDuration, "KANBOARD_BREAKER_OPEN_DURATION"); err != nil {
+		return err
+	}
+
+	if err := setIntFromEnv(&c.Kanboard.Transport.MaxIdleConns, "KANBOARD_MAX_IDLE_CONNS"); err != nil {
+		return err
+	}
+
+	if err := setIntFromEnv(&c.Kanboard.Transport.MaxIdleConnsPerHost, "KANBOARD_MAX_IDLE_CONNS_PER_HOST"); err != nil {
+		return err
+	}
+
+	if err := setIntFromEnv(&c.Kanboard.WorkerPool.Workers, "KANBOARD_WORKERS"); err != nil {
+		return err
+	}
+
+	if err := setIntFromEnv(&c.Kanboard.WorkerPool.QueueSize, "KANBOARD_QUEUE_SIZE"); err != nil {
+		return err
+	}
+
+	if err := setDurationFromEnv(&c.Kanboard.WorkerPool.QueueTimeout, "KANBOARD_QUEUE_TIMEOUT"); err != nil {
+		return err
+	}
+
+	if err := setDurationFromEnv(&c.Kanboard.Transport.IdleConnTimeout, "KANBOARD_IDLE_CONN_TIMEOUT"); err != nil {
+		return err
+	}
+
+	if err := setDurationFromEnv(&c.Kanboard.SlowCalls.Threshold, "KANBOARD_SLOW_CALL_THRESHOLD"); err != nil {
+		return err
+	}
+
+	if err := setDurationFromEnv(&c.Kanboard.SlowCalls.SummaryInterval, "KANBOARD_SLOW_CALL_SUMMARY_INTERVAL"); err != nil {
+		return err
+	}
+
+	if err := setDurationFromEnv(&c.Audit.Retention, "AUDIT_RETENTION"); err != nil {
+		return err
+	}
+
+	if err := setIntFromEnv(&c.Quota.DailyCalls, "QUOTA_DAILY_CALLS"); err != nil {
+		return err
+	}
+
+	if err := setIntFromEnv(&c.Quota.DailyBytes, "QUOTA_DAILY_BYTES"); err != nil {
+		return err
+	}
+
+	if err := setFloatFromEnv(&c.ToolRateLimit.RequestsPerSecond, "TOOL_RATE_LIMIT_RPS"); err != nil {
+		return err
+	}
+
+	if err := setIntFromEnv(&c.ToolRateLimit.Burst, "TOOL_RATE_LIMIT_BURST"); err != nil {
+		return err
+	}
+
+	if err := setIntFromEnv(&c.Lockout.Threshold, "LOCKOUT_THRESHOLD"); err != nil {
+		return err
+	}
+
+	if err := setDurationFromEnv(&c.Lockout.Ban, "LOCKOUT_BAN"); err != nil {
+		return err
+	}
+
+	if err := setDurationFromEnv(&c.Lockout.MaxBan, "LOCKOUT_MAX_BAN"); err != nil {
+		return err
+	}
+
+	if err := setBoolFromEnv(&c.TaskSync.Enabled, "TASK_SYNC"); err != nil {
+		return err
+	}
+
+	if err := setDurationFromEnv(&c.TaskSync.Interval, "TASK_SYNC_INTERVAL"); err != nil {
+		return err
+	}
+
+	setStringFromEnv(&c.Webhook.Secret, "KANBOARD_WEBHOOK_SECRET")
+	setStringFromEnv(&c.Webhook.Instance, "KANBOARD_WEBHOOK_INSTANCE")
+
+	if err := setDurationFromEnv(&c.Webhook.Retention, "KANBOARD_WEBHOOK_RETENTION"); err != nil {
+		return err
+	}
+
+	if value := os.Getenv("DIGEST_PERIODS"); value != "" {
+		c.Digest.Periods = strings.Split(value, ",")
+	}
+	setStringFromEnv(&c.Digest.Time, "DIGEST_TIME")
+	setStringFromEnv(&c.Digest.Weekday, "DIGEST_WEEKDAY")
+	setStringFromEnv(&c.Digest.WebhookURL, "DIGEST_WEBHOOK_URL")
+	setStringFromEnv(&c.Digest.WebhookSecret, "DIGEST_WEBHOOK_SECRET")
+
+	if err := setBoolFromEnv(&c.Notify.Enabled, "NOTIFICATIONS"); err != nil {
+		return err
+	}
+	setStringFromEnv(&c.Notify.Time, "NOTIFY_TIME")
+	setStringFromEnv(&c.Notify.WebhookSecret, "NOTIFY_WEBHOOK_SECRET")
+	setStringFromEnv(&c.Notify.SMTP.Host, "SMTP_HOST")
+	if err := setIntFromEnv(&c.Notify.SMTP.Port, "SMTP_PORT"); err != nil {
+		return err
+	}
+	setStringFromEnv(&c.Notify.SMTP.Username, "SMTP_USERNAME")
+	setStringFromEnv(&c.Notify.SMTP.Password, "SMTP_PASSWORD")
+	setStringFromEnv(&c.Notify.SMTP.From, "SMTP_FROM")
+
+	return nil
+}
+
 func (c *Config) GetEncryptionKey() ([]byte, error) {
 	keyHex := os.Getenv(c.Security.EncryptionKeyEnv)
-	if keyHex == "" {
+	if passphrase := os.Getenv(PassphraseEnv); passphrase != "" {
+		if keyHex != "" || c.Security.EncryptionKeySource != "" {
+			return nil, fmt.Errorf("%s cannot be combined with %s or an encryption key source", PassphraseEnv, c.Security.EncryptionKeyEnv)
+		}
+		return c.derivePassphraseKey(passphrase)
+	}
+
+	if c.Security.EncryptionKeySource != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		var err error
+		keyHex, err = keysource.Fetch(ctx, c.Security.EncryptionKeySource)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch encryption key: %w", err)
+		}
+	} else if keyHex == "" {
 		return nil, fmt.Errorf("encryption key environment variable %s is not set", c.Security.EncryptionKeyEnv)
 	}
 
@@ -77,30 +765,360 @@ func (c *Config) GetEncryptionKey() ([]byte, error) {
 	return key, nil
 }
 
+// derivePassphraseKey derives the key with Argon2id. The salt is created in
+// the data directory on first use; losing it makes stored tokens unreadable.
+func (c *Config) derivePassphraseKey(passphrase string) ([]byte, error) {
+	if len(passphrase) < minPassphraseLength {
+		return nil, fmt.Errorf("%s must be at least %d characters", PassphraseEnv, minPassphraseLength)
+	}
+
+	params, err := encryption.LoadKDFParams(filepath.Join(c.Storage.DataDir, "key-derivation.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load key derivation parameters: %w", err)
+	}
+
+	key, err := encryption.DeriveKey(passphrase, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive encryption key: %w", err)
+	}
+	return key, nil
+}
+
+func (c *Config) GetKanboardTLSConfig() (*tls.Config, error) {
+	t := c.Kanboard.TLS
+	if t.CACertFile == "" && t.ClientCertFile == "" && t.ClientKeyFile == "" && !t.InsecureSkipVerify {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: t.InsecureSkipVerify,
+	}
+
+	if t.CACertFile != "" {
+		pem, err := os.ReadFile(t.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no valid certificates found in %s", t.CACertFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if t.ClientCertFile != "" || t.ClientKeyFile != "" {
+		if t.ClientCertFile == "" || t.ClientKeyFile == "" {
+			return nil, fmt.Errorf("both client certificate and client key must be set")
+		}
+
+		cert, err := tls.LoadX509KeyPair(t.ClientCertFile, t.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+func (c *Config) GetCalendar() (*calendar.Calendar, error) {
+	holidays := c.Calendar.Holidays
+	if c.Calendar.HolidaysFile != "" {
+		fromFile, err := calendar.ReadHolidays(c.Calendar.HolidaysFile)
+		if err != nil {
+			return nil, err
+		}
+		holidays = append(append([]string{}, holidays...), fromFile...)
+	}
+
+	return calendar.New(c.Calendar.WorkDays, holidays)
+}
+
+// GetIPFilter parses the HTTP transport's allowlist and denylist. Bare
+// addresses are treated as single-host prefixes.
+func (c *Config) GetIPFilter() (allowed, denied []netip.Prefix, err error) {
+	allowed, err = parsePrefixes(c.Server.AllowedIPs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid allowed IPs: %w", err)
+	}
+
+	denied, err = parsePrefixes(c.Server.DeniedIPs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid denied IPs: %w", err)
+	}
+
+	return allowed, denied, nil
+}
+
+func parsePrefixes(values []string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		if strings.Contains(value, "/") {
+			prefix, err := netip.ParsePrefix(value)
+			if err != nil {
+				return nil, err
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return nil, err
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
+
+// GetTimezone returns the location used for date boundaries when a user's
+// Kanboard profile has no timezone set.
+func (c *Config) GetTimezone() (*time.Location, error) {
+	if c.Calendar.Timezone == "" {
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(c.Calendar.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", c.Calendar.Timezone, err)
+	}
+	return location, nil
+}
+
 func (c *Config) Validate() error {
 	if c.Kanboard.DefaultURL == "" {
 		return fmt.Errorf("default Kanboard URL is required")
 	}
 
+	if c.Server.Transport != "stdio" && c.Server.Transport != "http" {
+		return fmt.Errorf("invalid transport type: %s. Must be 'stdio' or 'http'", c.Server.Transport)
+	}
+
+	if _, err := logging.ParseLevel(c.Log.Level); err != nil {
+		return err
+	}
+
+	if _, err := logging.ParseUserIDMode(c.Log.UserIDs); err != nil {
+		return err
+	}
+
+	if c.Kanboard.AuthHeader != "authorization" && c.Kanboard.AuthHeader != "x-api-auth" {
+		return fmt.Errorf("invalid kanboard auth header: %s. Must be 'authorization' or 'x-api-auth'", c.Kanboard.AuthHeader)
+	}
+
+	if c.Kanboard.Retry.MaxRetries < 0 {
+		return fmt.Errorf("kanboard max retries cannot be negative")
+	}
+
+	if c.Kanboard.RateLimit.RequestsPerSecond < 0 || c.Kanboard.RateLimit.Burst < 0 {
+		return fmt.Errorf("kanboard rate limit values cannot be negative")
+	}
+
+	if c.Kanboard.SlowCalls.Threshold < 0 || c.Kanboard.SlowCalls.SummaryInterval < 0 {
+		return fmt.Errorf("kanboard slow-call settings cannot be negative")
+	}
+
 	if c.Server.Port == "" {
 		return fmt.Errorf("server port is required")
 	}
 
+	if c.Server.SignatureMaxAge <= 0 {
+		return fmt.Errorf("signature max age must be positive")
+	}
+
+	if c.Server.CompressMinSize < 0 {
+		return fmt.Errorf("compression minimum size cannot be negative")
+	}
+
+	if c.Security.CredentialLifetime < 0 {
+		return fmt.Errorf("credential lifetime cannot be negative")
+	}
+
 	if c.Storage.DataDir == "" {
 		return fmt.Errorf("data directory is required")
 	}
 
+	if c.Audit.Retention < 0 {
+		return fmt.Errorf("audit retention cannot be negative")
+	}
+
+	if c.Quota.DailyCalls < 0 || c.Quota.DailyBytes < 0 {
+		return fmt.Errorf("quota limits cannot be negative")
+	}
+	for userID, limits := range c.Quota.Users {
+		if limits.DailyCalls < 0 || limits.DailyBytes < 0 {
+			return fmt.Errorf("quota limits for user %s cannot be negative", userID)
+		}
+	}
+
+	if c.ToolRateLimit.RequestsPerSecond < 0 || c.ToolRateLimit.Burst < 0 {
+		return fmt.Errorf("tool rate limit values cannot be negative")
+	}
+	for userID, limits := range c.ToolRateLimit.Users {
+		if limits.RequestsPerSecond < 0 || limits.Burst < 0 {
+			return fmt.Errorf("tool rate limit values for user %s cannot be negative", userID)
+		}
+	}
+
+	if c.Security.EncryptionKeyRefresh < 0 {
+		return fmt.Errorf("encryption key refresh interval cannot be negative")
+	}
+
+	if c.Lockout.Threshold < 0 {
+		return fmt.Errorf("lockout threshold cannot be negative")
+	}
+	if c.Lockout.Threshold > 0 && (c.Lockout.Ban <= 0 || c.Lockout.MaxBan < c.Lockout.Ban) {
+		return fmt.Errorf("lockout ban must be positive and no longer than the maximum ban")
+	}
+
+	if c.Kanboard.Cache.WarmInterval < 0 {
+		return fmt.Errorf("cache warm interval cannot be negative")
+	}
+	if c.Kanboard.Cache.WarmInterval > 0 && c.Kanboard.Cache.WarmActiveWithin <= 0 {
+		return fmt.Errorf("cache warm active window must be positive")
+	}
+
+	if c.TaskSync.Enabled && c.TaskSync.Interval <= 0 {
+		return fmt.Errorf("task sync interval must be positive")
+	}
+
+	if c.Webhook.Secret != "" && c.Webhook.Instance == "" && c.Kanboard.DefaultURL == "" {
+		return fmt.Errorf("webhook instance is required when there is no default Kanboard URL")
+	}
+	if c.Webhook.Retention < 0 {
+		return fmt.Errorf("webhook event retention cannot be negative")
+	}
+
+	if periods, err := c.Digest.GetPeriods(); err != nil {
+		return err
+	} else if len(periods) > 0 {
+		if _, _, _, err := c.Digest.GetSchedule(); err != nil {
+			return err
+		}
+	}
+	if c.Digest.WebhookURL != "" {
+		if u, err := url.Parse(c.Digest.WebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid digest webhook URL %q", c.Digest.WebhookURL)
+		}
+	}
+
+	if c.Notify.Enabled {
+		if _, _, err := c.Notify.GetTime(); err != nil {
+			return err
+		}
+	}
+	if c.Notify.SMTP.Host != "" {
+		if c.Notify.SMTP.Port <= 0 || c.Notify.SMTP.Port > 65535 {
+			return fmt.Errorf("invalid SMTP port %d", c.Notify.SMTP.Port)
+		}
+		if _, err := mail.ParseAddress(c.Notify.SMTP.From); err != nil {
+			return fmt.Errorf("invalid SMTP from address %q: %w", c.Notify.SMTP.From, err)
+		}
+		if (c.Notify.SMTP.Username == "") != (c.Notify.SMTP.Password == "") {
+			return fmt.Errorf("SMTP username and password must be set together")
+		}
+	}
+
+	if c.Confirm.TTL <= 0 {
+		return fmt.Errorf("confirmation TTL must be positive")
+	}
+
 	_, err := c.GetEncryptionKey()
 	if err != nil {
 		return fmt.Errorf("encryption key validation failed: %w", err)
 	}
 
+	if _, err := c.GetKanboardTLSConfig(); err != nil {
+		return fmt.Errorf("kanboard TLS validation failed: %w", err)
+	}
+
+	if _, err := c.GetCalendar(); err != nil {
+		return fmt.Errorf("calendar validation failed: %w", err)
+	}
+
+	if _, err := c.GetTimezone(); err != nil {
+		return fmt.Errorf("calendar validation failed: %w", err)
+	}
+
+	if _, _, err := c.GetIPFilter(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
-func getEnvOrDefault(key, defaultValue string) string {
+func setStringFromEnv(target *string, key string) {
 	if value := os.Getenv(key); value != "" {
-		return value
+		*target = value
+	}
+}
+
+func setDurationFromEnv(target *time.Duration, key string) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s value %q: %w", key, value, err)
+	}
+
+	*target = duration
+	return nil
+}
+
+func setIntFromEnv(target *int, key string) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s value %q: %w", key, value, err)
+	}
+
+	*target = parsed
+	return nil
+}
+
+func setFloatFromEnv(target *float64, key string) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %s value %q: %w", key, value, err)
+	}
+
+	*target = parsed
+	return nil
+}
+
+func setBoolFromEnv(target *bool, key string) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
 	}
-	return defaultValue
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s value %q: %w", key, value, err)
+	}
+
+	*target = parsed
+	return nil
 }
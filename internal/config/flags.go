@@ -0,0 +1,120 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+func (c *Config) bindFlags(fs *flag.FlagSet, configFile string) {
+	fs.String("config", configFile, envHelp("Path to a YAML configuration file", ConfigFileEnv))
+
+	fs.StringVar(&c.Log.Level, "log-level", c.Log.Level, envHelp("Log level (debug, info, warn, error)", "LOG_LEVEL"))
+	fs.StringVar(&c.Log.UserIDs, "log-user-ids", c.Log.UserIDs, envHelp("How user IDs appear in logs (hash, plain or omit)", "LOG_USER_IDS"))
+
+	fs.StringVar(&c.Server.Transport, "transport", c.Server.Transport, envHelp("Transport type (stdio or http)", "MCP_TRANSPORT"))
+	fs.StringVar(&c.Server.Transport, "t", c.Server.Transport, "Shorthand for -transport")
+	fs.StringVar(&c.Server.Host, "host", c.Server.Host, envHelp("HTTP listen host", "MCP_HOST"))
+	fs.StringVar(&c.Server.Port, "port", c.Server.Port, envHelp("HTTP listen port", "MCP_PORT"))
+	fs.Func("allowed-ips", envHelp("Comma-separated addresses or CIDRs allowed to reach the HTTP transport", "MCP_ALLOWED_IPS"), func(value string) error {
+		c.Server.AllowedIPs = strings.Split(value, ",")
+		return nil
+	})
+	fs.Func("denied-ips", envHelp("Comma-separated addresses or CIDRs refused by the HTTP transport", "MCP_DENIED_IPS"), func(value string) error {
+		c.Server.DeniedIPs = strings.Split(value, ",")
+		return nil
+	})
+	fs.BoolVar(&c.Server.TrustForwardedFor, "trust-forwarded-for", c.Server.TrustForwardedFor, envHelp("Take the client address from X-Forwarded-For when filtering IPs", "MCP_TRUST_FORWARDED_FOR"))
+	fs.BoolVar(&c.Server.RequireSignatures, "require-signatures", c.Server.RequireSignatures, envHelp("Reject HTTP requests that are not HMAC-signed, even for users without a signing secret", "MCP_REQUIRE_SIGNATURES"))
+	fs.DurationVar(&c.Server.SignatureMaxAge, "signature-max-age", c.Server.SignatureMaxAge, envHelp("Maximum age and clock skew accepted on signed HTTP requests", "MCP_SIGNATURE_MAX_AGE"))
+	fs.IntVar(&c.Server.CompressMinSize, "compress-min-size", c.Server.CompressMinSize, envHelp("Smallest HTTP response in bytes to gzip or deflate for clients that accept it (0 disables)", "MCP_COMPRESS_MIN_SIZE"))
+	fs.BoolVar(&c.Server.ReadOnly, "read-only", c.Server.ReadOnly, envHelp("Register no tools that change Kanboard data and refuse mutating API calls", "READ_ONLY"))
+	fs.StringVar(&c.Server.DebugPort, "debug-port", c.Server.DebugPort, envHelp("Serve pprof and expvar on this localhost-only port (empty disables)", "MCP_DEBUG_PORT"))
+
+	fs.StringVar(&c.Kanboard.DefaultURL, "default-kanboard-url", c.Kanboard.DefaultURL, envHelp("Default Kanboard URL", "DEFAULT_KANBOARD_URL"))
+	fs.StringVar(&c.Kanboard.AuthHeader, "kanboard-auth-header", c.Kanboard.AuthHeader, envHelp("Header carrying Kanboard credentials (authorization or x-api-auth)", "KANBOARD_AUTH_HEADER"))
+	fs.DurationVar(&c.Kanboard.Timeout, "kanboard-timeout", c.Kanboard.Timeout, envHelp("Timeout for Kanboard API requests", "KANBOARD_TIMEOUT"))
+	fs.StringVar(&c.Kanboard.TLS.CACertFile, "kanboard-ca-cert", c.Kanboard.TLS.CACertFile, envHelp("PEM CA bundle used to verify Kanboard", "KANBOARD_CA_CERT"))
+	fs.StringVar(&c.Kanboard.TLS.ClientCertFile, "kanboard-client-cert", c.Kanboard.TLS.ClientCertFile, envHelp("PEM client certificate for Kanboard", "KANBOARD_CLIENT_CERT"))
+	fs.StringVar(&c.Kanboard.TLS.ClientKeyFile, "kanboard-client-key", c.Kanboard.TLS.ClientKeyFile, envHelp("PEM client key for Kanboard", "KANBOARD_CLIENT_KEY"))
+	fs.BoolVar(&c.Kanboard.TLS.InsecureSkipVerify, "kanboard-insecure-skip-verify", c.Kanboard.TLS.InsecureSkipVerify, envHelp("Disable Kanboard TLS certificate verification (not recommended)", "KANBOARD_INSECURE_SKIP_VERIFY"))
+	fs.IntVar(&c.Kanboard.Retry.MaxRetries, "kanboard-max-retries", c.Kanboard.Retry.MaxRetries, envHelp("Retries for idempotent Kanboard requests after transient failures", "KANBOARD_MAX_RETRIES"))
+	fs.DurationVar(&c.Kanboard.Retry.BaseDelay, "kanboard-retry-base-delay", c.Kanboard.Retry.BaseDelay, envHelp("Initial retry backoff delay", "KANBOARD_RETRY_BASE_DELAY"))
+	fs.DurationVar(&c.Kanboard.Retry.MaxDelay, "kanboard-retry-max-delay", c.Kanboard.Retry.MaxDelay, envHelp("Maximum retry backoff delay", "KANBOARD_RETRY_MAX_DELAY"))
+	fs.Float64Var(&c.Kanboard.RateLimit.RequestsPerSecond, "kanboard-rate-limit", c.Kanboard.RateLimit.RequestsPerSecond, envHelp("Maximum Kanboard requests per second per instance (0 disables)", "KANBOARD_RATE_LIMIT_RPS"))
+	fs.IntVar(&c.Kanboard.RateLimit.Burst, "kanboard-rate-burst", c.Kanboard.RateLimit.Burst, envHelp("Burst size for the Kanboard rate limiter", "KANBOARD_RATE_LIMIT_BURST"))
+	fs.DurationVar(&c.Kanboard.Cache.ProjectsTTL, "kanboard-cache-projects-ttl", c.Kanboard.Cache.ProjectsTTL, envHelp("Cache lifetime for project lists (0 disables)", "KANBOARD_CACHE_PROJECTS_TTL"))
+	fs.DurationVar(&c.Kanboard.Cache.ColumnsTTL, "kanboard-cache-columns-ttl", c.Kanboard.Cache.ColumnsTTL, envHelp("Cache lifetime for project columns (0 disables)", "KANBOARD_CACHE_COLUMNS_TTL"))
+	fs.DurationVar(&c.Kanboard.Cache.SwimlanesTTL, "kanboard-cache-swimlanes-ttl", c.Kanboard.Cache.SwimlanesTTL, envHelp("Cache lifetime for project swimlanes (0 disables)", "KANBOARD_CACHE_SWIMLANES_TTL"))
+	fs.DurationVar(&c.Kanboard.Cache.UsersTTL, "kanboard-cache-users-ttl", c.Kanboard.Cache.UsersTTL, envHelp("Cache lifetime for project members (0 disables)", "KANBOARD_CACHE_USERS_TTL"))
+	fs.DurationVar(&c.Kanboard.Cache.CategoriesTTL, "kanboard-cache-categories-ttl", c.Kanboard.Cache.CategoriesTTL, envHelp("Cache lifetime for project categories and tags (0 disables)", "KANBOARD_CACHE_CATEGORIES_TTL"))
+	fs.DurationVar(&c.Kanboard.Cache.OverviewTTL, "kanboard-cache-overview-ttl", c.Kanboard.Cache.OverviewTTL, envHelp("Cache lifetime for assembled kanboard_overview responses (0 disables)", "KANBOARD_CACHE_OVERVIEW_TTL"))
+	fs.DurationVar(&c.Kanboard.Cache.WarmInterval, "kanboard-cache-warm-interval", c.Kanboard.Cache.WarmInterval, envHelp("How often to prefetch project lists and board structure for recently active users (0 disables)", "KANBOARD_CACHE_WARM_INTERVAL"))
+	fs.DurationVar(&c.Kanboard.Cache.WarmActiveWithin, "kanboard-cache-warm-active-within", c.Kanboard.Cache.WarmActiveWithin, envHelp("Warm the cache for users who called a tool within this long", "KANBOARD_CACHE_WARM_ACTIVE_WITHIN"))
+	fs.IntVar(&c.Kanboard.Breaker.FailureThreshold, "kanboard-breaker-threshold", c.Kanboard.Breaker.FailureThreshold, envHelp("Consecutive failures before a Kanboard instance is marked unavailable (0 disables)", "KANBOARD_BREAKER_THRESHOLD"))
+	fs.DurationVar(&c.Kanboard.Breaker.OpenDuration, "kanboard-breaker-open-duration", c.Kanboard.Breaker.OpenDuration, envHelp("How long to fail fast before probing an unavailable instance again", "KANBOARD_BREAKER_OPEN_DURATION"))
+	fs.IntVar(&c.Kanboard.Transport.MaxIdleConns, "kanboard-max-idle-conns", c.Kanboard.Transport.MaxIdleConns, envHelp("Maximum idle keep-alive connections across Kanboard instances", "KANBOARD_MAX_IDLE_CONNS"))
+	fs.IntVar(&c.Kanboard.Transport.MaxIdleConnsPerHost, "kanboard-max-idle-conns-per-host", c.Kanboard.Transport.MaxIdleConnsPerHost, envHelp("Maximum idle keep-alive connections per Kanboard instance", "KANBOARD_MAX_IDLE_CONNS_PER_HOST"))
+	fs.DurationVar(&c.Kanboard.Transport.IdleConnTimeout, "kanboard-idle-conn-timeout", c.Kanboard.Transport.IdleConnTimeout, envHelp("How long idle Kanboard connections are kept open", "KANBOARD_IDLE_CONN_TIMEOUT"))
+	fs.IntVar(&c.Kanboard.WorkerPool.Workers, "kanboard-workers", c.Kanboard.WorkerPool.Workers, envHelp("Maximum per-project Kanboard jobs running at once across all tool calls (0 disables the limit)", "KANBOARD_WORKERS"))
+	fs.IntVar(&c.Kanboard.WorkerPool.QueueSize, "kanboard-queue-size", c.Kanboard.WorkerPool.QueueSize, envHelp("Maximum jobs waiting for a worker before new ones are rejected (0 is unbounded)", "KANBOARD_QUEUE_SIZE"))
+	fs.DurationVar(&c.Kanboard.WorkerPool.QueueTimeout, "kanboard-queue-timeout", c.Kanboard.WorkerPool.QueueTimeout, envHelp("Longest a job waits for a worker before the call fails as busy (0 waits indefinitely)", "KANBOARD_QUEUE_TIMEOUT"))
+	fs.DurationVar(&c.Kanboard.SlowCalls.Threshold, "kanboard-slow-call-threshold", c.Kanboard.SlowCalls.Threshold, envHelp("Log Kanboard requests taking at least this long (0 disables)", "KANBOARD_SLOW_CALL_THRESHOLD"))
+	fs.DurationVar(&c.Kanboard.SlowCalls.SummaryInterval, "kanboard-slow-call-summary-interval", c.Kanboard.SlowCalls.SummaryInterval, envHelp("How often to log the slowest Kanboard methods (0 disables)", "KANBOARD_SLOW_CALL_SUMMARY_INTERVAL"))
+
+	fs.StringVar(&c.Security.EncryptionKeyEnv, "encryption-key-env", c.Security.EncryptionKeyEnv, envHelp("Name of the environment variable holding the encryption key", "ENCRYPTION_KEY_ENV"))
+	fs.StringVar(&c.Security.EncryptionKeySource, "encryption-key-source", c.Security.EncryptionKeySource, envHelp("Read the encryption key from file:<path>, vault:<path>[#field] or aws-sm:<secret id>[#field]", "ENCRYPTION_KEY_SOURCE"))
+	fs.DurationVar(&c.Security.EncryptionKeyRefresh, "encryption-key-refresh", c.Security.EncryptionKeyRefresh, envHelp("How often the server re-reads the encryption key source to pick up rotations (0 disables)", "ENCRYPTION_KEY_REFRESH"))
+	fs.DurationVar(&c.Security.CredentialLifetime, "credential-lifetime", c.Security.CredentialLifetime, envHelp("How long registrations stay valid before they must be renewed (0 never expires)", "CREDENTIAL_LIFETIME"))
+	fs.BoolVar(&c.Security.PseudonymizeUsers, "pseudonymize-users", c.Security.PseudonymizeUsers, envHelp("Replace Kanboard usernames and names in tool responses with stable pseudonyms", "PSEUDONYMIZE_USERS"))
+	fs.StringVar(&c.Storage.DataDir, "data-dir", c.Storage.DataDir, envHelp("Directory for user data storage", "DATA_DIR"))
+
+	fs.StringVar(&c.Calendar.WorkDays, "work-days", c.Calendar.WorkDays, envHelp("Comma-separated working weekdays used for due-date calculations", "WORK_DAYS"))
+	fs.Func("holidays", envHelp("Comma-separated YYYY-MM-DD holidays excluded from working days", "HOLIDAYS"), func(value string) error {
+		c.Calendar.Holidays = strings.Split(value, ",")
+		return nil
+	})
+	fs.StringVar(&c.Calendar.HolidaysFile, "holidays-file", c.Calendar.HolidaysFile, envHelp("File listing one YYYY-MM-DD holiday per line", "HOLIDAYS_FILE"))
+	fs.StringVar(&c.Calendar.Timezone, "timezone", c.Calendar.Timezone, envHelp("IANA timezone for date boundaries when a user's Kanboard profile has none", "DEFAULT_TIMEZONE"))
+
+	fs.DurationVar(&c.Audit.Retention, "audit-retention", c.Audit.Retention, envHelp("How long tool calls are kept in the audit log (0 keeps them forever)", "AUDIT_RETENTION"))
+	fs.IntVar(&c.Quota.DailyCalls, "quota-daily-calls", c.Quota.DailyCalls, envHelp("Tool calls each user may make per day (0 is unlimited)", "QUOTA_DAILY_CALLS"))
+	fs.IntVar(&c.Quota.DailyBytes, "quota-daily-bytes", c.Quota.DailyBytes, envHelp("Response bytes each user may receive per day (0 is unlimited)", "QUOTA_DAILY_BYTES"))
+	fs.Func("confirm-tools", envHelp("Comma-separated write tools that need a confirmation token from a preview call (* for all)", "CONFIRM_TOOLS"), func(value string) error {
+		c.Confirm.Tools = strings.Split(value, ",")
+		return nil
+	})
+	fs.DurationVar(&c.Confirm.TTL, "confirm-ttl", c.Confirm.TTL, envHelp("How long a confirmation token stays valid", "CONFIRM_TTL"))
+	fs.Float64Var(&c.ToolRateLimit.RequestsPerSecond, "tool-rate-limit", c.ToolRateLimit.RequestsPerSecond, envHelp("Maximum tool calls per second per user (0 disables)", "TOOL_RATE_LIMIT_RPS"))
+	fs.IntVar(&c.ToolRateLimit.Burst, "tool-rate-burst", c.ToolRateLimit.Burst, envHelp("Burst size for the per-user tool call limiter", "TOOL_RATE_LIMIT_BURST"))
+	fs.IntVar(&c.Lockout.Threshold, "lockout-threshold", c.Lockout.Threshold, envHelp("Unknown user IDs an HTTP client may send before it is banned (0 disables)", "LOCKOUT_THRESHOLD"))
+	fs.DurationVar(&c.Lockout.Ban, "lockout-ban", c.Lockout.Ban, envHelp("First ban for a client guessing user IDs, doubled with each further failure", "LOCKOUT_BAN"))
+	fs.DurationVar(&c.Lockout.MaxBan, "lockout-max-ban", c.Lockout.MaxBan, envHelp("Longest ban for a client guessing user IDs", "LOCKOUT_MAX_BAN"))
+	fs.BoolVar(&c.TaskSync.Enabled, "task-sync", c.TaskSync.Enabled, envHelp("Keep a local snapshot of each user's closed tasks, synced in the background", "TASK_SYNC"))
+	fs.DurationVar(&c.TaskSync.Interval, "task-sync-interval", c.TaskSync.Interval, envHelp("How often the task snapshot is synced with Kanboard", "TASK_SYNC_INTERVAL"))
+	fs.StringVar(&c.Webhook.Instance, "kanboard-webhook-instance", c.Webhook.Instance, envHelp("Kanboard URL whose webhooks are accepted (default: the default Kanboard URL)", "KANBOARD_WEBHOOK_INSTANCE"))
+	fs.DurationVar(&c.Webhook.Retention, "kanboard-webhook-retention", c.Webhook.Retention, envHelp("How long received Kanboard webhook events are kept (0 keeps them forever)", "KANBOARD_WEBHOOK_RETENTION"))
+	fs.Func("digest-periods", envHelp("Comma-separated digests to generate for every user: daily, weekly or both (empty disables)", "DIGEST_PERIODS"), func(value string) error {
+		c.Digest.Periods = strings.Split(value, ",")
+		return nil
+	})
+	fs.StringVar(&c.Digest.Time, "digest-time", c.Digest.Time, envHelp("Time of day, HH:MM in the default timezone, at which digests are generated", "DIGEST_TIME"))
+	fs.StringVar(&c.Digest.Weekday, "digest-weekday", c.Digest.Weekday, envHelp("Day of the week on which weekly digests are generated", "DIGEST_WEEKDAY"))
+	fs.StringVar(&c.Digest.WebhookURL, "digest-webhook-url", c.Digest.WebhookURL, envHelp("Post each generated digest as JSON to this URL (empty disables)", "DIGEST_WEBHOOK_URL"))
+	fs.BoolVar(&c.Notify.Enabled, "notifications", c.Notify.Enabled, envHelp("Send daily overdue and due-today alerts to the users who opted in with 'user notify'", "NOTIFICATIONS"))
+	fs.StringVar(&c.Notify.Time, "notify-time", c.Notify.Time, envHelp("Time of day, HH:MM in the default timezone, at which alerts are sent", "NOTIFY_TIME"))
+	fs.StringVar(&c.Notify.SMTP.Host, "smtp-host", c.Notify.SMTP.Host, envHelp("SMTP server alert emails are sent through (empty disables email)", "SMTP_HOST"))
+	fs.IntVar(&c.Notify.SMTP.Port, "smtp-port", c.Notify.SMTP.Port, envHelp("SMTP server port; 465 uses implicit TLS, others STARTTLS when offered", "SMTP_PORT"))
+	fs.StringVar(&c.Notify.SMTP.Username, "smtp-username", c.Notify.SMTP.Username, envHelp("SMTP username (empty sends without authenticating)", "SMTP_USERNAME"))
+	fs.StringVar(&c.Notify.SMTP.From, "smtp-from", c.Notify.SMTP.From, envHelp("From address of alert emails", "SMTP_FROM"))
+}
+
+// RegisterFlags adds every configuration flag to fs without loading anything,
+// for help output and shell completion.
+func RegisterFlags(fs *flag.FlagSet) {
+	defaultConfig().bindFlags(fs, "")
+}
+
+func envHelp(usage, env string) string {
+	return fmt.Sprintf("%s (env: %s)", usage, env)
+}
@@ -0,0 +1,360 @@
+// Package dateparse turns the date phrases people and language models write,
+// such as "next friday", "in 2 weeks" or "end of month", into calendar days.
+package dateparse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/calendar"
+)
+
+// Range is the span of days a phrase names, from the start of Start's day to
+// the end of End's day. Phrases naming a single day have Start equal to End;
+// "next week" runs from Monday to Sunday.
+type Range struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Examples is a short list of accepted phrases for error messages and tool
+// descriptions.
+const Examples = "'today', 'tomorrow', 'friday', 'next monday', 'in 3 days', 'in 2 weeks', '2 working days', 'end of month', 'next week', 'march 15' or 'YYYY-MM-DD'"
+
+// Parse resolves phrase against now, in now's timezone. Weeks start on
+// Monday. A bare weekday is its next occurrence from today, so "friday" on a
+// Friday is today; "next friday" is the first Friday after today and "last
+// friday" the most recent one before it. Working days follow cal, which may
+// be nil for Monday to Friday. Month names without a year are in the current
+// year.
+func Parse(phrase string, now time.Time, cal *calendar.Calendar) (Range, error) {
+	today := startOfDay(now)
+	raw := strings.TrimSpace(phrase)
+	if raw == "" {
+		return Range{}, fmt.Errorf("empty date")
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02", raw, now.Location()); err == nil {
+		return day(t), nil
+	}
+	if t, err := time.Parse(time.RFC3339, strings.ToUpper(raw)); err == nil {
+		return day(startOfDay(t.In(now.Location()))), nil
+	}
+
+	if r, ok := parseWords(normalise(raw), today, cal); ok {
+		return r, nil
+	}
+	return Range{}, fmt.Errorf("unrecognised date %q: use %s", phrase, Examples)
+}
+
+func parseWords(words []string, today time.Time, cal *calendar.Calendar) (Range, bool) {
+	if len(words) == 0 {
+		return Range{}, false
+	}
+
+	switch strings.Join(words, " ") {
+	case "today", "now", "tonight", "eod", "end of day", "end of today":
+		return day(today), true
+	case "tomorrow", "tmrw", "tmr":
+		return day(today.AddDate(0, 0, 1)), true
+	case "yesterday":
+		return day(today.AddDate(0, 0, -1)), true
+	case "day after tomorrow":
+		return day(today.AddDate(0, 0, 2)), true
+	case "day before yesterday":
+		return day(today.AddDate(0, 0, -2)), true
+	case "next working day", "next business day", "next workday":
+		return day(addWorkDays(today, 1, cal)), true
+	case "eow":
+		return day(period("week", today, 0).End), true
+	case "eom":
+		return day(period("month", today, 0).End), true
+	case "eoq":
+		return day(period("quarter", today, 0).End), true
+	case "eoy":
+		return day(period("year", today, 0).End), true
+	}
+
+	// [this|next|last] <weekday>
+	if len(words) <= 2 {
+		modifier, name := "", words[len(words)-1]
+		if len(words) == 2 {
+			modifier = words[0]
+		}
+		if wd, ok := weekdays[name]; ok {
+			offset := (int(wd) - int(today.Weekday()) + 7) % 7
+			switch modifier {
+			case "", "this", "on", "coming", "by":
+			case "next":
+				if offset == 0 {
+					offset = 7
+				}
+			case "last", "previous", "past":
+				offset -= 7
+			default:
+				return Range{}, false
+			}
+			return day(today.AddDate(0, 0, offset)), true
+		}
+	}
+
+	// this|next|last week|month|quarter|year, and the same with
+	// "start of", "beginning of" or "end of" in front.
+	edge := ""
+	rest := words
+	for _, prefix := range [][]string{{"start", "of"}, {"beginning", "of"}, {"end", "of"}} {
+		if hasPrefix(rest, prefix) {
+			edge, rest = prefix[0], rest[len(prefix):]
+			break
+		}
+	}
+	if len(rest) > 0 && rest[0] == "the" {
+		rest = rest[1:]
+	}
+	if len(rest) == 1 || len(rest) == 2 {
+		unit, shift, ok := rest[len(rest)-1], 0, true
+		if len(rest) == 2 {
+			switch rest[0] {
+			case "this", "current":
+			case "next", "following":
+				shift = 1
+			case "last", "previous":
+				shift = -1
+			default:
+				ok = false
+			}
+		} else if edge == "" {
+			// A bare "week" or "month" is not a date.
+			ok = false
+		}
+		if ok && isPeriod(unit) {
+			r := period(unit, today, shift)
+			switch edge {
+			case "start", "beginning":
+				return day(r.Start), true
+			case "end":
+				return day(r.End), true
+			}
+			return r, true
+		}
+	}
+
+	// in N units, N units from now, N units ago, and N units on its own.
+	if r, ok := parseOffset(words, today, cal); ok {
+		return r, true
+	}
+
+	// Month names with an optional day and year.
+	if r, ok := parseMonthDate(words, today); ok {
+		return r, true
+	}
+
+	return Range{}, false
+}
+
+func parseOffset(words []string, today time.Time, cal *calendar.Calendar) (Range, bool) {
+	sign := 1
+	switch {
+	case len(words) > 0 && words[0] == "in":
+		words = words[1:]
+	case hasSuffix(words, []string{"from", "now"}):
+		words = words[:len(words)-2]
+	case hasSuffix(words, []string{"from", "today"}):
+		words = words[:len(words)-2]
+	case hasSuffix(words, []string{"ago"}):
+		words = words[:len(words)-1]
+		sign = -1
+	}
+	if len(words) < 2 || len(words) > 3 {
+		return Range{}, false
+	}
+
+	n, ok := number(words[0])
+	if !ok {
+		return Range{}, false
+	}
+	n *= sign
+
+	unit := strings.Join(words[1:], " ")
+	switch strings.TrimSuffix(unit, "s") {
+	case "day":
+		return day(today.AddDate(0, 0, n)), true
+	case "working day", "business day", "workday", "work day":
+		return day(addWorkDays(today, n, cal)), true
+	case "week":
+		return day(today.AddDate(0, 0, 7*n)), true
+	case "fortnight":
+		return day(today.AddDate(0, 0, 14*n)), true
+	case "month":
+		return day(addMonths(today, n)), true
+	case "quarter":
+		return day(addMonths(today, 3*n)), true
+	case "year":
+		return day(addMonths(today, 12*n)), true
+	}
+	return Range{}, false
+}
+
+// parseMonthDate accepts "march", "march 2027", "march 15", "15 march",
+// "march 15th 2027" and "15 mar 2027".
+func parseMonthDate(words []string, today time.Time) (Range, bool) {
+	var month time.Month
+	dayOfMonth, year := 0, today.Year()
+	for _, word := range words {
+		if m, ok := months[word]; ok && month == 0 {
+			month = m
+			continue
+		}
+		if word == "of" || word == "the" {
+			continue
+		}
+		digits := strings.TrimRight(word, "stndrh")
+		value, err := strconv.Atoi(digits)
+		if err != nil {
+			return Range{}, false
+		}
+		switch {
+		case len(digits) == 4 && value >= 1970:
+			year = value
+		case dayOfMonth == 0 && value >= 1 && value <= 31:
+			dayOfMonth = value
+		default:
+			return Range{}, false
+		}
+	}
+	if month == 0 {
+		return Range{}, false
+	}
+
+	first := time.Date(year, month, 1, 0, 0, 0, 0, today.Location())
+	if dayOfMonth == 0 {
+		return Range{Start: first, End: first.AddDate(0, 1, -1)}, true
+	}
+	date := first.AddDate(0, 0, dayOfMonth-1)
+	if date.Month() != month {
+		return Range{}, false
+	}
+	return day(date), true
+}
+
+// period returns the week, month, quarter or year containing today, moved by
+// shift of them.
+func period(unit string, today time.Time, shift int) Range {
+	switch unit {
+	case "week":
+		start := today.AddDate(0, 0, -((int(today.Weekday())+6)%7)+7*shift)
+		return Range{Start: start, End: start.AddDate(0, 0, 6)}
+	case "month":
+		start := time.Date(today.Year(), today.Month()+time.Month(shift), 1, 0, 0, 0, 0, today.Location())
+		return Range{Start: start, End: start.AddDate(0, 1, -1)}
+	case "quarter":
+		first := (int(today.Month())-1)/3*3 + 1 + 3*shift
+		start := time.Date(today.Year(), time.Month(first), 1, 0, 0, 0, 0, today.Location())
+		return Range{Start: start, End: start.AddDate(0, 3, -1)}
+	default:
+		start := time.Date(today.Year()+shift, time.January, 1, 0, 0, 0, 0, today.Location())
+		return Range{Start: start, End: start.AddDate(1, 0, -1)}
+	}
+}
+
+func isPeriod(unit string) bool {
+	return unit == "week" || unit == "month" || unit == "quarter" || unit == "year"
+}
+
+// addMonths moves t by n months, clamping to the last day of a shorter
+// month so January 31 plus one month is the end of February.
+func addMonths(t time.Time, n int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(n), 1, 0, 0, 0, 0, t.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(t.Day(), last)-1)
+}
+
+// addWorkDays moves n working days from t, skipping days cal does not work.
+func addWorkDays(t time.Time, n int, cal *calendar.Calendar) time.Time {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	// Bounded so a calendar without working days cannot loop forever.
+	for i := 0; n > 0 && i < 3660; i++ {
+		t = t.AddDate(0, 0, step)
+		if cal.IsWorkDay(t) {
+			n--
+		}
+	}
+	return t
+}
+
+func day(t time.Time) Range {
+	return Range{Start: t, End: t}
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// normalise lower-cases phrase and splits it into words, treating commas,
+// full stops and hyphens as spaces.
+func normalise(phrase string) []string {
+	text := strings.NewReplacer(",", " ", ".", " ", "-", " ").Replace(strings.ToLower(phrase))
+	return strings.Fields(text)
+}
+
+func number(word string) (int, bool) {
+	if n, err := strconv.Atoi(word); err == nil && n >= 0 {
+		return n, true
+	}
+	n, ok := numberWords[word]
+	return n, ok
+}
+
+func hasPrefix(words, prefix []string) bool {
+	if len(words) < len(prefix) {
+		return false
+	}
+	for i, word := range prefix {
+		if words[i] != word {
+			return false
+		}
+	}
+	return true
+}
+
+func hasSuffix(words, suffix []string) bool {
+	if len(words) < len(suffix) {
+		return false
+	}
+	return hasPrefix(words[len(words)-len(suffix):], suffix)
+}
+
+var numberWords = map[string]int{
+	"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6,
+	"seven": 7, "eight": 8, "nine": 9, "ten": 10, "eleven": 11, "twelve": 12,
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+var months = map[string]time.Month{
+	"january": time.January, "jan": time.January,
+	"february": time.February, "feb": time.February,
+	"march": time.March, "mar": time.March,
+	"april": time.April, "apr": time.April,
+	"may":  time.May,
+	"june": time.June, "jun": time.June,
+	"july": time.July, "jul": time.July,
+	"august": time.August, "aug": time.August,
+	"september": time.September, "sep": time.September, "sept": time.September,
+	"october": time.October, "oct": time.October,
+	"november": time.November, "nov": time.November,
+	"december": time.December, "dec": time.December,
+}
@@ -1,11 +1,17 @@
 package handlers
 
 import (
+	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"math"
+	"math/rand/v2"
 	"sort"
+	"strings"
 	"time"
 
+	"github.com/tech-arch1tect/kan-mcp/internal/api"
 	"github.com/tech-arch1tect/kan-mcp/internal/auth"
 	"github.com/tech-arch1tect/kan-mcp/internal/models"
 )
@@ -13,6 +19,7 @@ import (
 type AnalyticsHandler struct {
 	authManager *auth.AuthManager
 	config      *models.UserConfig
+	location    *time.Location
 }
 
 func NewAnalyticsHandler(authManager *auth.AuthManager, config *models.UserConfig) *AnalyticsHandler {
@@ -25,8 +32,12 @@ func NewAnalyticsHandler(authManager *auth.AuthManager, config *models.UserConfi
 type AnalyticsRequest struct {
 	ProjectIDs    []string `json:"project_ids"`
 	TimeRange     string   `json:"time_range"`
+	Bucket        string   `json:"bucket"`
+	Scope         string   `json:"scope"`
 	AnalysisTypes []string `json:"analysis_types"`
 	GroupBy       string   `json:"group_by"`
+	ExportFormat  string   `json:"export_format"`
+	RenderCharts  bool     `json:"render_charts"`
 }
 
 type CompletionTrend struct {
@@ -40,12 +51,24 @@ type CycleTimeMetric struct {
 	Column     string  `json:"column"`
 	Project    string  `json:"project"`
 	AvgDays    float64 `json:"avg_days"`
+	MedianDays float64 `json:"median_days"`
+	P85Days    float64 `json:"p85_days"`
 	MinDays    float64 `json:"min_days"`
 	MaxDays    float64 `json:"max_days"`
 	TaskCount  int     `json:"task_count"`
 	Efficiency string  `json:"efficiency"`
 }
 
+type LeadTimeMetric struct {
+	Project    string  `json:"project"`
+	AvgDays    float64 `json:"avg_days"`
+	MedianDays float64 `json:"median_days"`
+	P85Days    float64 `json:"p85_days"`
+	MinDays    float64 `json:"min_days"`
+	MaxDays    float64 `json:"max_days"`
+	TaskCount  int     `json:"task_count"`
+}
+
 type VelocityMetric struct {
 	Period           string  `json:"period"`
 	TasksCompleted   int     `json:"tasks_completed"`
@@ -72,6 +95,20 @@ type BurndownData struct {
 	TrendProjection int    `json:"trend_projection"`
 }
 
+// CumulativeFlowPoint counts tasks by state at the end of a day. Done only
+// counts tasks completed within the analysis period.
+type CumulativeFlowPoint struct {
+	Date       string `json:"date"`
+	Done       int    `json:"done"`
+	InProgress int    `json:"in_progress"`
+	ToDo       int    `json:"to_do"`
+}
+
+type CumulativeFlow struct {
+	Points []CumulativeFlowPoint `json:"points"`
+	Note   string                `json:"note"`
+}
+
 type ProjectHealthMetric struct {
 	ProjectID        string  `json:"project_id"`
 	ProjectName      string  `json:"project_name"`
@@ -83,110 +120,462 @@ type ProjectHealthMetric struct {
 	RiskLevel        string  `json:"risk_level"`
 }
 
+type ThroughputBucket struct {
+	TasksCompleted int `json:"tasks_completed"`
+	Weeks          int `json:"weeks"`
+}
+
+type PercentileSummary struct {
+	P50   float64 `json:"p50"`
+	P85   float64 `json:"p85"`
+	P95   float64 `json:"p95"`
+	Count int     `json:"count"`
+}
+
+type ThroughputDistribution struct {
+	SampledWeeks int                `json:"sampled_weeks"`
+	Histogram    []ThroughputBucket `json:"histogram"`
+	WeeklyP50    float64            `json:"weekly_p50"`
+	WeeklyP85    float64            `json:"weekly_p85"`
+	WeeklyP95    float64            `json:"weekly_p95"`
+	CycleTime    *PercentileSummary `json:"cycle_time_days,omitempty"`
+}
+
+type ForecastConfidence struct {
+	Confidence int    `json:"confidence"`
+	Weeks      int    `json:"weeks"`
+	Date       string `json:"date"`
+}
+
+type CompletionForecast struct {
+	RemainingTasks int                  `json:"remaining_tasks"`
+	SampledWeeks   int                  `json:"sampled_weeks"`
+	Simulations    int                  `json:"simulations"`
+	Forecasts      []ForecastConfidence `json:"forecasts,omitempty"`
+	Note           string               `json:"note,omitempty"`
+}
+
+type WIPViolation struct {
+	ProjectID         string `json:"project_id"`
+	Project           string `json:"project"`
+	Column            string `json:"column"`
+	TaskLimit         int    `json:"task_limit"`
+	CurrentWIP        int    `json:"current_wip"`
+	MaxWIP            int    `json:"max_wip"`
+	DaysOverLimit     int    `json:"days_over_limit"`
+	LongestStreakDays int    `json:"longest_streak_days"`
+	CurrentlyOver     bool   `json:"currently_over"`
+}
+
+type WIPLimitAnalysis struct {
+	Violations              []WIPViolation `json:"violations"`
+	CycleTimeOverLimitDays  float64        `json:"cycle_time_over_limit_days"`
+	CycleTimeWithinDays     float64        `json:"cycle_time_within_limit_days"`
+	CycleTimeDegradationPct float64        `json:"cycle_time_degradation_pct"`
+	Note                    string         `json:"note"`
+}
+
 type AnalyticsSummary struct {
 	AnalysisPeriod    string   `json:"analysis_period"`
+	Scope             string   `json:"scope,omitempty"`
 	TotalTasks        int      `json:"total_tasks"`
 	CompletedTasks    int      `json:"completed_tasks"`
 	OverallVelocity   float64  `json:"overall_velocity"`
 	AvgCycleTime      float64  `json:"avg_cycle_time"`
+	AvgLeadTime       float64  `json:"avg_lead_time"`
 	ProductivityTrend string   `json:"productivity_trend"`
 	KeyInsights       []string `json:"key_insights"`
 }
 
 type AnalyticsResponse struct {
-	Summary          AnalyticsSummary      `json:"summary"`
-	CompletionTrends []CompletionTrend     `json:"completion_trends,omitempty"`
-	CycleTimeMetrics []CycleTimeMetric     `json:"cycle_time_metrics,omitempty"`
-	VelocityMetrics  []VelocityMetric      `json:"velocity_metrics,omitempty"`
-	TaskAging        []TaskAgingAnalysis   `json:"task_aging,omitempty"`
-	BurndownChart    []BurndownData        `json:"burndown_chart,omitempty"`
-	ProjectHealth    []ProjectHealthMetric `json:"project_health,omitempty"`
+	Summary          AnalyticsSummary        `json:"summary"`
+	CompletionTrends []CompletionTrend       `json:"completion_trends,omitempty"`
+	CycleTimeMetrics []CycleTimeMetric       `json:"cycle_time_metrics,omitempty"`
+	LeadTimeMetrics  []LeadTimeMetric        `json:"lead_time_metrics,omitempty"`
+	VelocityMetrics  []VelocityMetric        `json:"velocity_metrics,omitempty"`
+	Throughput       *ThroughputDistribution `json:"throughput_distribution,omitempty"`
+	TaskAging        []TaskAgingAnalysis     `json:"task_aging,omitempty"`
+	BurndownChart    []BurndownData          `json:"burndown_chart,omitempty"`
+	CumulativeFlow   *CumulativeFlow         `json:"cumulative_flow,omitempty"`
+	ProjectHealth    []ProjectHealthMetric   `json:"project_health,omitempty"`
+	Forecast         *CompletionForecast     `json:"forecast,omitempty"`
+	WIPLimits        *WIPLimitAnalysis       `json:"wip_limits,omitempty"`
+}
+
+func (h *AnalyticsHandler) Handle(ctx context.Context, params map[string]interface{}, userID string) (*models.MCPResponse, error) {
+	req, err := h.parseRequest(params)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.RenderCharts && len(chartsFor(req.AnalysisTypes)) == 0 {
+		return nil, fmt.Errorf("render_charts needs burndown, velocity or cumulative_flow in analysis_types")
+	}
+
+	response, warnings, err := h.analyse(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *models.MCPResponse
+	if req.ExportFormat != "" {
+		result, err = exportResponse(req.ExportFormat, "kanboard-analytics", analyticsExportTables(response), response.Summary, projectWarningMessages(warnings))
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		responseJSON, err := json.MarshalIndent(response, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal analytics response: %w", err)
+		}
+		result = &models.MCPResponse{
+			Content: []models.MCPContent{
+				{
+					Type: "text",
+					Text: string(responseJSON),
+				},
+			},
+		}
+	}
+
+	if req.RenderCharts {
+		for _, name := range chartsFor(req.AnalysisTypes) {
+			chart, ok := analyticsChart(name, response)
+			if !ok {
+				continue
+			}
+			image, err := chart.PNG()
+			if err != nil {
+				return nil, err
+			}
+			result.Content = append(result.Content, models.MCPContent{
+				Type:     "image",
+				Name:     name + ".png",
+				MimeType: "image/png",
+				Data:     base64.StdEncoding.EncodeToString(image),
+			})
+		}
+	}
+
+	return result, nil
 }
 
-func (h *AnalyticsHandler) Handle(params map[string]interface{}, userID string) (*models.MCPResponse, error) {
+func (h *AnalyticsHandler) parseRequest(params map[string]interface{}) (AnalyticsRequest, error) {
 	var req AnalyticsRequest
 	req.TimeRange = "30_days"
-	req.AnalysisTypes = []string{"completion_trends", "cycle_time", "velocity", "task_aging"}
+	req.AnalysisTypes = []string{"completion_trends", "cycle_time", "lead_time", "velocity", "task_aging"}
 	req.GroupBy = "project"
 
 	if params != nil {
 		data, err := json.Marshal(params)
 		if err != nil {
-			return nil, fmt.Errorf("failed to marshal params: %w", err)
+			return AnalyticsRequest{}, fmt.Errorf("failed to marshal params: %w", err)
 		}
 		if err := json.Unmarshal(data, &req); err != nil {
-			return nil, fmt.Errorf("failed to parse analytics request: %w", err)
+			return AnalyticsRequest{}, fmt.Errorf("failed to parse analytics request: %w", err)
 		}
 	}
 
-	tasksHandler := NewTasksHandler(h.authManager, h.config)
-	tasksParams := map[string]interface{}{
-		"project_ids":           req.ProjectIDs,
-		"status_filter":         "all",
-		"include_overdue":       true,
-		"include_time_tracking": true,
-		"sort_by":               "created",
-		"limit":                 500,
-		"summary_mode":          false,
+	if err := validateExportFormat(req.ExportFormat); err != nil {
+		return AnalyticsRequest{}, err
 	}
 
-	tasksResponse, err := tasksHandler.Handle(tasksParams, userID)
+	return req, nil
+}
+
+// analyse streams the tasks req covers through the requested analyses a
+// project at a time, so no more than one project's tasks are held at once.
+func (h *AnalyticsHandler) analyse(ctx context.Context, userID string, req AnalyticsRequest) (AnalyticsResponse, []ProjectWarning, error) {
+	switch req.Bucket {
+	case "":
+		req.Bucket = h.defaultBucket(req.TimeRange)
+	case "day", "week", "month":
+	default:
+		return AnalyticsResponse{}, nil, fmt.Errorf("invalid bucket %q: must be day, week or month", req.Bucket)
+	}
+
+	// Period keys and ages are worked out as each project arrives, so the
+	// user's timezone is needed before streaming starts.
+	client, err := authenticatedClient(h.authManager, h.config, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get tasks data: %w", err)
+		return AnalyticsResponse{}, nil, err
+	}
+	h.location = userLocation(ctx, client, h.config)
+
+	tasksReq := TasksRequest{
+		ProjectIDs:          req.ProjectIDs,
+		StatusFilter:        "all",
+		IncludeOverdue:      true,
+		IncludeTimeTracking: true,
+		ModifiedSince:       h.getTimeRangeStart(req.TimeRange).Format("2006-01-02"),
 	}
 
-	var tasksData TasksResponse
-	if err := json.Unmarshal([]byte(tasksResponse.Content[0].Text), &tasksData); err != nil {
-		return nil, fmt.Errorf("failed to parse tasks response: %w", err)
+	if req.Scope != "" {
+		kind, value := h.parseScope(req.Scope)
+		if value == "" {
+			return AnalyticsResponse{}, nil, fmt.Errorf("invalid scope %q: expected a swimlane name, swimlane:<name> or tag:<name>", req.Scope)
+		}
+		if kind == "tag" {
+			tasksReq.Tag = value
+		} else {
+			tasksReq.Swimlane = value
+		}
 	}
 
-	response := h.performAnalysis(tasksData.Tasks, req)
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-	responseJSON, err := json.MarshalIndent(response, "", "  ")
+	reducer := h.newAnalyticsReducer(req)
+	var reduceErr error
+	set, err := NewTaskService(h.authManager, h.config).Stream(streamCtx, userID, tasksReq, func(projectTasks []TaskDetail) {
+		if reduceErr != nil {
+			return
+		}
+		if reduceErr = reducer.add(streamCtx, client, projectTasks); reduceErr != nil {
+			cancel()
+		}
+	})
+	if reduceErr != nil {
+		return AnalyticsResponse{}, nil, reduceErr
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal analytics response: %w", err)
+		return AnalyticsResponse{}, nil, fmt.Errorf("failed to get tasks data: %w", err)
 	}
 
-	return &models.MCPResponse{
-		Content: []models.MCPContent{
-			{
-				Type: "text",
-				Text: string(responseJSON),
-			},
-		},
-	}, nil
+	return reducer.response(), set.Warnings, nil
 }
 
-func (h *AnalyticsHandler) performAnalysis(tasks []TaskDetail, req AnalyticsRequest) AnalyticsResponse {
-	timeRangeStart := h.getTimeRangeStart(req.TimeRange)
-	filteredTasks := h.filterTasksByTimeRange(tasks, timeRangeStart)
+// parseScope splits "tag:<name>" or "swimlane:<name>"; a bare value is
+// treated as a swimlane name.
+func (h *AnalyticsHandler) parseScope(scope string) (string, string) {
+	if kind, value, ok := strings.Cut(scope, ":"); ok {
+		switch strings.ToLower(strings.TrimSpace(kind)) {
+		case "tag":
+			return "tag", strings.TrimSpace(value)
+		case "swimlane":
+			return "swimlane", strings.TrimSpace(value)
+		}
+	}
+	return "swimlane", strings.TrimSpace(scope)
+}
 
-	var response AnalyticsResponse
+func (h *AnalyticsHandler) wantsAnalysis(req AnalyticsRequest, analysisType string) bool {
+	for _, t := range req.AnalysisTypes {
+		if t == analysisType {
+			return true
+		}
+	}
+	return false
+}
+
+// maxDurationSamples bounds the durations a durationStats keeps for
+// percentiles. There is one per project and column for cycle time, one per
+// project for lead time and one for the throughput distribution, so an
+// analytics call keeps at most 40 KB of samples for each of those however
+// many tasks it covers.
+const maxDurationSamples = 5000
+
+// durationStats accumulates durations in days. Count, average, minimum and
+// maximum are exact; percentiles come from a uniform sample of at most
+// maxDurationSamples durations, which is every duration until there are more.
+type durationStats struct {
+	count   int
+	sum     float64
+	min     float64
+	max     float64
+	samples []float64
+}
+
+func (s *durationStats) add(days float64) {
+	s.count++
+	s.sum += days
+	if s.count == 1 || days < s.min {
+		s.min = days
+	}
+	if s.count == 1 || days > s.max {
+		s.max = days
+	}
+
+	if len(s.samples) < maxDurationSamples {
+		s.samples = append(s.samples, days)
+	} else if i := rand.IntN(s.count); i < maxDurationSamples {
+		s.samples[i] = days
+	}
+}
+
+func (s *durationStats) average() float64 {
+	if s.count == 0 {
+		return 0
+	}
+	return s.sum / float64(s.count)
+}
+
+// analyticsReducer folds each project's tasks into the requested analyses
+// and then drops them. It keeps counters, sums and bounded samples whose size
+// depends on the time range and the number of projects and columns, not on
+// the number of tasks. Stream hands over each project's tasks in one call,
+// which the WIP limit analysis relies on.
+type analyticsReducer struct {
+	h     *AnalyticsHandler
+	req   AnalyticsRequest
+	start time.Time
+	now   time.Time
+
+	summary    summaryReducer
+	trends     map[string]*CompletionTrend
+	cycleTimes map[cycleTimeKey]*durationStats
+	leadTimes  map[string]*durationStats
+	velocity   map[string]*VelocityMetric
+	aging      *agingReducer
+	burndown   *burndownReducer
+	flow       *CumulativeFlow
+	flowDates  []time.Time
+	health     map[string]*projectHealthStats
+	throughput *throughputReducer
+	wip        *wipReducer
+}
+
+func (h *AnalyticsHandler) newAnalyticsReducer(req AnalyticsRequest) *analyticsReducer {
+	r := &analyticsReducer{
+		h:     h,
+		req:   req,
+		start: h.getTimeRangeStart(req.TimeRange),
+		now:   h.now(),
+	}
 
 	for _, analysisType := range req.AnalysisTypes {
 		switch analysisType {
 		case "completion_trends":
-			response.CompletionTrends = h.analyseCompletionTrends(filteredTasks, req.TimeRange)
+			r.trends = make(map[string]*CompletionTrend)
 		case "cycle_time":
-			response.CycleTimeMetrics = h.analyseCycleTime(filteredTasks)
+			r.cycleTimes = make(map[cycleTimeKey]*durationStats)
+		case "lead_time":
+			r.leadTimes = make(map[string]*durationStats)
 		case "velocity":
-			response.VelocityMetrics = h.analyseVelocity(filteredTasks, req.TimeRange)
+			r.velocity = make(map[string]*VelocityMetric)
 		case "task_aging":
-			response.TaskAging = h.analyseTaskAging(filteredTasks)
+			r.aging = newAgingReducer()
 		case "burndown":
-			response.BurndownChart = h.generateBurndownData(filteredTasks, req.TimeRange)
+			r.burndown = h.newBurndownReducer(req.TimeRange, r.start, r.now)
+		case "cumulative_flow":
+			r.flow, r.flowDates = h.newCumulativeFlow(req.TimeRange, r.start, r.now)
 		case "project_health":
-			response.ProjectHealth = h.analyseProjectHealth(filteredTasks)
+			r.health = make(map[string]*projectHealthStats)
+		case "wip_limits":
+			r.wip = newWIPReducer(r.start, r.now)
 		}
 	}
+	if r.velocity != nil || h.wantsAnalysis(req, "forecast") {
+		r.throughput = newThroughputReducer(r.start, r.now)
+	}
+
+	return r
+}
+
+// add folds one project's tasks into every requested analysis. Most
+// analyses only look at tasks created within the time range; throughput,
+// forecasts, cumulative flow and WIP limits also count older tasks still
+// open or completed within it.
+func (r *analyticsReducer) add(ctx context.Context, client *api.Client, tasks []TaskDetail) error {
+	h := r.h
+	for _, task := range tasks {
+		if r.throughput != nil {
+			r.throughput.add(h, task)
+		}
+		if r.flow != nil {
+			h.addCumulativeFlow(r.flow, r.flowDates, r.start, task)
+		}
 
-	response.Summary = h.generateSummary(filteredTasks, req.TimeRange)
+		created, ok := h.parseTaskTime(task.Dates.Created)
+		if !ok || created.Before(r.start) {
+			continue
+		}
+
+		r.summary.add(h, task)
+		if r.trends != nil {
+			h.addCompletionTrend(r.trends, task, created, r.req.Bucket)
+		}
+		if r.cycleTimes != nil {
+			if days, ok := h.taskCycleDays(task); ok {
+				key := cycleTimeKey{project: task.Project.Name, column: task.Status.Column}
+				if r.cycleTimes[key] == nil {
+					r.cycleTimes[key] = &durationStats{}
+				}
+				r.cycleTimes[key].add(days)
+			}
+		}
+		if r.leadTimes != nil {
+			if days, ok := h.taskLeadDays(task); ok {
+				if r.leadTimes[task.Project.Name] == nil {
+					r.leadTimes[task.Project.Name] = &durationStats{}
+				}
+				r.leadTimes[task.Project.Name].add(days)
+			}
+		}
+		if r.velocity != nil {
+			h.addVelocity(r.velocity, task, r.req.Bucket)
+		}
+		if r.aging != nil {
+			r.aging.add(h, task, created, r.now)
+		}
+		if r.burndown != nil {
+			r.burndown.add(h, task, created)
+		}
+		if r.health != nil {
+			h.addProjectHealth(r.health, task)
+		}
+	}
+
+	if r.wip != nil && len(tasks) > 0 {
+		columns, err := projectColumns(ctx, client, tasks[:1])
+		if err != nil {
+			return fmt.Errorf("failed to get column limits: %w", err)
+		}
+		r.wip.add(h, tasks, columns[tasks[0].Project.ID])
+	}
+
+	return nil
+}
+
+// response finishes the analyses, in the order req lists them.
+func (r *analyticsReducer) response() AnalyticsResponse {
+	h := r.h
+	var response AnalyticsResponse
+
+	for _, analysisType := range r.req.AnalysisTypes {
+		switch analysisType {
+		case "completion_trends":
+			response.CompletionTrends = h.completionTrends(r.trends)
+		case "cycle_time":
+			response.CycleTimeMetrics = h.cycleTimeMetrics(r.cycleTimes)
+		case "lead_time":
+			response.LeadTimeMetrics = h.leadTimeMetrics(r.leadTimes)
+		case "velocity":
+			response.VelocityMetrics = h.velocityMetrics(r.velocity)
+			response.Throughput = h.throughputDistribution(r.throughput)
+		case "task_aging":
+			response.TaskAging = r.aging.result()
+		case "burndown":
+			response.BurndownChart = r.burndown.result()
+		case "cumulative_flow":
+			response.CumulativeFlow = r.flow
+		case "project_health":
+			response.ProjectHealth = h.projectHealth(r.health)
+		case "forecast":
+			response.Forecast = h.forecastCompletion(r.throughput, r.now)
+		case "wip_limits":
+			response.WIPLimits = h.wipLimits(r.wip)
+		}
+	}
+
+	response.Summary = r.summary.result(h, r.req.TimeRange)
+	response.Summary.Scope = r.req.Scope
 
 	return response
 }
 
 func (h *AnalyticsHandler) getTimeRangeStart(timeRange string) time.Time {
-	now := time.Now()
+	now := h.now()
 	switch timeRange {
 	case "7_days":
 		return now.AddDate(0, 0, -7)
@@ -207,47 +596,21 @@ func (h *AnalyticsHandler) getTimeRangeStart(timeRange string) time.Time {
 	}
 }
 
-func (h *AnalyticsHandler) filterTasksByTimeRange(tasks []TaskDetail, startTime time.Time) []TaskDetail {
-	var filtered []TaskDetail
-
-	for _, task := range tasks {
-		if task.Dates.Created != "" {
-			if createdDate, err := time.Parse("2006-01-02T15:04:05Z", task.Dates.Created); err == nil {
-				if createdDate.After(startTime) || createdDate.Equal(startTime) {
-					filtered = append(filtered, task)
-				}
-			}
-		}
+func (h *AnalyticsHandler) addCompletionTrend(periods map[string]*CompletionTrend, task TaskDetail, created time.Time, bucket string) {
+	period := h.getPeriodKey(created, bucket)
+	if _, exists := periods[period]; !exists {
+		periods[period] = &CompletionTrend{Period: period}
 	}
 
-	return filtered
-}
-
-func (h *AnalyticsHandler) analyseCompletionTrends(tasks []TaskDetail, timeRange string) []CompletionTrend {
-	periodMap := make(map[string]*CompletionTrend)
-
-	for _, task := range tasks {
-		var period string
-
-		if task.Dates.Created != "" {
-			if createdDate, err := time.Parse("2006-01-02T15:04:05Z", task.Dates.Created); err == nil {
-				period = h.getPeriodKey(createdDate, timeRange)
-
-				if _, exists := periodMap[period]; !exists {
-					periodMap[period] = &CompletionTrend{Period: period}
-				}
-
-				periodMap[period].TasksCreated++
-
-				if h.isTaskCompleted(task) {
-					periodMap[period].TasksCompleted++
-				}
-			}
-		}
+	periods[period].TasksCreated++
+	if h.isTaskCompleted(task) {
+		periods[period].TasksCompleted++
 	}
+}
 
+func (h *AnalyticsHandler) completionTrends(periods map[string]*CompletionTrend) []CompletionTrend {
 	var trends []CompletionTrend
-	for _, trend := range periodMap {
+	for _, trend := range periods {
 		if trend.TasksCreated > 0 {
 			trend.CompletionRate = float64(trend.TasksCompleted) / float64(trend.TasksCreated) * 100
 		}
@@ -261,62 +624,15 @@ func (h *AnalyticsHandler) analyseCompletionTrends(tasks []TaskDetail, timeRange
 	return trends
 }
 
-func (h *AnalyticsHandler) analyseCycleTime(tasks []TaskDetail) []CycleTimeMetric {
-	columnMap := make(map[string][]float64)
-
-	for _, task := range tasks {
-		if !h.isTaskCompleted(task) {
-			continue
-		}
-
-		var startTime, endTime time.Time
-		var err error
-
-		if task.Dates.Started != "" {
-			startTime, err = time.Parse("2006-01-02T15:04:05Z", task.Dates.Started)
-		} else if task.Dates.Created != "" {
-			startTime, err = time.Parse("2006-01-02T15:04:05Z", task.Dates.Created)
-		}
-
-		if err != nil {
-			continue
-		}
-
-		if task.Dates.Modified != "" {
-			endTime, err = time.Parse("2006-01-02T15:04:05Z", task.Dates.Modified)
-			if err != nil {
-				continue
-			}
-		} else {
-			continue
-		}
-
-		cycleDays := endTime.Sub(startTime).Hours() / 24
-		if cycleDays > 0 {
-			key := fmt.Sprintf("%s:%s", task.Project.Name, task.Status.Column)
-			columnMap[key] = append(columnMap[key], cycleDays)
-		}
-	}
+type cycleTimeKey struct {
+	project string
+	column  string
+}
 
+func (h *AnalyticsHandler) cycleTimeMetrics(columns map[cycleTimeKey]*durationStats) []CycleTimeMetric {
 	var metrics []CycleTimeMetric
-	for key, times := range columnMap {
-		if len(times) == 0 {
-			continue
-		}
-
-		keyParts := fmt.Sprintf("%s", key)
-		project := "Unknown"
-		column := "Unknown"
-
-		if len(keyParts) > 0 {
-
-			project = "Project"
-			column = "Column"
-		}
-
-		avg := h.calculateAverage(times)
-		min := h.calculateMin(times)
-		max := h.calculateMax(times)
+	for key, times := range columns {
+		avg := times.average()
 
 		efficiency := "Good"
 		if avg > 14 {
@@ -325,17 +641,38 @@ func (h *AnalyticsHandler) analyseCycleTime(tasks []TaskDetail) []CycleTimeMetri
 			efficiency = "Average"
 		}
 
-		metric := CycleTimeMetric{
-			Column:     column,
-			Project:    project,
+		metrics = append(metrics, CycleTimeMetric{
+			Column:     key.column,
+			Project:    key.project,
 			AvgDays:    avg,
-			MinDays:    min,
-			MaxDays:    max,
-			TaskCount:  len(times),
+			MedianDays: h.calculatePercentile(times.samples, 50),
+			P85Days:    h.calculatePercentile(times.samples, 85),
+			MinDays:    times.min,
+			MaxDays:    times.max,
+			TaskCount:  times.count,
 			Efficiency: efficiency,
-		}
+		})
+	}
+
+	sort.Slice(metrics, func(i, j int) bool {
+		return metrics[i].AvgDays > metrics[j].AvgDays
+	})
 
-		metrics = append(metrics, metric)
+	return metrics
+}
+
+func (h *AnalyticsHandler) leadTimeMetrics(projects map[string]*durationStats) []LeadTimeMetric {
+	var metrics []LeadTimeMetric
+	for project, times := range projects {
+		metrics = append(metrics, LeadTimeMetric{
+			Project:    project,
+			AvgDays:    times.average(),
+			MedianDays: h.calculatePercentile(times.samples, 50),
+			P85Days:    h.calculatePercentile(times.samples, 85),
+			MinDays:    times.min,
+			MaxDays:    times.max,
+			TaskCount:  times.count,
+		})
 	}
 
 	sort.Slice(metrics, func(i, j int) bool {
@@ -345,44 +682,81 @@ func (h *AnalyticsHandler) analyseCycleTime(tasks []TaskDetail) []CycleTimeMetri
 	return metrics
 }
 
-func (h *AnalyticsHandler) analyseVelocity(tasks []TaskDetail, timeRange string) []VelocityMetric {
-	periodMap := make(map[string]*VelocityMetric)
+// taskCycleDays measures from when work started to completion.
+func (h *AnalyticsHandler) taskCycleDays(task TaskDetail) (float64, bool) {
+	if !h.isTaskCompleted(task) {
+		return 0, false
+	}
 
-	for _, task := range tasks {
-		if !h.isTaskCompleted(task) {
-			continue
-		}
+	startTime, ok := h.parseTaskTime(task.Dates.Started)
+	if !ok {
+		return 0, false
+	}
 
-		var completedDate time.Time
-		var err error
+	endTime, ok := h.taskCompletionTime(task)
+	if !ok {
+		return 0, false
+	}
 
-		if task.Dates.Modified != "" {
-			completedDate, err = time.Parse("2006-01-02T15:04:05Z", task.Dates.Modified)
-			if err != nil {
-				continue
-			}
-		} else {
-			continue
-		}
+	days := endTime.Sub(startTime).Hours() / 24
+	return days, days > 0
+}
 
-		period := h.getPeriodKey(completedDate, timeRange)
+// taskLeadDays measures from creation to completion.
+func (h *AnalyticsHandler) taskLeadDays(task TaskDetail) (float64, bool) {
+	if !h.isTaskCompleted(task) {
+		return 0, false
+	}
 
-		if _, exists := periodMap[period]; !exists {
-			periodMap[period] = &VelocityMetric{Period: period}
-		}
+	startTime, ok := h.parseTaskTime(task.Dates.Created)
+	if !ok {
+		return 0, false
+	}
 
-		metric := periodMap[period]
-		metric.TasksCompleted++
-		metric.StoryPoints += 1
+	endTime, ok := h.taskCompletionTime(task)
+	if !ok {
+		return 0, false
+	}
 
-		if task.TimeTracking != nil {
-			metric.EstimatedHours += task.TimeTracking.EstimatedHours
-			metric.ActualHours += task.TimeTracking.SpentHours
-		}
+	days := endTime.Sub(startTime).Hours() / 24
+	return days, days > 0
+}
+
+func (h *AnalyticsHandler) taskCompletionTime(task TaskDetail) (time.Time, bool) {
+	if t, ok := h.parseTaskTime(task.Dates.Completed); ok {
+		return t, true
 	}
+	return h.parseTaskTime(task.Dates.Moved)
+}
 
+func (h *AnalyticsHandler) addVelocity(periods map[string]*VelocityMetric, task TaskDetail, bucket string) {
+	if !h.isTaskCompleted(task) {
+		return
+	}
+
+	completedDate, ok := h.taskCompletionTime(task)
+	if !ok {
+		return
+	}
+
+	period := h.getPeriodKey(completedDate, bucket)
+	if _, exists := periods[period]; !exists {
+		periods[period] = &VelocityMetric{Period: period}
+	}
+
+	metric := periods[period]
+	metric.TasksCompleted++
+	metric.StoryPoints += 1
+
+	if task.TimeTracking != nil {
+		metric.EstimatedHours += task.TimeTracking.EstimatedHours
+		metric.ActualHours += task.TimeTracking.SpentHours
+	}
+}
+
+func (h *AnalyticsHandler) velocityMetrics(periods map[string]*VelocityMetric) []VelocityMetric {
 	var metrics []VelocityMetric
-	for _, metric := range periodMap {
+	for _, metric := range periods {
 		if metric.EstimatedHours > 0 {
 			efficiency := metric.ActualHours / metric.EstimatedHours
 			if efficiency <= 1.1 {
@@ -407,62 +781,63 @@ func (h *AnalyticsHandler) analyseVelocity(tasks []TaskDetail, timeRange string)
 	return metrics
 }
 
-func (h *AnalyticsHandler) analyseTaskAging(tasks []TaskDetail) []TaskAgingAnalysis {
-	now := time.Now()
-	ageGroups := map[string]*TaskAgingAnalysis{
-		"0-7 days":   {AgeGroup: "0-7 days"},
-		"8-14 days":  {AgeGroup: "8-14 days"},
-		"15-30 days": {AgeGroup: "15-30 days"},
-		"31-60 days": {AgeGroup: "31-60 days"},
-		"60+ days":   {AgeGroup: "60+ days"},
-	}
-
-	activeTasks := 0
-	var oldestTaskTitle string
-	var maxAge float64
-
-	for _, task := range tasks {
-		if h.isTaskCompleted(task) {
-			continue
-		}
-
-		activeTasks++
+// agingReducer sorts open tasks into age groups.
+type agingReducer struct {
+	groups      map[string]*TaskAgingAnalysis
+	activeTasks int
+	oldestTitle string
+	maxAge      float64
+}
 
-		if task.Dates.Created != "" {
-			if createdDate, err := time.Parse("2006-01-02T15:04:05Z", task.Dates.Created); err == nil {
-				age := now.Sub(createdDate).Hours() / 24
+func newAgingReducer() *agingReducer {
+	return &agingReducer{
+		groups: map[string]*TaskAgingAnalysis{
+			"0-7 days":   {AgeGroup: "0-7 days"},
+			"8-14 days":  {AgeGroup: "8-14 days"},
+			"15-30 days": {AgeGroup: "15-30 days"},
+			"31-60 days": {AgeGroup: "31-60 days"},
+			"60+ days":   {AgeGroup: "60+ days"},
+		},
+	}
+}
 
-				if age > maxAge {
-					maxAge = age
-					oldestTaskTitle = task.Title
-				}
+func (a *agingReducer) add(h *AnalyticsHandler, task TaskDetail, created, now time.Time) {
+	if h.isTaskCompleted(task) {
+		return
+	}
+	a.activeTasks++
 
-				var group *TaskAgingAnalysis
-				switch {
-				case age <= 7:
-					group = ageGroups["0-7 days"]
-				case age <= 14:
-					group = ageGroups["8-14 days"]
-				case age <= 30:
-					group = ageGroups["15-30 days"]
-				case age <= 60:
-					group = ageGroups["31-60 days"]
-				default:
-					group = ageGroups["60+ days"]
-				}
+	age := now.Sub(created).Hours() / 24
+	if age > a.maxAge {
+		a.maxAge = age
+		a.oldestTitle = task.Title
+	}
 
-				group.TaskCount++
-				group.AvgAgeDays = (group.AvgAgeDays*float64(group.TaskCount-1) + age) / float64(group.TaskCount)
-			}
-		}
+	var group *TaskAgingAnalysis
+	switch {
+	case age <= 7:
+		group = a.groups["0-7 days"]
+	case age <= 14:
+		group = a.groups["8-14 days"]
+	case age <= 30:
+		group = a.groups["15-30 days"]
+	case age <= 60:
+		group = a.groups["31-60 days"]
+	default:
+		group = a.groups["60+ days"]
 	}
 
+	group.TaskCount++
+	group.AvgAgeDays = (group.AvgAgeDays*float64(group.TaskCount-1) + age) / float64(group.TaskCount)
+}
+
+func (a *agingReducer) result() []TaskAgingAnalysis {
 	var analysis []TaskAgingAnalysis
-	for _, group := range ageGroups {
+	for _, group := range a.groups {
 		if group.TaskCount > 0 {
-			group.Percentage = float64(group.TaskCount) / float64(activeTasks) * 100
-			if group.AgeGroup == "60+ days" && oldestTaskTitle != "" {
-				group.OldestTask = oldestTaskTitle
+			group.Percentage = float64(group.TaskCount) / float64(a.activeTasks) * 100
+			if group.AgeGroup == "60+ days" && a.oldestTitle != "" {
+				group.OldestTask = a.oldestTitle
 			}
 			analysis = append(analysis, *group)
 		}
@@ -475,13 +850,18 @@ func (h *AnalyticsHandler) analyseTaskAging(tasks []TaskDetail) []TaskAgingAnaly
 	return analysis
 }
 
-func (h *AnalyticsHandler) generateBurndownData(tasks []TaskDetail, timeRange string) []BurndownData {
-	timeRangeStart := h.getTimeRangeStart(timeRange)
-	now := time.Now()
+// burndownReducer counts, for each point of the chart, the tasks created and
+// completed by then.
+type burndownReducer struct {
+	dates      []time.Time
+	start      time.Time
+	totalTasks int
+	created    []int
+	completed  []int
+}
 
-	var dates []time.Time
+func (h *AnalyticsHandler) newBurndownReducer(timeRange string, start, now time.Time) *burndownReducer {
 	var interval time.Duration
-
 	switch timeRange {
 	case "7_days", "14_days":
 		interval = 24 * time.Hour
@@ -491,59 +871,53 @@ func (h *AnalyticsHandler) generateBurndownData(tasks []TaskDetail, timeRange st
 		interval = 7 * 24 * time.Hour
 	}
 
-	for date := timeRangeStart; date.Before(now) || date.Equal(now); date = date.Add(interval) {
-		dates = append(dates, date)
+	b := &burndownReducer{start: start}
+	for date := start; date.Before(now) || date.Equal(now); date = date.Add(interval) {
+		b.dates = append(b.dates, date)
 	}
+	b.created = make([]int, len(b.dates))
+	b.completed = make([]int, len(b.dates))
+	return b
+}
 
-	if len(dates) == 0 {
-		return []BurndownData{}
+func (b *burndownReducer) add(h *AnalyticsHandler, task TaskDetail, created time.Time) {
+	if !created.After(b.start) {
+		b.totalTasks++
 	}
 
-	totalTasks := 0
-	for _, task := range tasks {
-		if task.Dates.Created != "" {
-			if createdDate, err := time.Parse("2006-01-02T15:04:05Z", task.Dates.Created); err == nil {
-				if createdDate.Before(timeRangeStart) || createdDate.Equal(timeRangeStart) {
-					totalTasks++
-				}
-			}
-		}
+	completed, isCompleted := time.Time{}, false
+	if h.isTaskCompleted(task) {
+		completed, isCompleted = h.taskCompletionTime(task)
 	}
 
-	var burndownData []BurndownData
-
-	for i, date := range dates {
-		completedByDate := 0
-		createdByDate := 0
+	for i, date := range b.dates {
+		if isCompleted && !completed.After(date) {
+			b.completed[i]++
+		}
+		if !created.After(date) {
+			b.created[i]++
+		}
+	}
+}
 
-		for _, task := range tasks {
-			if h.isTaskCompleted(task) && task.Dates.Modified != "" {
-				if modifiedDate, err := time.Parse("2006-01-02T15:04:05Z", task.Dates.Modified); err == nil {
-					if modifiedDate.Before(date) || modifiedDate.Equal(date) {
-						completedByDate++
-					}
-				}
-			}
+func (b *burndownReducer) result() []BurndownData {
+	if len(b.dates) == 0 {
+		return []BurndownData{}
+	}
 
-			if task.Dates.Created != "" {
-				if createdDate, err := time.Parse("2006-01-02T15:04:05Z", task.Dates.Created); err == nil {
-					if createdDate.Before(date) || createdDate.Equal(date) {
-						createdByDate++
-					}
-				}
-			}
-		}
+	var burndownData []BurndownData
 
-		currentTotal := totalTasks + createdByDate
-		remainingTasks := currentTotal - completedByDate
+	for i, date := range b.dates {
+		currentTotal := b.totalTasks + b.created[i]
+		remainingTasks := currentTotal - b.completed[i]
 
-		progress := float64(i) / float64(len(dates)-1)
-		idealRemaining := int(float64(totalTasks) * (1.0 - progress))
+		progress := float64(i) / float64(len(b.dates)-1)
+		idealRemaining := int(float64(b.totalTasks) * (1.0 - progress))
 
 		trendProjection := remainingTasks
 		if i > 0 && len(burndownData) > 0 {
 			velocity := burndownData[i-1].RemainingTasks - remainingTasks
-			remainingDays := len(dates) - i - 1
+			remainingDays := len(b.dates) - i - 1
 			trendProjection = remainingTasks - (velocity * remainingDays)
 			if trendProjection < 0 {
 				trendProjection = 0
@@ -553,7 +927,7 @@ func (h *AnalyticsHandler) generateBurndownData(tasks []TaskDetail, timeRange st
 		burndownData = append(burndownData, BurndownData{
 			Date:            date.Format("2006-01-02"),
 			RemainingTasks:  remainingTasks,
-			CompletedTasks:  completedByDate,
+			CompletedTasks:  b.completed[i],
 			IdealRemaining:  idealRemaining,
 			TrendProjection: trendProjection,
 		})
@@ -562,65 +936,109 @@ func (h *AnalyticsHandler) generateBurndownData(tasks []TaskDetail, timeRange st
 	return burndownData
 }
 
-func (h *AnalyticsHandler) analyseProjectHealth(tasks []TaskDetail) []ProjectHealthMetric {
-	projectMap := make(map[string]*ProjectHealthMetric)
-	projectStats := make(map[string]*struct {
-		totalTasks     int
-		completedTasks int
-		overdueTasks   int
-		onTimeTasks    int
-		totalHours     float64
-		spentHours     float64
-	})
+// newCumulativeFlow sets up the points of the cumulative flow, which are the
+// same as the burndown's.
+func (h *AnalyticsHandler) newCumulativeFlow(timeRange string, start, now time.Time) (*CumulativeFlow, []time.Time) {
+	interval := 24 * time.Hour
+	switch timeRange {
+	case "7_days", "14_days", "30_days", "60_days":
+	default:
+		interval = 7 * 24 * time.Hour
+	}
 
-	for _, task := range tasks {
-		projectKey := task.Project.ID
+	flow := &CumulativeFlow{
+		Points: []CumulativeFlowPoint{},
+		Note:   "States are derived from each task's created, started and completed dates; Kanboard does not expose column history",
+	}
+	var dates []time.Time
+	for date := start; !date.After(now); date = date.Add(interval) {
+		flow.Points = append(flow.Points, CumulativeFlowPoint{Date: date.Format("2006-01-02")})
+		dates = append(dates, date)
+	}
+	return flow, dates
+}
 
-		if _, exists := projectMap[projectKey]; !exists {
-			projectMap[projectKey] = &ProjectHealthMetric{
-				ProjectID:   task.Project.ID,
-				ProjectName: task.Project.Name,
+// addCumulativeFlow counts task at each point as not yet started, started or
+// completed. Open tasks created before the period are included; tasks
+// completed before it are not.
+func (h *AnalyticsHandler) addCumulativeFlow(flow *CumulativeFlow, dates []time.Time, start time.Time, task TaskDetail) {
+	created, ok := h.parseTaskTime(task.Dates.Created)
+	if !ok {
+		return
+	}
+	completed, isCompleted := time.Time{}, false
+	if h.isTaskCompleted(task) {
+		completed, isCompleted = h.taskCompletionTime(task)
+	}
+	started, isStarted := h.parseTaskTime(task.Dates.Started)
+
+	for i, date := range dates {
+		if created.After(date) {
+			continue
+		}
+		point := &flow.Points[i]
+		if isCompleted && !completed.After(date) {
+			if !completed.Before(start) {
+				point.Done++
 			}
-			projectStats[projectKey] = &struct {
-				totalTasks     int
-				completedTasks int
-				overdueTasks   int
-				onTimeTasks    int
-				totalHours     float64
-				spentHours     float64
-			}{}
-		}
-
-		stats := projectStats[projectKey]
-		stats.totalTasks++
-
-		if h.isTaskCompleted(task) {
-			stats.completedTasks++
-
-			if task.Dates.Due != "" && task.Dates.Modified != "" {
-				if dueDate, err1 := time.Parse("2006-01-02T15:04:05Z", task.Dates.Due); err1 == nil {
-					if modifiedDate, err2 := time.Parse("2006-01-02T15:04:05Z", task.Dates.Modified); err2 == nil {
-						if modifiedDate.Before(dueDate) || modifiedDate.Equal(dueDate) {
-							stats.onTimeTasks++
-						}
-					}
+			continue
+		}
+		if isStarted && !started.After(date) {
+			point.InProgress++
+		} else {
+			point.ToDo++
+		}
+	}
+}
+
+type projectHealthStats struct {
+	metric         ProjectHealthMetric
+	totalTasks     int
+	completedTasks int
+	overdueTasks   int
+	onTimeTasks    int
+	totalHours     float64
+	spentHours     float64
+}
+
+func (h *AnalyticsHandler) addProjectHealth(projects map[string]*projectHealthStats, task TaskDetail) {
+	stats, exists := projects[task.Project.ID]
+	if !exists {
+		stats = &projectHealthStats{metric: ProjectHealthMetric{
+			ProjectID:   task.Project.ID,
+			ProjectName: task.Project.Name,
+		}}
+		projects[task.Project.ID] = stats
+	}
+
+	stats.totalTasks++
+
+	if h.isTaskCompleted(task) {
+		stats.completedTasks++
+
+		if dueDate, ok := h.parseTaskTime(task.Dates.Due); ok {
+			if completedDate, ok := h.taskCompletionTime(task); ok {
+				if completedDate.Before(dueDate) || completedDate.Equal(dueDate) {
+					stats.onTimeTasks++
 				}
 			}
 		}
+	}
 
-		if task.IsOverdue {
-			stats.overdueTasks++
-		}
+	if task.IsOverdue {
+		stats.overdueTasks++
+	}
 
-		if task.TimeTracking != nil {
-			stats.totalHours += task.TimeTracking.EstimatedHours
-			stats.spentHours += task.TimeTracking.SpentHours
-		}
+	if task.TimeTracking != nil {
+		stats.totalHours += task.TimeTracking.EstimatedHours
+		stats.spentHours += task.TimeTracking.SpentHours
 	}
+}
 
+func (h *AnalyticsHandler) projectHealth(projects map[string]*projectHealthStats) []ProjectHealthMetric {
 	var health []ProjectHealthMetric
-	for projectKey, metric := range projectMap {
-		stats := projectStats[projectKey]
+	for _, stats := range projects {
+		metric := stats.metric
 
 		if stats.totalTasks > 0 {
 			metric.CompletionRate = float64(stats.completedTasks) / float64(stats.totalTasks) * 100
@@ -666,7 +1084,7 @@ func (h *AnalyticsHandler) analyseProjectHealth(tasks []TaskDetail) []ProjectHea
 			metric.RiskLevel = "Low"
 		}
 
-		health = append(health, *metric)
+		health = append(health, metric)
 	}
 
 	sort.Slice(health, func(i, j int) bool {
@@ -676,19 +1094,301 @@ func (h *AnalyticsHandler) analyseProjectHealth(tasks []TaskDetail) []ProjectHea
 	return health
 }
 
-func (h *AnalyticsHandler) generateSummary(tasks []TaskDetail, timeRange string) AnalyticsSummary {
-	totalTasks := len(tasks)
-	completedTasks := 0
+// throughputReducer counts completions per 7-day window walking back from
+// now, including weeks where nothing was completed, along with the cycle
+// times and open tasks the forecast needs.
+type throughputReducer struct {
+	since      time.Time
+	now        time.Time
+	weekly     []int
+	cycleTimes durationStats
+	remaining  int
+}
+
+func newThroughputReducer(since, now time.Time) *throughputReducer {
+	weeks := int(now.Sub(since).Hours() / (24 * 7))
+	if weeks < 1 {
+		weeks = 1
+	}
+	return &throughputReducer{since: since, now: now, weekly: make([]int, weeks)}
+}
+
+func (t *throughputReducer) add(h *AnalyticsHandler, task TaskDetail) {
+	if !h.isTaskCompleted(task) {
+		t.remaining++
+		return
+	}
+
+	if days, ok := h.taskCycleDays(task); ok {
+		t.cycleTimes.add(days)
+	}
+
+	completedAt, ok := h.taskCompletionTime(task)
+	if !ok || completedAt.Before(t.since) {
+		return
+	}
+
+	week := int(t.now.Sub(completedAt).Hours() / (24 * 7))
+	if week >= 0 && week < len(t.weekly) {
+		t.weekly[week]++
+	}
+}
+
+func (h *AnalyticsHandler) throughputDistribution(t *throughputReducer) *ThroughputDistribution {
+	counts := make(map[int]int)
+	values := make([]float64, len(t.weekly))
+	for i, completed := range t.weekly {
+		counts[completed]++
+		values[i] = float64(completed)
+	}
+
+	distribution := &ThroughputDistribution{
+		SampledWeeks: len(t.weekly),
+		WeeklyP50:    h.calculatePercentile(values, 50),
+		WeeklyP85:    h.calculatePercentile(values, 85),
+		WeeklyP95:    h.calculatePercentile(values, 95),
+	}
+
+	for completed, weeks := range counts {
+		distribution.Histogram = append(distribution.Histogram, ThroughputBucket{
+			TasksCompleted: completed,
+			Weeks:          weeks,
+		})
+	}
+	sort.Slice(distribution.Histogram, func(i, j int) bool {
+		return distribution.Histogram[i].TasksCompleted < distribution.Histogram[j].TasksCompleted
+	})
+
+	if t.cycleTimes.count > 0 {
+		distribution.CycleTime = &PercentileSummary{
+			P50:   h.calculatePercentile(t.cycleTimes.samples, 50),
+			P85:   h.calculatePercentile(t.cycleTimes.samples, 85),
+			P95:   h.calculatePercentile(t.cycleTimes.samples, 95),
+			Count: t.cycleTimes.count,
+		}
+	}
+
+	return distribution
+}
+
+// wipReducer reconstructs daily WIP for each limited column from the tasks
+// currently in it: a task counts from its date_moved until completion. A
+// project's over-limit days are only known once all its tasks are in, so
+// each project is reduced in one go.
+type wipReducer struct {
+	startDay   time.Time
+	now        time.Time
+	days       int
+	violations []WIPViolation
+	over       durationStats
+	within     durationStats
+}
+
+func newWIPReducer(since, now time.Time) *wipReducer {
+	startDay := time.Date(since.Year(), since.Month(), since.Day(), 0, 0, 0, 0, time.UTC)
+	return &wipReducer{
+		startDay: startDay,
+		now:      now,
+		days:     int(now.Sub(startDay).Hours()/24) + 1,
+	}
+}
+
+// add reduces one project's tasks against the project's columns.
+func (w *wipReducer) add(h *AnalyticsHandler, tasks []TaskDetail, columns []models.Column) {
+	overLimitDays := make([]bool, w.days)
+
+	for _, column := range columns {
+		if column.TaskLimit <= 0 {
+			continue
+		}
+
+		daily := make([]int, w.days)
+		current := 0
+		for _, task := range tasks {
+			if task.Status.Column != column.Title {
+				continue
+			}
+
+			entered, ok := h.parseTaskTime(task.Dates.Moved)
+			if !ok {
+				entered, ok = h.parseTaskTime(task.Dates.Created)
+			}
+			if !ok {
+				continue
+			}
+
+			left := w.now
+			completed := h.isTaskCompleted(task)
+			if completed {
+				if completedAt, ok := h.taskCompletionTime(task); ok {
+					left = completedAt
+				}
+			} else {
+				current++
+			}
+
+			for day := 0; day < w.days; day++ {
+				dayEnd := w.startDay.AddDate(0, 0, day+1)
+				dayStart := w.startDay.AddDate(0, 0, day)
+				if entered.Before(dayEnd) && (!completed || !left.Before(dayStart)) {
+					daily[day]++
+				}
+			}
+		}
+
+		violation := WIPViolation{
+			ProjectID:     tasks[0].Project.ID,
+			Project:       tasks[0].Project.Name,
+			Column:        column.Title,
+			TaskLimit:     column.TaskLimit,
+			CurrentWIP:    current,
+			CurrentlyOver: current > column.TaskLimit,
+		}
+
+		streak := 0
+		for day, wip := range daily {
+			if wip > violation.MaxWIP {
+				violation.MaxWIP = wip
+			}
+			if wip > column.TaskLimit {
+				violation.DaysOverLimit++
+				overLimitDays[day] = true
+				streak++
+				if streak > violation.LongestStreakDays {
+					violation.LongestStreakDays = streak
+				}
+			} else {
+				streak = 0
+			}
+		}
+
+		if violation.DaysOverLimit > 0 || violation.CurrentlyOver {
+			w.violations = append(w.violations, violation)
+		}
+	}
 
 	for _, task := range tasks {
-		if h.isTaskCompleted(task) {
-			completedTasks++
+		cycleDays, ok := h.taskCycleDays(task)
+		if !ok {
+			continue
+		}
+		completedAt, _ := h.taskCompletionTime(task)
+		day := int(completedAt.Sub(w.startDay).Hours() / 24)
+		if day < 0 || day >= w.days {
+			continue
+		}
+		if overLimitDays[day] {
+			w.over.count++
+			w.over.sum += cycleDays
+		} else {
+			w.within.count++
+			w.within.sum += cycleDays
+		}
+	}
+}
+
+func (h *AnalyticsHandler) wipLimits(w *wipReducer) *WIPLimitAnalysis {
+	analysis := &WIPLimitAnalysis{
+		Violations: w.violations,
+		Note:       "Daily WIP is reconstructed from each task's current column and date_moved; Kanboard does not expose earlier column history",
+	}
+	if analysis.Violations == nil {
+		analysis.Violations = []WIPViolation{}
+	}
+
+	sort.Slice(analysis.Violations, func(i, j int) bool {
+		return analysis.Violations[i].DaysOverLimit > analysis.Violations[j].DaysOverLimit
+	})
+
+	analysis.CycleTimeOverLimitDays = w.over.average()
+	analysis.CycleTimeWithinDays = w.within.average()
+	if analysis.CycleTimeWithinDays > 0 && w.over.count > 0 {
+		analysis.CycleTimeDegradationPct = (analysis.CycleTimeOverLimitDays - analysis.CycleTimeWithinDays) / analysis.CycleTimeWithinDays * 100
+	}
+
+	return analysis
+}
+
+const (
+	forecastSimulations = 10000
+	forecastMaxWeeks    = 520
+)
+
+func (h *AnalyticsHandler) forecastCompletion(t *throughputReducer, now time.Time) *CompletionForecast {
+	throughput := t.weekly
+	weeks := len(throughput)
+	remaining := t.remaining
+
+	forecast := &CompletionForecast{
+		RemainingTasks: remaining,
+		SampledWeeks:   weeks,
+	}
+
+	if remaining == 0 {
+		forecast.Note = "No open tasks remain"
+		return forecast
+	}
+
+	total := 0
+	for _, count := range throughput {
+		total += count
+	}
+	if total == 0 {
+		forecast.Note = "No tasks were completed in the sampled period, so a forecast cannot be made"
+		return forecast
+	}
+
+	results := make([]float64, forecastSimulations)
+	for i := range results {
+		left := remaining
+		elapsed := 0
+		for left > 0 && elapsed < forecastMaxWeeks {
+			left -= throughput[rand.IntN(len(throughput))]
+			elapsed++
 		}
+		results[i] = float64(elapsed)
+	}
+	forecast.Simulations = forecastSimulations
+
+	for _, confidence := range []int{50, 85, 95} {
+		weeksNeeded := int(math.Ceil(h.calculatePercentile(results, float64(confidence))))
+		forecast.Forecasts = append(forecast.Forecasts, ForecastConfidence{
+			Confidence: confidence,
+			Weeks:      weeksNeeded,
+			Date:       now.AddDate(0, 0, weeksNeeded*7).Format("2006-01-02"),
+		})
 	}
 
+	return forecast
+}
+
+// summaryReducer counts the tasks created within the time range.
+type summaryReducer struct {
+	totalTasks     int
+	completedTasks int
+	cycleTimes     durationStats
+	leadTimes      durationStats
+}
+
+func (s *summaryReducer) add(h *AnalyticsHandler, task TaskDetail) {
+	s.totalTasks++
+	if h.isTaskCompleted(task) {
+		s.completedTasks++
+	}
+	if days, ok := h.taskCycleDays(task); ok {
+		s.cycleTimes.count++
+		s.cycleTimes.sum += days
+	}
+	if days, ok := h.taskLeadDays(task); ok {
+		s.leadTimes.count++
+		s.leadTimes.sum += days
+	}
+}
+
+func (s *summaryReducer) result(h *AnalyticsHandler, timeRange string) AnalyticsSummary {
 	var insights []string
-	if totalTasks > 0 {
-		completionRate := float64(completedTasks) / float64(totalTasks) * 100
+	if s.totalTasks > 0 {
+		completionRate := float64(s.completedTasks) / float64(s.totalTasks) * 100
 		if completionRate > 80 {
 			insights = append(insights, "High completion rate indicates strong delivery performance")
 		} else if completionRate < 50 {
@@ -698,69 +1398,78 @@ func (h *AnalyticsHandler) generateSummary(tasks []TaskDetail, timeRange string)
 
 	return AnalyticsSummary{
 		AnalysisPeriod:    timeRange,
-		TotalTasks:        totalTasks,
-		CompletedTasks:    completedTasks,
-		OverallVelocity:   float64(completedTasks),
-		AvgCycleTime:      7.5,
+		TotalTasks:        s.totalTasks,
+		CompletedTasks:    s.completedTasks,
+		OverallVelocity:   float64(s.completedTasks),
+		AvgCycleTime:      s.cycleTimes.average(),
+		AvgLeadTime:       s.leadTimes.average(),
 		ProductivityTrend: "Stable",
 		KeyInsights:       insights,
 	}
 }
 
-func (h *AnalyticsHandler) getPeriodKey(date time.Time, timeRange string) string {
+func (h *AnalyticsHandler) defaultBucket(timeRange string) string {
 	switch timeRange {
 	case "7_days", "14_days":
-		return date.Format("2006-01-02")
+		return "day"
 	case "30_days", "60_days", "90_days":
-		return date.Format("2006-W15")
+		return "week"
+	default:
+		return "month"
+	}
+}
+
+func (h *AnalyticsHandler) getPeriodKey(date time.Time, bucket string) string {
+	switch bucket {
+	case "day":
+		return date.Format("2006-01-02")
+	case "week":
+		year, week := date.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
 	default:
 		return date.Format("2006-01")
 	}
 }
 
 func (h *AnalyticsHandler) isTaskCompleted(task TaskDetail) bool {
-	completedColumns := []string{"Done", "Completed", "Closed", "Finished"}
-	for _, col := range completedColumns {
-		if task.Status.Column == col {
-			return true
-		}
-	}
-	return false
+	return !task.Status.IsActive || task.Dates.Completed != ""
 }
 
-func (h *AnalyticsHandler) calculateAverage(values []float64) float64 {
-	if len(values) == 0 {
-		return 0
-	}
-	sum := 0.0
-	for _, v := range values {
-		sum += v
+// now returns the current time in the user's timezone, so period buckets
+// line up with their calendar.
+func (h *AnalyticsHandler) now() time.Time {
+	if h.location == nil {
+		return time.Now().In(defaultLocation(h.config))
 	}
-	return sum / float64(len(values))
+	return time.Now().In(h.location)
 }
 
-func (h *AnalyticsHandler) calculateMin(values []float64) float64 {
-	if len(values) == 0 {
-		return 0
+func (h *AnalyticsHandler) parseTaskTime(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
 	}
-	min := values[0]
-	for _, v := range values {
-		if v < min {
-			min = v
-		}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
 	}
-	return min
+	return t, true
 }
 
-func (h *AnalyticsHandler) calculateMax(values []float64) float64 {
+// calculatePercentile uses linear interpolation between the closest ranks.
+func (h *AnalyticsHandler) calculatePercentile(values []float64, percentile float64) float64 {
 	if len(values) == 0 {
 		return 0
 	}
-	max := values[0]
-	for _, v := range values {
-		if v > max {
-			max = v
-		}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	rank := percentile / 100 * float64(len(sorted)-1)
+	lower := int(rank)
+	if lower >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
 	}
-	return max
+	fraction := rank - float64(lower)
+	return sorted[lower] + fraction*(sorted[lower+1]-sorted[lower])
 }
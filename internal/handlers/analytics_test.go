@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDurationStats(t *testing.T) {
+	tests := []struct {
+		name  string
+		count int
+	}{
+		{"empty", 0},
+		{"under the sample bound", 100},
+		{"at the sample bound", maxDurationSamples},
+		{"over the sample bound", 3 * maxDurationSamples},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stats durationStats
+			for i := 1; i <= tt.count; i++ {
+				stats.add(float64(i))
+			}
+
+			if stats.count != tt.count {
+				t.Errorf("count = %d, want %d", stats.count, tt.count)
+			}
+			if want := min(tt.count, maxDurationSamples); len(stats.samples) != want {
+				t.Errorf("kept %d samples, want %d", len(stats.samples), want)
+			}
+			if tt.count == 0 {
+				if stats.average() != 0 {
+					t.Errorf("average = %g, want 0", stats.average())
+				}
+				return
+			}
+			if want := float64(tt.count+1) / 2; stats.average() != want {
+				t.Errorf("average = %g, want %g", stats.average(), want)
+			}
+			if stats.min != 1 || stats.max != float64(tt.count) {
+				t.Errorf("min, max = %g, %g, want 1, %d", stats.min, stats.max, tt.count)
+			}
+
+			// A uniform sample puts the median near the true one.
+			h := &AnalyticsHandler{}
+			median := h.calculatePercentile(stats.samples, 50)
+			if want := float64(tt.count+1) / 2; math.Abs(median-want) > 0.05*float64(tt.count) {
+				t.Errorf("median = %g, want about %g", median, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"flag"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/auth"
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+	"github.com/tech-arch1tect/kan-mcp/internal/storage"
+	"github.com/tech-arch1tect/kan-mcp/internal/workpool"
+)
+
+var (
+	benchProjects = flag.Int("bench.projects", 200, "number of synthetic projects the benchmarks run against")
+	benchTasks    = flag.Int("bench.tasks", 10000, "number of synthetic tasks, spread evenly across projects")
+	benchCache    = flag.Bool("bench.cache", false, "cache project structure responses, as a server with the default cache TTLs would")
+)
+
+type benchCase struct {
+	name   string
+	params map[string]interface{}
+}
+
+// benchmarkEnv serves a synthetic Kanboard instance and registers a user
+// against it, returning the user's config and ID.
+func benchmarkEnv(b *testing.B) (*auth.AuthManager, *models.UserConfig, string) {
+	b.Helper()
+
+	kanboard, err := newSyntheticKanboard(*benchProjects, *benchTasks)
+	if err != nil {
+		b.Fatalf("failed to generate Kanboard data: %v", err)
+	}
+	server := httptest.NewServer(kanboard)
+	b.Cleanup(server.Close)
+
+	userStore, err := storage.NewFileStore(b.TempDir())
+	if err != nil {
+		b.Fatalf("failed to create user store: %v", err)
+	}
+	encryptionKey := make([]byte, 32)
+	rand.Read(encryptionKey)
+	authManager, err := auth.NewAuthManager(encryptionKey, userStore)
+	if err != nil {
+		b.Fatalf("failed to create auth manager: %v", err)
+	}
+	user, err := authManager.RegisterUser(server.URL, "user1", "token")
+	if err != nil {
+		b.Fatalf("failed to register user: %v", err)
+	}
+
+	config := &models.UserConfig{
+		DefaultKanboardURL: server.URL,
+		EncryptionKey:      encryptionKey,
+		KanboardAuthHeader: "Authorization",
+		KanboardTimeout:    time.Minute,
+		KanboardTransport: models.TransportSettings{
+			MaxIdleConns:        100,
+			MaxIdleConnsPerHost: 100,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		DefaultLocation: time.UTC,
+		WorkPool:        workpool.New(8, 1000, time.Minute),
+	}
+	if *benchCache {
+		config.KanboardCacheTTLs = map[string]time.Duration{
+			"getMyProjects":    time.Hour,
+			"getProjectById":   time.Hour,
+			"getColumns":       time.Hour,
+			"getAllSwimlanes":  time.Hour,
+			"getProjectUsers":  time.Hour,
+			"getAllCategories": time.Hour,
+			"getTagsByProject": time.Hour,
+		}
+	}
+
+	return authManager, config, user.UserID
+}
+
+// runBenchCases runs each case as a sub-benchmark. A failing call would make
+// the timings meaningless, so one call is checked before measuring.
+func runBenchCases(b *testing.B, handle func(context.Context, map[string]interface{}, string) (*models.MCPResponse, error), userID string, cases []benchCase) {
+	ctx := context.Background()
+	for _, bc := range cases {
+		b.Run(bc.name, func(b *testing.B) {
+			if _, err := handle(ctx, bc.params, userID); err != nil {
+				b.Fatal(err)
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := handle(ctx, bc.params, userID); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkTasksHandler(b *testing.B) {
+	authManager, config, userID := benchmarkEnv(b)
+	handler := NewTasksHandler(authManager, config)
+	runBenchCases(b, handler.Handle, userID, []benchCase{
+		{"summary", map[string]interface{}{"summary_mode": true, "limit": 100}},
+		{"full", map[string]interface{}{"summary_mode": false, "limit": 100}},
+		{"all_statuses", map[string]interface{}{"status_filter": "all", "summary_mode": true, "limit": 100}},
+	})
+}
+
+func BenchmarkAnalyticsHandler(b *testing.B) {
+	authManager, config, userID := benchmarkEnv(b)
+	handler := NewAnalyticsHandler(authManager, config)
+	runBenchCases(b, handler.Handle, userID, []benchCase{
+		{"30_days", map[string]interface{}{"time_range": "30_days"}},
+		{"1_year", map[string]interface{}{
+			"time_range":     "1_year",
+			"analysis_types": []string{"completion_trends", "cycle_time", "lead_time", "velocity", "task_aging", "burndown", "project_health", "forecast", "wip_limits"},
+		}},
+	})
+}
+
+func BenchmarkOverviewHandler(b *testing.B) {
+	authManager, config, userID := benchmarkEnv(b)
+	handler := NewOverviewHandler(authManager, config)
+	runBenchCases(b, handler.Handle, userID, []benchCase{
+		{"refresh", map[string]interface{}{"include_task_counts": true, "include_due_counts": true, "force_refresh": true}},
+	})
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/auth"
+	"github.com/tech-arch1tect/kan-mcp/internal/logging"
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+)
+
+// CacheWarmer fetches the project lists and board structure of recently
+// active users into the shared response cache, so the first tool call of a
+// session does not pay for them. Users count as active when the audit log
+// has a call from them within activeWithin.
+type CacheWarmer struct {
+	authManager  *auth.AuthManager
+	config       *models.UserConfig
+	toolCalls    ToolCallLog
+	interval     time.Duration
+	activeWithin time.Duration
+}
+
+func NewCacheWarmer(authManager *auth.AuthManager, config *models.UserConfig, toolCalls ToolCallLog, interval, activeWithin time.Duration) *CacheWarmer {
+	return &CacheWarmer{
+		authManager:  authManager,
+		config:       config,
+		toolCalls:    toolCalls,
+		interval:     interval,
+		activeWithin: activeWithin,
+	}
+}
+
+// Run warms the cache straight away and then once per interval until ctx is
+// done.
+func (w *CacheWarmer) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.WarmAll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// WarmAll warms the cache for every recently active user. Failures are
+// logged and retried next time.
+func (w *CacheWarmer) WarmAll(ctx context.Context) {
+	started := time.Now()
+
+	calls, err := w.toolCalls.Calls(started.Add(-w.activeWithin))
+	if err != nil {
+		logging.Warnf("Cache warming: failed to read recent tool calls: %v", err)
+		return
+	}
+
+	seen := make(map[string]bool)
+	warmed := 0
+	for _, call := range calls {
+		if call.UserID == "" || seen[call.UserID] {
+			continue
+		}
+		seen[call.UserID] = true
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err := w.warmUser(ctx, call.UserID); err != nil {
+			logging.Debugf("Cache warming for user %s failed: %v", logging.UserID(call.UserID), err)
+			continue
+		}
+		warmed++
+	}
+
+	logging.Debugf("Cache warming: warmed %d of %d active users in %s", warmed, len(seen), time.Since(started))
+}
+
+func (w *CacheWarmer) warmUser(ctx context.Context, userID string) error {
+	user, err := w.authManager.AuthenticateUser(userID)
+	if err != nil {
+		return fmt.Errorf("authentication failed: %w", err)
+	}
+
+	token, err := kanboardToken(w.authManager, w.config, user)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt token: %w", err)
+	}
+
+	kanboardURL := user.KanboardURL
+	if kanboardURL == "" {
+		kanboardURL = w.config.DefaultKanboardURL
+	}
+
+	client := newKanboardClient(w.config, kanboardURL, user, token)
+	projects, err := NewTasksHandler(w.authManager, w.config).getFilteredProjects(ctx, client, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get projects: %w", err)
+	}
+
+	for _, project := range projects {
+		if err := client.PrefetchProjectStructure(ctx, project.ID); err != nil {
+			return fmt.Errorf("project %d: %w", project.ID, err)
+		}
+	}
+
+	return nil
+}
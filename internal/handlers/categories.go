@@ -0,0 +1,287 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/api"
+	"github.com/tech-arch1tect/kan-mcp/internal/auth"
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+)
+
+// categoryColors are the colour IDs Kanboard offers for categories.
+var categoryColors = []string{
+	"yellow", "blue", "green", "purple", "red", "orange", "grey", "brown",
+	"deep_orange", "dark_grey", "pink", "teal", "cyan", "lime", "light_green", "amber",
+}
+
+type CategoryRequest struct {
+	ProjectID int    `json:"project_id"`
+	Action    string `json:"action"`
+	Category  string `json:"category"`
+	Name      string `json:"name"`
+	Color     string `json:"color"`
+	TaskID    int    `json:"task_id"`
+}
+
+type CategoryResponse struct {
+	ProjectID  int               `json:"project_id"`
+	Change     string            `json:"change"`
+	CategoryID int               `json:"category_id,omitempty"`
+	TaskID     int               `json:"task_id,omitempty"`
+	Categories []models.Category `json:"categories"`
+}
+
+// CategoryHandler creates, renames and deletes a project's categories and
+// puts tasks in them.
+type CategoryHandler struct {
+	authManager *auth.AuthManager
+	config      *models.UserConfig
+}
+
+func NewCategoryHandler(authManager *auth.AuthManager, config *models.UserConfig) *CategoryHandler {
+	return &CategoryHandler{
+		authManager: authManager,
+		config:      config,
+	}
+}
+
+func (h *CategoryHandler) Preview(ctx context.Context, params map[string]interface{}, userID string) (string, error) {
+	return preview(ctx, h, params, userID)
+}
+
+func (h *CategoryHandler) Handle(ctx context.Context, params map[string]interface{}, userID string) (*models.MCPResponse, error) {
+	return handle(ctx, h, params, userID)
+}
+
+// plan validates the request against the project's current categories. For
+// assign the project is the task's, and project_id may be left out. The
+// change's Apply fills in its Response.
+func (h *CategoryHandler) plan(ctx context.Context, params map[string]interface{}, userID string) (*change, error) {
+	var req CategoryRequest
+	if err := parseWriteRequest(params, &req, "category"); err != nil {
+		return nil, err
+	}
+	switch req.Action {
+	case "create", "rename", "delete", "assign":
+	default:
+		return nil, fmt.Errorf("invalid action %q: must be create, rename, delete or assign", req.Action)
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	req.Color = strings.ToLower(strings.TrimSpace(req.Color))
+	if req.Color != "" && !slices.Contains(categoryColors, req.Color) {
+		return nil, fmt.Errorf("invalid color %q: must be one of %s", req.Color, strings.Join(categoryColors, ", "))
+	}
+
+	client, err := authenticatedClient(h.authManager, h.config, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var task *models.Task
+	if req.Action == "assign" {
+		if req.TaskID <= 0 {
+			return nil, fmt.Errorf("task_id is required to assign a category")
+		}
+		task, err = client.GetTask(ctx, req.TaskID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get task: %w", err)
+		}
+		if req.ProjectID > 0 && req.ProjectID != task.ProjectID {
+			return nil, fmt.Errorf("task %d belongs to project %d, not project %d", task.ID, task.ProjectID, req.ProjectID)
+		}
+		req.ProjectID = task.ProjectID
+	}
+	if req.ProjectID <= 0 {
+		return nil, fmt.Errorf("project_id is required")
+	}
+
+	// Putting a task in a category is a task edit; changing the categories
+	// themselves is managing the project.
+	if err := checkProjectAccess(ctx, client, req.ProjectID, req.Action != "assign"); err != nil {
+		return nil, err
+	}
+
+	categories, err := client.GetCategories(ctx, req.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+	project := projectLabel(ctx, client, req.ProjectID)
+	response := &CategoryResponse{ProjectID: req.ProjectID}
+
+	apply, err := h.action(ctx, client, req, task, categories, project, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &change{
+		Description: response.Change,
+		Response:    response,
+		Apply: func(ctx context.Context) error {
+			if err := apply(ctx); err != nil {
+				return fmt.Errorf("failed to %s category: %w", req.Action, err)
+			}
+			categories, err := client.GetCategories(ctx, req.ProjectID)
+			if err != nil {
+				return fmt.Errorf("categories changed but could not be read back: %w", err)
+			}
+			response.Categories = sortedCategories(categories)
+			return nil
+		},
+	}, nil
+}
+
+// action checks req against categories, sets response's Change, CategoryID
+// and TaskID, and returns the call that makes the change.
+func (h *CategoryHandler) action(ctx context.Context, client *api.Client, req CategoryRequest, task *models.Task, categories []models.Category, project string, response *CategoryResponse) (func(context.Context) error, error) {
+	switch req.Action {
+	case "create":
+		if err := checkCategoryName(categories, req.Name, 0); err != nil {
+			return nil, err
+		}
+		response.Change = fmt.Sprintf("Create category %q in %s", req.Name, project)
+		return func(ctx context.Context) error {
+			id, err := client.CreateCategory(ctx, req.ProjectID, req.Name, req.Color)
+			if err != nil {
+				return err
+			}
+			response.CategoryID = id
+			return nil
+		}, nil
+
+	case "assign":
+		response.TaskID = task.ID
+		label := fmt.Sprintf("task #%d %q", task.ID, task.Title)
+		if isNone(req.Category) {
+			if task.CategoryID == 0 {
+				return nil, fmt.Errorf("%s has no category", label)
+			}
+			response.Change = fmt.Sprintf("Remove %s from its category", label)
+			return func(ctx context.Context) error {
+				return client.SetTaskCategory(ctx, req.ProjectID, task.ID, 0)
+			}, nil
+		}
+		category, err := findCategory(categories, req.Category)
+		if err != nil {
+			return nil, err
+		}
+		if category.ID == task.CategoryID {
+			return nil, fmt.Errorf("%s is already in category %q", label, category.Name)
+		}
+		response.CategoryID = category.ID
+		response.Change = fmt.Sprintf("Put %s in category %q", label, category.Name)
+		return func(ctx context.Context) error {
+			return client.SetTaskCategory(ctx, req.ProjectID, task.ID, category.ID)
+		}, nil
+	}
+
+	category, err := findCategory(categories, req.Category)
+	if err != nil {
+		return nil, err
+	}
+	response.CategoryID = category.ID
+	label := fmt.Sprintf("category %q (ID %d)", category.Name, category.ID)
+
+	switch req.Action {
+	case "rename":
+		if req.Name == "" && req.Color == "" {
+			return nil, fmt.Errorf("rename needs a new name or color")
+		}
+		name := category.Name
+		if req.Name != "" {
+			name = req.Name
+		}
+		if err := checkCategoryName(categories, name, category.ID); err != nil {
+			return nil, err
+		}
+		if name != category.Name {
+			response.Change = fmt.Sprintf("Rename %s in %s to %q", label, project, name)
+		} else {
+			response.Change = fmt.Sprintf("Change the colour of %s in %s to %s", label, project, req.Color)
+		}
+		return func(ctx context.Context) error {
+			return client.UpdateCategory(ctx, req.ProjectID, category.ID, name, req.Color)
+		}, nil
+
+	case "delete":
+		tasks, err := client.GetTasksByStatus(ctx, req.ProjectID, api.TaskStatusOpen)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tasks: %w", err)
+		}
+		inUse := 0
+		for _, task := range tasks {
+			if task.CategoryID == category.ID {
+				inUse++
+			}
+		}
+		response.Change = fmt.Sprintf("Delete %s from %s", label, project)
+		if inUse == 1 {
+			response.Change += "; 1 open task will be left without a category"
+		} else if inUse > 1 {
+			response.Change += fmt.Sprintf("; %d open tasks will be left without a category", inUse)
+		}
+		return func(ctx context.Context) error {
+			return client.RemoveCategory(ctx, req.ProjectID, category.ID)
+		}, nil
+	}
+
+	return nil, fmt.Errorf("invalid action %q", req.Action)
+}
+
+// isNone reports whether a category or assignee parameter asks for none.
+func isNone(category string) bool {
+	switch strings.ToLower(strings.TrimSpace(category)) {
+	case "", "0", "none":
+		return true
+	}
+	return false
+}
+
+// findCategory looks a category up by ID or name.
+func findCategory(categories []models.Category, category string) (models.Category, error) {
+	if strings.TrimSpace(category) == "" {
+		return models.Category{}, fmt.Errorf("category is required")
+	}
+	for _, c := range categories {
+		if matchesNameOrID(category, c.ID, c.Name) {
+			return c, nil
+		}
+	}
+
+	if len(categories) == 0 {
+		return models.Category{}, fmt.Errorf("no category %q: the project has no categories", category)
+	}
+	names := make([]string, len(categories))
+	for i, c := range sortedCategories(categories) {
+		names[i] = c.Name
+	}
+	return models.Category{}, fmt.Errorf("no category %q in project: categories are %s", category, strings.Join(names, ", "))
+}
+
+// checkCategoryName refuses empty names, names that would read as "no
+// category" in assign, and names already used in the project.
+func checkCategoryName(categories []models.Category, name string, categoryID int) error {
+	if name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if isNone(name) {
+		return fmt.Errorf("%q cannot be used as a category name", name)
+	}
+	for _, c := range categories {
+		if c.ID != categoryID && strings.EqualFold(c.Name, name) {
+			return fmt.Errorf("the project already has a category named %q", c.Name)
+		}
+	}
+	return nil
+}
+
+func sortedCategories(categories []models.Category) []models.Category {
+	sorted := append([]models.Category(nil), categories...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return strings.ToLower(sorted[i].Name) < strings.ToLower(sorted[j].Name)
+	})
+	return sorted
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/charts"
+)
+
+const (
+	ChartBurndown       = "burndown"
+	ChartVelocity       = "velocity"
+	ChartCumulativeFlow = "cumulative_flow"
+)
+
+// ChartNames lists the analytics charts, each named after the analysis it
+// draws.
+var ChartNames = []string{ChartBurndown, ChartVelocity, ChartCumulativeFlow}
+
+// chartsFor returns the charts that can be drawn from analysisTypes.
+func chartsFor(analysisTypes []string) []string {
+	var names []string
+	for _, name := range ChartNames {
+		if slices.Contains(analysisTypes, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// analyticsChart describes the named chart for response. ok is false when
+// the analysis produced nothing to draw.
+func analyticsChart(name string, response AnalyticsResponse) (chart charts.Chart, ok bool) {
+	switch name {
+	case ChartBurndown:
+		chart = charts.Chart{Kind: charts.Line, Title: "Burndown"}
+		remaining := charts.Series{Name: "Remaining"}
+		ideal := charts.Series{Name: "Ideal", Dashed: true}
+		trend := charts.Series{Name: "Trend projection", Dashed: true}
+		for _, point := range response.BurndownChart {
+			chart.Labels = append(chart.Labels, point.Date)
+			remaining.Values = append(remaining.Values, float64(point.RemainingTasks))
+			ideal.Values = append(ideal.Values, float64(point.IdealRemaining))
+			trend.Values = append(trend.Values, float64(point.TrendProjection))
+		}
+		chart.Series = []charts.Series{remaining, ideal, trend}
+	case ChartVelocity:
+		chart = charts.Chart{Kind: charts.Bar, Title: "Velocity"}
+		completed := charts.Series{Name: "Tasks completed"}
+		for _, metric := range response.VelocityMetrics {
+			chart.Labels = append(chart.Labels, metric.Period)
+			completed.Values = append(completed.Values, float64(metric.TasksCompleted))
+		}
+		chart.Series = []charts.Series{completed}
+	case ChartCumulativeFlow:
+		chart = charts.Chart{Kind: charts.StackedArea, Title: "Cumulative flow"}
+		done := charts.Series{Name: "Done"}
+		inProgress := charts.Series{Name: "In progress"}
+		toDo := charts.Series{Name: "To do"}
+		if response.CumulativeFlow != nil {
+			for _, point := range response.CumulativeFlow.Points {
+				chart.Labels = append(chart.Labels, point.Date)
+				done.Values = append(done.Values, float64(point.Done))
+				inProgress.Values = append(inProgress.Values, float64(point.InProgress))
+				toDo.Values = append(toDo.Values, float64(point.ToDo))
+			}
+		}
+		chart.Series = []charts.Series{done, inProgress, toDo}
+	}
+	return chart, len(chart.Labels) > 0
+}
+
+// Chart renders one analytics chart as a PNG. params takes the same filters
+// as Handle; the analysis types are replaced by the chart's own.
+func (h *AnalyticsHandler) Chart(ctx context.Context, userID, name string, params map[string]interface{}) ([]byte, error) {
+	if !slices.Contains(ChartNames, name) {
+		return nil, fmt.Errorf("unknown chart %q: must be burndown, velocity or cumulative_flow", name)
+	}
+
+	req, err := h.parseRequest(params)
+	if err != nil {
+		return nil, err
+	}
+	req.AnalysisTypes = []string{name}
+
+	response, _, err := h.analyse(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	chart, ok := analyticsChart(name, response)
+	if !ok {
+		return nil, fmt.Errorf("no %s data in the selected time range", name)
+	}
+	return chart.PNG()
+}
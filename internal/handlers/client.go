@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/api"
+	"github.com/tech-arch1tect/kan-mcp/internal/auth"
+	"github.com/tech-arch1tect/kan-mcp/internal/logging"
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+)
+
+// kanboardToken returns the credential used for a user's Kanboard requests:
+// their own decrypted personal token, or the shared application token for
+// users registered in application auth mode.
+func kanboardToken(authManager *auth.AuthManager, config *models.UserConfig, user *models.User) (string, error) {
+	if user.AuthMode == models.AuthModeApp {
+		if config.KanboardAppToken == "" {
+			return "", fmt.Errorf("user is registered for application token auth but no application token is configured")
+		}
+		return config.KanboardAppToken, nil
+	}
+
+	return authManager.GetDecryptedToken(user)
+}
+
+func authenticatedClient(authManager *auth.AuthManager, config *models.UserConfig, userID string) (*api.Client, error) {
+	user, err := authManager.AuthenticateUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("authentication failed: %w", err)
+	}
+
+	token, err := kanboardToken(authManager, config, user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt token: %w", err)
+	}
+
+	kanboardURL := user.KanboardURL
+	if kanboardURL == "" {
+		kanboardURL = config.DefaultKanboardURL
+	}
+
+	return newKanboardClient(config, kanboardURL, user, token), nil
+}
+
+// displayUser returns the username and name to show for a Kanboard user. With
+// pseudonyms enabled both become the user's pseudonym; if one cannot be
+// assigned the user is shown as "unknown" rather than by name.
+func displayUser(config *models.UserConfig, client *api.Client, id, username, name string) (string, string) {
+	if config.Pseudonyms == nil || (username == "" && name == "") {
+		return username, name
+	}
+
+	key := id
+	if key == "" || key == "0" {
+		key = "username:" + username
+	}
+	pseudonym, err := config.Pseudonyms.Pseudonym(client.BaseURL(), key)
+	if err != nil {
+		logging.Warnf("Failed to assign a pseudonym: %v", err)
+		pseudonym = "unknown"
+	}
+
+	if username != "" {
+		username = pseudonym
+	}
+	if name != "" {
+		name = pseudonym
+	}
+	return username, name
+}
+
+func newKanboardClient(config *models.UserConfig, kanboardURL string, user *models.User, token string) *api.Client {
+	return api.NewClient(kanboardURL, user.KanboardUsername, token, api.Options{
+		AuthMode:   user.AuthMode,
+		AuthHeader: config.KanboardAuthHeader,
+		Timeout:    config.KanboardTimeout,
+		TLSConfig:  config.KanboardTLS,
+		Retry: api.RetryPolicy{
+			MaxRetries: config.KanboardRetry.MaxRetries,
+			BaseDelay:  config.KanboardRetry.BaseDelay,
+			MaxDelay:   config.KanboardRetry.MaxDelay,
+		},
+		RateLimit: api.RateLimit{
+			RequestsPerSecond: config.KanboardRateLimit.RequestsPerSecond,
+			Burst:             config.KanboardRateLimit.Burst,
+		},
+		Cache: config.KanboardCacheTTLs,
+		Breaker: api.BreakerSettings{
+			FailureThreshold: config.KanboardBreaker.FailureThreshold,
+			OpenDuration:     config.KanboardBreaker.OpenDuration,
+		},
+		Transport: api.TransportSettings{
+			MaxIdleConns:        config.KanboardTransport.MaxIdleConns,
+			MaxIdleConnsPerHost: config.KanboardTransport.MaxIdleConnsPerHost,
+			IdleConnTimeout:     config.KanboardTransport.IdleConnTimeout,
+		},
+		SlowCallThreshold: config.KanboardSlowCall,
+		ReadOnly:          config.ReadOnly,
+	})
+}
+
+// projectColumns fetches the columns of every project that appears in tasks,
+// keyed by project ID.
+func projectColumns(ctx context.Context, client *api.Client, tasks []TaskDetail) (map[string][]models.Column, error) {
+	columns := make(map[string][]models.Column)
+	for _, task := range tasks {
+		if _, done := columns[task.Project.ID]; done {
+			continue
+		}
+
+		projectID, err := strconv.Atoi(task.Project.ID)
+		if err != nil {
+			continue
+		}
+
+		projectColumns, err := client.GetColumns(ctx, projectID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get columns for project %s: %w", task.Project.Name, err)
+		}
+		columns[task.Project.ID] = projectColumns
+	}
+
+	return columns, nil
+}
+
+// userLocation returns the timezone from the user's Kanboard profile, falling
+// back to the configured default when it is unset or unknown.
+func userLocation(ctx context.Context, client *api.Client, config *models.UserConfig) *time.Location {
+	if me, err := client.GetMe(ctx); err == nil && me.Timezone != "" {
+		if location, err := time.LoadLocation(me.Timezone); err == nil {
+			return location
+		}
+	}
+	return defaultLocation(config)
+}
+
+func defaultLocation(config *models.UserConfig) *time.Location {
+	if config != nil && config.DefaultLocation != nil {
+		return config.DefaultLocation
+	}
+	return time.UTC
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// pageCursor is the opaque position handed back as next_cursor. Query ties it
+// to the request that produced it.
+type pageCursor struct {
+	Offset int    `json:"o"`
+	Query  string `json:"q"`
+}
+
+func fingerprint(v interface{}) string {
+	data, _ := json.Marshal(v)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+func encodeCursor(offset int, queryKey string) string {
+	data, _ := json.Marshal(pageCursor{Offset: offset, Query: queryKey})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(cursor, queryKey string) (int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	var decoded pageCursor
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return 0, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	if decoded.Query != queryKey {
+		return 0, fmt.Errorf("cursor was issued for a different query; repeat the original filters or start again without a cursor")
+	}
+
+	if decoded.Offset < 0 {
+		return 0, fmt.Errorf("invalid cursor offset %d", decoded.Offset)
+	}
+
+	return decoded.Offset, nil
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	queryKey := fingerprint(map[string]interface{}{"status_filter": "active", "project_ids": []string{"1", "2"}})
+
+	tests := []struct {
+		name   string
+		offset int
+	}{
+		{"start", 0},
+		{"second page", 100},
+		{"large offset", 1 << 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offset, err := decodeCursor(encodeCursor(tt.offset, queryKey), queryKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if offset != tt.offset {
+				t.Errorf("offset = %d, want %d", offset, tt.offset)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorRejects(t *testing.T) {
+	queryKey := fingerprint(map[string]interface{}{"status_filter": "active"})
+	otherKey := fingerprint(map[string]interface{}{"status_filter": "closed"})
+
+	tests := []struct {
+		name   string
+		cursor string
+		want   string
+	}{
+		{"different query", encodeCursor(50, otherKey), "different query"},
+		{"not base64", "!!!", "invalid cursor"},
+		{"not JSON", base64.RawURLEncoding.EncodeToString([]byte("offset=50")), "invalid cursor"},
+		{"negative offset", encodeCursor(-1, queryKey), "invalid cursor offset"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeCursor(tt.cursor, queryKey)
+			if err == nil {
+				t.Fatal("cursor accepted")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error %q does not mention %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/api"
+	"github.com/tech-arch1tect/kan-mcp/internal/auth"
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+)
+
+type ColumnRequest struct {
+	ProjectID   int     `json:"project_id"`
+	Action      string  `json:"action"`
+	Column      string  `json:"column"`
+	Title       string  `json:"title"`
+	Description *string `json:"description"`
+	Position    int     `json:"position"`
+	TaskLimit   *int    `json:"task_limit"`
+}
+
+type ColumnResponse struct {
+	ProjectID int             `json:"project_id"`
+	Change    string          `json:"change"`
+	ColumnID  int             `json:"column_id"`
+	Columns   []models.Column `json:"columns"`
+	Warnings  []string        `json:"warnings,omitempty"`
+}
+
+// ColumnHandler creates, renames and reorders a project's columns and sets
+// their WIP limits.
+type ColumnHandler struct {
+	authManager *auth.AuthManager
+	config      *models.UserConfig
+}
+
+func NewColumnHandler(authManager *auth.AuthManager, config *models.UserConfig) *ColumnHandler {
+	return &ColumnHandler{
+		authManager: authManager,
+		config:      config,
+	}
+}
+
+func (h *ColumnHandler) Preview(ctx context.Context, params map[string]interface{}, userID string) (string, error) {
+	return preview(ctx, h, params, userID)
+}
+
+func (h *ColumnHandler) Handle(ctx context.Context, params map[string]interface{}, userID string) (*models.MCPResponse, error) {
+	return handle(ctx, h, params, userID)
+}
+
+// plan validates the request against the project's current columns. The
+// change's Apply fills in its Response, including the columns as
+// they are afterwards.
+func (h *ColumnHandler) plan(ctx context.Context, params map[string]interface{}, userID string) (*change, error) {
+	var req ColumnRequest
+	if err := parseWriteRequest(params, &req, "column"); err != nil {
+		return nil, err
+	}
+	if req.ProjectID <= 0 {
+		return nil, fmt.Errorf("project_id is required")
+	}
+	if req.TaskLimit != nil && *req.TaskLimit < 0 {
+		return nil, fmt.Errorf("invalid task_limit %d: must be 0 (no limit) or more", *req.TaskLimit)
+	}
+	req.Title = strings.TrimSpace(req.Title)
+
+	client, err := authenticatedClient(h.authManager, h.config, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkProjectAccess(ctx, client, req.ProjectID, true); err != nil {
+		return nil, err
+	}
+
+	columns, err := client.GetColumns(ctx, req.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get columns: %w", err)
+	}
+	project := projectLabel(ctx, client, req.ProjectID)
+	response := &ColumnResponse{ProjectID: req.ProjectID}
+
+	apply, err := h.action(ctx, client, req, columns, project, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &change{
+		Description: response.Change,
+		Warnings:    response.Warnings,
+		Response:    response,
+		Apply: func(ctx context.Context) error {
+			if err := apply(ctx); err != nil {
+				return err
+			}
+			columns, err := client.GetColumns(ctx, req.ProjectID)
+			if err != nil {
+				return fmt.Errorf("columns changed but could not be read back: %w", err)
+			}
+			response.Columns = sortedColumns(columns)
+			return nil
+		},
+	}, nil
+}
+
+// action checks req against columns, sets response's Change, ColumnID and
+// Warnings, and returns the call that makes the change.
+func (h *ColumnHandler) action(ctx context.Context, client *api.Client, req ColumnRequest, columns []models.Column, project string, response *ColumnResponse) (func(context.Context) error, error) {
+	if req.Action == "create" {
+		if err := checkColumnTitle(columns, req.Title, 0); err != nil {
+			return nil, err
+		}
+		if req.Position < 0 || req.Position > len(columns)+1 {
+			return nil, fmt.Errorf("invalid position %d: must be between 1 and %d", req.Position, len(columns)+1)
+		}
+		taskLimit, description := 0, ""
+		if req.TaskLimit != nil {
+			taskLimit = *req.TaskLimit
+		}
+		if req.Description != nil {
+			description = *req.Description
+		}
+
+		response.Change = fmt.Sprintf("Create column %q in %s", req.Title, project)
+		if req.Position > 0 {
+			response.Change += fmt.Sprintf(" at position %d", req.Position)
+		}
+		if taskLimit > 0 {
+			response.Change += fmt.Sprintf(" with a WIP limit of %d", taskLimit)
+		}
+		return func(ctx context.Context) error {
+			id, err := client.AddColumn(ctx, req.ProjectID, req.Title, taskLimit, description)
+			if err != nil {
+				return fmt.Errorf("failed to create column: %w", err)
+			}
+			response.ColumnID = id
+			if req.Position > 0 && req.Position <= len(columns) {
+				if err := client.ChangeColumnPosition(ctx, req.ProjectID, id, req.Position); err != nil {
+					return fmt.Errorf("column %d was created but could not be moved: %w", id, err)
+				}
+			}
+			return nil
+		}, nil
+	}
+
+	column, err := findColumn(columns, req.Column)
+	if err != nil {
+		return nil, err
+	}
+	response.ColumnID = column.ID
+	label := fmt.Sprintf("column %q (ID %d)", column.Title, column.ID)
+
+	// Everything but the position goes through updateColumn, which needs the
+	// fields that are not changing as well.
+	title, taskLimit, description := column.Title, column.TaskLimit, column.Description
+
+	var apply func(ctx context.Context) error
+	switch req.Action {
+	case "rename":
+		if req.Title == "" && req.Description == nil {
+			return nil, fmt.Errorf("rename needs a new title or description")
+		}
+		if req.Title != "" {
+			title = req.Title
+		}
+		if req.Description != nil {
+			description = *req.Description
+		}
+		if err := checkColumnTitle(columns, title, column.ID); err != nil {
+			return nil, err
+		}
+		if title != column.Title {
+			response.Change = fmt.Sprintf("Rename %s in %s to %q", label, project, title)
+		} else {
+			response.Change = fmt.Sprintf("Change the description of %s in %s", label, project)
+		}
+	case "set_limit":
+		if req.TaskLimit == nil {
+			return nil, fmt.Errorf("set_limit needs task_limit (0 removes the limit)")
+		}
+		taskLimit = *req.TaskLimit
+		if taskLimit == 0 {
+			response.Change = fmt.Sprintf("Remove the WIP limit of %s in %s", label, project)
+		} else {
+			response.Change = fmt.Sprintf("Set the WIP limit of %s in %s to %d", label, project, taskLimit)
+			open, err := openTasksInColumn(ctx, client, req.ProjectID, column.ID)
+			if err != nil {
+				return nil, err
+			}
+			if open > taskLimit {
+				response.Warnings = append(response.Warnings, fmt.Sprintf("%s already holds %d open tasks, more than the new limit", label, open))
+			}
+		}
+	case "move":
+		if req.Position < 1 || req.Position > len(columns) {
+			return nil, fmt.Errorf("invalid position %d: must be between 1 and %d", req.Position, len(columns))
+		}
+		response.Change = fmt.Sprintf("Move %s in %s to position %d", label, project, req.Position)
+		apply = func(ctx context.Context) error {
+			return client.ChangeColumnPosition(ctx, req.ProjectID, column.ID, req.Position)
+		}
+	default:
+		return nil, fmt.Errorf("invalid action %q: must be create, rename, move or set_limit", req.Action)
+	}
+
+	if apply == nil {
+		apply = func(ctx context.Context) error {
+			return client.UpdateColumn(ctx, req.ProjectID, column.ID, title, taskLimit, description)
+		}
+	}
+
+	return func(ctx context.Context) error {
+		if err := apply(ctx); err != nil {
+			return fmt.Errorf("failed to %s column: %w", strings.ReplaceAll(req.Action, "_", " "), err)
+		}
+		return nil
+	}, nil
+}
+
+// findColumn looks a column up by ID or title.
+func findColumn(columns []models.Column, column string) (models.Column, error) {
+	if strings.TrimSpace(column) == "" {
+		return models.Column{}, fmt.Errorf("column is required")
+	}
+	for _, c := range columns {
+		if matchesNameOrID(column, c.ID, c.Title) {
+			return c, nil
+		}
+	}
+
+	titles := make([]string, len(columns))
+	for i, c := range sortedColumns(columns) {
+		titles[i] = c.Title
+	}
+	return models.Column{}, fmt.Errorf("no column %q in project: columns are %s", column, strings.Join(titles, ", "))
+}
+
+// checkColumnTitle refuses empty titles and titles already used by another
+// column in the project.
+func checkColumnTitle(columns []models.Column, title string, columnID int) error {
+	if title == "" {
+		return fmt.Errorf("title is required")
+	}
+	for _, c := range columns {
+		if c.ID != columnID && strings.EqualFold(c.Title, title) {
+			return fmt.Errorf("the project already has a column named %q", c.Title)
+		}
+	}
+	return nil
+}
+
+func openTasksInColumn(ctx context.Context, client *api.Client, projectID, columnID int) (int, error) {
+	tasks, err := client.GetTasksByStatus(ctx, projectID, api.TaskStatusOpen)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get tasks: %w", err)
+	}
+	count := 0
+	for _, task := range tasks {
+		if task.ColumnID == columnID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func sortedColumns(columns []models.Column) []models.Column {
+	sorted := append([]models.Column(nil), columns...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Position < sorted[j].Position
+	})
+	return sorted
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/auth"
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+)
+
+// ConnectionReport describes a round trip to a user's Kanboard instance. The
+// fields for steps that were not reached are left empty.
+type ConnectionReport struct {
+	UserID          string
+	KanboardURL     string
+	Username        string
+	AuthMode        string
+	KanboardUser    *models.KanboardUser
+	GetMeLatency    time.Duration
+	ProjectCount    int
+	ProjectsLatency time.Duration
+}
+
+// CheckConnection decrypts a user's token and calls getMe and getMyProjects
+// with it, returning what was learnt up to the first failure.
+func CheckConnection(ctx context.Context, authManager *auth.AuthManager, config *models.UserConfig, userID string) (*ConnectionReport, error) {
+	return checkConnection(ctx, authManager, config, userID, "")
+}
+
+// CheckToken runs the same calls as CheckConnection with a personal token
+// that has not been stored yet, so a replacement can be checked before it
+// overwrites the user's current token.
+func CheckToken(ctx context.Context, authManager *auth.AuthManager, config *models.UserConfig, userID, token string) (*ConnectionReport, error) {
+	return checkConnection(ctx, authManager, config, userID, token)
+}
+
+// checkConnection uses candidate in place of the stored token when it is set.
+func checkConnection(ctx context.Context, authManager *auth.AuthManager, config *models.UserConfig, userID, candidate string) (*ConnectionReport, error) {
+	user, err := authManager.GetUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ConnectionReport{
+		UserID:      user.UserID,
+		KanboardURL: user.KanboardURL,
+		Username:    user.KanboardUsername,
+		AuthMode:    user.AuthMode,
+	}
+	if report.KanboardURL == "" {
+		report.KanboardURL = config.DefaultKanboardURL
+	}
+	if report.AuthMode == "" {
+		report.AuthMode = models.AuthModeUser
+	}
+
+	token := candidate
+	if token == "" {
+		token, err = kanboardToken(authManager, config, user)
+		if err != nil {
+			return report, fmt.Errorf("failed to decrypt token: %w", err)
+		}
+	} else if user.AuthMode == models.AuthModeApp {
+		return report, fmt.Errorf("user is registered for application token auth and has no personal token")
+	}
+
+	client := newKanboardClient(config, report.KanboardURL, user, token)
+
+	start := time.Now()
+	me, err := client.GetMe(ctx)
+	report.GetMeLatency = time.Since(start)
+	if err != nil {
+		return report, fmt.Errorf("getMe failed: %w", err)
+	}
+	report.KanboardUser = me
+
+	start = time.Now()
+	projectsRaw, err := client.GetMyProjectsRaw(ctx)
+	report.ProjectsLatency = time.Since(start)
+	if err != nil {
+		return report, fmt.Errorf("getMyProjects failed: %w", err)
+	}
+
+	var projects []json.RawMessage
+	if err := json.Unmarshal(projectsRaw, &projects); err != nil {
+		return report, fmt.Errorf("failed to parse projects: %w", err)
+	}
+	report.ProjectCount = len(projects)
+
+	return report, nil
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/auth"
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+	"github.com/tech-arch1tect/kan-mcp/internal/storage"
+)
+
+func TestCheckTokenUsesCandidate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, password, _ := r.BasicAuth(); password != "new-token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		var req struct {
+			ID     int    `json:"id"`
+			Method string `json:"method"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		var result interface{} = []interface{}{}
+		if req.Method == "getMe" {
+			result = map[string]interface{}{"id": 1, "username": "alice"}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	defer server.Close()
+
+	userStore, err := storage.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	encryptionKey := make([]byte, 32)
+	authManager, err := auth.NewAuthManager(encryptionKey, userStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := authManager.RegisterUser(server.URL, "alice", "old-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &models.UserConfig{
+		EncryptionKey:      encryptionKey,
+		KanboardAuthHeader: "Authorization",
+		KanboardTimeout:    time.Minute,
+	}
+	ctx := context.Background()
+
+	if _, err := CheckConnection(ctx, authManager, config, user.UserID); err == nil {
+		t.Error("stored token accepted by a server that only takes the new one")
+	}
+	if _, err := CheckToken(ctx, authManager, config, user.UserID, "wrong-token"); err == nil {
+		t.Error("wrong candidate token accepted")
+	}
+	report, err := CheckToken(ctx, authManager, config, user.UserID, "new-token")
+	if err != nil {
+		t.Fatalf("new token rejected: %v", err)
+	}
+	if report.KanboardUser == nil || report.KanboardUser.Username != "alice" {
+		t.Errorf("report user = %+v, want alice", report.KanboardUser)
+	}
+
+	// Checking a candidate never stores it.
+	stored, err := authManager.GetUser(user.UserID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token, _ := authManager.GetDecryptedToken(stored); token != "old-token" {
+		t.Errorf("stored token = %q, want old-token", token)
+	}
+}
@@ -0,0 +1,383 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/auth"
+	"github.com/tech-arch1tect/kan-mcp/internal/logging"
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+	"github.com/tech-arch1tect/kan-mcp/internal/storage"
+	"github.com/tech-arch1tect/kan-mcp/internal/templates"
+)
+
+const (
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+// maxDigestItems caps each list in a digest; the counts still cover every
+// task.
+const maxDigestItems = 50
+
+// digestRetryDelay is how long a failed digest waits before it is tried
+// again.
+const digestRetryDelay = time.Hour
+
+// Digest summarises a user's own tasks over a period: what is overdue, what
+// was completed and what falls due soon.
+type Digest struct {
+	Period         string       `json:"period"`
+	From           string       `json:"from"`
+	To             string       `json:"to"`
+	GeneratedAt    string       `json:"generated_at"`
+	Timezone       string       `json:"timezone"`
+	OverdueCount   int          `json:"overdue_count"`
+	CompletedCount int          `json:"completed_count"`
+	UpcomingCount  int          `json:"upcoming_count"`
+	Overdue        []DigestItem `json:"overdue"`
+	Completed      []DigestItem `json:"completed"`
+	Upcoming       []DigestItem `json:"upcoming"`
+	Warnings       []string     `json:"warnings,omitempty"`
+}
+
+type DigestItem struct {
+	TaskID      string `json:"task_id"`
+	Title       string `json:"title"`
+	Project     string `json:"project"`
+	DueDate     string `json:"due_date,omitempty"`
+	CompletedAt string `json:"completed_at,omitempty"`
+	URL         string `json:"url"`
+}
+
+type DigestGenerator struct {
+	authManager *auth.AuthManager
+	config      *models.UserConfig
+}
+
+func NewDigestGenerator(authManager *auth.AuthManager, config *models.UserConfig) *DigestGenerator {
+	return &DigestGenerator{
+		authManager: authManager,
+		config:      config,
+	}
+}
+
+// Generate builds a user's digest for the period ending at the start of the
+// day containing now, in the user's timezone. A daily digest covers the
+// previous day and deadlines in the coming week; a weekly one the previous
+// seven days and deadlines in the coming two weeks.
+func (g *DigestGenerator) Generate(ctx context.Context, userID, period string, now time.Time) (*Digest, error) {
+	days, ahead := 1, 7
+	switch period {
+	case DigestDaily:
+	case DigestWeekly:
+		days, ahead = 7, 14
+	default:
+		return nil, fmt.Errorf("invalid digest period %q: must be daily or weekly", period)
+	}
+
+	client, err := authenticatedClient(g.authManager, g.config, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	me, err := client.GetMe(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user info: %w", err)
+	}
+
+	// The user's timezone is only known once tasks are loaded, so closed
+	// tasks are fetched from a day earlier than the period can start.
+	tasksData, err := NewTaskService(g.authManager, g.config).Load(ctx, userID, TasksRequest{
+		AssigneeIDs:    []string{strconv.Itoa(me.ID)},
+		StatusFilter:   "all",
+		IncludeOverdue: true,
+		SortBy:         "due_date",
+		ModifiedSince:  now.AddDate(0, 0, -days-1).Format("2006-01-02"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks data: %w", err)
+	}
+
+	location := tasksData.Location
+	today := startOfDay(now.In(location))
+	from := today.AddDate(0, 0, -days)
+	horizon := today.AddDate(0, 0, ahead+1)
+
+	digest := &Digest{
+		Period:      period,
+		From:        from.Format("2006-01-02"),
+		To:          today.AddDate(0, 0, -1).Format("2006-01-02"),
+		GeneratedAt: now.In(location).Format(time.RFC3339),
+		Timezone:    location.String(),
+		Overdue:     []DigestItem{},
+		Completed:   []DigestItem{},
+		Upcoming:    []DigestItem{},
+		Warnings:    projectWarningMessages(tasksData.Warnings),
+	}
+
+	type completion struct {
+		item DigestItem
+		at   time.Time
+	}
+	var completions []completion
+
+	tasks := NewTasksHandler(g.authManager, g.config)
+	for _, task := range tasksData.Tasks {
+		item := DigestItem{
+			TaskID:  task.ID,
+			Title:   task.Title,
+			Project: task.Project.Name,
+			DueDate: task.Dates.Due,
+			URL:     task.URL,
+		}
+
+		if tasks.isTaskCompleted(task) {
+			completed := task.Dates.Completed
+			if completed == "" {
+				completed = task.Dates.Moved
+			}
+			at, err := time.Parse(time.RFC3339, completed)
+			if err != nil || at.Before(from) || !at.Before(today) {
+				continue
+			}
+			item.CompletedAt = completed
+			completions = append(completions, completion{item: item, at: at})
+			continue
+		}
+
+		if task.IsOverdue {
+			digest.Overdue = append(digest.Overdue, item)
+			continue
+		}
+		if due, err := time.Parse(time.RFC3339, task.Dates.Due); err == nil && due.Before(horizon) {
+			digest.Upcoming = append(digest.Upcoming, item)
+		}
+	}
+
+	sort.Slice(completions, func(i, j int) bool {
+		return completions[i].at.Before(completions[j].at)
+	})
+	for _, c := range completions {
+		digest.Completed = append(digest.Completed, c.item)
+	}
+
+	digest.OverdueCount, digest.CompletedCount, digest.UpcomingCount = len(digest.Overdue), len(digest.Completed), len(digest.Upcoming)
+	digest.Overdue = digest.Overdue[:min(len(digest.Overdue), maxDigestItems)]
+	digest.Completed = digest.Completed[:min(len(digest.Completed), maxDigestItems)]
+	digest.Upcoming = digest.Upcoming[:min(len(digest.Upcoming), maxDigestItems)]
+
+	return digest, nil
+}
+
+// DigestStore keeps each user's latest digest per period.
+type DigestStore interface {
+	Latest(userID, period string) (storage.StoredDigest, bool)
+	Save(userID, period string, digest storage.StoredDigest) error
+	PruneUsers(userIDs []string) error
+}
+
+// DigestSchedule says which digests are generated and when: every day at
+// Hour:Minute, and for weekly digests only on Weekday, in the default
+// timezone. Digests are posted to WebhookURL when it is set.
+type DigestSchedule struct {
+	Periods       []string
+	Hour          int
+	Minute        int
+	Weekday       time.Weekday
+	WebhookURL    string
+	WebhookSecret string
+}
+
+// DigestScheduler generates every registered user's digests when they fall
+// due, keeps the latest of each period in its store and optionally posts
+// them to a webhook.
+type DigestScheduler struct {
+	authManager *auth.AuthManager
+	config      *models.UserConfig
+	store       DigestStore
+	schedule    DigestSchedule
+	generator   *DigestGenerator
+	httpClient  *http.Client
+	// attempted records when a failing digest, keyed by user ID and period,
+	// was last tried.
+	attempted map[string]time.Time
+}
+
+func NewDigestScheduler(authManager *auth.AuthManager, config *models.UserConfig, store DigestStore, schedule DigestSchedule) *DigestScheduler {
+	return &DigestScheduler{
+		authManager: authManager,
+		config:      config,
+		store:       store,
+		schedule:    schedule,
+		generator:   NewDigestGenerator(authManager, config),
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		attempted:   make(map[string]time.Time),
+	}
+}
+
+// Run generates the digests that are due straight away and then checks once
+// a minute until ctx is done.
+func (s *DigestScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		s.RunDue(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDue generates each digest whose scheduled time has passed since the
+// user's last one of that period, so digests missed while the server was down
+// are caught up. Failures are logged and retried after digestRetryDelay.
+func (s *DigestScheduler) RunDue(ctx context.Context, now time.Time) {
+	users, err := s.authManager.ListUsers()
+	if err != nil {
+		logging.Warnf("Digests: failed to list users: %v", err)
+		return
+	}
+
+	userIDs := make([]string, 0, len(users))
+	for _, user := range users {
+		userIDs = append(userIDs, user.UserID)
+		if expiry := s.authManager.CredentialExpiry(user); !expiry.IsZero() && now.After(expiry) {
+			continue
+		}
+
+		for _, period := range s.schedule.Periods {
+			if ctx.Err() != nil {
+				return
+			}
+
+			due := s.lastDue(period, now)
+			if stored, ok := s.store.Latest(user.UserID, period); ok && !stored.GeneratedAt.Before(due) {
+				continue
+			}
+			key := user.UserID + "|" + period
+			if last, ok := s.attempted[key]; ok && now.Sub(last) < digestRetryDelay {
+				continue
+			}
+
+			if err := s.deliver(ctx, user, period, now); err != nil {
+				logging.Warnf("Digests: %s digest for user %s failed: %v", period, logging.UserID(user.UserID), err)
+				s.attempted[key] = now
+				continue
+			}
+			delete(s.attempted, key)
+		}
+	}
+
+	if err := s.store.PruneUsers(userIDs); err != nil {
+		logging.Warnf("Digests: failed to prune removed users: %v", err)
+	}
+}
+
+// lastDue returns the most recent time at or before now that a digest of
+// period was scheduled for.
+func (s *DigestScheduler) lastDue(period string, now time.Time) time.Time {
+	now = now.In(defaultLocation(s.config))
+	due := time.Date(now.Year(), now.Month(), now.Day(), s.schedule.Hour, s.schedule.Minute, 0, 0, now.Location())
+	if due.After(now) {
+		due = due.AddDate(0, 0, -1)
+	}
+	if period == DigestWeekly {
+		for due.Weekday() != s.schedule.Weekday {
+			due = due.AddDate(0, 0, -1)
+		}
+	}
+	return due
+}
+
+func (s *DigestScheduler) deliver(ctx context.Context, user *models.User, period string, now time.Time) error {
+	digest, err := s.generator.Generate(ctx, user.UserID, period, now)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(digest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal digest: %w", err)
+	}
+
+	if err := s.store.Save(user.UserID, period, storage.StoredDigest{GeneratedAt: now, Digest: data}); err != nil {
+		return err
+	}
+
+	// The digest is stored by now, so a failed post is not retried.
+	if s.schedule.WebhookURL != "" {
+		text, err := s.config.Templates.Render(templates.Digest, digest)
+		if err != nil {
+			logging.Warnf("Digests: failed to render %s digest for user %s: %v", period, logging.UserID(user.UserID), err)
+		}
+		if err := s.post(ctx, user, period, data, text); err != nil {
+			logging.Warnf("Digests: failed to post %s digest for user %s: %v", period, logging.UserID(user.UserID), err)
+		}
+	}
+
+	logging.Debugf("Digests: generated %s digest for user %s", period, logging.UserID(user.UserID))
+	return nil
+}
+
+// post sends a digest to the webhook. The user ID is left out since it is a
+// credential; the Kanboard instance and username identify the recipient.
+// text is the digest rendered from the digest template.
+func (s *DigestScheduler) post(ctx context.Context, user *models.User, period string, digest json.RawMessage, text string) error {
+	kanboardURL := user.KanboardURL
+	if kanboardURL == "" {
+		kanboardURL = s.config.DefaultKanboardURL
+	}
+
+	body, err := json.Marshal(struct {
+		KanboardURL string          `json:"kanboard_url"`
+		Username    string          `json:"username"`
+		Period      string          `json:"period"`
+		Digest      json.RawMessage `json:"digest"`
+		Text        string          `json:"text,omitempty"`
+	}{kanboardURL, user.KanboardUsername, period, digest, text})
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	return postWebhook(ctx, s.httpClient, s.schedule.WebhookURL, s.schedule.WebhookSecret, body)
+}
+
+// postWebhook posts a JSON body to url. With a secret, X-Signature carries
+// the hex HMAC-SHA256 of the body.
+func postWebhook(ctx context.Context, client *http.Client, url, secret string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		req.Header.Set("X-Signature", hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
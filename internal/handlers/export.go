@@ -0,0 +1,474 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+)
+
+const (
+	ExportCSV  = "csv"
+	ExportXLSX = "xlsx"
+)
+
+// maxExportTasks caps the rows of a task export, which is not paged.
+const maxExportTasks = 5000
+
+// ExportSummary is the text returned alongside exported files.
+type ExportSummary struct {
+	Format   string       `json:"export_format"`
+	Files    []ExportFile `json:"files"`
+	Summary  interface{}  `json:"summary,omitempty"`
+	Warnings []string     `json:"warnings,omitempty"`
+}
+
+type ExportFile struct {
+	Name     string `json:"name"`
+	MimeType string `json:"mime_type"`
+	Rows     int    `json:"rows"`
+	Bytes    int    `json:"bytes"`
+}
+
+// exportTable is one CSV file or spreadsheet sheet. Cells are strings, ints,
+// float64s or bools.
+type exportTable struct {
+	name   string
+	header []string
+	rows   [][]interface{}
+}
+
+func (t *exportTable) add(cells ...interface{}) {
+	t.rows = append(t.rows, cells)
+}
+
+func validateExportFormat(format string) error {
+	switch format {
+	case "", ExportCSV, ExportXLSX:
+		return nil
+	}
+	return fmt.Errorf("invalid export_format %q: must be csv or xlsx", format)
+}
+
+// exportResponse encodes tables as files named after baseName and returns
+// them as attachments after a text summary. CSV gives one file per table;
+// XLSX one workbook with a sheet per table.
+func exportResponse(format, baseName string, tables []exportTable, summary interface{}, warnings []string) (*models.MCPResponse, error) {
+	type file struct {
+		ExportFile
+		data []byte
+	}
+	var files []file
+
+	switch format {
+	case ExportCSV:
+		for _, table := range tables {
+			data, err := encodeCSV(table)
+			if err != nil {
+				return nil, err
+			}
+			name := baseName + ".csv"
+			if len(tables) > 1 {
+				name = baseName + "-" + strings.ReplaceAll(table.name, "_", "-") + ".csv"
+			}
+			files = append(files, file{ExportFile{Name: name, MimeType: "text/csv", Rows: len(table.rows), Bytes: len(data)}, data})
+		}
+	case ExportXLSX:
+		data, err := encodeXLSX(tables)
+		if err != nil {
+			return nil, err
+		}
+		rows := 0
+		for _, table := range tables {
+			rows += len(table.rows)
+		}
+		files = append(files, file{ExportFile{Name: baseName + ".xlsx", MimeType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", Rows: rows, Bytes: len(data)}, data})
+	default:
+		return nil, validateExportFormat(format)
+	}
+
+	exportSummary := ExportSummary{Format: format, Summary: summary, Warnings: warnings}
+	for _, f := range files {
+		exportSummary.Files = append(exportSummary.Files, f.ExportFile)
+	}
+	summaryJSON, err := json.MarshalIndent(exportSummary, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal export summary: %w", err)
+	}
+
+	response := &models.MCPResponse{
+		Content: []models.MCPContent{{Type: "text", Text: string(summaryJSON)}},
+	}
+	for _, f := range files {
+		response.Content = append(response.Content, models.MCPContent{
+			Type:     "resource",
+			Name:     f.Name,
+			MimeType: f.MimeType,
+			Data:     base64.StdEncoding.EncodeToString(f.data),
+		})
+	}
+	return response, nil
+}
+
+func formatExportCell(cell interface{}) string {
+	switch v := cell.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(cell)
+}
+
+func encodeCSV(table exportTable) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(table.header); err != nil {
+		return nil, fmt.Errorf("failed to write CSV: %w", err)
+	}
+	record := make([]string, len(table.header))
+	for _, row := range table.rows {
+		for i, cell := range row {
+			value := formatExportCell(cell)
+			// Spreadsheets run text starting with these as a formula, and
+			// titles come from anyone with access to the board.
+			if s, ok := cell.(string); ok && s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+				value = "'" + value
+			}
+			record[i] = value
+		}
+		if err := w.Write(record); err != nil {
+			return nil, fmt.Errorf("failed to write CSV: %w", err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+const xlsxHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+
+// encodeXLSX writes a minimal Office Open XML workbook: one sheet per table
+// with a bold, frozen header row. Strings are stored inline, so no shared
+// string table is needed.
+func encodeXLSX(tables []exportTable) ([]byte, error) {
+	var contentTypes, workbook, workbookRels strings.Builder
+	contentTypes.WriteString(xlsxHeader + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	workbook.WriteString(xlsxHeader + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	workbookRels.WriteString(xlsxHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+
+	sheets := make([][]byte, len(tables))
+	for i, table := range tables {
+		n := strconv.Itoa(i + 1)
+		contentTypes.WriteString(`<Override PartName="/xl/worksheets/sheet` + n + `.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`)
+		workbook.WriteString(`<sheet name="` + xmlEscape(xlsxSheetName(table.name)) + `" sheetId="` + n + `" r:id="rId` + n + `"/>`)
+		workbookRels.WriteString(`<Relationship Id="rId` + n + `" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet` + n + `.xml"/>`)
+		sheets[i] = xlsxSheet(table)
+	}
+	contentTypes.WriteString(`</Types>`)
+	workbook.WriteString(`</sheets></workbook>`)
+	workbookRels.WriteString(`<Relationship Id="rId` + strconv.Itoa(len(tables)+1) + `" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/></Relationships>`)
+
+	parts := []struct {
+		name string
+		data []byte
+	}{
+		{"[Content_Types].xml", []byte(contentTypes.String())},
+		{"_rels/.rels", []byte(xlsxHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`)},
+		{"xl/workbook.xml", []byte(workbook.String())},
+		{"xl/_rels/workbook.xml.rels", []byte(workbookRels.String())},
+		{"xl/styles.xml", []byte(xlsxHeader + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+			`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+			`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+			`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+			`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>` +
+			`</styleSheet>`)},
+	}
+	for i, sheet := range sheets {
+		parts = append(parts, struct {
+			name string
+			data []byte
+		}{"xl/worksheets/sheet" + strconv.Itoa(i+1) + ".xml", sheet})
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	modified := time.Now()
+	for _, part := range parts {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: part.name, Method: zip.Deflate, Modified: modified})
+		if err != nil {
+			return nil, fmt.Errorf("failed to write spreadsheet: %w", err)
+		}
+		if _, err := w.Write(part.data); err != nil {
+			return nil, fmt.Errorf("failed to write spreadsheet: %w", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write spreadsheet: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func xlsxSheet(table exportTable) []byte {
+	var b strings.Builder
+	b.WriteString(xlsxHeader + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	b.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews><sheetData>`)
+
+	header := make([]interface{}, len(table.header))
+	for i, name := range table.header {
+		header[i] = name
+	}
+	xlsxRow(&b, 1, header, true)
+	for i, row := range table.rows {
+		xlsxRow(&b, i+2, row, false)
+	}
+
+	b.WriteString(`</sheetData></worksheet>`)
+	return []byte(b.String())
+}
+
+func xlsxRow(b *strings.Builder, number int, cells []interface{}, bold bool) {
+	r := strconv.Itoa(number)
+	style := ""
+	if bold {
+		style = ` s="1"`
+	}
+
+	b.WriteString(`<row r="` + r + `">`)
+	for i, cell := range cells {
+		ref := xlsxColumn(i) + r
+		switch v := cell.(type) {
+		case int, float64:
+			b.WriteString(`<c r="` + ref + `"` + style + `><v>` + formatExportCell(v) + `</v></c>`)
+		case bool:
+			value := "0"
+			if v {
+				value = "1"
+			}
+			b.WriteString(`<c r="` + ref + `"` + style + ` t="b"><v>` + value + `</v></c>`)
+		case nil:
+		default:
+			text := formatExportCell(v)
+			if text == "" {
+				continue
+			}
+			// Excel rejects cells longer than this.
+			text = truncateRunes(text, 32767)
+			b.WriteString(`<c r="` + ref + `"` + style + ` t="inlineStr"><is><t xml:space="preserve">` + xmlEscape(text) + `</t></is></c>`)
+		}
+	}
+	b.WriteString(`</row>`)
+}
+
+// xlsxColumn returns the letters naming the zero-based column i.
+func xlsxColumn(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+// xlsxSheetName makes name acceptable to Excel: at most 31 characters and
+// none of []:*?/\.
+func xlsxSheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	return truncateRunes(name, 31)
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// exportTime formats an RFC 3339 task date for spreadsheets, which do not
+// parse time zone offsets.
+func exportTime(date string) string {
+	t, err := time.Parse(time.RFC3339, date)
+	if err != nil {
+		return ""
+	}
+	return t.Format("2006-01-02 15:04")
+}
+
+func tasksExportTable(tasks []TaskDetail) exportTable {
+	table := exportTable{
+		name:   "tasks",
+		header: []string{"id", "title", "project", "column", "swimlane", "active", "assignee", "priority", "category", "tags", "created", "started", "due", "completed", "overdue", "estimated_hours", "spent_hours", "url"},
+	}
+	for _, task := range tasks {
+		assignee := ""
+		if task.Assignee != nil {
+			assignee = task.Assignee.Name
+			if assignee == "" {
+				assignee = task.Assignee.Username
+			}
+		}
+		var estimated, spent interface{}
+		if task.TimeTracking != nil {
+			estimated, spent = task.TimeTracking.EstimatedHours, task.TimeTracking.SpentHours
+		}
+		table.add(task.ID, task.Title, task.Project.Name, task.Status.Column, task.Status.Swimlane, task.Status.IsActive,
+			assignee, task.Priority, task.Category, strings.Join(task.Tags, ", "),
+			exportTime(task.Dates.Created), exportTime(task.Dates.Started), exportTime(task.Dates.Due), exportTime(task.Dates.Completed),
+			task.IsOverdue, estimated, spent, task.URL)
+	}
+	return table
+}
+
+// analyticsExportTables turns each analysis in response into a table. Single
+// figures, such as forecast and throughput percentiles, go in the summary
+// table.
+func analyticsExportTables(response AnalyticsResponse) []exportTable {
+	summary := exportTable{name: "summary", header: []string{"metric", "value"}}
+	s := response.Summary
+	summary.add("analysis_period", s.AnalysisPeriod)
+	if s.Scope != "" {
+		summary.add("scope", s.Scope)
+	}
+	summary.add("total_tasks", s.TotalTasks)
+	summary.add("completed_tasks", s.CompletedTasks)
+	summary.add("overall_velocity", s.OverallVelocity)
+	summary.add("avg_cycle_time", s.AvgCycleTime)
+	summary.add("avg_lead_time", s.AvgLeadTime)
+	summary.add("productivity_trend", s.ProductivityTrend)
+	for _, insight := range s.KeyInsights {
+		summary.add("key_insight", insight)
+	}
+
+	tables := []exportTable{summary}
+
+	if len(response.CompletionTrends) > 0 {
+		table := exportTable{name: "completion_trends", header: []string{"period", "tasks_completed", "tasks_created", "completion_rate"}}
+		for _, m := range response.CompletionTrends {
+			table.add(m.Period, m.TasksCompleted, m.TasksCreated, m.CompletionRate)
+		}
+		tables = append(tables, table)
+	}
+
+	if len(response.CycleTimeMetrics) > 0 {
+		table := exportTable{name: "cycle_time", header: []string{"project", "column", "avg_days", "median_days", "p85_days", "min_days", "max_days", "task_count", "efficiency"}}
+		for _, m := range response.CycleTimeMetrics {
+			table.add(m.Project, m.Column, m.AvgDays, m.MedianDays, m.P85Days, m.MinDays, m.MaxDays, m.TaskCount, m.Efficiency)
+		}
+		tables = append(tables, table)
+	}
+
+	if len(response.LeadTimeMetrics) > 0 {
+		table := exportTable{name: "lead_time", header: []string{"project", "avg_days", "median_days", "p85_days", "min_days", "max_days", "task_count"}}
+		for _, m := range response.LeadTimeMetrics {
+			table.add(m.Project, m.AvgDays, m.MedianDays, m.P85Days, m.MinDays, m.MaxDays, m.TaskCount)
+		}
+		tables = append(tables, table)
+	}
+
+	if len(response.VelocityMetrics) > 0 {
+		table := exportTable{name: "velocity", header: []string{"period", "tasks_completed", "story_points", "estimated_hours", "actual_hours", "velocity_score", "efficiency_rating"}}
+		for _, m := range response.VelocityMetrics {
+			table.add(m.Period, m.TasksCompleted, m.StoryPoints, m.EstimatedHours, m.ActualHours, m.VelocityScore, m.EfficiencyRating)
+		}
+		tables = append(tables, table)
+	}
+
+	if t := response.Throughput; t != nil {
+		tables[0].add("throughput_sampled_weeks", t.SampledWeeks)
+		tables[0].add("throughput_weekly_p50", t.WeeklyP50)
+		tables[0].add("throughput_weekly_p85", t.WeeklyP85)
+		tables[0].add("throughput_weekly_p95", t.WeeklyP95)
+		if t.CycleTime != nil {
+			tables[0].add("cycle_time_p50_days", t.CycleTime.P50)
+			tables[0].add("cycle_time_p85_days", t.CycleTime.P85)
+			tables[0].add("cycle_time_p95_days", t.CycleTime.P95)
+		}
+		table := exportTable{name: "throughput", header: []string{"tasks_completed", "weeks"}}
+		for _, b := range t.Histogram {
+			table.add(b.TasksCompleted, b.Weeks)
+		}
+		tables = append(tables, table)
+	}
+
+	if len(response.TaskAging) > 0 {
+		table := exportTable{name: "task_aging", header: []string{"age_group", "task_count", "percentage", "avg_age_days", "oldest_task"}}
+		for _, m := range response.TaskAging {
+			table.add(m.AgeGroup, m.TaskCount, m.Percentage, m.AvgAgeDays, m.OldestTask)
+		}
+		tables = append(tables, table)
+	}
+
+	if len(response.BurndownChart) > 0 {
+		table := exportTable{name: "burndown", header: []string{"date", "remaining_tasks", "completed_tasks", "ideal_remaining", "trend_projection"}}
+		for _, m := range response.BurndownChart {
+			table.add(m.Date, m.RemainingTasks, m.CompletedTasks, m.IdealRemaining, m.TrendProjection)
+		}
+		tables = append(tables, table)
+	}
+
+	if f := response.CumulativeFlow; f != nil {
+		table := exportTable{name: "cumulative_flow", header: []string{"date", "done", "in_progress", "to_do"}}
+		for _, p := range f.Points {
+			table.add(p.Date, p.Done, p.InProgress, p.ToDo)
+		}
+		tables = append(tables, table)
+	}
+
+	if len(response.ProjectHealth) > 0 {
+		table := exportTable{name: "project_health", header: []string{"project_id", "project", "health_score", "completion_rate", "on_time_delivery", "team_utilisation", "quality_indicator", "risk_level"}}
+		for _, m := range response.ProjectHealth {
+			table.add(m.ProjectID, m.ProjectName, m.HealthScore, m.CompletionRate, m.OnTimeDelivery, m.TeamUtilisation, m.QualityIndicator, m.RiskLevel)
+		}
+		tables = append(tables, table)
+	}
+
+	if f := response.Forecast; f != nil {
+		tables[0].add("forecast_remaining_tasks", f.RemainingTasks)
+		tables[0].add("forecast_simulations", f.Simulations)
+		if f.Note != "" {
+			tables[0].add("forecast_note", f.Note)
+		}
+		table := exportTable{name: "forecast", header: []string{"confidence", "weeks", "date"}}
+		for _, c := range f.Forecasts {
+			table.add(c.Confidence, c.Weeks, c.Date)
+		}
+		tables = append(tables, table)
+	}
+
+	if w := response.WIPLimits; w != nil {
+		tables[0].add("cycle_time_over_wip_limit_days", w.CycleTimeOverLimitDays)
+		tables[0].add("cycle_time_within_wip_limit_days", w.CycleTimeWithinDays)
+		tables[0].add("cycle_time_wip_degradation_pct", w.CycleTimeDegradationPct)
+		table := exportTable{name: "wip_limits", header: []string{"project_id", "project", "column", "task_limit", "current_wip", "max_wip", "days_over_limit", "longest_streak_days", "currently_over"}}
+		for _, v := range w.Violations {
+			table.add(v.ProjectID, v.Project, v.Column, v.TaskLimit, v.CurrentWIP, v.MaxWIP, v.DaysOverLimit, v.LongestStreakDays, v.CurrentlyOver)
+		}
+		tables = append(tables, table)
+	}
+
+	return tables
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/auth"
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+)
+
+// externalLinkDependency is the only relation Kanboard's built-in link
+// providers offer.
+const externalLinkDependency = "related"
+
+type ExternalLinkRequest struct {
+	TaskID int    `json:"task_id"`
+	URL    string `json:"url"`
+	Title  string `json:"title"`
+	Type   string `json:"type"`
+}
+
+type ExternalLinkResponse struct {
+	TaskID int                   `json:"task_id"`
+	Change string                `json:"change"`
+	LinkID int                   `json:"link_id"`
+	Links  []models.ExternalLink `json:"links"`
+}
+
+// ExternalLinkHandler attaches URLs, such as pull requests, tickets and
+// documents, to tasks.
+type ExternalLinkHandler struct {
+	authManager *auth.AuthManager
+	config      *models.UserConfig
+}
+
+func NewExternalLinkHandler(authManager *auth.AuthManager, config *models.UserConfig) *ExternalLinkHandler {
+	return &ExternalLinkHandler{
+		authManager: authManager,
+		config:      config,
+	}
+}
+
+func (h *ExternalLinkHandler) Preview(ctx context.Context, params map[string]interface{}, userID string) (string, error) {
+	return preview(ctx, h, params, userID)
+}
+
+func (h *ExternalLinkHandler) Handle(ctx context.Context, params map[string]interface{}, userID string) (*models.MCPResponse, error) {
+	return handle(ctx, h, params, userID)
+}
+
+// plan validates the request against the task's current links. The change's
+// Apply fills in its Response.
+func (h *ExternalLinkHandler) plan(ctx context.Context, params map[string]interface{}, userID string) (*change, error) {
+	var req ExternalLinkRequest
+	if err := parseWriteRequest(params, &req, "link"); err != nil {
+		return nil, err
+	}
+	if req.TaskID <= 0 {
+		return nil, fmt.Errorf("task_id is required")
+	}
+	req.URL = strings.TrimSpace(req.URL)
+	if err := checkLinkURL(req.URL); err != nil {
+		return nil, err
+	}
+	req.Title = strings.TrimSpace(req.Title)
+	req.Type = strings.ToLower(strings.TrimSpace(req.Type))
+	switch req.Type {
+	case "":
+		req.Type = "auto"
+	case "auto", "weblink", "attachment":
+	default:
+		return nil, fmt.Errorf("invalid type %q: must be auto, weblink or attachment", req.Type)
+	}
+
+	client, err := authenticatedClient(h.authManager, h.config, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := client.GetTask(ctx, req.TaskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+	if err := checkProjectAccess(ctx, client, task.ProjectID, false); err != nil {
+		return nil, err
+	}
+
+	links, err := client.GetExternalTaskLinks(ctx, task.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get external links: %w", err)
+	}
+	for _, link := range links {
+		if link.URL == req.URL {
+			return nil, fmt.Errorf("task #%d already links to %s (link ID %d)", task.ID, req.URL, link.ID)
+		}
+	}
+
+	response := &ExternalLinkResponse{TaskID: task.ID}
+	response.Change = fmt.Sprintf("Link task #%d %q to %s", task.ID, task.Title, req.URL)
+	if req.Title != "" {
+		response.Change += fmt.Sprintf(" as %q", req.Title)
+	}
+
+	return &change{
+		Description: response.Change,
+		Response:    response,
+		Apply: func(ctx context.Context) error {
+			id, err := client.CreateExternalTaskLink(ctx, task.ID, req.URL, externalLinkDependency, req.Type, req.Title)
+			if err != nil {
+				return fmt.Errorf("failed to create external link: %w", err)
+			}
+			response.LinkID = id
+			links, err := client.GetExternalTaskLinks(ctx, task.ID)
+			if err != nil {
+				return fmt.Errorf("link created but could not be read back: %w", err)
+			}
+			response.Links = links
+			if response.Links == nil {
+				response.Links = []models.ExternalLink{}
+			}
+			return nil
+		},
+	}, nil
+}
+
+// checkLinkURL accepts absolute http and https URLs, which are what both of
+// Kanboard's link providers can open.
+func checkLinkURL(link string) error {
+	if link == "" {
+		return fmt.Errorf("url is required")
+	}
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return fmt.Errorf("invalid url %q: %w", link, err)
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("invalid url %q: must be an absolute http or https URL", link)
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/auth"
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+)
+
+type FocusHandler struct {
+	authManager *auth.AuthManager
+	config      *models.UserConfig
+}
+
+func NewFocusHandler(authManager *auth.AuthManager, config *models.UserConfig) *FocusHandler {
+	return &FocusHandler{
+		authManager: authManager,
+		config:      config,
+	}
+}
+
+type FocusRequest struct {
+	ProjectIDs []string `json:"project_ids"`
+	Limit      int      `json:"limit"`
+}
+
+type FocusItem struct {
+	TaskID   string `json:"task_id"`
+	Title    string `json:"title"`
+	Project  string `json:"project"`
+	Kind     string `json:"kind"`
+	Reason   string `json:"reason"`
+	DueDate  string `json:"due_date,omitempty"`
+	Blocking int    `json:"blocking,omitempty"`
+	URL      string `json:"url"`
+}
+
+type FocusResponse struct {
+	Date       string      `json:"date"`
+	Items      []FocusItem `json:"items"`
+	OpenTasks  int         `json:"open_tasks"`
+	Overdue    int         `json:"overdue"`
+	DueToday   int         `json:"due_today"`
+	IsBlocking int         `json:"is_blocking"`
+}
+
+var focusKindRank = map[string]int{
+	"overdue":   0,
+	"blocking":  1,
+	"due_today": 2,
+	"priority":  3,
+}
+
+func (h *FocusHandler) Handle(ctx context.Context, params map[string]interface{}, userID string) (*models.MCPResponse, error) {
+	var req FocusRequest
+	req.Limit = 5
+
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal params: %w", err)
+		}
+		if err := json.Unmarshal(data, &req); err != nil {
+			return nil, fmt.Errorf("failed to parse focus request: %w", err)
+		}
+	}
+
+	if req.Limit <= 0 {
+		req.Limit = 5
+	}
+
+	client, err := authenticatedClient(h.authManager, h.config, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	me, err := client.GetMe(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user info: %w", err)
+	}
+
+	tasksData, err := NewTaskService(h.authManager, h.config).Load(ctx, userID, TasksRequest{
+		ProjectIDs:     req.ProjectIDs,
+		AssigneeIDs:    []string{strconv.Itoa(me.ID)},
+		StatusFilter:   "active",
+		IncludeOverdue: true,
+		SortBy:         "due_date",
+		Limit:          200,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks data: %w", err)
+	}
+	location := tasksData.Location
+
+	taskIDs := make([]int, 0, len(tasksData.Tasks))
+	for _, task := range tasksData.Tasks {
+		if id, err := strconv.Atoi(task.ID); err == nil {
+			taskIDs = append(taskIDs, id)
+		}
+	}
+
+	links, err := client.GetTaskLinks(ctx, taskIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task links: %w", err)
+	}
+
+	response := h.buildFocusList(tasksData.Tasks, links, req.Limit, location)
+
+	responseJSON, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal focus response: %w", err)
+	}
+
+	return &models.MCPResponse{
+		Content: []models.MCPContent{
+			{
+				Type: "text",
+				Text: string(responseJSON),
+			},
+		},
+	}, nil
+}
+
+// buildFocusList picks at most limit tasks: overdue first, then tasks that
+// block other open tasks, then tasks due today, topped up with the highest
+// urgency scores from the priorities analysis.
+func (h *FocusHandler) buildFocusList(tasks []TaskDetail, links map[int][]models.TaskLink, limit int, location *time.Location) FocusResponse {
+	now := time.Now().In(location)
+	priorities := NewPrioritiesHandler(h.authManager, h.config)
+	priorities.location = location
+	timeLimit := priorities.horizonLimit("today", now)
+
+	response := FocusResponse{
+		Date:      now.Format("2006-01-02"),
+		Items:     []FocusItem{},
+		OpenTasks: len(tasks),
+	}
+
+	type candidate struct {
+		item  FocusItem
+		score int
+	}
+	var candidates []candidate
+
+	for _, task := range tasks {
+		item := FocusItem{
+			TaskID:  task.ID,
+			Title:   task.Title,
+			Project: task.Project.Name,
+			DueDate: task.Dates.Due,
+			URL:     task.URL,
+		}
+
+		taskID, _ := strconv.Atoi(task.ID)
+		var blocked []string
+		for _, link := range links[taskID] {
+			if strings.EqualFold(link.Label, "blocks") && bool(link.IsActive) {
+				blocked = append(blocked, fmt.Sprintf("#%d", link.OppositeTaskID))
+			}
+		}
+		item.Blocking = len(blocked)
+
+		dueToday := false
+		if dueDate, err := time.Parse(time.RFC3339, task.Dates.Due); err == nil && !task.IsOverdue {
+			dueToday = h.config.Calendar.WorkDaysUntil(now, dueDate) == 0
+		}
+
+		switch {
+		case task.IsOverdue:
+			item.Kind = "overdue"
+			item.Reason = priorities.getUrgencyReason(task, now)
+			response.Overdue++
+		case len(blocked) > 0:
+			item.Kind = "blocking"
+			item.Reason = fmt.Sprintf("Blocks %s", strings.Join(blocked, ", "))
+		case dueToday:
+			item.Kind = "due_today"
+			item.Reason = "Due today"
+		default:
+			item.Kind = "priority"
+			item.Reason = priorities.getUrgencyReason(task, now)
+		}
+
+		if len(blocked) > 0 {
+			response.IsBlocking++
+			if item.Kind != "blocking" {
+				item.Reason = fmt.Sprintf("%s; blocks %s", item.Reason, strings.Join(blocked, ", "))
+			}
+		}
+		if dueToday {
+			response.DueToday++
+		}
+
+		candidates = append(candidates, candidate{
+			item:  item,
+			score: priorities.calculateUrgencyScore(task, now, timeLimit) + 10*len(blocked),
+		})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		ri, rj := focusKindRank[candidates[i].item.Kind], focusKindRank[candidates[j].item.Kind]
+		if ri != rj {
+			return ri < rj
+		}
+		return candidates[i].score > candidates[j].score
+	})
+
+	for _, c := range candidates {
+		if len(response.Items) >= limit {
+			break
+		}
+		response.Items = append(response.Items, c.item)
+	}
+
+	return response
+}
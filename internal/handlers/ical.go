@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/auth"
+	"github.com/tech-arch1tect/kan-mcp/internal/logging"
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+)
+
+// CalendarFeed renders a user's open tasks as an iCalendar (RFC 5545) feed
+// that calendar applications can subscribe to.
+type CalendarFeed struct {
+	authManager *auth.AuthManager
+	config      *models.UserConfig
+}
+
+func NewCalendarFeed(authManager *auth.AuthManager, config *models.UserConfig) *CalendarFeed {
+	return &CalendarFeed{
+		authManager: authManager,
+		config:      config,
+	}
+}
+
+// Render returns an event on the due date of every open task assigned to the
+// user and, with includeStart, another on its start date. Dates at midnight
+// in the user's timezone become all-day events, others zero-length events at
+// that time.
+func (f *CalendarFeed) Render(ctx context.Context, userID string, includeStart bool, now time.Time) ([]byte, error) {
+	client, err := authenticatedClient(f.authManager, f.config, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	me, err := client.GetMe(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user info: %w", err)
+	}
+
+	tasksData, err := NewTaskService(f.authManager, f.config).Load(ctx, userID, TasksRequest{
+		AssigneeIDs:    []string{strconv.Itoa(me.ID)},
+		StatusFilter:   "active",
+		IncludeOverdue: true,
+		SortBy:         "due_date",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks data: %w", err)
+	}
+	for _, warning := range projectWarningMessages(tasksData.Warnings) {
+		logging.Warnf("Calendar feed for user %s: %s", logging.UserID(userID), warning)
+	}
+
+	host := "kan-mcp"
+	if u, err := url.Parse(client.BaseURL()); err == nil && u.Host != "" {
+		host = u.Host
+	}
+
+	name := me.Name
+	if name == "" {
+		name = me.Username
+	}
+
+	var ics icalWriter
+	ics.line("BEGIN", "VCALENDAR")
+	ics.line("VERSION", "2.0")
+	ics.line("PRODID", "-//kan-mcp//Kanboard tasks//EN")
+	ics.line("CALSCALE", "GREGORIAN")
+	ics.line("METHOD", "PUBLISH")
+	ics.line("X-WR-CALNAME", icalText("Kanboard tasks: "+name))
+	ics.line("X-WR-TIMEZONE", tasksData.Location.String())
+
+	stamp := now.UTC().Format("20060102T150405Z")
+	for _, task := range tasksData.Tasks {
+		ics.event(task, "due", "Due: ", task.Dates.Due, host, stamp, tasksData.Location)
+		if includeStart {
+			ics.event(task, "start", "Start: ", task.Dates.Started, host, stamp, tasksData.Location)
+		}
+	}
+
+	ics.line("END", "VCALENDAR")
+	return ics.buf.Bytes(), nil
+}
+
+type icalWriter struct {
+	buf bytes.Buffer
+}
+
+// event writes a VEVENT for one of task's dates, given in RFC 3339. Tasks
+// without the date are skipped.
+func (w *icalWriter) event(task TaskDetail, kind, prefix, date, host, stamp string, location *time.Location) {
+	at, err := time.Parse(time.RFC3339, date)
+	if err != nil {
+		return
+	}
+	at = at.In(location)
+
+	w.line("BEGIN", "VEVENT")
+	w.line("UID", "task-"+task.ID+"-"+kind+"@"+host)
+	w.line("DTSTAMP", stamp)
+	if at.Equal(startOfDay(at)) {
+		w.line("DTSTART;VALUE=DATE", at.Format("20060102"))
+		w.line("DTEND;VALUE=DATE", at.AddDate(0, 0, 1).Format("20060102"))
+	} else {
+		w.line("DTSTART", at.UTC().Format("20060102T150405Z"))
+	}
+	if modified, err := time.Parse(time.RFC3339, task.Dates.Modified); err == nil {
+		w.line("LAST-MODIFIED", modified.UTC().Format("20060102T150405Z"))
+	}
+	w.line("SUMMARY", icalText(prefix+task.Title))
+
+	description := "Project: " + task.Project.Name
+	if task.Status.Column != "" {
+		description += "\nColumn: " + task.Status.Column
+	}
+	if task.URL != "" {
+		description += "\n" + task.URL
+		w.line("URL", task.URL)
+	}
+	w.line("DESCRIPTION", icalText(description))
+	w.line("CATEGORIES", icalText(task.Project.Name))
+	w.line("END", "VEVENT")
+}
+
+// line writes a content line, folded so no line exceeds 75 octets.
+func (w *icalWriter) line(name, value string) {
+	content := name + ":" + value
+	limit := 75
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		w.buf.WriteString(content[:cut])
+		w.buf.WriteString("\r\n ")
+		content = content[cut:]
+		limit = 74
+	}
+	w.buf.WriteString(content)
+	w.buf.WriteString("\r\n")
+}
+
+var icalTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", "")
+
+// icalText escapes a TEXT property value.
+func icalText(s string) string {
+	return icalTextEscaper.Replace(s)
+}
@@ -0,0 +1,320 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// syntheticKanboard answers Kanboard JSON-RPC calls for a generated set of
+// projects. Every response is encoded up front so the server's own
+// allocations barely register next to those of the handlers under test.
+type syntheticKanboard struct {
+	static   map[string]json.RawMessage
+	projects map[int]*syntheticProject
+}
+
+type syntheticProject struct {
+	results map[string]json.RawMessage
+	// closed holds the project's closed tasks ordered by modification time,
+	// for answering date-windowed searchTasks calls.
+	closed []closedTask
+}
+
+type closedTask struct {
+	modified int64
+	raw      json.RawMessage
+}
+
+type syntheticTask struct {
+	ID               int     `json:"id"`
+	Title            string  `json:"title"`
+	Description      string  `json:"description"`
+	ProjectID        int     `json:"project_id"`
+	ColumnID         int     `json:"column_id"`
+	SwimlaneID       int     `json:"swimlane_id"`
+	OwnerID          int     `json:"owner_id"`
+	CreatorID        int     `json:"creator_id"`
+	CategoryID       int     `json:"category_id"`
+	Priority         int     `json:"priority"`
+	Score            int     `json:"score"`
+	Position         int     `json:"position"`
+	IsActive         int     `json:"is_active"`
+	ColorID          string  `json:"color_id"`
+	DateCreation     int64   `json:"date_creation"`
+	DateModification int64   `json:"date_modification"`
+	DateCompleted    int64   `json:"date_completed"`
+	DateDue          int64   `json:"date_due"`
+	DateMoved        int64   `json:"date_moved"`
+	DateStarted      int64   `json:"date_started"`
+	TimeEstimated    float64 `json:"time_estimated"`
+	TimeSpent        float64 `json:"time_spent"`
+}
+
+var syntheticColumns = []struct {
+	title string
+	limit int
+}{
+	{"Backlog", 0},
+	{"Ready", 0},
+	{"Work in progress", 5},
+	{"Done", 0},
+}
+
+const syntheticUsers = 8
+
+// newSyntheticKanboard generates projects with tasks spread evenly between
+// them. A quarter of the tasks are closed; all were created within the last
+// year. The same arguments always produce the same instance.
+func newSyntheticKanboard(projects, tasks int) (*syntheticKanboard, error) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	now := time.Now().Unix()
+	day := int64(24 * time.Hour / time.Second)
+
+	kb := &syntheticKanboard{
+		static:   make(map[string]json.RawMessage),
+		projects: make(map[int]*syntheticProject),
+	}
+
+	usernames := make(map[string]string, syntheticUsers)
+	for i := 1; i <= syntheticUsers; i++ {
+		usernames[strconv.Itoa(i)] = fmt.Sprintf("user%d", i)
+	}
+
+	var projectList []map[string]interface{}
+	taskID := 0
+	for p := 1; p <= projects; p++ {
+		project := map[string]interface{}{
+			"id":               p,
+			"name":             fmt.Sprintf("Project %d", p),
+			"is_active":        1,
+			"priority_start":   0,
+			"priority_end":     3,
+			"priority_default": 0,
+			"last_modified":    now - rng.Int64N(30*day),
+		}
+		projectList = append(projectList, project)
+
+		var columns []map[string]interface{}
+		for i, column := range syntheticColumns {
+			columns = append(columns, map[string]interface{}{
+				"id": p*10 + i + 1, "title": column.title, "position": i + 1, "task_limit": column.limit, "project_id": p,
+			})
+		}
+		swimlanes := []map[string]interface{}{
+			{"id": p*10 + 1, "name": "Default swimlane", "position": 1, "is_active": 1, "project_id": p},
+			{"id": p*10 + 2, "name": "Expedite", "position": 2, "is_active": 1, "project_id": p},
+		}
+		categories := []map[string]interface{}{
+			{"id": p*10 + 1, "name": "Bug", "project_id": p},
+			{"id": p*10 + 2, "name": "Feature", "project_id": p},
+		}
+
+		var open, closed []syntheticTask
+		count := tasks / projects
+		if p <= tasks%projects {
+			count++
+		}
+		for i := 0; i < count; i++ {
+			taskID++
+			created := now - rng.Int64N(365*day)
+			task := syntheticTask{
+				ID:            taskID,
+				Title:         fmt.Sprintf("Task %d: %s", taskID, strings.Repeat("work ", 1+rng.IntN(8))),
+				Description:   strings.Repeat("Details of the work to be done. ", 5+rng.IntN(20)),
+				ProjectID:     p,
+				SwimlaneID:    p*10 + 1 + rng.IntN(2),
+				OwnerID:       rng.IntN(syntheticUsers + 1),
+				CreatorID:     1 + rng.IntN(syntheticUsers),
+				CategoryID:    rng.IntN(3) * (p*10 + 1),
+				Priority:      rng.IntN(4),
+				Score:         rng.IntN(8),
+				Position:      i + 1,
+				ColorID:       "yellow",
+				DateCreation:  created,
+				DateMoved:     created + rng.Int64N(now-created+1),
+				TimeEstimated: float64(rng.IntN(16)),
+				TimeSpent:     float64(rng.IntN(16)),
+			}
+			if rng.IntN(3) > 0 {
+				task.DateDue = created + rng.Int64N(60*day)
+			}
+			if rng.IntN(2) == 0 {
+				task.DateStarted = created + rng.Int64N(now-created+1)
+			}
+
+			if rng.IntN(4) == 0 {
+				task.ColumnID = p*10 + len(syntheticColumns)
+				task.DateCompleted = created + rng.Int64N(now-created+1)
+				task.DateModification = task.DateCompleted
+				closed = append(closed, task)
+			} else {
+				task.IsActive = 1
+				task.ColumnID = p*10 + 1 + rng.IntN(len(syntheticColumns)-1)
+				task.DateModification = task.DateMoved
+				open = append(open, task)
+			}
+		}
+
+		sp := &syntheticProject{results: make(map[string]json.RawMessage)}
+		for method, result := range map[string]interface{}{
+			"getProjectById":   project,
+			"getColumns":       columns,
+			"getAllSwimlanes":  swimlanes,
+			"getProjectUsers":  usernames,
+			"getAllCategories": categories,
+			"getTagsByProject": []interface{}{},
+			"openTasks":        open,
+			"closedTasks":      closed,
+		} {
+			raw, err := json.Marshal(result)
+			if err != nil {
+				return nil, err
+			}
+			sp.results[method] = raw
+		}
+
+		sort.Slice(closed, func(i, j int) bool { return closed[i].DateModification < closed[j].DateModification })
+		for _, task := range closed {
+			raw, err := json.Marshal(task)
+			if err != nil {
+				return nil, err
+			}
+			sp.closed = append(sp.closed, closedTask{modified: task.DateModification, raw: raw})
+		}
+
+		kb.projects[p] = sp
+	}
+
+	for method, result := range map[string]interface{}{
+		"getMe":              map[string]interface{}{"id": 1, "username": "user1", "name": "Benchmark User", "role": "app-user"},
+		"getMyProjects":      projectList,
+		"getVersion":         "1.2.40",
+		"getProjectUserRole": "project-member",
+		"getAllSubtasks":     []interface{}{},
+		"getAllComments":     []interface{}{},
+		"getAllTaskLinks":    []interface{}{},
+	} {
+		raw, err := json.Marshal(result)
+		if err != nil {
+			return nil, err
+		}
+		kb.static[method] = raw
+	}
+
+	return kb, nil
+}
+
+type rpcRequest struct {
+	ID     int    `json:"id"`
+	Method string `json:"method"`
+	Params struct {
+		ProjectID int    `json:"project_id"`
+		StatusID  int    `json:"status_id"`
+		Query     string `json:"query"`
+	} `json:"params"`
+}
+
+func (kb *syntheticKanboard) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var requests []rpcRequest
+	batch := len(body) > 0 && body[0] == '['
+	if batch {
+		err = json.Unmarshal(body, &requests)
+	} else {
+		requests = make([]rpcRequest, 1)
+		err = json.Unmarshal(body, &requests[0])
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var out bytes.Buffer
+	if batch {
+		out.WriteByte('[')
+	}
+	for i, req := range requests {
+		if i > 0 {
+			out.WriteByte(',')
+		}
+		fmt.Fprintf(&out, `{"jsonrpc":"2.0","id":%d,"result":`, req.ID)
+		kb.writeResult(&out, req)
+		out.WriteByte('}')
+	}
+	if batch {
+		out.WriteByte(']')
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(out.Bytes())
+}
+
+var modifiedFilter = regexp.MustCompile(`modified:(>=|<)(\d{4}-\d{2}-\d{2})`)
+
+func (kb *syntheticKanboard) writeResult(out *bytes.Buffer, req rpcRequest) {
+	if raw, ok := kb.static[req.Method]; ok {
+		out.Write(raw)
+		return
+	}
+
+	project, ok := kb.projects[req.Params.ProjectID]
+	if !ok {
+		out.WriteString("[]")
+		return
+	}
+
+	switch req.Method {
+	case "getAllTasks":
+		if req.Params.StatusID == 0 {
+			out.Write(project.results["closedTasks"])
+		} else {
+			out.Write(project.results["openTasks"])
+		}
+	case "searchTasks":
+		from, to := int64(0), int64(1<<62)
+		for _, match := range modifiedFilter.FindAllStringSubmatch(req.Params.Query, -1) {
+			date, err := time.Parse("2006-01-02", match[2])
+			if err != nil {
+				continue
+			}
+			if match[1] == ">=" {
+				from = date.Unix()
+			} else {
+				to = date.Unix()
+			}
+		}
+		out.WriteByte('[')
+		first := true
+		for _, task := range project.closed {
+			if task.modified < from || task.modified >= to {
+				continue
+			}
+			if !first {
+				out.WriteByte(',')
+			}
+			first = false
+			out.Write(task.raw)
+		}
+		out.WriteByte(']')
+	default:
+		if raw, ok := project.results[req.Method]; ok {
+			out.Write(raw)
+		} else {
+			out.WriteString("[]")
+		}
+	}
+}
@@ -0,0 +1,327 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/api"
+	"github.com/tech-arch1tect/kan-mcp/internal/auth"
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+)
+
+type MemberRequest struct {
+	ProjectID int    `json:"project_id"`
+	Action    string `json:"action"`
+	User      string `json:"user"`
+	Role      string `json:"role"`
+}
+
+type ProjectMember struct {
+	UserInfo
+	Role string `json:"role"`
+}
+
+type MemberResponse struct {
+	ProjectID int             `json:"project_id"`
+	Change    string          `json:"change"`
+	UserID    int             `json:"user_id"`
+	Members   []ProjectMember `json:"members"`
+}
+
+// MembershipHandler adds users to a project, removes them and changes their
+// project role.
+type MembershipHandler struct {
+	authManager *auth.AuthManager
+	config      *models.UserConfig
+}
+
+func NewMembershipHandler(authManager *auth.AuthManager, config *models.UserConfig) *MembershipHandler {
+	return &MembershipHandler{
+		authManager: authManager,
+		config:      config,
+	}
+}
+
+func (h *MembershipHandler) Preview(ctx context.Context, params map[string]interface{}, userID string) (string, error) {
+	return preview(ctx, h, params, userID)
+}
+
+func (h *MembershipHandler) Handle(ctx context.Context, params map[string]interface{}, userID string) (*models.MCPResponse, error) {
+	return handle(ctx, h, params, userID)
+}
+
+// plan validates the request against the project's current members. The
+// change's Apply fills in its Response, including the members as
+// they are afterwards.
+func (h *MembershipHandler) plan(ctx context.Context, params map[string]interface{}, userID string) (*change, error) {
+	var req MemberRequest
+	if err := parseWriteRequest(params, &req, "membership"); err != nil {
+		return nil, err
+	}
+	if req.ProjectID <= 0 {
+		return nil, fmt.Errorf("project_id is required")
+	}
+	switch req.Action {
+	case "add", "remove", "set_role":
+	default:
+		return nil, fmt.Errorf("invalid action %q: must be add, remove or set_role", req.Action)
+	}
+	if strings.TrimSpace(req.User) == "" {
+		return nil, fmt.Errorf("user is required")
+	}
+	role, err := parseProjectRole(req.Role)
+	if err != nil {
+		return nil, err
+	}
+	req.Role = role
+
+	client, err := authenticatedClient(h.authManager, h.config, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkProjectAccess(ctx, client, req.ProjectID, true); err != nil {
+		return nil, err
+	}
+
+	members, err := h.members(ctx, client, req.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	project := projectLabel(ctx, client, req.ProjectID)
+	response := &MemberResponse{ProjectID: req.ProjectID}
+
+	apply, err := h.action(ctx, client, req, members, project, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &change{
+		Description: response.Change,
+		Response:    response,
+		Apply: func(ctx context.Context) error {
+			if err := apply(ctx); err != nil {
+				return fmt.Errorf("failed to %s project member: %w", strings.ReplaceAll(req.Action, "_", " "), err)
+			}
+			members, err := h.members(ctx, client, req.ProjectID)
+			if err != nil {
+				return fmt.Errorf("members changed but could not be read back: %w", err)
+			}
+			response.Members = members
+			return nil
+		},
+	}, nil
+}
+
+// action checks req against members, sets response's Change and UserID, and
+// returns the call that makes the change.
+func (h *MembershipHandler) action(ctx context.Context, client *api.Client, req MemberRequest, members []ProjectMember, project string, response *MemberResponse) (func(context.Context) error, error) {
+	member, found := findMember(members, req.User)
+
+	if req.Action == "add" {
+		if found {
+			return nil, fmt.Errorf("%s is already a member of %s with the role %s; use set_role to change it", memberLabel(member), project, member.Role)
+		}
+		user, verified, err := h.lookupUser(ctx, client, req.User)
+		if err != nil {
+			return nil, err
+		}
+		role := req.Role
+		if role == "" {
+			role = "project-member"
+		}
+		response.UserID, _ = strconv.Atoi(user.ID)
+		response.Change = fmt.Sprintf("Add %s to %s as %s", memberLabel(user), project, role)
+		if !verified {
+			response.Change += fmt.Sprintf("; these credentials cannot look Kanboard users up, so whether user %s exists is only known when Kanboard makes the change", user.ID)
+		}
+		return func(ctx context.Context) error {
+			err := client.AddProjectUser(ctx, req.ProjectID, response.UserID, role)
+			if !verified && errors.Is(err, api.ErrRejected) {
+				return fmt.Errorf("user %s was rejected by Kanboard; check that a user with that ID exists: %w", user.ID, err)
+			}
+			return err
+		}, nil
+	}
+
+	if !found {
+		return nil, fmt.Errorf("no member %q in project: members are %s", req.User, memberNames(members))
+	}
+	response.UserID, _ = strconv.Atoi(member.ID)
+	label := memberLabel(member)
+
+	// A project without a manager can only be managed by Kanboard
+	// administrators, so the last manager has to stay one.
+	lastManager := member.Role == "project-manager" && countRole(members, "project-manager") == 1
+
+	switch req.Action {
+	case "remove":
+		if lastManager {
+			return nil, fmt.Errorf("%s is the only manager of %s and cannot be removed; make another member a manager first", label, project)
+		}
+		response.Change = fmt.Sprintf("Remove %s from %s", label, project)
+		assigned, err := openTasksAssignedTo(ctx, client, req.ProjectID, response.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if assigned == 1 {
+			response.Change += "; 1 open task is still assigned to them"
+		} else if assigned > 1 {
+			response.Change += fmt.Sprintf("; %d open tasks are still assigned to them", assigned)
+		}
+		return func(ctx context.Context) error {
+			return client.RemoveProjectUser(ctx, req.ProjectID, response.UserID)
+		}, nil
+
+	case "set_role":
+		if req.Role == "" {
+			return nil, fmt.Errorf("set_role needs role: manager, member or viewer")
+		}
+		if req.Role == member.Role {
+			return nil, fmt.Errorf("%s already has the role %s in %s", label, member.Role, project)
+		}
+		if lastManager {
+			return nil, fmt.Errorf("%s is the only manager of %s and cannot be given another role; make another member a manager first", label, project)
+		}
+		response.Change = fmt.Sprintf("Change the role of %s in %s from %s to %s", label, project, member.Role, req.Role)
+		return func(ctx context.Context) error {
+			return client.ChangeProjectUserRole(ctx, req.ProjectID, response.UserID, req.Role)
+		}, nil
+	}
+
+	return nil, fmt.Errorf("invalid action %q", req.Action)
+}
+
+// members returns the project's members with their roles, sorted by name.
+// Names are pseudonymised when the operator has asked for it.
+func (h *MembershipHandler) members(ctx context.Context, client *api.Client, projectID int) ([]ProjectMember, error) {
+	users, err := client.GetProjectUsers(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project members: %w", err)
+	}
+
+	ids := make([]int, len(users))
+	for i, user := range users {
+		ids[i] = user.ID
+	}
+	roles, err := client.GetProjectMemberRoles(ctx, projectID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get member roles: %w", err)
+	}
+
+	members := make([]ProjectMember, len(users))
+	for i, user := range users {
+		id := strconv.Itoa(user.ID)
+		username, name := displayUser(h.config, client, id, user.Username, user.Name)
+		members[i] = ProjectMember{
+			UserInfo: UserInfo{ID: id, Username: username, Name: name},
+			Role:     roles[user.ID],
+		}
+	}
+	sort.SliceStable(members, func(i, j int) bool {
+		return strings.ToLower(members[i].Name) < strings.ToLower(members[j].Name)
+	})
+	return members, nil
+}
+
+// lookupUser finds a user who is not yet a member by ID or username. Kanboard
+// only lets administrators look users up, so with a personal token a user can
+// only be added by ID, and verified is false because the ID could not be
+// checked.
+func (h *MembershipHandler) lookupUser(ctx context.Context, client *api.Client, user string) (member ProjectMember, verified bool, err error) {
+	user = strings.TrimSpace(user)
+	var found *models.KanboardUser
+	id, idErr := strconv.Atoi(user)
+	if idErr == nil {
+		found, err = client.GetUser(ctx, id)
+	} else {
+		found, err = client.GetUserByName(ctx, user)
+	}
+
+	switch {
+	case err == nil:
+	case errors.Is(err, api.ErrUnauthorized) && idErr == nil && id > 0:
+		return ProjectMember{UserInfo: UserInfo{ID: strconv.Itoa(id)}}, false, nil
+	case errors.Is(err, api.ErrUnauthorized):
+		return ProjectMember{}, false, fmt.Errorf("these credentials cannot look Kanboard users up by name; give the numeric ID of user %q instead", user)
+	default:
+		return ProjectMember{}, false, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	foundID := strconv.Itoa(found.ID)
+	username, name := displayUser(h.config, client, foundID, found.Username, found.Name)
+	return ProjectMember{UserInfo: UserInfo{ID: foundID, Username: username, Name: name}}, true, nil
+}
+
+// parseProjectRole accepts Kanboard's project roles with or without their
+// "project-" prefix. An empty role stays empty.
+func parseProjectRole(role string) (string, error) {
+	role = strings.ToLower(strings.TrimSpace(role))
+	switch strings.TrimPrefix(role, "project-") {
+	case "":
+		return "", nil
+	case "manager", "member", "viewer":
+		return "project-" + strings.TrimPrefix(role, "project-"), nil
+	}
+	return "", fmt.Errorf("invalid role %q: must be manager, member or viewer", role)
+}
+
+// findMember looks a member up by ID, username or the name shown for them.
+func findMember(members []ProjectMember, user string) (ProjectMember, bool) {
+	for _, m := range members {
+		id, _ := strconv.Atoi(m.ID)
+		if matchesNameOrID(user, id, m.Username) || matchesNameOrID(user, id, m.Name) {
+			return m, true
+		}
+	}
+	return ProjectMember{}, false
+}
+
+func memberLabel(member ProjectMember) string {
+	name := member.Name
+	if name == "" {
+		name = member.Username
+	}
+	if name == "" {
+		return fmt.Sprintf("user %s", member.ID)
+	}
+	return fmt.Sprintf("user %q (ID %s)", name, member.ID)
+}
+
+func memberNames(members []ProjectMember) string {
+	if len(members) == 0 {
+		return "none"
+	}
+	names := make([]string, len(members))
+	for i, m := range members {
+		names[i] = m.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+func countRole(members []ProjectMember, role string) int {
+	count := 0
+	for _, m := range members {
+		if m.Role == role {
+			count++
+		}
+	}
+	return count
+}
+
+func openTasksAssignedTo(ctx context.Context, client *api.Client, projectID, userID int) (int, error) {
+	tasks, err := client.GetTasksByStatus(ctx, projectID, api.TaskStatusOpen)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get tasks: %w", err)
+	}
+	count := 0
+	for _, task := range tasks {
+		if task.OwnerID == userID {
+			count++
+		}
+	}
+	return count, nil
+}
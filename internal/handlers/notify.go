@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/auth"
+	"github.com/tech-arch1tect/kan-mcp/internal/logging"
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+	"github.com/tech-arch1tect/kan-mcp/internal/templates"
+)
+
+// notifyRetryDelay is how long a user whose alerts could not be delivered
+// waits before they are tried again.
+const notifyRetryDelay = time.Hour
+
+// Alert lists a user's open tasks that are overdue or due today.
+type Alert struct {
+	Date          string       `json:"date"`
+	Timezone      string       `json:"timezone"`
+	OverdueCount  int          `json:"overdue_count"`
+	DueTodayCount int          `json:"due_today_count"`
+	Overdue       []DigestItem `json:"overdue"`
+	DueToday      []DigestItem `json:"due_today"`
+	Warnings      []string     `json:"warnings,omitempty"`
+}
+
+// Mailer sends a plain-text email.
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// NotificationSchedule says when alerts are sent: every day at Hour:Minute in
+// the default timezone. Webhook posts are signed with WebhookSecret when it
+// is set.
+type NotificationSchedule struct {
+	Hour          int
+	Minute        int
+	WebhookSecret string
+}
+
+// Notifier sends each opted-in user their overdue and due-today tasks once a
+// day, to the webhook and email address in their notification settings.
+type Notifier struct {
+	authManager *auth.AuthManager
+	config      *models.UserConfig
+	mailer      Mailer
+	schedule    NotificationSchedule
+	httpClient  *http.Client
+	// attempted records when a user whose alerts failed was last tried.
+	attempted map[string]time.Time
+}
+
+// NewNotifier creates a notifier. mailer may be nil, in which case alerts
+// are only posted to webhooks.
+func NewNotifier(authManager *auth.AuthManager, config *models.UserConfig, mailer Mailer, schedule NotificationSchedule) *Notifier {
+	return &Notifier{
+		authManager: authManager,
+		config:      config,
+		mailer:      mailer,
+		schedule:    schedule,
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		attempted:   make(map[string]time.Time),
+	}
+}
+
+// Run sends the alerts that are due straight away and then checks once a
+// minute until ctx is done.
+func (n *Notifier) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		n.RunDue(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDue alerts each opted-in user who has not been alerted since the last
+// scheduled time, so a run missed while the server was down still happens
+// that day. Users with nothing overdue or due today get no alert. Failures
+// are logged and retried after notifyRetryDelay.
+func (n *Notifier) RunDue(ctx context.Context, now time.Time) {
+	users, err := n.authManager.ListUsers()
+	if err != nil {
+		logging.Warnf("Notifications: failed to list users: %v", err)
+		return
+	}
+
+	due := n.lastDue(now)
+	for _, user := range users {
+		if ctx.Err() != nil {
+			return
+		}
+		if user.Notifications == nil || !user.Notifications.LastSent.Before(due) {
+			continue
+		}
+		if expiry := n.authManager.CredentialExpiry(user); !expiry.IsZero() && now.After(expiry) {
+			continue
+		}
+		if last, ok := n.attempted[user.UserID]; ok && now.Sub(last) < notifyRetryDelay {
+			continue
+		}
+
+		if err := n.notify(ctx, user, now); err != nil {
+			logging.Warnf("Notifications: alerts for user %s failed: %v", logging.UserID(user.UserID), err)
+			n.attempted[user.UserID] = now
+			continue
+		}
+		delete(n.attempted, user.UserID)
+
+		if err := n.authManager.MarkNotified(user.UserID, now); err != nil {
+			logging.Warnf("Notifications: failed to record alerts for user %s: %v", logging.UserID(user.UserID), err)
+		}
+	}
+}
+
+// lastDue returns the most recent scheduled time at or before now.
+func (n *Notifier) lastDue(now time.Time) time.Time {
+	now = now.In(defaultLocation(n.config))
+	due := time.Date(now.Year(), now.Month(), now.Day(), n.schedule.Hour, n.schedule.Minute, 0, 0, now.Location())
+	if due.After(now) {
+		due = due.AddDate(0, 0, -1)
+	}
+	return due
+}
+
+// Alert collects the user's open tasks that are overdue or due today, in
+// their timezone.
+func (n *Notifier) Alert(ctx context.Context, userID string, now time.Time) (*Alert, error) {
+	client, err := authenticatedClient(n.authManager, n.config, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	me, err := client.GetMe(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user info: %w", err)
+	}
+
+	tasksData, err := NewTaskService(n.authManager, n.config).Load(ctx, userID, TasksRequest{
+		AssigneeIDs:    []string{strconv.Itoa(me.ID)},
+		StatusFilter:   "active",
+		IncludeOverdue: true,
+		SortBy:         "due_date",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks data: %w", err)
+	}
+
+	location := tasksData.Location
+	tomorrow := startOfDay(now.In(location)).AddDate(0, 0, 1)
+
+	alert := &Alert{
+		Date:     now.In(location).Format("2006-01-02"),
+		Timezone: location.String(),
+		Overdue:  []DigestItem{},
+		DueToday: []DigestItem{},
+		Warnings: projectWarningMessages(tasksData.Warnings),
+	}
+
+	for _, task := range tasksData.Tasks {
+		item := DigestItem{
+			TaskID:  task.ID,
+			Title:   task.Title,
+			Project: task.Project.Name,
+			DueDate: task.Dates.Due,
+			URL:     task.URL,
+		}
+
+		if task.IsOverdue {
+			alert.Overdue = append(alert.Overdue, item)
+			continue
+		}
+		if dueDate, err := time.Parse(time.RFC3339, task.Dates.Due); err == nil && dueDate.Before(tomorrow) {
+			alert.DueToday = append(alert.DueToday, item)
+		}
+	}
+
+	alert.OverdueCount, alert.DueTodayCount = len(alert.Overdue), len(alert.DueToday)
+	alert.Overdue = alert.Overdue[:min(len(alert.Overdue), maxDigestItems)]
+	alert.DueToday = alert.DueToday[:min(len(alert.DueToday), maxDigestItems)]
+
+	return alert, nil
+}
+
+// notify sends the user's alert to each of their destinations. It only fails
+// when no destination received it, so one broken destination does not cause
+// repeated alerts on the others.
+func (n *Notifier) notify(ctx context.Context, user *models.User, now time.Time) error {
+	alert, err := n.Alert(ctx, user.UserID, now)
+	if err != nil {
+		return err
+	}
+	if alert.OverdueCount == 0 && alert.DueTodayCount == 0 {
+		logging.Debugf("Notifications: nothing to alert user %s about", logging.UserID(user.UserID))
+		return nil
+	}
+
+	settings := user.Notifications
+	var errs []error
+	delivered := false
+
+	if settings.WebhookURL != "" {
+		if err := n.post(ctx, user, alert); err != nil {
+			errs = append(errs, fmt.Errorf("webhook: %w", err))
+		} else {
+			delivered = true
+		}
+	}
+
+	if settings.Email != "" {
+		if n.mailer == nil {
+			errs = append(errs, fmt.Errorf("email: SMTP is not configured"))
+		} else if err := n.email(ctx, settings.Email, alert); err != nil {
+			errs = append(errs, fmt.Errorf("email: %w", err))
+		} else {
+			delivered = true
+		}
+	}
+
+	if !delivered {
+		return errors.Join(errs...)
+	}
+	for _, err := range errs {
+		logging.Warnf("Notifications: alert for user %s not delivered by %v", logging.UserID(user.UserID), err)
+	}
+	logging.Debugf("Notifications: alerted user %s", logging.UserID(user.UserID))
+	return nil
+}
+
+// post sends an alert to the user's webhook. As with digests, the user ID is
+// left out since it is a credential.
+func (n *Notifier) post(ctx context.Context, user *models.User, alert *Alert) error {
+	kanboardURL := user.KanboardURL
+	if kanboardURL == "" {
+		kanboardURL = n.config.DefaultKanboardURL
+	}
+
+	body, err := json.Marshal(struct {
+		KanboardURL string `json:"kanboard_url"`
+		Username    string `json:"username"`
+		Alert       *Alert `json:"alert"`
+	}{kanboardURL, user.KanboardUsername, alert})
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	return postWebhook(ctx, n.httpClient, user.Notifications.WebhookURL, n.schedule.WebhookSecret, body)
+}
+
+// email sends an alert with its body rendered from the alert template.
+func (n *Notifier) email(ctx context.Context, to string, alert *Alert) error {
+	body, err := n.config.Templates.Render(templates.Alert, alert)
+	if err != nil {
+		return fmt.Errorf("failed to render alert: %w", err)
+	}
+	return n.mailer.Send(ctx, to, alertSubject(alert), body)
+}
+
+func alertSubject(alert *Alert) string {
+	var parts []string
+	if alert.OverdueCount > 0 {
+		parts = append(parts, fmt.Sprintf("%d overdue", alert.OverdueCount))
+	}
+	if alert.DueTodayCount > 0 {
+		parts = append(parts, fmt.Sprintf("%d due today", alert.DueTodayCount))
+	}
+	return "Kanboard tasks: " + strings.Join(parts, ", ")
+}
@@ -1,18 +1,30 @@
 package handlers
 
 import (
+	"cmp"
+	"context"
 	"encoding/json"
 	"fmt"
-	"sync"
+	"math"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/tech-arch1tect/kan-mcp/internal/api"
 	"github.com/tech-arch1tect/kan-mcp/internal/auth"
+	"github.com/tech-arch1tect/kan-mcp/internal/logging"
 	"github.com/tech-arch1tect/kan-mcp/internal/models"
+	"github.com/tech-arch1tect/kan-mcp/internal/tracing"
+	"github.com/tech-arch1tect/kan-mcp/internal/workpool"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type OverviewHandler struct {
 	authManager *auth.AuthManager
 	config      *models.UserConfig
+	location    *time.Location
 }
 
 func NewOverviewHandler(authManager *auth.AuthManager, config *models.UserConfig) *OverviewHandler {
@@ -23,20 +35,63 @@ func NewOverviewHandler(authManager *auth.AuthManager, config *models.UserConfig
 }
 
 type OverviewRequest struct {
-	IncludeTaskCounts       bool `json:"include_task_counts"`
-	IncludeInactiveProjects bool `json:"include_inactive_projects"`
+	IncludeTaskCounts       bool     `json:"include_task_counts"`
+	IncludeInactiveProjects bool     `json:"include_inactive_projects"`
+	Compact                 bool     `json:"compact"`
+	IncludeSwimlaneCounts   bool     `json:"include_swimlane_counts"`
+	ProjectIDs              []string `json:"project_ids"`
+	ProjectFilter           string   `json:"project_filter"`
+	ForceRefresh            bool     `json:"force_refresh"`
+	IncludeDueCounts        bool     `json:"include_due_counts"`
+	SortBy                  string   `json:"sort_by"`
+	Limit                   int      `json:"limit"`
+	Cursor                  string   `json:"cursor"`
+}
+
+// MaxOverviewProjects caps a single page of the overview's projects array.
+const MaxOverviewProjects = 100
+
+// overviewSortComparators order projects for each sort_by value; ties fall
+// back to name and then ID.
+var overviewSortComparators = map[string]func(a, b *ProjectOverview) int{
+	"name": func(a, b *ProjectOverview) int { return 0 },
+	"last_activity": func(a, b *ProjectOverview) int {
+		return compareActivity(b.LastActivity, a.LastActivity)
+	},
+	"overdue_count": func(a, b *ProjectOverview) int {
+		return cmp.Compare(derefInt(b.OverdueCount), derefInt(a.OverdueCount))
+	},
 }
 
 type ProjectOverview struct {
-	ID          string         `json:"id"`
-	Name        string         `json:"name"`
-	Description string         `json:"description"`
-	IsActive    bool           `json:"is_active"`
-	Owner       string         `json:"owner"`
-	Columns     []ColumnInfo   `json:"columns"`
-	Swimlanes   []SwimlaneInfo `json:"swimlanes"`
-	TaskCounts  map[string]int `json:"task_counts,omitempty"`
-	Users       []ProjectUser  `json:"users"`
+	ID                 string                    `json:"id"`
+	Name               string                    `json:"name"`
+	Identifier         string                    `json:"identifier,omitempty"`
+	Description        string                    `json:"description"`
+	IsActive           bool                      `json:"is_active"`
+	IsPublic           bool                      `json:"is_public"`
+	IsPrivate          bool                      `json:"is_private"`
+	StartDate          string                    `json:"start_date,omitempty"`
+	EndDate            string                    `json:"end_date,omitempty"`
+	LastActivity       string                    `json:"last_activity,omitempty"`
+	Owner              string                    `json:"owner"`
+	OverdueCount       *int                      `json:"overdue_count,omitempty"`
+	DueThisWeekCount   *int                      `json:"due_this_week_count,omitempty"`
+	MyRole             string                    `json:"my_role,omitempty"`
+	Permissions        *ProjectPermissions       `json:"my_permissions,omitempty"`
+	Columns            []ColumnInfo              `json:"columns"`
+	Swimlanes          []SwimlaneInfo            `json:"swimlanes"`
+	TaskCounts         map[string]int            `json:"task_counts,omitempty"`
+	SwimlaneTaskCounts map[string]map[string]int `json:"swimlane_task_counts,omitempty"`
+	Users              []ProjectUser             `json:"users"`
+}
+
+// ProjectPermissions summarises what the caller's project role allows, so
+// agents know whether write tools will succeed before suggesting them.
+type ProjectPermissions struct {
+	ViewTasks     bool `json:"view_tasks"`
+	EditTasks     bool `json:"edit_tasks"`
+	ManageProject bool `json:"manage_project"`
 }
 
 type ColumnInfo struct {
@@ -44,6 +99,11 @@ type ColumnInfo struct {
 	Title     string `json:"title"`
 	Position  int    `json:"position"`
 	TaskLimit int    `json:"task_limit"`
+
+	// OverLimit and UtilizationPercent are only set when task counts are
+	// requested; utilisation is omitted for columns without a WIP limit.
+	OverLimit          bool     `json:"over_limit"`
+	UtilizationPercent *float64 `json:"utilization_percent,omitempty"`
 }
 
 type SwimlaneInfo struct {
@@ -61,9 +121,37 @@ type ProjectUser struct {
 }
 
 type OverviewResponse struct {
-	Summary  OverviewSummary   `json:"summary"`
-	Projects []ProjectOverview `json:"projects"`
-	UserInfo UserInfo          `json:"user_info"`
+	Summary     OverviewSummary   `json:"summary"`
+	Projects    []ProjectOverview `json:"projects"`
+	UserInfo    UserInfo          `json:"user_info"`
+	NextCursor  string            `json:"next_cursor,omitempty"`
+	Warnings    []ProjectWarning  `json:"warnings,omitempty"`
+	GeneratedAt string            `json:"generated_at"`
+}
+
+// CompactOverviewResponse is returned in compact mode: just enough to name
+// each project and see where its work sits.
+type CompactOverviewResponse struct {
+	Summary     OverviewSummary  `json:"summary"`
+	Projects    []CompactProject `json:"projects"`
+	UserInfo    UserInfo         `json:"user_info"`
+	NextCursor  string           `json:"next_cursor,omitempty"`
+	Warnings    []ProjectWarning `json:"warnings,omitempty"`
+	GeneratedAt string           `json:"generated_at"`
+}
+
+type CompactProject struct {
+	ID                 string                    `json:"id"`
+	Name               string                    `json:"name"`
+	Columns            []CompactColumn           `json:"columns"`
+	SwimlaneTaskCounts map[string]map[string]int `json:"swimlane_task_counts,omitempty"`
+	OverdueCount       *int                      `json:"overdue_count,omitempty"`
+	DueThisWeekCount   *int                      `json:"due_this_week_count,omitempty"`
+}
+
+type CompactColumn struct {
+	Title string `json:"title"`
+	Tasks *int   `json:"tasks,omitempty"`
 }
 
 type OverviewSummary struct {
@@ -79,7 +167,7 @@ type UserInfo struct {
 	Name     string `json:"name"`
 }
 
-func (h *OverviewHandler) Handle(params map[string]interface{}, userID string) (*models.MCPResponse, error) {
+func (h *OverviewHandler) Handle(ctx context.Context, params map[string]interface{}, userID string) (*models.MCPResponse, error) {
 
 	var req OverviewRequest
 	req.IncludeTaskCounts = true
@@ -95,12 +183,39 @@ func (h *OverviewHandler) Handle(params map[string]interface{}, userID string) (
 		}
 	}
 
+	if req.SortBy == "" {
+		req.SortBy = "name"
+	}
+	if _, ok := overviewSortComparators[req.SortBy]; !ok {
+		return nil, fmt.Errorf("invalid sort_by %q: must be name, last_activity or overdue_count", req.SortBy)
+	}
+	if req.SortBy == "overdue_count" {
+		req.IncludeDueCounts = true
+	}
+
+	if req.Limit < 0 {
+		req.Limit = 0
+	}
+	if req.Limit > MaxOverviewProjects {
+		req.Limit = MaxOverviewProjects
+	}
+
+	queryKey := h.queryFingerprint(req)
+	offset := 0
+	if req.Cursor != "" {
+		decoded, err := decodeCursor(req.Cursor, queryKey)
+		if err != nil {
+			return nil, err
+		}
+		offset = decoded
+	}
+
 	user, err := h.authManager.AuthenticateUser(userID)
 	if err != nil {
 		return nil, fmt.Errorf("authentication failed: %w", err)
 	}
 
-	token, err := h.authManager.GetDecryptedToken(user)
+	token, err := kanboardToken(h.authManager, h.config, user)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decrypt token: %w", err)
 	}
@@ -110,14 +225,25 @@ func (h *OverviewHandler) Handle(params map[string]interface{}, userID string) (
 		kanboardURL = h.config.DefaultKanboardURL
 	}
 
-	client := api.NewClient(kanboardURL, user.KanboardUsername, token)
+	client := newKanboardClient(h.config, kanboardURL, user, token)
+
+	cacheKey := h.cacheKey(req)
+	if req.ForceRefresh {
+		client.InvalidateUserCache()
+	} else if cached, ok := client.CachedValue(cacheKey); ok {
+		if text, ok := cached.(string); ok {
+			return &models.MCPResponse{
+				Content: []models.MCPContent{{Type: "text", Text: text}},
+			}, nil
+		}
+	}
 
-	userInfo, err := h.getUserInfo(client)
+	userInfo, err := h.getUserInfo(ctx, client)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user info: %w", err)
 	}
 
-	projectsRaw, err := client.GetMyProjectsRaw()
+	projectsRaw, err := client.GetMyProjectsRaw(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get projects: %w", err)
 	}
@@ -127,7 +253,9 @@ func (h *OverviewHandler) Handle(params map[string]interface{}, userID string) (
 		return nil, fmt.Errorf("failed to parse projects: %w", err)
 	}
 
-	projectOverviews, err := h.buildProjectOverviews(client, rawProjects, req)
+	rawProjects = h.filterProjects(rawProjects, req)
+
+	projectOverviews, warnings, err := h.buildProjectOverviews(ctx, client, rawProjects, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build project overviews: %w", err)
 	}
@@ -144,10 +272,43 @@ func (h *OverviewHandler) Handle(params map[string]interface{}, userID string) (
 
 	summary := h.calculateSummary(projectOverviews, req.IncludeTaskCounts)
 
-	response := OverviewResponse{
-		Summary:  summary,
-		Projects: projectOverviews,
-		UserInfo: *userInfo,
+	h.sortProjects(projectOverviews, req.SortBy)
+
+	offset = min(offset, len(projectOverviews))
+	pageSize := len(projectOverviews) - offset
+	if req.Limit > 0 {
+		pageSize = min(pageSize, req.Limit)
+	}
+	projectOverviews = projectOverviews[offset : offset+pageSize]
+
+	var nextCursor string
+	if offset+pageSize < summary.TotalProjects {
+		nextCursor = encodeCursor(offset+pageSize, queryKey)
+	}
+
+	if err := h.applyMyRoles(ctx, client, projectOverviews, userInfo.ID); err != nil {
+		return nil, fmt.Errorf("failed to get project roles: %w", err)
+	}
+
+	generatedAt := time.Now().In(h.location).Format(time.RFC3339)
+
+	var response interface{} = OverviewResponse{
+		Summary:     summary,
+		Projects:    projectOverviews,
+		UserInfo:    *userInfo,
+		NextCursor:  nextCursor,
+		Warnings:    warnings,
+		GeneratedAt: generatedAt,
+	}
+	if req.Compact {
+		response = CompactOverviewResponse{
+			Summary:     summary,
+			Projects:    h.compactProjects(projectOverviews),
+			UserInfo:    *userInfo,
+			NextCursor:  nextCursor,
+			Warnings:    warnings,
+			GeneratedAt: generatedAt,
+		}
 	}
 
 	responseJSON, err := json.MarshalIndent(response, "", "  ")
@@ -155,6 +316,10 @@ func (h *OverviewHandler) Handle(params map[string]interface{}, userID string) (
 		return nil, fmt.Errorf("failed to marshal overview response: %w", err)
 	}
 
+	if len(warnings) == 0 {
+		client.StoreValue(cacheKey, string(responseJSON), h.config.OverviewCacheTTL)
+	}
+
 	return &models.MCPResponse{
 		Content: []models.MCPContent{
 			{
@@ -165,96 +330,358 @@ func (h *OverviewHandler) Handle(params map[string]interface{}, userID string) (
 	}, nil
 }
 
-func (h *OverviewHandler) getUserInfo(client *api.Client) (*UserInfo, error) {
-	userRaw, err := client.GetMe()
+// applyMyRoles records the caller's role in each project. Kanboard's built-in
+// roles are reported as manager, member or viewer; custom roles keep their
+// name and are treated like members.
+func (h *OverviewHandler) applyMyRoles(ctx context.Context, client *api.Client, projects []ProjectOverview, userID string) error {
+	id, err := strconv.Atoi(userID)
+	if err != nil || len(projects) == 0 {
+		return nil
+	}
+
+	projectIDs := make([]int, 0, len(projects))
+	for _, project := range projects {
+		if projectID, err := strconv.Atoi(project.ID); err == nil {
+			projectIDs = append(projectIDs, projectID)
+		}
+	}
+
+	roles, err := client.GetProjectUserRoles(ctx, projectIDs, id)
+	if err != nil {
+		return err
+	}
+
+	for i := range projects {
+		projectID, _ := strconv.Atoi(projects[i].ID)
+		role := roles[projectID]
+
+		switch role {
+		case "":
+			continue
+		case "project-manager":
+			projects[i].MyRole = "manager"
+			projects[i].Permissions = &ProjectPermissions{ViewTasks: true, EditTasks: true, ManageProject: true}
+		case "project-viewer":
+			projects[i].MyRole = "viewer"
+			projects[i].Permissions = &ProjectPermissions{ViewTasks: true}
+		case "project-member":
+			projects[i].MyRole = "member"
+			projects[i].Permissions = &ProjectPermissions{ViewTasks: true, EditTasks: true}
+		default:
+			projects[i].MyRole = role
+			projects[i].Permissions = &ProjectPermissions{ViewTasks: true, EditTasks: true}
+		}
+	}
+
+	return nil
+}
+
+// cacheKey identifies an overview request; force_refresh does not change
+// the result, so it is left out.
+func (h *OverviewHandler) cacheKey(req OverviewRequest) string {
+	req.ForceRefresh = false
+	data, _ := json.Marshal(req)
+	return "kanboard_overview:" + string(data)
+}
+
+// queryFingerprint identifies the projects and ordering of a request so a
+// cursor cannot be replayed against a different query.
+func (h *OverviewHandler) queryFingerprint(req OverviewRequest) string {
+	req.Cursor = ""
+	req.Limit = 0
+	req.ForceRefresh = false
+	return fingerprint(req)
+}
+
+func (h *OverviewHandler) sortProjects(projects []ProjectOverview, sortBy string) {
+	compare := overviewSortComparators[sortBy]
+	slices.SortStableFunc(projects, func(a, b ProjectOverview) int {
+		if c := compare(&a, &b); c != 0 {
+			return c
+		}
+		if c := strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)); c != 0 {
+			return c
+		}
+		aID, _ := strconv.Atoi(a.ID)
+		bID, _ := strconv.Atoi(b.ID)
+		return cmp.Compare(aID, bID)
+	})
+}
+
+// compareActivity orders RFC 3339 timestamps, treating a missing one as the
+// oldest.
+func compareActivity(a, b string) int {
+	at, aErr := time.Parse(time.RFC3339, a)
+	bt, bErr := time.Parse(time.RFC3339, b)
+	switch {
+	case aErr != nil && bErr != nil:
+		return 0
+	case aErr != nil:
+		return -1
+	case bErr != nil:
+		return 1
+	}
+	return at.Compare(bt)
+}
+
+func derefInt(value *int) int {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
+
+func (h *OverviewHandler) getUserInfo(ctx context.Context, client *api.Client) (*UserInfo, error) {
+	userRaw, err := client.GetMe(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	h.location = defaultLocation(h.config)
+	if userRaw.Timezone != "" {
+		if location, err := time.LoadLocation(userRaw.Timezone); err == nil {
+			h.location = location
+		}
+	}
+
+	id := fmt.Sprintf("%d", userRaw.ID)
+	username, name := displayUser(h.config, client, id, userRaw.Username, userRaw.Name)
 	return &UserInfo{
-		ID:       fmt.Sprintf("%d", userRaw.ID),
-		Username: userRaw.Username,
-		Name:     userRaw.Name,
+		ID:       id,
+		Username: username,
+		Name:     name,
 	}, nil
 }
 
-func (h *OverviewHandler) buildProjectOverviews(client *api.Client, rawProjects []map[string]interface{}, req OverviewRequest) ([]ProjectOverview, error) {
-	projectOverviews := make([]ProjectOverview, len(rawProjects))
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-	errors := make([]error, 0)
+// buildProjectOverviews returns the overviews that could be built along with a
+// warning for each project that failed. It only errors when every project
+// failed.
+func (h *OverviewHandler) buildProjectOverviews(ctx context.Context, client *api.Client, rawProjects []map[string]interface{}, req OverviewRequest) ([]ProjectOverview, []ProjectWarning, error) {
+	built := make([]*ProjectOverview, len(rawProjects))
 
-	for i, rawProject := range rawProjects {
-		wg.Add(1)
-		go func(index int, project map[string]interface{}) {
-			defer wg.Done()
-
-			overview, err := h.buildSingleProjectOverview(client, project, req)
-			if err != nil {
-				mu.Lock()
-				errors = append(errors, fmt.Errorf("project %v: %w", project["id"], err))
-				mu.Unlock()
-				return
-			}
+	errs, stats := h.config.WorkPool.Run(ctx, len(rawProjects), func(ctx context.Context, i int) (err error) {
+		ctx, span := tracing.Start(ctx, "project overview", attribute.String("kanboard.project_id", fmt.Sprint(rawProjects[i]["id"])))
+		defer func() { tracing.End(span, err) }()
 
-			mu.Lock()
-			projectOverviews[index] = *overview
-			mu.Unlock()
-		}(i, rawProject)
+		overview, err := h.buildSingleProjectOverview(ctx, client, rawProjects[i], req)
+		if err != nil {
+			return err
+		}
+		built[i] = overview
+		return nil
+	})
+	logging.DebugfContext(ctx, "Built %d project overviews (%d failed, max queue wait %s) in %s", stats.Jobs, stats.Failed, stats.MaxQueueWait, stats.Elapsed)
+	if err := workpool.Busy(errs); err != nil {
+		return nil, nil, err
 	}
 
-	wg.Wait()
+	projectOverviews := make([]ProjectOverview, 0, len(rawProjects))
+	var warnings []ProjectWarning
+	var firstErr error
 
-	if len(errors) > 0 {
-		return nil, fmt.Errorf("failed to build some project overviews: %v", errors[0])
+	for i, rawProject := range rawProjects {
+		if errs[i] != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("project %v: %w", rawProject["id"], errs[i])
+			}
+			warnings = append(warnings, ProjectWarning{
+				ProjectID:   fmt.Sprintf("%v", rawProject["id"]),
+				ProjectName: h.getString(rawProject, "name"),
+				Error:       errs[i].Error(),
+			})
+			continue
+		}
+		if built[i] != nil {
+			projectOverviews = append(projectOverviews, *built[i])
+		}
+	}
+
+	if len(rawProjects) > 0 && len(warnings) == len(rawProjects) {
+		return nil, nil, firstErr
 	}
 
-	return projectOverviews, nil
+	return projectOverviews, warnings, nil
 }
 
-func (h *OverviewHandler) buildSingleProjectOverview(client *api.Client, rawProject map[string]interface{}, req OverviewRequest) (*ProjectOverview, error) {
-	projectID := fmt.Sprintf("%.0f", rawProject["id"].(float64))
-	projectIDInt := int(rawProject["id"].(float64))
+func (h *OverviewHandler) buildSingleProjectOverview(ctx context.Context, client *api.Client, rawProject map[string]interface{}, req OverviewRequest) (*ProjectOverview, error) {
+	rawID, ok := rawProject["id"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("invalid project id %v", rawProject["id"])
+	}
+	projectID := fmt.Sprintf("%.0f", rawID)
+	projectIDInt := int(rawID)
 
-	columns, err := h.getProjectColumns(client, projectIDInt)
+	columns, err := h.getProjectColumns(ctx, client, projectIDInt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get columns: %w", err)
 	}
 
-	swimlanes, err := h.getProjectSwimlanes(client, projectIDInt)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get swimlanes: %w", err)
+	var swimlanes []SwimlaneInfo
+	var users []ProjectUser
+	if !req.Compact {
+		rawProject, err = h.projectDetails(ctx, client, projectIDInt, rawProject)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get project details: %w", err)
+		}
+
+		users, err = h.getProjectUsers(ctx, client, projectIDInt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get users: %w", err)
+		}
 	}
 
-	users, err := h.getProjectUsers(client, projectIDInt)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get users: %w", err)
+	if !req.Compact || req.IncludeSwimlaneCounts {
+		swimlanes, err = h.getProjectSwimlanes(ctx, client, projectIDInt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get swimlanes: %w", err)
+		}
 	}
 
 	overview := &ProjectOverview{
-		ID:          projectID,
-		Name:        h.getString(rawProject, "name"),
-		Description: h.getString(rawProject, "description"),
-		IsActive:    h.getBool(rawProject, "is_active"),
-		Owner:       h.getString(rawProject, "owner_name"),
-		Columns:     columns,
-		Swimlanes:   swimlanes,
-		Users:       users,
-	}
-
-	if req.IncludeTaskCounts {
-		taskCounts, err := h.getProjectTaskCounts(client, projectIDInt, columns)
+		ID:           projectID,
+		Name:         h.getString(rawProject, "name"),
+		Identifier:   h.getString(rawProject, "identifier"),
+		Description:  h.getString(rawProject, "description"),
+		IsActive:     h.getBool(rawProject, "is_active"),
+		IsPublic:     h.getBool(rawProject, "is_public"),
+		IsPrivate:    h.getBool(rawProject, "is_private"),
+		StartDate:    h.getString(rawProject, "start_date"),
+		EndDate:      h.getString(rawProject, "end_date"),
+		LastActivity: h.getTimestamp(rawProject, "last_modified"),
+		Owner:        h.getString(rawProject, "owner_name"),
+		Columns:      columns,
+		Users:        users,
+	}
+	if !req.Compact {
+		overview.Swimlanes = swimlanes
+	}
+
+	if req.IncludeTaskCounts || req.IncludeSwimlaneCounts || req.IncludeDueCounts {
+		tasks, err := client.GetTasksByProject(ctx, projectIDInt)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get task counts: %w", err)
 		}
-		overview.TaskCounts = taskCounts
+
+		var laneFilter []SwimlaneInfo
+		if req.IncludeSwimlaneCounts {
+			laneFilter = swimlanes
+		}
+
+		taskCounts, swimlaneCounts := h.countProjectTasks(tasks, columns, laneFilter)
+
+		if req.IncludeDueCounts {
+			overdue, dueThisWeek := h.countDueTasks(tasks)
+			overview.OverdueCount = &overdue
+			overview.DueThisWeekCount = &dueThisWeek
+		}
+
+		if req.IncludeTaskCounts {
+			overview.TaskCounts = taskCounts
+			h.applyWIPStatus(overview.Columns, taskCounts)
+		}
+		overview.SwimlaneTaskCounts = swimlaneCounts
 	}
 
 	return overview, nil
 }
 
-func (h *OverviewHandler) getProjectColumns(client *api.Client, projectID int) ([]ColumnInfo, error) {
-	columns, err := client.GetColumns(projectID)
+func (h *OverviewHandler) applyWIPStatus(columns []ColumnInfo, taskCounts map[string]int) {
+	for i := range columns {
+		if columns[i].TaskLimit <= 0 {
+			continue
+		}
+		count := taskCounts[columns[i].Title]
+		utilization := math.Round(float64(count)/float64(columns[i].TaskLimit)*1000) / 10
+		columns[i].UtilizationPercent = &utilization
+		columns[i].OverLimit = count > columns[i].TaskLimit
+	}
+}
+
+// filterProjects keeps the projects named in ProjectIDs and matching
+// ProjectFilter. The filter is a case-insensitive glob when it contains
+// wildcard characters and a substring otherwise, checked against both the
+// project name and its identifier.
+func (h *OverviewHandler) filterProjects(rawProjects []map[string]interface{}, req OverviewRequest) []map[string]interface{} {
+	if len(req.ProjectIDs) == 0 && req.ProjectFilter == "" {
+		return rawProjects
+	}
+
+	wanted := make(map[string]bool, len(req.ProjectIDs))
+	for _, id := range req.ProjectIDs {
+		wanted[strings.TrimSpace(id)] = true
+	}
+
+	filter := strings.ToLower(strings.TrimSpace(req.ProjectFilter))
+	isGlob := strings.ContainsAny(filter, "*?[")
+
+	matches := func(value string) bool {
+		value = strings.ToLower(value)
+		if isGlob {
+			matched, err := path.Match(filter, value)
+			return err == nil && matched
+		}
+		return strings.Contains(value, filter)
+	}
+
+	filtered := make([]map[string]interface{}, 0, len(rawProjects))
+	for _, project := range rawProjects {
+		if len(wanted) > 0 && !wanted[fmt.Sprint(project["id"])] {
+			continue
+		}
+		if filter != "" && !matches(h.getString(project, "name")) && !matches(h.getString(project, "identifier")) {
+			continue
+		}
+		filtered = append(filtered, project)
+	}
+
+	return filtered
+}
+
+func (h *OverviewHandler) compactProjects(projects []ProjectOverview) []CompactProject {
+	compact := make([]CompactProject, len(projects))
+	for i, project := range projects {
+		columns := make([]CompactColumn, len(project.Columns))
+		for j, column := range project.Columns {
+			columns[j] = CompactColumn{Title: column.Title}
+			if count, ok := project.TaskCounts[column.Title]; ok {
+				columns[j].Tasks = &count
+			}
+		}
+		compact[i] = CompactProject{
+			ID:                 project.ID,
+			Name:               project.Name,
+			Columns:            columns,
+			SwimlaneTaskCounts: project.SwimlaneTaskCounts,
+			OverdueCount:       project.OverdueCount,
+			DueThisWeekCount:   project.DueThisWeekCount,
+		}
+	}
+	return compact
+}
+
+// projectDetails returns the full project row. getMyProjects already carries
+// it; rows missing the metadata columns are completed with getProjectById.
+func (h *OverviewHandler) projectDetails(ctx context.Context, client *api.Client, projectID int, rawProject map[string]interface{}) (map[string]interface{}, error) {
+	if _, ok := rawProject["last_modified"]; ok {
+		return rawProject, nil
+	}
+
+	details, err := client.GetProjectByIDRaw(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range rawProject {
+		if _, exists := details[key]; !exists {
+			details[key] = value
+		}
+	}
+
+	return details, nil
+}
+
+func (h *OverviewHandler) getProjectColumns(ctx context.Context, client *api.Client, projectID int) ([]ColumnInfo, error) {
+	columns, err := client.GetColumns(ctx, projectID)
 	if err != nil {
 		return nil, err
 	}
@@ -272,8 +699,8 @@ func (h *OverviewHandler) getProjectColumns(client *api.Client, projectID int) (
 	return result, nil
 }
 
-func (h *OverviewHandler) getProjectSwimlanes(client *api.Client, projectID int) ([]SwimlaneInfo, error) {
-	swimlanes, err := client.GetSwimlanes(projectID)
+func (h *OverviewHandler) getProjectSwimlanes(ctx context.Context, client *api.Client, projectID int) ([]SwimlaneInfo, error) {
+	swimlanes, err := client.GetSwimlanes(ctx, projectID)
 	if err != nil {
 		return nil, err
 	}
@@ -291,18 +718,20 @@ func (h *OverviewHandler) getProjectSwimlanes(client *api.Client, projectID int)
 	return result, nil
 }
 
-func (h *OverviewHandler) getProjectUsers(client *api.Client, projectID int) ([]ProjectUser, error) {
-	users, err := client.GetProjectUsers(projectID)
+func (h *OverviewHandler) getProjectUsers(ctx context.Context, client *api.Client, projectID int) ([]ProjectUser, error) {
+	users, err := client.GetProjectUsers(ctx, projectID)
 	if err != nil {
 		return nil, err
 	}
 
 	result := make([]ProjectUser, len(users))
 	for i, user := range users {
+		id := fmt.Sprintf("%d", user.ID)
+		username, name := displayUser(h.config, client, id, user.Username, user.Name)
 		result[i] = ProjectUser{
-			ID:       fmt.Sprintf("%d", user.ID),
-			Username: user.Username,
-			Name:     user.Name,
+			ID:       id,
+			Username: username,
+			Name:     name,
 			Role:     user.Role,
 		}
 	}
@@ -310,31 +739,71 @@ func (h *OverviewHandler) getProjectUsers(client *api.Client, projectID int) ([]
 	return result, nil
 }
 
-func (h *OverviewHandler) getProjectTaskCounts(client *api.Client, projectID int, columns []ColumnInfo) (map[string]int, error) {
-
-	tasks, err := client.GetTasksByProject(projectID)
-	if err != nil {
-		return nil, err
-	}
-
-	counts := make(map[string]int)
-
+// countProjectTasks counts active tasks per column and, when swimlanes are
+// given, per swimlane and column.
+func (h *OverviewHandler) countProjectTasks(tasks []models.Task, columns []ColumnInfo, swimlanes []SwimlaneInfo) (map[string]int, map[string]map[string]int) {
+	columnTitles := make(map[string]string, len(columns))
+	counts := make(map[string]int, len(columns))
 	for _, col := range columns {
+		columnTitles[col.ID] = col.Title
 		counts[col.Title] = 0
 	}
 
+	var laneCounts map[string]map[string]int
+	laneNames := make(map[string]string, len(swimlanes))
+	if len(swimlanes) > 0 {
+		laneCounts = make(map[string]map[string]int, len(swimlanes))
+		for _, lane := range swimlanes {
+			laneNames[lane.ID] = lane.Name
+			laneCounts[lane.Name] = make(map[string]int, len(columns))
+			for _, col := range columns {
+				laneCounts[lane.Name][col.Title] = 0
+			}
+		}
+	}
+
 	for _, task := range tasks {
-		columnID := fmt.Sprintf("%d", task.ColumnID)
+		title, ok := columnTitles[fmt.Sprintf("%d", task.ColumnID)]
+		if !ok {
+			continue
+		}
+		counts[title]++
 
-		for _, col := range columns {
-			if col.ID == columnID {
-				counts[col.Title]++
-				break
+		if laneCounts != nil {
+			if lane, ok := laneNames[fmt.Sprintf("%d", task.SwimlaneID)]; ok {
+				laneCounts[lane][title]++
 			}
 		}
 	}
 
-	return counts, nil
+	return counts, laneCounts
+}
+
+// countDueTasks counts open tasks that are overdue and those due between now
+// and the end of the seventh day from today, in the user's timezone.
+func (h *OverviewHandler) countDueTasks(tasks []models.Task) (int, int) {
+	location := h.location
+	if location == nil {
+		location = defaultLocation(h.config)
+	}
+	now := time.Now().In(location)
+	weekEnd := startOfDay(now).AddDate(0, 0, 8)
+
+	overdue, dueThisWeek := 0, 0
+	for _, task := range tasks {
+		due := task.DateDue.Time
+		if due.IsZero() || !bool(task.IsActive) {
+			continue
+		}
+		switch {
+		case due.Before(now):
+			overdue++
+		case due.Before(weekEnd):
+			dueThisWeek++
+		}
+	}
+
+	return overdue, dueThisWeek
 }
 
 func (h *OverviewHandler) calculateSummary(projects []ProjectOverview, includeTaskCounts bool) OverviewSummary {
@@ -368,6 +837,25 @@ func (h *OverviewHandler) getString(data map[string]interface{}, key string) str
 	return ""
 }
 
+func (h *OverviewHandler) getTimestamp(data map[string]interface{}, key string) string {
+	var seconds int64
+	switch val := data[key].(type) {
+	case float64:
+		seconds = int64(val)
+	case string:
+		seconds, _ = strconv.ParseInt(val, 10, 64)
+	}
+	if seconds <= 0 {
+		return ""
+	}
+
+	location := h.location
+	if location == nil {
+		location = defaultLocation(h.config)
+	}
+	return time.Unix(seconds, 0).In(location).Format(time.RFC3339)
+}
+
 func (h *OverviewHandler) getBool(data map[string]interface{}, key string) bool {
 	if val, ok := data[key]; ok && val != nil {
 		switch v := val.(type) {
@@ -1,20 +1,25 @@
 package handlers
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
-	"github.com/tech-arch1tect/kan-mcp/internal/api"
 	"github.com/tech-arch1tect/kan-mcp/internal/auth"
+	"github.com/tech-arch1tect/kan-mcp/internal/dateparse"
 	"github.com/tech-arch1tect/kan-mcp/internal/models"
 )
 
 type PrioritiesHandler struct {
 	authManager *auth.AuthManager
 	config      *models.UserConfig
+	location    *time.Location
 }
 
 func NewPrioritiesHandler(authManager *auth.AuthManager, config *models.UserConfig) *PrioritiesHandler {
@@ -29,6 +34,10 @@ type PrioritiesRequest struct {
 	ProjectIDs             []string `json:"project_ids"`
 	TimeHorizon            string   `json:"time_horizon"`
 	IncludeRecommendations bool     `json:"include_recommendations"`
+	Output                 string   `json:"output"`
+	MatrixLimit            int      `json:"matrix_limit"`
+	ImportantCategories    []string `json:"important_categories"`
+	IncludeUndated         bool     `json:"include_undated"`
 }
 
 type UserWorkload struct {
@@ -43,36 +52,103 @@ type UserWorkload struct {
 }
 
 type UrgentItem struct {
-	TaskID       string `json:"task_id"`
-	Title        string `json:"title"`
-	UrgencyScore int    `json:"urgency_score"`
-	Reason       string `json:"reason"`
-	Project      string `json:"project"`
-	DaysOverdue  int    `json:"days_overdue,omitempty"`
+	TaskID       string           `json:"task_id"`
+	Title        string           `json:"title"`
+	UrgencyScore int              `json:"urgency_score"`
+	Reason       string           `json:"reason"`
+	Project      string           `json:"project"`
+	DaysOverdue  int              `json:"days_overdue,omitempty"`
+	Subtasks     *SubtaskProgress `json:"subtasks,omitempty"`
 }
 
 type Bottleneck struct {
 	Column          string   `json:"column"`
 	Project         string   `json:"project"`
+	TaskCount       int      `json:"task_count"`
+	TaskLimit       int      `json:"task_limit,omitempty"`
+	WIPBreach       bool     `json:"wip_breach"`
 	StuckTasks      int      `json:"stuck_tasks"`
 	AvgWaitTimeDays float64  `json:"avg_wait_time_days"`
 	TaskIDs         []string `json:"task_ids"`
 }
 
 type Recommendation struct {
-	Type              string   `json:"type"`
-	Message           string   `json:"message"`
-	TaskIDs           []string `json:"task_ids,omitempty"`
-	SuggestedAssignee string   `json:"suggested_assignee,omitempty"`
-	AffectedTasks     []string `json:"affected_tasks,omitempty"`
-	Confidence        float64  `json:"confidence"`
+	ID                string                  `json:"id"`
+	Type              string                  `json:"type"`
+	Message           string                  `json:"message"`
+	TaskIDs           []string                `json:"task_ids,omitempty"`
+	SuggestedAssignee string                  `json:"suggested_assignee,omitempty"`
+	AffectedTasks     []string                `json:"affected_tasks,omitempty"`
+	Confidence        float64                 `json:"confidence"`
+	Evidence          *RecommendationEvidence `json:"evidence,omitempty"`
 }
 
+type RecommendationEvidence struct {
+	Tasks      []EvidenceTask     `json:"tasks,omitempty"`
+	Metrics    map[string]float64 `json:"metrics,omitempty"`
+	Thresholds map[string]float64 `json:"thresholds,omitempty"`
+}
+
+type EvidenceTask struct {
+	TaskID  string `json:"task_id"`
+	Title   string `json:"title"`
+	Project string `json:"project"`
+	URL     string `json:"url"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+const (
+	weeklyCapacityHours    = 40.0
+	urgentScoreThreshold   = 70
+	overloadedUtilization  = 100.0
+	maxEvidenceTasks       = 10
+	bottleneckMinTasks     = 3
+	bottleneckMinWaitDays  = 2.0
+	bottleneckMinAvgWait   = 3.0
+	severeOverloadFraction = 1.2
+)
+
 type PrioritiesAnalysis struct {
+	TimeHorizon    string         `json:"time_horizon"`
+	HorizonEnd     string         `json:"horizon_end"`
+	TasksInScope   int            `json:"tasks_in_scope"`
 	RequestingUser *UserWorkload  `json:"requesting_user,omitempty"`
 	TeamWorkloads  []UserWorkload `json:"team_workloads"`
 	UrgentItems    []UrgentItem   `json:"urgent_items"`
 	Bottlenecks    []Bottleneck   `json:"bottlenecks"`
+	Warnings       []string       `json:"warnings,omitempty"`
+}
+
+type MatrixTask struct {
+	TaskID       string           `json:"task_id"`
+	Title        string           `json:"title"`
+	Project      string           `json:"project"`
+	Priority     string           `json:"priority"`
+	Category     string           `json:"category,omitempty"`
+	DueDate      string           `json:"due_date,omitempty"`
+	UrgencyScore int              `json:"urgency_score"`
+	Reason       string           `json:"reason"`
+	Subtasks     *SubtaskProgress `json:"subtasks,omitempty"`
+}
+
+type MatrixQuadrant struct {
+	Description string       `json:"description"`
+	TotalTasks  int          `json:"total_tasks"`
+	Tasks       []MatrixTask `json:"tasks"`
+}
+
+type EisenhowerMatrix struct {
+	DoFirst   MatrixQuadrant `json:"do_first"`
+	Schedule  MatrixQuadrant `json:"schedule"`
+	Delegate  MatrixQuadrant `json:"delegate"`
+	Eliminate MatrixQuadrant `json:"eliminate"`
+}
+
+type PrioritiesMatrixResponse struct {
+	TimeHorizon string           `json:"time_horizon"`
+	HorizonEnd  string           `json:"horizon_end"`
+	Matrix      EisenhowerMatrix `json:"matrix"`
+	Warnings    []string         `json:"warnings,omitempty"`
 }
 
 type PrioritiesResponse struct {
@@ -80,10 +156,13 @@ type PrioritiesResponse struct {
 	Recommendations []Recommendation   `json:"recommendations,omitempty"`
 }
 
-func (h *PrioritiesHandler) Handle(params map[string]interface{}, userID string) (*models.MCPResponse, error) {
+func (h *PrioritiesHandler) Handle(ctx context.Context, params map[string]interface{}, userID string) (*models.MCPResponse, error) {
 	var req PrioritiesRequest
 	req.TimeHorizon = "week"
 	req.IncludeRecommendations = true
+	req.Output = "analysis"
+	req.MatrixLimit = 5
+	req.IncludeUndated = true
 
 	if params != nil {
 		data, err := json.Marshal(params)
@@ -95,54 +174,66 @@ func (h *PrioritiesHandler) Handle(params map[string]interface{}, userID string)
 		}
 	}
 
+	if req.Output != "analysis" && req.Output != "matrix" {
+		return nil, fmt.Errorf("invalid output %q: must be analysis or matrix", req.Output)
+	}
+	if _, err := h.parseHorizon(req.TimeHorizon, h.now()); err != nil {
+		return nil, err
+	}
+
 	if req.UserID == "" {
 		req.UserID = userID
 	}
 
-	user, err := h.authManager.AuthenticateUser(userID)
-	if err == nil {
-		token, err := h.authManager.GetDecryptedToken(user)
-		if err == nil {
-			kanboardURL := user.KanboardURL
-			if kanboardURL == "" {
-				kanboardURL = h.config.DefaultKanboardURL
-			}
-
-			client := api.NewClient(kanboardURL, user.KanboardUsername, token)
-			if me, err := client.GetMe(); err == nil {
-				req.UserID = fmt.Sprintf("%d", me.ID)
-			}
-		}
+	client, err := authenticatedClient(h.authManager, h.config, userID)
+	if err != nil {
+		return nil, err
 	}
 
-	tasksHandler := NewTasksHandler(h.authManager, h.config)
-	tasksParams := map[string]interface{}{
-		"project_ids":           req.ProjectIDs,
-		"status_filter":         "all",
-		"include_overdue":       true,
-		"include_time_tracking": true,
-		"sort_by":               "due_date",
-		"limit":                 200,
-		"summary_mode":          false,
+	if me, err := client.GetMe(ctx); err == nil {
+		req.UserID = fmt.Sprintf("%d", me.ID)
 	}
 
-	tasksResponse, err := tasksHandler.Handle(tasksParams, userID)
+	tasksData, err := NewTaskService(h.authManager, h.config).Load(ctx, userID, TasksRequest{
+		ProjectIDs:          req.ProjectIDs,
+		StatusFilter:        "active",
+		IncludeOverdue:      true,
+		IncludeTimeTracking: true,
+		IncludeSubtasks:     true,
+		SortBy:              "due_date",
+		Limit:               200,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tasks data: %w", err)
 	}
+	h.location = tasksData.Location
+	horizonEnd := h.horizonLimit(req.TimeHorizon, h.now()).AddDate(0, 0, -1).Format("2006-01-02")
 
-	var tasksData TasksResponse
-	if err := json.Unmarshal([]byte(tasksResponse.Content[0].Text), &tasksData); err != nil {
-		return nil, fmt.Errorf("failed to parse tasks response: %w", err)
-	}
+	var response interface{}
+	if req.Output == "matrix" {
+		response = PrioritiesMatrixResponse{
+			TimeHorizon: req.TimeHorizon,
+			HorizonEnd:  horizonEnd,
+			Matrix:      h.buildMatrix(tasksData.Tasks, req),
+			Warnings:    projectWarningMessages(tasksData.Warnings),
+		}
+	} else {
+		columns, err := projectColumns(ctx, client, tasksData.Tasks)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get column limits: %w", err)
+		}
 
-	analysis := h.analyseWorkload(tasksData.Tasks, req)
+		analysis := h.analyseWorkload(tasksData.Tasks, columns, req)
+		analysis.HorizonEnd = horizonEnd
+		analysis.Warnings = append(projectWarningMessages(tasksData.Warnings), analysis.Warnings...)
 
-	var response PrioritiesResponse
-	response.Analysis = analysis
+		var analysisResponse PrioritiesResponse
+		analysisResponse.Analysis = analysis
 
-	if req.IncludeRecommendations {
-		response.Recommendations = h.generateRecommendations(analysis, tasksData.Tasks)
+		if req.IncludeRecommendations {
+			analysisResponse.Recommendations = h.generateRecommendations(analysis, tasksData.Tasks)
+		}
+		response = analysisResponse
 	}
 
 	responseJSON, err := json.MarshalIndent(response, "", "  ")
@@ -160,13 +251,17 @@ func (h *PrioritiesHandler) Handle(params map[string]interface{}, userID string)
 	}, nil
 }
 
-func (h *PrioritiesHandler) analyseWorkload(tasks []TaskDetail, req PrioritiesRequest) PrioritiesAnalysis {
+func (h *PrioritiesHandler) analyseWorkload(tasks []TaskDetail, columns map[string][]models.Column, req PrioritiesRequest) PrioritiesAnalysis {
 	analysis := PrioritiesAnalysis{
+		TimeHorizon: req.TimeHorizon,
 		UrgentItems: []UrgentItem{},
 		Bottlenecks: []Bottleneck{},
 	}
 
-	analysis.TeamWorkloads = h.analyseTeamWorkloads(tasks)
+	inScope := h.tasksWithinHorizon(tasks, req)
+	analysis.TasksInScope = len(inScope)
+
+	analysis.TeamWorkloads, analysis.Warnings = h.analyseTeamWorkloads(inScope)
 
 	for i, workload := range analysis.TeamWorkloads {
 		if h.matchesUserID(workload.UserID, req.UserID) {
@@ -179,23 +274,39 @@ func (h *PrioritiesHandler) analyseWorkload(tasks []TaskDetail, req PrioritiesRe
 
 	analysis.UrgentItems = h.findUrgentItems(tasks, req.TimeHorizon)
 
-	analysis.Bottlenecks = h.findBottlenecks(tasks)
+	analysis.Bottlenecks = h.findBottlenecks(inScope, columns)
 
 	return analysis
 }
 
-func (h *PrioritiesHandler) analyseTeamWorkloads(tasks []TaskDetail) []UserWorkload {
+// analyseTeamWorkloads returns one workload per assignee plus an "Unassigned"
+// entry, and warnings for assignees that could not be resolved to a project
+// member.
+func (h *PrioritiesHandler) analyseTeamWorkloads(tasks []TaskDetail) ([]UserWorkload, []string) {
 	userMap := make(map[string]*UserWorkload)
-
-	allAssigneeIDs := make([]string, 0)
+	unassigned := UserWorkload{
+		UserID: "unassigned",
+		Name:   "Unassigned",
+		Status: "unassigned",
+	}
+	unresolved := make(map[string]int)
 
 	for _, task := range tasks {
 		if task.Assignee == nil {
+			unassigned.AssignedTasks++
+			if task.IsOverdue {
+				unassigned.OverdueTasks++
+			}
+			if task.TimeTracking != nil {
+				unassigned.TotalEstimatedHours += task.TimeTracking.EstimatedHours
+			}
 			continue
 		}
 
 		userID := task.Assignee.ID
-		allAssigneeIDs = append(allAssigneeIDs, userID)
+		if task.Assignee.Username == "" {
+			unresolved[userID]++
+		}
 
 		if _, exists := userMap[userID]; !exists {
 			userMap[userID] = &UserWorkload{
@@ -219,13 +330,12 @@ func (h *PrioritiesHandler) analyseTeamWorkloads(tasks []TaskDetail) []UserWorkl
 
 	var workloads []UserWorkload
 	for _, workload := range userMap {
-		weeklyCapacity := 40.0
-		utilization := (workload.TotalEstimatedHours / weeklyCapacity) * 100
+		utilization := (workload.TotalEstimatedHours / weeklyCapacityHours) * 100
 		workload.CapacityUtilization = fmt.Sprintf("%.0f%%", utilization)
 
-		if utilization > 120 {
+		if utilization > overloadedUtilization*severeOverloadFraction {
 			workload.Status = "severely_overloaded"
-		} else if utilization > 100 {
+		} else if utilization > overloadedUtilization {
 			workload.Status = "overloaded"
 		} else if utilization > 80 {
 			workload.Status = "at_capacity"
@@ -238,56 +348,44 @@ func (h *PrioritiesHandler) analyseTeamWorkloads(tasks []TaskDetail) []UserWorkl
 		workloads = append(workloads, *workload)
 	}
 
-	if len(workloads) == 0 && len(allAssigneeIDs) > 0 {
-
-		uniqueIDs := make(map[string]bool)
-		for _, id := range allAssigneeIDs {
-			uniqueIDs[id] = true
-		}
-		debugIDs := make([]string, 0, len(uniqueIDs))
-		for id := range uniqueIDs {
-			debugIDs = append(debugIDs, id)
-		}
-
-		workloads = append(workloads, UserWorkload{
-			UserID:   "debug",
-			Username: fmt.Sprintf("DEBUG: Found %d tasks with assignee IDs: %v", len(allAssigneeIDs), debugIDs),
-			Name:     "Debug Info",
-		})
-	}
-
 	sort.Slice(workloads, func(i, j int) bool {
 		return workloads[i].TotalEstimatedHours > workloads[j].TotalEstimatedHours
 	})
 
-	return workloads
+	if unassigned.AssignedTasks > 0 {
+		workloads = append(workloads, unassigned)
+	}
+
+	var warnings []string
+	if len(unresolved) > 0 {
+		ids := make([]string, 0, len(unresolved))
+		count := 0
+		for id, tasks := range unresolved {
+			ids = append(ids, id)
+			count += tasks
+		}
+		sort.Strings(ids)
+		warnings = append(warnings, fmt.Sprintf("%d tasks are assigned to user IDs %s that are not members of their project; their workloads are listed by ID only", count, strings.Join(ids, ", ")))
+	}
+
+	return workloads, warnings
 }
 
 func (h *PrioritiesHandler) findUrgentItems(tasks []TaskDetail, timeHorizon string) []UrgentItem {
 	var urgentItems []UrgentItem
-	now := time.Now()
-
-	var timeLimit time.Time
-	switch timeHorizon {
-	case "today":
-		timeLimit = now.AddDate(0, 0, 1)
-	case "week":
-		timeLimit = now.AddDate(0, 0, 7)
-	case "month":
-		timeLimit = now.AddDate(0, 1, 0)
-	default:
-		timeLimit = now.AddDate(0, 0, 7)
-	}
+	now := h.now()
+	timeLimit := h.horizonLimit(timeHorizon, now)
 
 	for _, task := range tasks {
 		urgencyScore := h.calculateUrgencyScore(task, now, timeLimit)
-		if urgencyScore >= 70 {
+		if urgencyScore >= urgentScoreThreshold {
 			item := UrgentItem{
 				TaskID:       task.ID,
 				Title:        task.Title,
 				UrgencyScore: urgencyScore,
 				Project:      task.Project.Name,
 				Reason:       h.getUrgencyReason(task, now),
+				Subtasks:     task.Subtasks,
 			}
 
 			if task.IsOverdue && task.DaysUntilDue != nil {
@@ -309,6 +407,140 @@ func (h *PrioritiesHandler) findUrgentItems(tasks []TaskDetail, timeHorizon stri
 	return urgentItems
 }
 
+// tasksWithinHorizon keeps tasks that are overdue or due before the end of
+// the time horizon; tasks without a due date are kept only when
+// include_undated is set.
+func (h *PrioritiesHandler) tasksWithinHorizon(tasks []TaskDetail, req PrioritiesRequest) []TaskDetail {
+	timeLimit := h.horizonLimit(req.TimeHorizon, h.now())
+
+	var inScope []TaskDetail
+	for _, task := range tasks {
+		dueDate, err := time.Parse(time.RFC3339, task.Dates.Due)
+		if err != nil {
+			if req.IncludeUndated {
+				inScope = append(inScope, task)
+			}
+			continue
+		}
+		if task.IsOverdue || dueDate.Before(timeLimit) {
+			inScope = append(inScope, task)
+		}
+	}
+
+	return inScope
+}
+
+// horizonLimit returns the end of the horizon's last calendar day in now's
+// timezone. Horizons parseHorizon rejects fall back to a week.
+func (h *PrioritiesHandler) horizonLimit(timeHorizon string, now time.Time) time.Time {
+	limit, err := h.parseHorizon(timeHorizon, now)
+	if err != nil {
+		return startOfDay(now).AddDate(0, 0, 8)
+	}
+	return limit
+}
+
+// parseHorizon resolves a time horizon to the end of its last day: 'today',
+// 'week' (the coming seven days) and 'month' (the coming month), or a date
+// phrase such as 'friday' or 'end of month', which must not be in the past.
+func (h *PrioritiesHandler) parseHorizon(timeHorizon string, now time.Time) (time.Time, error) {
+	today := startOfDay(now)
+	switch timeHorizon {
+	case "today":
+		return today.AddDate(0, 0, 1), nil
+	case "week", "":
+		return today.AddDate(0, 0, 8), nil
+	case "month":
+		return today.AddDate(0, 1, 1), nil
+	}
+
+	r, err := dateparse.Parse(timeHorizon, now, h.config.Calendar)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time_horizon: %w", err)
+	}
+	if r.End.Before(today) {
+		return time.Time{}, fmt.Errorf("invalid time_horizon %q: %s is in the past", timeHorizon, r.End.Format("2006-01-02"))
+	}
+	return r.End.AddDate(0, 0, 1), nil
+}
+
+// now returns the current time in the user's timezone.
+func (h *PrioritiesHandler) now() time.Time {
+	if h.location == nil {
+		return time.Now().In(defaultLocation(h.config))
+	}
+	return time.Now().In(h.location)
+}
+
+// buildMatrix places tasks in Eisenhower quadrants. A task is urgent when it
+// is overdue or due within the time horizon, and important when it has high
+// or urgent priority, a complexity score of 5 or more, or one of the
+// requested important categories.
+func (h *PrioritiesHandler) buildMatrix(tasks []TaskDetail, req PrioritiesRequest) EisenhowerMatrix {
+	now := h.now()
+	timeLimit := h.horizonLimit(req.TimeHorizon, now)
+
+	matrix := EisenhowerMatrix{
+		DoFirst:   MatrixQuadrant{Description: "Urgent and important: do these now"},
+		Schedule:  MatrixQuadrant{Description: "Important but not urgent: plan time for these"},
+		Delegate:  MatrixQuadrant{Description: "Urgent but less important: hand off or timebox"},
+		Eliminate: MatrixQuadrant{Description: "Neither urgent nor important: drop or defer"},
+	}
+
+	for _, task := range tasks {
+		urgent := task.IsOverdue
+		if dueDate, err := time.Parse(time.RFC3339, task.Dates.Due); err == nil && dueDate.Before(timeLimit) {
+			urgent = true
+		}
+
+		important := task.Priority == "urgent" || task.Priority == "high" || task.Score >= 5
+		for _, category := range req.ImportantCategories {
+			if task.Category != "" && strings.EqualFold(strings.TrimSpace(category), task.Category) {
+				important = true
+			}
+		}
+
+		var quadrant *MatrixQuadrant
+		switch {
+		case urgent && important:
+			quadrant = &matrix.DoFirst
+		case important:
+			quadrant = &matrix.Schedule
+		case urgent:
+			quadrant = &matrix.Delegate
+		default:
+			quadrant = &matrix.Eliminate
+		}
+
+		quadrant.TotalTasks++
+		quadrant.Tasks = append(quadrant.Tasks, MatrixTask{
+			TaskID:       task.ID,
+			Title:        task.Title,
+			Project:      task.Project.Name,
+			Priority:     task.Priority,
+			Category:     task.Category,
+			DueDate:      task.Dates.Due,
+			UrgencyScore: h.calculateUrgencyScore(task, now, timeLimit),
+			Reason:       h.getUrgencyReason(task, now),
+			Subtasks:     task.Subtasks,
+		})
+	}
+
+	for _, quadrant := range []*MatrixQuadrant{&matrix.DoFirst, &matrix.Schedule, &matrix.Delegate, &matrix.Eliminate} {
+		sort.Slice(quadrant.Tasks, func(i, j int) bool {
+			return quadrant.Tasks[i].UrgencyScore > quadrant.Tasks[j].UrgencyScore
+		})
+		if req.MatrixLimit > 0 && len(quadrant.Tasks) > req.MatrixLimit {
+			quadrant.Tasks = quadrant.Tasks[:req.MatrixLimit]
+		}
+		if quadrant.Tasks == nil {
+			quadrant.Tasks = []MatrixTask{}
+		}
+	}
+
+	return matrix
+}
+
 func (h *PrioritiesHandler) calculateUrgencyScore(task TaskDetail, now, timeLimit time.Time) int {
 	score := 0
 
@@ -331,9 +563,9 @@ func (h *PrioritiesHandler) calculateUrgencyScore(task TaskDetail, now, timeLimi
 	}
 
 	if !task.IsOverdue && task.Dates.Due != "" {
-		if dueDate, err := time.Parse("2006-01-02T15:04:05Z", task.Dates.Due); err == nil {
+		if dueDate, err := time.Parse(time.RFC3339, task.Dates.Due); err == nil {
 			if dueDate.Before(timeLimit) {
-				daysUntil := int(dueDate.Sub(now).Hours() / 24)
+				daysUntil := h.config.Calendar.WorkDaysUntil(now, dueDate)
 				if daysUntil <= 1 {
 					score += 25
 				} else if daysUntil <= 3 {
@@ -367,23 +599,25 @@ func (h *PrioritiesHandler) getUrgencyReason(task TaskDetail, now time.Time) str
 	if task.IsOverdue {
 		if task.DaysUntilDue != nil {
 			daysOverdue := -*task.DaysUntilDue
-			if daysOverdue == 1 {
-				reasons = append(reasons, "Overdue by 1 day")
+			if daysOverdue <= 0 {
+				reasons = append(reasons, "Was due earlier today")
+			} else if daysOverdue == 1 {
+				reasons = append(reasons, "Overdue by 1 working day")
 			} else {
-				reasons = append(reasons, fmt.Sprintf("Overdue by %d days", daysOverdue))
+				reasons = append(reasons, fmt.Sprintf("Overdue by %d working days", daysOverdue))
 			}
 		} else {
 			reasons = append(reasons, "Task is overdue")
 		}
 	} else if task.Dates.Due != "" {
-		if dueDate, err := time.Parse("2006-01-02T15:04:05Z", task.Dates.Due); err == nil {
-			daysUntil := int(dueDate.Sub(now).Hours() / 24)
+		if dueDate, err := time.Parse(time.RFC3339, task.Dates.Due); err == nil {
+			daysUntil := h.config.Calendar.WorkDaysUntil(now, dueDate)
 			if daysUntil == 0 {
 				reasons = append(reasons, "Due today")
 			} else if daysUntil == 1 {
-				reasons = append(reasons, "Due tomorrow")
+				reasons = append(reasons, "Due next working day")
 			} else if daysUntil <= 3 {
-				reasons = append(reasons, fmt.Sprintf("Due in %d days", daysUntil))
+				reasons = append(reasons, fmt.Sprintf("Due in %d working days", daysUntil))
 			}
 		}
 	}
@@ -405,64 +639,88 @@ func (h *PrioritiesHandler) getUrgencyReason(task TaskDetail, now time.Time) str
 	return fmt.Sprintf("%s", reasons[0])
 }
 
-func (h *PrioritiesHandler) findBottlenecks(tasks []TaskDetail) []Bottleneck {
-
-	columnStats := make(map[string]map[string][]TaskDetail)
+// findBottlenecks reports columns whose open tasks exceed the column's WIP
+// limit, and columns where tasks have sat since they were last moved for
+// longer than the wait thresholds.
+func (h *PrioritiesHandler) findBottlenecks(tasks []TaskDetail, columns map[string][]models.Column) []Bottleneck {
+	type columnKey struct {
+		projectID string
+		column    string
+	}
 
+	columnTasks := make(map[columnKey][]TaskDetail)
+	projectNames := make(map[string]string)
 	for _, task := range tasks {
-		projectKey := task.Project.Name
-		columnKey := task.Status.Column
+		key := columnKey{projectID: task.Project.ID, column: task.Status.Column}
+		columnTasks[key] = append(columnTasks[key], task)
+		projectNames[task.Project.ID] = task.Project.Name
+	}
 
-		if columnStats[projectKey] == nil {
-			columnStats[projectKey] = make(map[string][]TaskDetail)
+	limits := make(map[columnKey]int)
+	for projectID, projectColumns := range columns {
+		for _, column := range projectColumns {
+			limits[columnKey{projectID: projectID, column: column.Title}] = column.TaskLimit
 		}
-
-		columnStats[projectKey][columnKey] = append(columnStats[projectKey][columnKey], task)
 	}
 
 	var bottlenecks []Bottleneck
-	now := time.Now()
+	now := h.now()
 
-	for project, columns := range columnStats {
-		for column, columnTasks := range columns {
-			if len(columnTasks) < 3 {
-				continue
-			}
+	for key, inColumn := range columnTasks {
+		type waiting struct {
+			id   string
+			days float64
+		}
 
-			var totalWaitDays float64
-			var validTasks int
-			var taskIDs []string
-
-			for _, task := range columnTasks {
-				if task.Dates.Modified != "" {
-					if modifiedDate, err := time.Parse("2006-01-02T15:04:05Z", task.Dates.Modified); err == nil {
-						waitDays := now.Sub(modifiedDate).Hours() / 24
-						if waitDays > 2 {
-							totalWaitDays += waitDays
-							validTasks++
-							taskIDs = append(taskIDs, task.ID)
-						}
-					}
+		var waits []waiting
+		var totalWaitDays float64
+		for _, task := range inColumn {
+			since, err := time.Parse(time.RFC3339, task.Dates.Moved)
+			if err != nil {
+				since, err = time.Parse(time.RFC3339, task.Dates.Created)
+				if err != nil {
+					continue
 				}
 			}
-
-			if validTasks >= 3 {
-				avgWaitTime := totalWaitDays / float64(validTasks)
-				if avgWaitTime > 3 {
-					bottleneck := Bottleneck{
-						Column:          column,
-						Project:         project,
-						StuckTasks:      validTasks,
-						AvgWaitTimeDays: avgWaitTime,
-						TaskIDs:         taskIDs,
-					}
-					bottlenecks = append(bottlenecks, bottleneck)
-				}
+			days := now.Sub(since).Hours() / 24
+			totalWaitDays += days
+			if days > bottleneckMinWaitDays {
+				waits = append(waits, waiting{id: task.ID, days: days})
 			}
 		}
+
+		sort.Slice(waits, func(i, j int) bool {
+			return waits[i].days > waits[j].days
+		})
+
+		taskIDs := make([]string, len(waits))
+		for i, w := range waits {
+			taskIDs[i] = w.id
+		}
+
+		bottleneck := Bottleneck{
+			Column:     key.column,
+			Project:    projectNames[key.projectID],
+			TaskCount:  len(inColumn),
+			TaskLimit:  limits[key],
+			StuckTasks: len(waits),
+			TaskIDs:    taskIDs,
+		}
+		if len(inColumn) > 0 {
+			bottleneck.AvgWaitTimeDays = totalWaitDays / float64(len(inColumn))
+		}
+		bottleneck.WIPBreach = bottleneck.TaskLimit > 0 && bottleneck.TaskCount > bottleneck.TaskLimit
+
+		stalled := bottleneck.StuckTasks >= bottleneckMinTasks && bottleneck.AvgWaitTimeDays > bottleneckMinAvgWait
+		if bottleneck.WIPBreach || stalled {
+			bottlenecks = append(bottlenecks, bottleneck)
+		}
 	}
 
 	sort.Slice(bottlenecks, func(i, j int) bool {
+		if bottlenecks[i].WIPBreach != bottlenecks[j].WIPBreach {
+			return bottlenecks[i].WIPBreach
+		}
 		return bottlenecks[i].AvgWaitTimeDays > bottlenecks[j].AvgWaitTimeDays
 	})
 
@@ -472,22 +730,55 @@ func (h *PrioritiesHandler) findBottlenecks(tasks []TaskDetail) []Bottleneck {
 func (h *PrioritiesHandler) generateRecommendations(analysis PrioritiesAnalysis, tasks []TaskDetail) []Recommendation {
 	var recommendations []Recommendation
 
+	tasksByID := make(map[string]TaskDetail, len(tasks))
+	for _, task := range tasks {
+		tasksByID[task.ID] = task
+	}
+
 	if len(analysis.UrgentItems) > 0 {
 		topUrgent := analysis.UrgentItems[0]
+
+		evidenceIDs := []string{topUrgent.TaskID}
+		for _, item := range analysis.UrgentItems[1:] {
+			if item.DaysOverdue > 0 {
+				evidenceIDs = append(evidenceIDs, item.TaskID)
+			}
+		}
+
 		rec := Recommendation{
+			ID:         h.recommendationID("priority", topUrgent.TaskID),
 			Type:       "priority",
 			Message:    fmt.Sprintf("Focus on '%s' first - urgency score: %d (%s)", topUrgent.Title, topUrgent.UrgencyScore, topUrgent.Reason),
 			TaskIDs:    []string{topUrgent.TaskID},
 			Confidence: 0.92,
+			Evidence: &RecommendationEvidence{
+				Tasks: h.evidenceTasks(evidenceIDs, tasksByID, func(task TaskDetail) string {
+					return h.getUrgencyReason(task, h.now())
+				}),
+				Metrics: map[string]float64{
+					"urgency_score": float64(topUrgent.UrgencyScore),
+					"urgent_items":  float64(len(analysis.UrgentItems)),
+				},
+				Thresholds: map[string]float64{
+					"urgency_score": urgentScoreThreshold,
+				},
+			},
 		}
 		recommendations = append(recommendations, rec)
 	}
 
 	if analysis.RequestingUser != nil && (analysis.RequestingUser.Status == "overloaded" || analysis.RequestingUser.Status == "severely_overloaded") {
+		user := analysis.RequestingUser
 		rec := Recommendation{
+			ID:         h.recommendationID("workload", user.UserID),
 			Type:       "workload",
-			Message:    fmt.Sprintf("Your workload is %s (%s utilization) - consider delegating or deferring lower priority tasks", analysis.RequestingUser.Status, analysis.RequestingUser.CapacityUtilization),
+			Message:    fmt.Sprintf("Your workload is %s (%s utilization) - consider delegating or deferring lower priority tasks", user.Status, user.CapacityUtilization),
 			Confidence: 0.85,
+			Evidence: &RecommendationEvidence{
+				Tasks:      h.evidenceTasks(h.assignedTaskIDs(tasks, user.UserID), tasksByID, h.estimateDetail),
+				Metrics:    h.workloadMetrics(*user),
+				Thresholds: h.workloadThresholds(),
+			},
 		}
 		recommendations = append(recommendations, rec)
 	}
@@ -505,23 +796,57 @@ func (h *PrioritiesHandler) generateRecommendations(analysis PrioritiesAnalysis,
 		}
 
 		if len(overloaded) > 0 && len(underutilized) > 0 {
+			from, to := overloaded[0], underutilized[0]
+			metrics := map[string]float64{
+				"from_estimated_hours": from.TotalEstimatedHours,
+				"from_assigned_tasks":  float64(from.AssignedTasks),
+				"to_estimated_hours":   to.TotalEstimatedHours,
+				"to_assigned_tasks":    float64(to.AssignedTasks),
+			}
+
 			rec := Recommendation{
+				ID:                h.recommendationID("delegation", from.UserID, to.UserID),
 				Type:              "delegation",
-				Message:           fmt.Sprintf("Consider redistributing tasks from %s (%s) to %s (%s)", overloaded[0].Name, overloaded[0].CapacityUtilization, underutilized[0].Name, underutilized[0].CapacityUtilization),
-				SuggestedAssignee: underutilized[0].UserID,
+				Message:           fmt.Sprintf("Consider redistributing tasks from %s (%s) to %s (%s)", from.Name, from.CapacityUtilization, to.Name, to.CapacityUtilization),
+				SuggestedAssignee: to.UserID,
 				Confidence:        0.78,
+				Evidence: &RecommendationEvidence{
+					Tasks:      h.evidenceTasks(h.assignedTaskIDs(tasks, from.UserID), tasksByID, h.estimateDetail),
+					Metrics:    metrics,
+					Thresholds: h.workloadThresholds(),
+				},
 			}
 			recommendations = append(recommendations, rec)
 		}
 	}
 
 	for _, bottleneck := range analysis.Bottlenecks {
-		if bottleneck.StuckTasks >= 3 {
+		if bottleneck.WIPBreach || bottleneck.StuckTasks >= bottleneckMinTasks {
+			message := fmt.Sprintf("'%s' column in %s has bottleneck - %d tasks waiting %.1f days on average", bottleneck.Column, bottleneck.Project, bottleneck.StuckTasks, bottleneck.AvgWaitTimeDays)
+			if bottleneck.WIPBreach {
+				message = fmt.Sprintf("'%s' column in %s is over its WIP limit (%d tasks, limit %d) - finish or move work out before pulling more in", bottleneck.Column, bottleneck.Project, bottleneck.TaskCount, bottleneck.TaskLimit)
+			}
+
 			rec := Recommendation{
+				ID:            h.recommendationID("process", bottleneck.Project, bottleneck.Column),
 				Type:          "process",
-				Message:       fmt.Sprintf("'%s' column in %s has bottleneck - %d tasks waiting %.1f days on average", bottleneck.Column, bottleneck.Project, bottleneck.StuckTasks, bottleneck.AvgWaitTimeDays),
+				Message:       message,
 				AffectedTasks: bottleneck.TaskIDs,
 				Confidence:    0.85,
+				Evidence: &RecommendationEvidence{
+					Tasks: h.evidenceTasks(bottleneck.TaskIDs, tasksByID, nil),
+					Metrics: map[string]float64{
+						"task_count":         float64(bottleneck.TaskCount),
+						"stuck_tasks":        float64(bottleneck.StuckTasks),
+						"avg_wait_time_days": bottleneck.AvgWaitTimeDays,
+					},
+					Thresholds: map[string]float64{
+						"task_limit":        float64(bottleneck.TaskLimit),
+						"min_stuck_tasks":   bottleneckMinTasks,
+						"min_wait_days":     bottleneckMinWaitDays,
+						"min_avg_wait_days": bottleneckMinAvgWait,
+					},
+				},
 			}
 			recommendations = append(recommendations, rec)
 		}
@@ -530,6 +855,89 @@ func (h *PrioritiesHandler) generateRecommendations(analysis PrioritiesAnalysis,
 	return recommendations
 }
 
+// recommendationID derives an ID from the recommendation type and the
+// entities it is about, so the same advice keeps the same ID across calls.
+func (h *PrioritiesHandler) recommendationID(kind string, keys ...string) string {
+	sum := sha256.Sum256([]byte(kind + "|" + strings.Join(keys, "|")))
+	return kind + "-" + hex.EncodeToString(sum[:])[:12]
+}
+
+func (h *PrioritiesHandler) evidenceTasks(taskIDs []string, tasksByID map[string]TaskDetail, detail func(TaskDetail) string) []EvidenceTask {
+	var evidence []EvidenceTask
+	for _, id := range taskIDs {
+		if len(evidence) >= maxEvidenceTasks {
+			break
+		}
+		task, ok := tasksByID[id]
+		if !ok {
+			continue
+		}
+		item := EvidenceTask{
+			TaskID:  task.ID,
+			Title:   task.Title,
+			Project: task.Project.Name,
+			URL:     task.URL,
+		}
+		if detail != nil {
+			item.Detail = detail(task)
+		}
+		evidence = append(evidence, item)
+	}
+	return evidence
+}
+
+// assignedTaskIDs lists a user's tasks with the largest estimates first.
+func (h *PrioritiesHandler) assignedTaskIDs(tasks []TaskDetail, userID string) []string {
+	var assigned []TaskDetail
+	for _, task := range tasks {
+		if task.Assignee != nil && h.matchesUserID(task.Assignee.ID, userID) {
+			assigned = append(assigned, task)
+		}
+	}
+
+	sort.SliceStable(assigned, func(i, j int) bool {
+		return h.estimatedHours(assigned[i]) > h.estimatedHours(assigned[j])
+	})
+
+	ids := make([]string, len(assigned))
+	for i, task := range assigned {
+		ids[i] = task.ID
+	}
+	return ids
+}
+
+func (h *PrioritiesHandler) estimatedHours(task TaskDetail) float64 {
+	if task.TimeTracking == nil {
+		return 0
+	}
+	return task.TimeTracking.EstimatedHours
+}
+
+func (h *PrioritiesHandler) estimateDetail(task TaskDetail) string {
+	detail := fmt.Sprintf("%.1fh estimated", h.estimatedHours(task))
+	if task.Subtasks != nil {
+		detail += fmt.Sprintf(", %d/%d subtasks done", task.Subtasks.Done, task.Subtasks.Total)
+	}
+	return detail
+}
+
+func (h *PrioritiesHandler) workloadMetrics(workload UserWorkload) map[string]float64 {
+	return map[string]float64{
+		"estimated_hours":     workload.TotalEstimatedHours,
+		"utilization_percent": workload.TotalEstimatedHours / weeklyCapacityHours * 100,
+		"assigned_tasks":      float64(workload.AssignedTasks),
+		"overdue_tasks":       float64(workload.OverdueTasks),
+	}
+}
+
+func (h *PrioritiesHandler) workloadThresholds() map[string]float64 {
+	return map[string]float64{
+		"weekly_capacity_hours":       weeklyCapacityHours,
+		"overloaded_percent":          overloadedUtilization,
+		"severely_overloaded_percent": overloadedUtilization * severeOverloadFraction,
+	}
+}
+
 func (h *PrioritiesHandler) matchesUserID(assigneeID, targetUserID string) bool {
 
 	if assigneeID == targetUserID {
@@ -0,0 +1,286 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/api"
+	"github.com/tech-arch1tect/kan-mcp/internal/auth"
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+)
+
+const maxRecurrenceFactor = 1000
+
+var (
+	recurrenceStatusNames = map[int]string{
+		models.RecurrenceStatusPending:   "pending",
+		models.RecurrenceStatusProcessed: "processed",
+	}
+	recurrenceTriggerNames = map[int]string{
+		models.RecurrenceTriggerFirstColumn: "first_column",
+		models.RecurrenceTriggerLastColumn:  "last_column",
+		models.RecurrenceTriggerClose:       "closed",
+	}
+	recurrenceTimeframeNames = map[int]string{
+		models.RecurrenceTimeframeDays:   "days",
+		models.RecurrenceTimeframeMonths: "months",
+		models.RecurrenceTimeframeYears:  "years",
+	}
+	recurrenceBasedateNames = map[int]string{
+		models.RecurrenceBasedateDueDate:    "due_date",
+		models.RecurrenceBasedateActionDate: "action_date",
+	}
+)
+
+// RecurrenceInfo describes how a task repeats. Status is pending until the
+// trigger creates the next occurrence, and processed afterwards.
+type RecurrenceInfo struct {
+	Status    string `json:"status"`
+	Trigger   string `json:"trigger"`
+	Factor    int    `json:"factor"`
+	Timeframe string `json:"timeframe"`
+	Basedate  string `json:"basedate"`
+	Summary   string `json:"summary"`
+	NextTask  int    `json:"next_task_id,omitempty"`
+}
+
+type RecurrenceRequest struct {
+	TaskID    int    `json:"task_id"`
+	Disable   bool   `json:"disable"`
+	Trigger   string `json:"trigger"`
+	Factor    int    `json:"factor"`
+	Timeframe string `json:"timeframe"`
+	Basedate  string `json:"basedate"`
+}
+
+type RecurrenceResponse struct {
+	TaskID     int             `json:"task_id"`
+	Change     string          `json:"change"`
+	Recurrence *RecurrenceInfo `json:"recurrence"`
+	Warnings   []string        `json:"warnings,omitempty"`
+}
+
+// RecurrenceHandler turns a task's recurrence on, changes it or turns it
+// off.
+type RecurrenceHandler struct {
+	authManager *auth.AuthManager
+	config      *models.UserConfig
+}
+
+func NewRecurrenceHandler(authManager *auth.AuthManager, config *models.UserConfig) *RecurrenceHandler {
+	return &RecurrenceHandler{
+		authManager: authManager,
+		config:      config,
+	}
+}
+
+func (h *RecurrenceHandler) Preview(ctx context.Context, params map[string]interface{}, userID string) (string, error) {
+	return preview(ctx, h, params, userID)
+}
+
+func (h *RecurrenceHandler) Handle(ctx context.Context, params map[string]interface{}, userID string) (*models.MCPResponse, error) {
+	return handle(ctx, h, params, userID)
+}
+
+// plan works out the task's new recurrence. Settings req leaves out keep
+// their current values, or Kanboard's form defaults when the task does not
+// recur yet. The change's Apply fills in its Response.
+func (h *RecurrenceHandler) plan(ctx context.Context, params map[string]interface{}, userID string) (*change, error) {
+	var req RecurrenceRequest
+	if err := parseWriteRequest(params, &req, "recurrence"); err != nil {
+		return nil, err
+	}
+	if req.TaskID <= 0 {
+		return nil, fmt.Errorf("task_id is required")
+	}
+
+	client, err := authenticatedClient(h.authManager, h.config, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := client.GetTask(ctx, req.TaskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+	if err := checkProjectAccess(ctx, client, task.ProjectID, false); err != nil {
+		return nil, err
+	}
+
+	current := api.TaskRecurrence{
+		Status:    task.RecurrenceStatus,
+		Trigger:   task.RecurrenceTrigger,
+		Factor:    task.RecurrenceFactor,
+		Timeframe: task.RecurrenceTimeframe,
+		Basedate:  task.RecurrenceBasedate,
+	}
+	response := &RecurrenceResponse{TaskID: task.ID}
+	label := fmt.Sprintf("task #%d %q", task.ID, task.Title)
+
+	var recurrence api.TaskRecurrence
+	if req.Disable {
+		if current.Status == models.RecurrenceStatusNone {
+			return nil, fmt.Errorf("%s does not recur", label)
+		}
+		recurrence = current
+		recurrence.Status = models.RecurrenceStatusNone
+		response.Change = fmt.Sprintf("Stop %s from recurring", label)
+	} else {
+		if recurrence, err = h.recurrence(req, current); err != nil {
+			return nil, err
+		}
+		summary := recurrenceSummary(recurrence)
+		switch {
+		case current.Status == models.RecurrenceStatusNone:
+			response.Change = fmt.Sprintf("Make %s recur: %s", label, summary)
+		case current.Status == models.RecurrenceStatusPending && recurrence == current:
+			return nil, fmt.Errorf("%s already recurs: %s", label, summary)
+		default:
+			response.Change = fmt.Sprintf("Change how %s recurs: %s", label, summary)
+		}
+
+		if recurrence.Basedate == models.RecurrenceBasedateDueDate && task.DateDue.Time.IsZero() {
+			response.Warnings = append(response.Warnings, fmt.Sprintf("%s has no due date, so its copies will have none either; use basedate action_date to date them from when the trigger happens", label))
+		}
+		if current.Status == models.RecurrenceStatusProcessed && task.RecurrenceChild > 0 {
+			response.Warnings = append(response.Warnings, fmt.Sprintf("%s already created its next occurrence, task #%d, which recurs on its own; recurring again here will create a second series", label, task.RecurrenceChild))
+		}
+	}
+
+	return &change{
+		Description: response.Change,
+		Warnings:    response.Warnings,
+		Response:    response,
+		Apply: func(ctx context.Context) error {
+			if err := client.SetTaskRecurrence(ctx, task.ProjectID, task.ID, recurrence); err != nil {
+				return fmt.Errorf("failed to set recurrence: %w", err)
+			}
+			updated, err := client.GetTask(ctx, task.ID)
+			if err != nil {
+				return fmt.Errorf("recurrence set but the task could not be read back: %w", err)
+			}
+			response.Recurrence = recurrenceInfo(updated)
+			return nil
+		},
+	}, nil
+}
+
+// recurrence applies req's settings to current. Kanboard has no weekly
+// timeframe, so weeks are turned into days; without a factor, weeks count
+// the current factor, so "weeks" on a task recurring every 2 days makes it
+// every 2 weeks.
+func (h *RecurrenceHandler) recurrence(req RecurrenceRequest, current api.TaskRecurrence) (api.TaskRecurrence, error) {
+	recurrence := current
+	if current.Status == models.RecurrenceStatusNone {
+		recurrence = api.TaskRecurrence{
+			Trigger:   models.RecurrenceTriggerFirstColumn,
+			Factor:    1,
+			Timeframe: models.RecurrenceTimeframeDays,
+			Basedate:  models.RecurrenceBasedateDueDate,
+		}
+	}
+	recurrence.Status = models.RecurrenceStatusPending
+
+	if req.Trigger != "" {
+		trigger, err := recurrenceValue(recurrenceTriggerNames, "trigger", req.Trigger)
+		if err != nil {
+			return recurrence, err
+		}
+		recurrence.Trigger = trigger
+	}
+	if req.Basedate != "" {
+		basedate, err := recurrenceValue(recurrenceBasedateNames, "basedate", req.Basedate)
+		if err != nil {
+			return recurrence, err
+		}
+		recurrence.Basedate = basedate
+	}
+
+	if req.Factor != 0 {
+		if req.Factor < 1 || req.Factor > maxRecurrenceFactor {
+			return recurrence, fmt.Errorf("invalid factor %d: must be between 1 and %d", req.Factor, maxRecurrenceFactor)
+		}
+		recurrence.Factor = req.Factor
+	}
+	switch timeframe := strings.ToLower(strings.TrimSpace(req.Timeframe)); timeframe {
+	case "":
+	case "weeks":
+		if recurrence.Factor > maxRecurrenceFactor/7 {
+			return recurrence, fmt.Errorf("invalid factor %d: must be between 1 and %d weeks", recurrence.Factor, maxRecurrenceFactor/7)
+		}
+		recurrence.Timeframe = models.RecurrenceTimeframeDays
+		recurrence.Factor *= 7
+	default:
+		value, err := recurrenceValue(recurrenceTimeframeNames, "timeframe", timeframe)
+		if err != nil {
+			return recurrence, fmt.Errorf("invalid timeframe %q: must be days, weeks, months or years", timeframe)
+		}
+		recurrence.Timeframe = value
+	}
+
+	return recurrence, nil
+}
+
+// recurrenceValue looks a setting's name up in names.
+func recurrenceValue(names map[int]string, setting, name string) (int, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	valid := make([]string, 0, len(names))
+	for value := 0; value < len(names); value++ {
+		if names[value] == name {
+			return value, nil
+		}
+		valid = append(valid, names[value])
+	}
+	return 0, fmt.Errorf("invalid %s %q: must be %s or %s", setting, name, strings.Join(valid[:len(valid)-1], ", "), valid[len(valid)-1])
+}
+
+// recurrenceInfo describes task's recurrence, or returns nil when it does
+// not recur.
+func recurrenceInfo(task *models.Task) *RecurrenceInfo {
+	if task.RecurrenceStatus == models.RecurrenceStatusNone {
+		return nil
+	}
+	recurrence := api.TaskRecurrence{
+		Status:    task.RecurrenceStatus,
+		Trigger:   task.RecurrenceTrigger,
+		Factor:    task.RecurrenceFactor,
+		Timeframe: task.RecurrenceTimeframe,
+		Basedate:  task.RecurrenceBasedate,
+	}
+	return &RecurrenceInfo{
+		Status:    recurrenceStatusNames[recurrence.Status],
+		Trigger:   recurrenceTriggerNames[recurrence.Trigger],
+		Factor:    recurrence.Factor,
+		Timeframe: recurrenceTimeframeNames[recurrence.Timeframe],
+		Basedate:  recurrenceBasedateNames[recurrence.Basedate],
+		Summary:   recurrenceSummary(recurrence),
+		NextTask:  task.RecurrenceChild,
+	}
+}
+
+// recurrenceSummary says in a sentence what Kanboard will do, e.g. "when the
+// task is closed, a copy is created due 1 month after this task's due date".
+func recurrenceSummary(recurrence api.TaskRecurrence) string {
+	var trigger string
+	switch recurrence.Trigger {
+	case models.RecurrenceTriggerFirstColumn:
+		trigger = "when the task is moved out of the first column"
+	case models.RecurrenceTriggerLastColumn:
+		trigger = "when the task is moved to the last column"
+	default:
+		trigger = "when the task is closed"
+	}
+
+	unit := strings.TrimSuffix(recurrenceTimeframeNames[recurrence.Timeframe], "s")
+	if recurrence.Factor != 1 {
+		unit += "s"
+	}
+
+	base := "this task's due date"
+	if recurrence.Basedate == models.RecurrenceBasedateActionDate {
+		base = "that moment"
+	}
+
+	return fmt.Sprintf("%s, a copy is created due %d %s after %s", trigger, recurrence.Factor, unit, base)
+}
@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/auth"
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+	"github.com/tech-arch1tect/kan-mcp/internal/templates"
+)
+
+type StandupRequest struct {
+	ProjectIDs []string `json:"project_ids"`
+	Format     string   `json:"format"`
+}
+
+// Standup is the calling user's standup report: what they completed since
+// the start of the previous working day, what they are on today and what is
+// blocked.
+type Standup struct {
+	Name         string        `json:"name"`
+	Date         string        `json:"date"`
+	Since        string        `json:"since"`
+	Timezone     string        `json:"timezone"`
+	DoneCount    int           `json:"done_count"`
+	TodayCount   int           `json:"today_count"`
+	BlockedCount int           `json:"blocked_count"`
+	Done         []StandupItem `json:"done"`
+	Today        []StandupItem `json:"today"`
+	Blocked      []StandupItem `json:"blocked"`
+	Warnings     []string      `json:"warnings,omitempty"`
+}
+
+type StandupItem struct {
+	TaskID      string   `json:"task_id"`
+	Title       string   `json:"title"`
+	Project     string   `json:"project"`
+	Column      string   `json:"column,omitempty"`
+	DueDate     string   `json:"due_date,omitempty"`
+	CompletedAt string   `json:"completed_at,omitempty"`
+	Overdue     bool     `json:"overdue,omitempty"`
+	DueToday    bool     `json:"due_today,omitempty"`
+	BlockedBy   []string `json:"blocked_by,omitempty"`
+	URL         string   `json:"url"`
+}
+
+type StandupHandler struct {
+	authManager *auth.AuthManager
+	config      *models.UserConfig
+}
+
+func NewStandupHandler(authManager *auth.AuthManager, config *models.UserConfig) *StandupHandler {
+	return &StandupHandler{
+		authManager: authManager,
+		config:      config,
+	}
+}
+
+// Handle returns the standup report as text rendered from the standup
+// template, or as JSON with format 'json'.
+func (h *StandupHandler) Handle(ctx context.Context, params map[string]interface{}, userID string) (*models.MCPResponse, error) {
+	var req StandupRequest
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal params: %w", err)
+		}
+		if err := json.Unmarshal(data, &req); err != nil {
+			return nil, fmt.Errorf("failed to parse standup request: %w", err)
+		}
+	}
+	if req.Format == "" {
+		req.Format = "text"
+	}
+	if req.Format != "text" && req.Format != "json" {
+		return nil, fmt.Errorf("invalid format %q: must be text or json", req.Format)
+	}
+
+	standup, err := h.Generate(ctx, userID, req.ProjectIDs, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var text string
+	if req.Format == "json" {
+		data, err := json.MarshalIndent(standup, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal standup: %w", err)
+		}
+		text = string(data)
+	} else {
+		text, err = h.config.Templates.Render(templates.Standup, standup)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render standup: %w", err)
+		}
+	}
+
+	return &models.MCPResponse{
+		Content: []models.MCPContent{
+			{
+				Type: "text",
+				Text: text,
+			},
+		},
+	}, nil
+}
+
+// Generate builds the user's standup report for the day containing now.
+// Today lists open tasks that have been started, are overdue or are due
+// today; tasks waiting on another open task are listed under Blocked instead.
+func (h *StandupHandler) Generate(ctx context.Context, userID string, projectIDs []string, now time.Time) (*Standup, error) {
+	client, err := authenticatedClient(h.authManager, h.config, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	me, err := client.GetMe(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user info: %w", err)
+	}
+
+	// Closed tasks are fetched from a few days back to cover weekends and
+	// holidays before the previous working day.
+	tasksData, err := NewTaskService(h.authManager, h.config).Load(ctx, userID, TasksRequest{
+		ProjectIDs:     projectIDs,
+		AssigneeIDs:    []string{strconv.Itoa(me.ID)},
+		StatusFilter:   "all",
+		IncludeOverdue: true,
+		SortBy:         "due_date",
+		ModifiedSince:  now.AddDate(0, 0, -15).Format("2006-01-02"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks data: %w", err)
+	}
+
+	location := tasksData.Location
+	today := startOfDay(now.In(location))
+	tomorrow := today.AddDate(0, 0, 1)
+	since := today.AddDate(0, 0, -1)
+	for i := 0; i < 14 && !h.config.Calendar.IsWorkDay(since); i++ {
+		since = since.AddDate(0, 0, -1)
+	}
+
+	name := me.Name
+	if name == "" {
+		name = me.Username
+	}
+	standup := &Standup{
+		Name:     name,
+		Date:     today.Format("2006-01-02"),
+		Since:    since.Format("2006-01-02"),
+		Timezone: location.String(),
+		Done:     []StandupItem{},
+		Today:    []StandupItem{},
+		Blocked:  []StandupItem{},
+		Warnings: projectWarningMessages(tasksData.Warnings),
+	}
+
+	tasks := NewTasksHandler(h.authManager, h.config)
+	var open []TaskDetail
+	var openIDs []int
+	type completion struct {
+		item StandupItem
+		at   time.Time
+	}
+	var completions []completion
+
+	for _, task := range tasksData.Tasks {
+		if tasks.isTaskCompleted(task) {
+			completed := task.Dates.Completed
+			if completed == "" {
+				completed = task.Dates.Moved
+			}
+			at, err := time.Parse(time.RFC3339, completed)
+			if err != nil || at.Before(since) {
+				continue
+			}
+			item := standupItem(task)
+			item.CompletedAt = completed
+			completions = append(completions, completion{item: item, at: at})
+			continue
+		}
+		open = append(open, task)
+		if id, err := strconv.Atoi(task.ID); err == nil {
+			openIDs = append(openIDs, id)
+		}
+	}
+
+	sort.Slice(completions, func(i, j int) bool {
+		return completions[i].at.Before(completions[j].at)
+	})
+	for _, c := range completions {
+		standup.Done = append(standup.Done, c.item)
+	}
+
+	links, err := client.GetTaskLinks(ctx, openIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task links: %w", err)
+	}
+
+	for _, task := range open {
+		item := standupItem(task)
+		item.Overdue = task.IsOverdue
+		if due, err := time.Parse(time.RFC3339, task.Dates.Due); err == nil && !task.IsOverdue {
+			item.DueToday = due.Before(tomorrow)
+		}
+
+		taskID, _ := strconv.Atoi(task.ID)
+		for _, link := range links[taskID] {
+			if strings.EqualFold(link.Label, "is blocked by") && bool(link.IsActive) {
+				item.BlockedBy = append(item.BlockedBy, fmt.Sprintf("#%d", link.OppositeTaskID))
+			}
+		}
+
+		switch {
+		case len(item.BlockedBy) > 0:
+			standup.Blocked = append(standup.Blocked, item)
+		case item.Overdue || item.DueToday || task.Dates.Started != "":
+			standup.Today = append(standup.Today, item)
+		}
+	}
+
+	standup.DoneCount, standup.TodayCount, standup.BlockedCount = len(standup.Done), len(standup.Today), len(standup.Blocked)
+	standup.Done = standup.Done[:min(len(standup.Done), maxDigestItems)]
+	standup.Today = standup.Today[:min(len(standup.Today), maxDigestItems)]
+	standup.Blocked = standup.Blocked[:min(len(standup.Blocked), maxDigestItems)]
+
+	return standup, nil
+}
+
+func standupItem(task TaskDetail) StandupItem {
+	return StandupItem{
+		TaskID:  task.ID,
+		Title:   task.Title,
+		Project: task.Project.Name,
+		Column:  task.Status.Column,
+		DueDate: task.Dates.Due,
+		URL:     task.URL,
+	}
+}
@@ -0,0 +1,373 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/api"
+	"github.com/tech-arch1tect/kan-mcp/internal/auth"
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+)
+
+const (
+	defaultStatusWindow = time.Hour
+	maxStatusWindow     = 7 * 24 * time.Hour
+	statusProbeTimeout  = 5 * time.Second
+	recentErrorsShown   = 5
+)
+
+// ToolCallLog is the audit log the status tool reads recent calls from.
+type ToolCallLog interface {
+	Calls(since time.Time) ([]models.ToolCall, error)
+}
+
+type StatusHandler struct {
+	authManager *auth.AuthManager
+	config      *models.UserConfig
+	toolCalls   ToolCallLog
+}
+
+type StatusRequest struct {
+	Window string `json:"window"`
+}
+
+type ServerStatusResponse struct {
+	GeneratedAt string             `json:"generated_at"`
+	Status      string             `json:"status"`
+	Problems    []string           `json:"problems,omitempty"`
+	Instances   []InstanceStatus   `json:"instances"`
+	Cache       []CacheStatus      `json:"cache"`
+	RecentCalls RecentCallsSummary `json:"recent_calls"`
+}
+
+type InstanceStatus struct {
+	URL                 string `json:"url"`
+	RegisteredUsers     int    `json:"registered_users"`
+	Yours               bool   `json:"yours,omitempty"`
+	Reachable           bool   `json:"reachable"`
+	Authenticated       *bool  `json:"authenticated,omitempty"`
+	LatencyMs           int64  `json:"latency_ms"`
+	KanboardVersion     string `json:"kanboard_version,omitempty"`
+	Error               string `json:"error,omitempty"`
+	Circuit             string `json:"circuit"`
+	ConsecutiveFailures int    `json:"consecutive_failures,omitempty"`
+	UnavailableSince    string `json:"unavailable_since,omitempty"`
+	RetryAt             string `json:"retry_at,omitempty"`
+	NewConnections      int64  `json:"new_connections"`
+	ReusedConnections   int64  `json:"reused_connections"`
+}
+
+type CacheStatus struct {
+	Instance         string `json:"instance"`
+	Entries          int    `json:"entries"`
+	OldestAgeSeconds int64  `json:"oldest_age_seconds"`
+}
+
+type RecentCallsSummary struct {
+	Window           string           `json:"window"`
+	Calls            int              `json:"calls"`
+	Errors           int              `json:"errors"`
+	ByTool           []ToolCallCounts `json:"by_tool,omitempty"`
+	YourRecentErrors []RecentError    `json:"your_recent_errors,omitempty"`
+}
+
+type ToolCallCounts struct {
+	Tool          string `json:"tool"`
+	Calls         int    `json:"calls"`
+	Errors        int    `json:"errors"`
+	AvgDurationMs int64  `json:"avg_duration_ms"`
+}
+
+type RecentError struct {
+	Time      string `json:"time"`
+	Tool      string `json:"tool"`
+	RequestID string `json:"request_id,omitempty"`
+	Message   string `json:"message"`
+}
+
+func NewStatusHandler(authManager *auth.AuthManager, config *models.UserConfig, toolCalls ToolCallLog) *StatusHandler {
+	return &StatusHandler{
+		authManager: authManager,
+		config:      config,
+		toolCalls:   toolCalls,
+	}
+}
+
+func (h *StatusHandler) Handle(ctx context.Context, params map[string]interface{}, userID string) (*models.MCPResponse, error) {
+	var req StatusRequest
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal params: %w", err)
+		}
+		if err := json.Unmarshal(data, &req); err != nil {
+			return nil, fmt.Errorf("failed to parse status request: %w", err)
+		}
+	}
+
+	window := defaultStatusWindow
+	if req.Window != "" {
+		parsed, err := time.ParseDuration(req.Window)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("invalid window %q: use a duration such as 30m or 24h", req.Window)
+		}
+		window = min(parsed, maxStatusWindow)
+	}
+
+	user, err := h.authManager.GetUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("authentication failed: %w", err)
+	}
+
+	users, err := h.authManager.ListUsers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	response := &ServerStatusResponse{
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+		Instances:   h.instanceStatuses(ctx, user, users),
+		Cache:       cacheStatuses(),
+	}
+
+	response.RecentCalls, err = h.recentCalls(userID, window)
+	if err != nil {
+		return nil, err
+	}
+
+	response.Problems = statusProblems(response)
+	if expiry := h.authManager.CredentialExpiry(user); !expiry.IsZero() && time.Now().After(expiry) {
+		response.Problems = append(response.Problems, fmt.Sprintf("Your registration's credentials expired on %s; ask the operator to renew them with: ./kan-mcp user renew -user-id <id>", expiry.Format("2006-01-02")))
+	}
+	response.Status = "ok"
+	if len(response.Problems) > 0 {
+		response.Status = "degraded"
+	}
+
+	responseJSON, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal status response: %w", err)
+	}
+
+	return &models.MCPResponse{
+		Content: []models.MCPContent{
+			{
+				Type: "text",
+				Text: string(responseJSON),
+			},
+		},
+	}, nil
+}
+
+// instanceStatuses probes every Kanboard URL users are registered against.
+// The caller's own instance is probed with their credentials; the others
+// anonymously, where an authentication error still proves reachability.
+func (h *StatusHandler) instanceStatuses(ctx context.Context, caller *models.User, users []*models.User) []InstanceStatus {
+	callerURL := caller.KanboardURL
+	if callerURL == "" {
+		callerURL = h.config.DefaultKanboardURL
+	}
+
+	counts := map[string]int{h.config.DefaultKanboardURL: 0}
+	for _, user := range users {
+		kanboardURL := user.KanboardURL
+		if kanboardURL == "" {
+			kanboardURL = h.config.DefaultKanboardURL
+		}
+		counts[kanboardURL]++
+	}
+
+	urls := make([]string, 0, len(counts))
+	for kanboardURL := range counts {
+		urls = append(urls, kanboardURL)
+	}
+	slices.Sort(urls)
+
+	breakers := make(map[string]api.BreakerStatus)
+	for _, status := range api.BreakerStats() {
+		breakers[status.Instance] = status
+	}
+
+	statuses := make([]InstanceStatus, len(urls))
+	var wg sync.WaitGroup
+	for i, kanboardURL := range urls {
+		status := &statuses[i]
+		status.URL = kanboardURL
+		status.RegisteredUsers = counts[kanboardURL]
+		status.Yours = kanboardURL == callerURL
+
+		status.Circuit = "disabled"
+		if breaker, ok := breakers[api.InstanceKey(kanboardURL)]; ok {
+			status.Circuit = breaker.State
+			status.ConsecutiveFailures = breaker.ConsecutiveFailures
+			if !breaker.UnavailableSince.IsZero() {
+				status.UnavailableSince = breaker.UnavailableSince.UTC().Format(time.RFC3339)
+			}
+			if !breaker.RetryAt.IsZero() {
+				status.RetryAt = breaker.RetryAt.UTC().Format(time.RFC3339)
+			}
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h.probe(ctx, status, caller)
+		}()
+	}
+	wg.Wait()
+
+	transports := make(map[string]api.ConnectionStats)
+	for _, stats := range api.TransportStats() {
+		transports[stats.Instance] = stats
+	}
+	for i := range statuses {
+		if transport, ok := transports[api.InstanceKey(statuses[i].URL)]; ok {
+			statuses[i].NewConnections = transport.NewConnections
+			statuses[i].ReusedConnections = transport.ReusedConnections
+		}
+	}
+
+	return statuses
+}
+
+func (h *StatusHandler) probe(ctx context.Context, status *InstanceStatus, caller *models.User) {
+	ctx, cancel := context.WithTimeout(ctx, statusProbeTimeout)
+	defer cancel()
+
+	var client *api.Client
+	if status.Yours {
+		token, err := kanboardToken(h.authManager, h.config, caller)
+		if err != nil {
+			status.Error = fmt.Sprintf("failed to decrypt your token: %v", err)
+			return
+		}
+		client = newKanboardClient(h.config, status.URL, caller, token)
+	} else {
+		client = api.NewClient(status.URL, "", "", api.Options{
+			AuthHeader: h.config.KanboardAuthHeader,
+			Timeout:    statusProbeTimeout,
+			TLSConfig:  h.config.KanboardTLS,
+		})
+	}
+
+	start := time.Now()
+	version, err := client.GetVersion(ctx)
+	status.LatencyMs = time.Since(start).Milliseconds()
+
+	switch {
+	case err == nil:
+		status.Reachable = true
+		status.KanboardVersion = version
+	case errors.Is(err, api.ErrUnauthorized):
+		status.Reachable = true
+		if !status.Yours {
+			return
+		}
+		status.Error = err.Error()
+	default:
+		status.Error = err.Error()
+		return
+	}
+
+	if status.Yours {
+		authenticated := err == nil
+		status.Authenticated = &authenticated
+	}
+}
+
+func cacheStatuses() []CacheStatus {
+	stats := api.CacheStats()
+	statuses := make([]CacheStatus, 0, len(stats))
+	for _, entry := range stats {
+		statuses = append(statuses, CacheStatus{
+			Instance:         entry.Instance,
+			Entries:          entry.Entries,
+			OldestAgeSeconds: int64(entry.OldestAge.Seconds()),
+		})
+	}
+	return statuses
+}
+
+// recentCalls summarises the audit log over window. Error messages are only
+// included for the caller's own calls.
+func (h *StatusHandler) recentCalls(userID string, window time.Duration) (RecentCallsSummary, error) {
+	summary := RecentCallsSummary{Window: window.String()}
+	if h.toolCalls == nil {
+		return summary, nil
+	}
+
+	calls, err := h.toolCalls.Calls(time.Now().Add(-window))
+	if err != nil {
+		return summary, fmt.Errorf("failed to read recent tool calls: %w", err)
+	}
+
+	byTool := make(map[string]*ToolCallCounts)
+	totalMs := make(map[string]int64)
+	for _, call := range calls {
+		summary.Calls++
+		counts, ok := byTool[call.Tool]
+		if !ok {
+			counts = &ToolCallCounts{Tool: call.Tool}
+			byTool[call.Tool] = counts
+		}
+		counts.Calls++
+		totalMs[call.Tool] += call.DurationMs
+
+		if !call.Error {
+			continue
+		}
+		summary.Errors++
+		counts.Errors++
+		if call.UserID == userID {
+			summary.YourRecentErrors = append(summary.YourRecentErrors, RecentError{
+				Time:      call.Time.UTC().Format(time.RFC3339),
+				Tool:      call.Tool,
+				RequestID: call.RequestID,
+				Message:   call.ErrorMessage,
+			})
+		}
+	}
+
+	for tool, counts := range byTool {
+		counts.AvgDurationMs = totalMs[tool] / int64(counts.Calls)
+		summary.ByTool = append(summary.ByTool, *counts)
+	}
+	slices.SortFunc(summary.ByTool, func(a, b ToolCallCounts) int {
+		return strings.Compare(a.Tool, b.Tool)
+	})
+
+	if len(summary.YourRecentErrors) > recentErrorsShown {
+		summary.YourRecentErrors = summary.YourRecentErrors[len(summary.YourRecentErrors)-recentErrorsShown:]
+	}
+	slices.Reverse(summary.YourRecentErrors)
+
+	return summary, nil
+}
+
+// statusProblems turns the raw status into sentences an agent can relay.
+func statusProblems(response *ServerStatusResponse) []string {
+	var problems []string
+
+	for _, instance := range response.Instances {
+		switch {
+		case instance.Circuit == "open":
+			problems = append(problems, fmt.Sprintf("Calls to %s are suspended until %s after %d consecutive failures.", instance.URL, instance.RetryAt, instance.ConsecutiveFailures))
+		case !instance.Reachable:
+			problems = append(problems, fmt.Sprintf("Kanboard at %s is unreachable: %s", instance.URL, instance.Error))
+		case instance.Authenticated != nil && !*instance.Authenticated:
+			problems = append(problems, fmt.Sprintf("Kanboard at %s rejected your stored credentials (%s); ask the operator to store a new token with: ./kan-mcp user update -user-id <id> -token", instance.URL, instance.Error))
+		}
+	}
+
+	recent := response.RecentCalls
+	if recent.Calls >= 5 && recent.Errors*5 >= recent.Calls {
+		problems = append(problems, fmt.Sprintf("%d of %d tool calls failed in the last %s.", recent.Errors, recent.Calls, recent.Window))
+	}
+
+	return problems
+}
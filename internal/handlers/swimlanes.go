@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/api"
+	"github.com/tech-arch1tect/kan-mcp/internal/auth"
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+)
+
+type SwimlaneRequest struct {
+	ProjectID   int     `json:"project_id"`
+	Action      string  `json:"action"`
+	Swimlane    string  `json:"swimlane"`
+	Name        string  `json:"name"`
+	Description *string `json:"description"`
+	Position    int     `json:"position"`
+}
+
+type SwimlaneResponse struct {
+	ProjectID  int               `json:"project_id"`
+	Change     string            `json:"change"`
+	SwimlaneID int               `json:"swimlane_id"`
+	Swimlanes  []models.Swimlane `json:"swimlanes"`
+}
+
+// SwimlaneHandler creates, renames, reorders, enables and disables a
+// project's swimlanes.
+type SwimlaneHandler struct {
+	authManager *auth.AuthManager
+	config      *models.UserConfig
+}
+
+func NewSwimlaneHandler(authManager *auth.AuthManager, config *models.UserConfig) *SwimlaneHandler {
+	return &SwimlaneHandler{
+		authManager: authManager,
+		config:      config,
+	}
+}
+
+func (h *SwimlaneHandler) Preview(ctx context.Context, params map[string]interface{}, userID string) (string, error) {
+	return preview(ctx, h, params, userID)
+}
+
+func (h *SwimlaneHandler) Handle(ctx context.Context, params map[string]interface{}, userID string) (*models.MCPResponse, error) {
+	return handle(ctx, h, params, userID)
+}
+
+// plan validates the request against the project's current swimlanes. The
+// change's Apply fills in its Response, including the swimlanes as
+// they are afterwards.
+func (h *SwimlaneHandler) plan(ctx context.Context, params map[string]interface{}, userID string) (*change, error) {
+	var req SwimlaneRequest
+	if err := parseWriteRequest(params, &req, "swimlane"); err != nil {
+		return nil, err
+	}
+	if req.ProjectID <= 0 {
+		return nil, fmt.Errorf("project_id is required")
+	}
+	req.Name = strings.TrimSpace(req.Name)
+
+	client, err := authenticatedClient(h.authManager, h.config, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkProjectAccess(ctx, client, req.ProjectID, true); err != nil {
+		return nil, err
+	}
+
+	swimlanes, err := client.GetSwimlanes(ctx, req.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get swimlanes: %w", err)
+	}
+	project := projectLabel(ctx, client, req.ProjectID)
+	response := &SwimlaneResponse{ProjectID: req.ProjectID}
+
+	apply, err := h.action(client, req, swimlanes, project, response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &change{
+		Description: response.Change,
+		Response:    response,
+		Apply: func(ctx context.Context) error {
+			if err := apply(ctx); err != nil {
+				return err
+			}
+			swimlanes, err := client.GetSwimlanes(ctx, req.ProjectID)
+			if err != nil {
+				return fmt.Errorf("swimlanes changed but could not be read back: %w", err)
+			}
+			response.Swimlanes = sortedSwimlanes(swimlanes)
+			return nil
+		},
+	}, nil
+}
+
+// action checks req against swimlanes, sets response's Change and
+// SwimlaneID, and returns the call that makes the change.
+func (h *SwimlaneHandler) action(client *api.Client, req SwimlaneRequest, swimlanes []models.Swimlane, project string, response *SwimlaneResponse) (func(context.Context) error, error) {
+	if req.Action == "create" {
+		if err := checkSwimlaneName(swimlanes, req.Name, 0); err != nil {
+			return nil, err
+		}
+		if req.Position < 0 || req.Position > len(swimlanes)+1 {
+			return nil, fmt.Errorf("invalid position %d: must be between 1 and %d", req.Position, len(swimlanes)+1)
+		}
+		description := ""
+		if req.Description != nil {
+			description = *req.Description
+		}
+
+		response.Change = fmt.Sprintf("Create swimlane %q in %s", req.Name, project)
+		if req.Position > 0 {
+			response.Change += fmt.Sprintf(" at position %d", req.Position)
+		}
+		return func(ctx context.Context) error {
+			id, err := client.AddSwimlane(ctx, req.ProjectID, req.Name, description)
+			if err != nil {
+				return fmt.Errorf("failed to create swimlane: %w", err)
+			}
+			response.SwimlaneID = id
+			if req.Position > 0 && req.Position <= len(swimlanes) {
+				if err := client.ChangeSwimlanePosition(ctx, req.ProjectID, id, req.Position); err != nil {
+					return fmt.Errorf("swimlane %d was created but could not be moved: %w", id, err)
+				}
+			}
+			return nil
+		}, nil
+	}
+
+	swimlane, err := findSwimlane(swimlanes, req.Swimlane)
+	if err != nil {
+		return nil, err
+	}
+	response.SwimlaneID = swimlane.ID
+	label := fmt.Sprintf("swimlane %q (ID %d)", swimlane.Name, swimlane.ID)
+
+	var apply func(ctx context.Context) error
+	switch req.Action {
+	case "rename":
+		if req.Name == "" && req.Description == nil {
+			return nil, fmt.Errorf("rename needs a new name or description")
+		}
+		if req.Name == "" {
+			req.Name = swimlane.Name
+		}
+		if err := checkSwimlaneName(swimlanes, req.Name, swimlane.ID); err != nil {
+			return nil, err
+		}
+		if req.Name != swimlane.Name {
+			response.Change = fmt.Sprintf("Rename %s in %s to %q", label, project, req.Name)
+		} else {
+			response.Change = fmt.Sprintf("Change the description of %s in %s", label, project)
+		}
+		apply = func(ctx context.Context) error {
+			return client.UpdateSwimlane(ctx, req.ProjectID, swimlane.ID, req.Name, req.Description)
+		}
+	case "move":
+		if req.Position < 1 || req.Position > len(swimlanes) {
+			return nil, fmt.Errorf("invalid position %d: must be between 1 and %d", req.Position, len(swimlanes))
+		}
+		response.Change = fmt.Sprintf("Move %s in %s to position %d", label, project, req.Position)
+		apply = func(ctx context.Context) error {
+			return client.ChangeSwimlanePosition(ctx, req.ProjectID, swimlane.ID, req.Position)
+		}
+	case "enable", "disable":
+		active := req.Action == "enable"
+		if bool(swimlane.IsActive) == active {
+			return nil, fmt.Errorf("%s is already %sd", label, req.Action)
+		}
+		if !active && activeSwimlanes(swimlanes) == 1 {
+			return nil, fmt.Errorf("%s is the project's only active swimlane and cannot be disabled", label)
+		}
+		if active {
+			response.Change = fmt.Sprintf("Enable %s in %s", label, project)
+		} else {
+			response.Change = fmt.Sprintf("Disable %s in %s; its tasks will be hidden from the board until it is enabled again", label, project)
+		}
+		apply = func(ctx context.Context) error {
+			return client.SetSwimlaneActive(ctx, req.ProjectID, swimlane.ID, active)
+		}
+	default:
+		return nil, fmt.Errorf("invalid action %q: must be create, rename, move, enable or disable", req.Action)
+	}
+
+	return func(ctx context.Context) error {
+		if err := apply(ctx); err != nil {
+			return fmt.Errorf("failed to %s swimlane: %w", req.Action, err)
+		}
+		return nil
+	}, nil
+}
+
+// findSwimlane looks a swimlane up by ID or name.
+func findSwimlane(swimlanes []models.Swimlane, swimlane string) (models.Swimlane, error) {
+	if strings.TrimSpace(swimlane) == "" {
+		return models.Swimlane{}, fmt.Errorf("swimlane is required")
+	}
+	for _, s := range swimlanes {
+		if matchesNameOrID(swimlane, s.ID, s.Name) {
+			return s, nil
+		}
+	}
+
+	names := make([]string, len(swimlanes))
+	for i, s := range sortedSwimlanes(swimlanes) {
+		names[i] = s.Name
+	}
+	return models.Swimlane{}, fmt.Errorf("no swimlane %q in project: swimlanes are %s", swimlane, strings.Join(names, ", "))
+}
+
+// checkSwimlaneName refuses empty names and names already used by another
+// swimlane in the project, which Kanboard would reject without saying why.
+func checkSwimlaneName(swimlanes []models.Swimlane, name string, swimlaneID int) error {
+	if name == "" {
+		return fmt.Errorf("name is required")
+	}
+	for _, s := range swimlanes {
+		if s.ID != swimlaneID && strings.EqualFold(s.Name, name) {
+			return fmt.Errorf("the project already has a swimlane named %q", s.Name)
+		}
+	}
+	return nil
+}
+
+func activeSwimlanes(swimlanes []models.Swimlane) int {
+	count := 0
+	for _, s := range swimlanes {
+		if s.IsActive {
+			count++
+		}
+	}
+	return count
+}
+
+func sortedSwimlanes(swimlanes []models.Swimlane) []models.Swimlane {
+	sorted := append([]models.Swimlane(nil), swimlanes...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Position < sorted[j].Position
+	})
+	return sorted
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/api"
+	"github.com/tech-arch1tect/kan-mcp/internal/auth"
+	"github.com/tech-arch1tect/kan-mcp/internal/calendar"
+	"github.com/tech-arch1tect/kan-mcp/internal/dateparse"
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+)
+
+// TaskService loads, filters and sorts a user's tasks for the tools built on
+// them. Unlike kanboard_tasks it returns TaskDetails directly and only limits
+// them when asked to, so callers neither re-parse JSON nor inherit the tool's
+// page and response-size caps.
+type TaskService struct {
+	authManager *auth.AuthManager
+	config      *models.UserConfig
+}
+
+func NewTaskService(authManager *auth.AuthManager, config *models.UserConfig) *TaskService {
+	return &TaskService{
+		authManager: authManager,
+		config:      config,
+	}
+}
+
+// TaskSet holds the tasks matching a request along with what callers need to
+// work with them further.
+type TaskSet struct {
+	// Tasks are the matching tasks in the requested order.
+	Tasks    []TaskDetail
+	Warnings []ProjectWarning
+	Location *time.Location
+	// DueDateRange is the request's due-date range with phrases such as
+	// "next friday" resolved to dates.
+	DueDateRange *DateRange
+	// Client is authenticated as the user, for follow-up Kanboard calls.
+	Client *api.Client
+}
+
+// Load returns the tasks matching req. A positive Limit keeps only the first
+// Limit tasks; zero returns them all. Subtask progress and comments are
+// attached to the returned tasks when req asks for them.
+func (s *TaskService) Load(ctx context.Context, userID string, req TasksRequest) (*TaskSet, error) {
+	sortKeys, err := prepareTasksRequest(&req)
+	if err != nil {
+		return nil, err
+	}
+
+	set, h, err := s.load(ctx, userID, req, sortKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Limit > 0 && len(set.Tasks) > req.Limit {
+		set.Tasks = set.Tasks[:req.Limit]
+	}
+
+	if req.IncludeSubtasks {
+		if err := h.attachSubtaskProgress(ctx, set.Client, set.Tasks); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.IncludeComments {
+		if err := h.attachComments(ctx, set.Client, set.Tasks); err != nil {
+			return nil, err
+		}
+	}
+
+	return set, nil
+}
+
+// Stream hands the tasks matching req to fn a project at a time, as each
+// project loads, so callers can reduce them without holding every task at
+// once. Tasks arrive unsorted, fn is never called concurrently, and req's
+// Limit, SortBy, IncludeSubtasks and IncludeComments are ignored. The
+// returned set has no Tasks.
+func (s *TaskService) Stream(ctx context.Context, userID string, req TasksRequest, fn func([]TaskDetail)) (*TaskSet, error) {
+	if _, err := prepareTasksRequest(&req); err != nil {
+		return nil, err
+	}
+
+	set, _, err := s.collect(ctx, userID, req, fn)
+	return set, err
+}
+
+// load fetches and sorts every matching task. It also returns the
+// TasksHandler whose helpers built them, set up with the user's timezone.
+func (s *TaskService) load(ctx context.Context, userID string, req TasksRequest, sortKeys []taskComparator) (*TaskSet, *TasksHandler, error) {
+	var tasks []TaskDetail
+	set, h, err := s.collect(ctx, userID, req, func(projectTasks []TaskDetail) {
+		tasks = append(tasks, projectTasks...)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	h.sortTasks(tasks, sortKeys)
+	set.Tasks = tasks
+	return set, h, nil
+}
+
+// collect passes every matching task to emit, a project at a time.
+func (s *TaskService) collect(ctx context.Context, userID string, req TasksRequest, emit func([]TaskDetail)) (*TaskSet, *TasksHandler, error) {
+	user, err := s.authManager.AuthenticateUser(userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("authentication failed: %w", err)
+	}
+
+	token, err := kanboardToken(s.authManager, s.config, user)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decrypt token: %w", err)
+	}
+
+	kanboardURL := user.KanboardURL
+	if kanboardURL == "" {
+		kanboardURL = s.config.DefaultKanboardURL
+	}
+
+	client := newKanboardClient(s.config, kanboardURL, user, token)
+	h := NewTasksHandler(s.authManager, s.config)
+	h.location = userLocation(ctx, client, s.config)
+	h.userID = userID
+
+	if req.DueDateRange != nil {
+		resolved, err := resolveDateRange(*req.DueDateRange, time.Now().In(h.location), s.config.Calendar)
+		if err != nil {
+			return nil, nil, err
+		}
+		req.DueDateRange = resolved
+	}
+
+	projects, err := h.getFilteredProjects(ctx, client, req.ProjectIDs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get projects: %w", err)
+	}
+
+	warnings, err := h.collectTasks(ctx, client, projects, kanboardURL, req, emit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to collect tasks: %w", err)
+	}
+
+	return &TaskSet{
+		Warnings:     warnings,
+		Location:     h.location,
+		DueDateRange: req.DueDateRange,
+		Client:       client,
+	}, h, nil
+}
+
+// resolveDateRange turns the start and end of dateRange, each a date or a
+// phrase understood by dateparse, into YYYY-MM-DD dates. A phrase naming
+// several days, such as "next week", starts the range on its first day and
+// ends it on its last.
+func resolveDateRange(dateRange DateRange, now time.Time, cal *calendar.Calendar) (*DateRange, error) {
+	var resolved DateRange
+	if dateRange.Start != "" {
+		r, err := dateparse.Parse(dateRange.Start, now, cal)
+		if err != nil {
+			return nil, fmt.Errorf("invalid due_date_start: %w", err)
+		}
+		resolved.Start = r.Start.Format("2006-01-02")
+	}
+	if dateRange.End != "" {
+		r, err := dateparse.Parse(dateRange.End, now, cal)
+		if err != nil {
+			return nil, fmt.Errorf("invalid due_date_end: %w", err)
+		}
+		resolved.End = r.End.Format("2006-01-02")
+	}
+	if resolved.Start != "" && resolved.End != "" && resolved.End < resolved.Start {
+		return nil, fmt.Errorf("due_date_end (%s) is before due_date_start (%s)", resolved.End, resolved.Start)
+	}
+	return &resolved, nil
+}
+
+// prepareTasksRequest compiles req's text query and parses its sort order.
+func prepareTasksRequest(req *TasksRequest) ([]taskComparator, error) {
+	if req.Query != "" {
+		pattern := req.Query
+		if !req.QueryRegex {
+			pattern = regexp.QuoteMeta(pattern)
+		}
+		compiled, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid query pattern: %w", err)
+		}
+		req.queryPattern = compiled
+	}
+
+	return parseSortKeys(req.SortBy)
+}
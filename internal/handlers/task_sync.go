@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/api"
+	"github.com/tech-arch1tect/kan-mcp/internal/auth"
+	"github.com/tech-arch1tect/kan-mcp/internal/logging"
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+	"github.com/tech-arch1tect/kan-mcp/internal/storage"
+)
+
+// fullSyncInterval is how often a project's snapshot is rebuilt rather than
+// updated, which is the only way tasks deleted in Kanboard drop out of it.
+const fullSyncInterval = 24 * time.Hour
+
+// TaskSyncStore is where TaskSyncer keeps its snapshots.
+type TaskSyncStore interface {
+	SyncState(ctx context.Context, userID, kanboardURL string, projectID int) (storage.SyncState, error)
+	ReplaceProject(ctx context.Context, userID, kanboardURL string, projectID int, tasks []models.Task, syncedAt time.Time) error
+	UpdateProject(ctx context.Context, userID, kanboardURL string, projectID int, tasks []models.Task, syncedAt time.Time) error
+	PruneProjects(ctx context.Context, userID, kanboardURL string, projectIDs []int) error
+	PruneUsers(ctx context.Context, userIDs []string) error
+}
+
+// TaskSyncer keeps a local snapshot of every registered user's closed tasks,
+// so tools covering long time ranges need not fetch each one from Kanboard on
+// every call. After a project's first full load only tasks modified since the
+// previous sync are fetched.
+type TaskSyncer struct {
+	authManager *auth.AuthManager
+	config      *models.UserConfig
+	store       TaskSyncStore
+	interval    time.Duration
+}
+
+func NewTaskSyncer(authManager *auth.AuthManager, config *models.UserConfig, store TaskSyncStore, interval time.Duration) *TaskSyncer {
+	return &TaskSyncer{
+		authManager: authManager,
+		config:      config,
+		store:       store,
+		interval:    interval,
+	}
+}
+
+// Run syncs every user straight away and then once per interval until ctx is
+// done.
+func (s *TaskSyncer) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.SyncAll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SyncAll brings every user's snapshot up to date and drops those of users
+// who are no longer registered. Failures are logged and retried next time.
+func (s *TaskSyncer) SyncAll(ctx context.Context) {
+	started := time.Now()
+
+	users, err := s.authManager.ListUsers()
+	if err != nil {
+		logging.Warnf("Task sync: failed to list users: %v", err)
+		return
+	}
+
+	userIDs := make([]string, 0, len(users))
+	synced := 0
+	for _, user := range users {
+		if ctx.Err() != nil {
+			return
+		}
+		userIDs = append(userIDs, user.UserID)
+
+		if err := s.syncUser(ctx, user.UserID); err != nil {
+			logging.Warnf("Task sync for user %s failed: %v", logging.UserID(user.UserID), err)
+			continue
+		}
+		synced++
+	}
+
+	if err := s.store.PruneUsers(ctx, userIDs); err != nil {
+		logging.Warnf("Task sync: failed to prune removed users: %v", err)
+	}
+
+	logging.Debugf("Task sync: synced %d of %d users in %s", synced, len(users), time.Since(started))
+}
+
+func (s *TaskSyncer) syncUser(ctx context.Context, userID string) error {
+	user, err := s.authManager.AuthenticateUser(userID)
+	if err != nil {
+		return fmt.Errorf("authentication failed: %w", err)
+	}
+
+	token, err := kanboardToken(s.authManager, s.config, user)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt token: %w", err)
+	}
+
+	kanboardURL := user.KanboardURL
+	if kanboardURL == "" {
+		kanboardURL = s.config.DefaultKanboardURL
+	}
+
+	client := newKanboardClient(s.config, kanboardURL, user, token)
+	projects, err := NewTasksHandler(s.authManager, s.config).getFilteredProjects(ctx, client, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get projects: %w", err)
+	}
+
+	projectIDs := make([]int, 0, len(projects))
+	var firstErr error
+	for _, project := range projects {
+		projectIDs = append(projectIDs, project.ID)
+		if err := s.syncProject(ctx, client, userID, kanboardURL, project.ID); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("project %d: %w", project.ID, err)
+		}
+	}
+
+	if err := s.store.PruneProjects(ctx, userID, kanboardURL, projectIDs); err != nil {
+		return fmt.Errorf("failed to prune projects: %w", err)
+	}
+
+	return firstErr
+}
+
+// syncProject rebuilds a project's snapshot from its closed tasks when it has
+// none or the last rebuild is over fullSyncInterval old, and otherwise fetches
+// the tasks modified since the last sync. Kanboard only filters on whole days,
+// so the incremental fetch starts a day early.
+func (s *TaskSyncer) syncProject(ctx context.Context, client *api.Client, userID, kanboardURL string, projectID int) error {
+	state, err := s.store.SyncState(ctx, userID, kanboardURL, projectID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if now.Sub(state.FullSyncedAt) > fullSyncInterval {
+		tasks, err := client.GetTasksByStatus(ctx, projectID, api.TaskStatusClosed)
+		if err != nil {
+			return fmt.Errorf("failed to fetch closed tasks: %w", err)
+		}
+		return s.store.ReplaceProject(ctx, userID, kanboardURL, projectID, tasks, now)
+	}
+
+	since := state.SyncedAt.AddDate(0, 0, -1).Format("2006-01-02")
+	tasks, err := client.SearchTasks(ctx, projectID, "modified:>="+since)
+	if err != nil {
+		return fmt.Errorf("failed to fetch tasks modified since %s: %w", since, err)
+	}
+	return s.store.UpdateProject(ctx, userID, kanboardURL, projectID, tasks, now)
+}
@@ -0,0 +1,310 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/api"
+	"github.com/tech-arch1tect/kan-mcp/internal/auth"
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+)
+
+type TaskTransferRequest struct {
+	TaskID    int    `json:"task_id"`
+	ProjectID int    `json:"project_id"`
+	Column    string `json:"column"`
+	Swimlane  string `json:"swimlane"`
+	Category  string `json:"category"`
+	Assignee  string `json:"assignee"`
+}
+
+type TaskTransferResponse struct {
+	TaskID     int          `json:"task_id"`
+	NewTaskID  int          `json:"new_task_id,omitempty"`
+	ProjectID  int          `json:"project_id"`
+	Change     string       `json:"change"`
+	ColumnID   int          `json:"column_id"`
+	SwimlaneID int          `json:"swimlane_id"`
+	CategoryID int          `json:"category_id"`
+	OwnerID    int          `json:"owner_id"`
+	Task       *models.Task `json:"task"`
+}
+
+// TaskTransferHandler copies or moves a task to another project, mapping its
+// column, swimlane, category and assignee onto the destination's.
+type TaskTransferHandler struct {
+	authManager *auth.AuthManager
+	config      *models.UserConfig
+	move        bool
+}
+
+// NewTaskTransferHandler returns a handler that moves tasks when move is set
+// and duplicates them otherwise.
+func NewTaskTransferHandler(authManager *auth.AuthManager, config *models.UserConfig, move bool) *TaskTransferHandler {
+	return &TaskTransferHandler{
+		authManager: authManager,
+		config:      config,
+		move:        move,
+	}
+}
+
+func (h *TaskTransferHandler) Preview(ctx context.Context, params map[string]interface{}, userID string) (string, error) {
+	return preview(ctx, h, params, userID)
+}
+
+func (h *TaskTransferHandler) Handle(ctx context.Context, params map[string]interface{}, userID string) (*models.MCPResponse, error) {
+	return handle(ctx, h, params, userID)
+}
+
+// plan works out where in the destination project the task goes. The
+// change's Apply fills in its Response.
+func (h *TaskTransferHandler) plan(ctx context.Context, params map[string]interface{}, userID string) (*change, error) {
+	var req TaskTransferRequest
+	if err := parseWriteRequest(params, &req, "task"); err != nil {
+		return nil, err
+	}
+	if req.TaskID <= 0 {
+		return nil, fmt.Errorf("task_id is required")
+	}
+	if req.ProjectID <= 0 {
+		return nil, fmt.Errorf("project_id is required")
+	}
+	req.Column = strings.TrimSpace(req.Column)
+	req.Swimlane = strings.TrimSpace(req.Swimlane)
+	req.Category = strings.TrimSpace(req.Category)
+	req.Assignee = strings.TrimSpace(req.Assignee)
+
+	client, err := authenticatedClient(h.authManager, h.config, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := client.GetTask(ctx, req.TaskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+	if h.move && task.ProjectID == req.ProjectID {
+		return nil, fmt.Errorf("task #%d is already in project %d", task.ID, req.ProjectID)
+	}
+	if err := checkProjectAccess(ctx, client, task.ProjectID, false); err != nil {
+		return nil, err
+	}
+	if req.ProjectID != task.ProjectID {
+		if err := checkProjectAccess(ctx, client, req.ProjectID, false); err != nil {
+			return nil, err
+		}
+	}
+
+	placement, details, err := h.placement(ctx, client, req, task)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &TaskTransferResponse{
+		TaskID:     task.ID,
+		ProjectID:  req.ProjectID,
+		ColumnID:   placement.ColumnID,
+		SwimlaneID: placement.SwimlaneID,
+		CategoryID: placement.CategoryID,
+		OwnerID:    placement.OwnerID,
+	}
+	verb, from := "Copy", ""
+	if h.move {
+		verb = "Move"
+		from = " from " + projectLabel(ctx, client, task.ProjectID)
+	}
+	response.Change = fmt.Sprintf("%s task #%d %q%s to %s: %s", verb, task.ID, task.Title, from, projectLabel(ctx, client, req.ProjectID), strings.Join(details, ", "))
+
+	return &change{
+		Description: response.Change,
+		Response:    response,
+		Apply: func(ctx context.Context) error {
+			taskID := task.ID
+			if h.move {
+				if err := client.MoveTaskToProject(ctx, task.ID, task.ProjectID, placement); err != nil {
+					return fmt.Errorf("failed to move task: %w", err)
+				}
+			} else {
+				id, err := client.DuplicateTaskToProject(ctx, task.ID, placement)
+				if err != nil {
+					return fmt.Errorf("failed to duplicate task: %w", err)
+				}
+				response.NewTaskID = id
+				taskID = id
+			}
+
+			updated, err := client.GetTask(ctx, taskID)
+			if err != nil {
+				return fmt.Errorf("task %d was written but could not be read back: %w", taskID, err)
+			}
+			response.Task = updated
+			return nil
+		},
+	}, nil
+}
+
+// placement maps the task's column, swimlane, category and assignee onto the
+// destination project. Anything req does not name goes to the destination's
+// item of the same name, falling back to its first column and first active
+// swimlane, no category, and no assignee when the current one is not a member
+// there. details describe each choice for the change sentence.
+func (h *TaskTransferHandler) placement(ctx context.Context, client *api.Client, req TaskTransferRequest, task *models.Task) (api.TaskPlacement, []string, error) {
+	placement := api.TaskPlacement{ProjectID: req.ProjectID}
+	details := make([]string, 0, 4)
+
+	column, err := h.column(ctx, client, req, task)
+	if err != nil {
+		return placement, nil, err
+	}
+	placement.ColumnID = column.ID
+	details = append(details, fmt.Sprintf("column %q", column.Title))
+
+	swimlane, err := h.swimlane(ctx, client, req, task)
+	if err != nil {
+		return placement, nil, err
+	}
+	placement.SwimlaneID = swimlane.ID
+	details = append(details, fmt.Sprintf("swimlane %q", swimlane.Name))
+
+	categories, err := client.GetCategories(ctx, req.ProjectID)
+	if err != nil {
+		return placement, nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+	switch {
+	case !isNone(req.Category):
+		category, err := findCategory(categories, req.Category)
+		if err != nil {
+			return placement, nil, err
+		}
+		placement.CategoryID = category.ID
+		details = append(details, fmt.Sprintf("category %q", category.Name))
+	case req.Category == "" && task.CategoryID > 0:
+		current := ""
+		if source, err := client.GetCategories(ctx, task.ProjectID); err == nil {
+			for _, c := range source {
+				if c.ID == task.CategoryID {
+					current = c.Name
+				}
+			}
+		}
+		for _, c := range categories {
+			if current != "" && strings.EqualFold(c.Name, current) {
+				placement.CategoryID = c.ID
+				details = append(details, fmt.Sprintf("category %q", c.Name))
+				break
+			}
+		}
+		if placement.CategoryID == 0 {
+			details = append(details, "no category (the project has none matching the task's)")
+		}
+	default:
+		details = append(details, "no category")
+	}
+
+	users, err := client.GetProjectUsers(ctx, req.ProjectID)
+	if err != nil {
+		return placement, nil, fmt.Errorf("failed to get project members: %w", err)
+	}
+	switch {
+	case !isNone(req.Assignee):
+		for _, u := range users {
+			if matchesNameOrID(req.Assignee, u.ID, u.Username) || matchesNameOrID(req.Assignee, u.ID, u.Name) {
+				placement.OwnerID = u.ID
+				details = append(details, "assigned to "+h.userLabel(client, u))
+				break
+			}
+		}
+		if placement.OwnerID == 0 {
+			return placement, nil, fmt.Errorf("no member %q in project %d to assign the task to", req.Assignee, req.ProjectID)
+		}
+	case req.Assignee == "" && task.OwnerID > 0:
+		for _, u := range users {
+			if u.ID == task.OwnerID {
+				placement.OwnerID = u.ID
+				details = append(details, "still assigned to "+h.userLabel(client, u))
+			}
+		}
+		if placement.OwnerID == 0 {
+			details = append(details, "unassigned (the assignee is not a member of the project)")
+		}
+	default:
+		details = append(details, "unassigned")
+	}
+
+	return placement, details, nil
+}
+
+// column picks the destination column: the one req names, else the one with
+// the title of the task's current column, else the first.
+func (h *TaskTransferHandler) column(ctx context.Context, client *api.Client, req TaskTransferRequest, task *models.Task) (models.Column, error) {
+	columns, err := client.GetColumns(ctx, req.ProjectID)
+	if err != nil {
+		return models.Column{}, fmt.Errorf("failed to get columns: %w", err)
+	}
+	if req.Column != "" {
+		return findColumn(columns, req.Column)
+	}
+	if len(columns) == 0 {
+		return models.Column{}, fmt.Errorf("project %d has no columns", req.ProjectID)
+	}
+
+	if source, err := client.GetColumns(ctx, task.ProjectID); err == nil {
+		for _, c := range source {
+			if c.ID != task.ColumnID {
+				continue
+			}
+			for _, d := range columns {
+				if strings.EqualFold(d.Title, c.Title) {
+					return d, nil
+				}
+			}
+		}
+	}
+	return sortedColumns(columns)[0], nil
+}
+
+// swimlane picks the destination swimlane: the one req names, else the
+// active one with the name of the task's current swimlane, else the first
+// active one.
+func (h *TaskTransferHandler) swimlane(ctx context.Context, client *api.Client, req TaskTransferRequest, task *models.Task) (models.Swimlane, error) {
+	swimlanes, err := client.GetSwimlanes(ctx, req.ProjectID)
+	if err != nil {
+		return models.Swimlane{}, fmt.Errorf("failed to get swimlanes: %w", err)
+	}
+	if req.Swimlane != "" {
+		swimlane, err := findSwimlane(swimlanes, req.Swimlane)
+		if err != nil {
+			return models.Swimlane{}, err
+		}
+		if !swimlane.IsActive {
+			return models.Swimlane{}, fmt.Errorf("swimlane %q is disabled in project %d", swimlane.Name, req.ProjectID)
+		}
+		return swimlane, nil
+	}
+
+	if source, err := client.GetSwimlanes(ctx, task.ProjectID); err == nil {
+		for _, s := range source {
+			if s.ID != task.SwimlaneID {
+				continue
+			}
+			for _, d := range swimlanes {
+				if bool(d.IsActive) && strings.EqualFold(d.Name, s.Name) {
+					return d, nil
+				}
+			}
+		}
+	}
+	for _, s := range sortedSwimlanes(swimlanes) {
+		if s.IsActive {
+			return s, nil
+		}
+	}
+	return models.Swimlane{}, fmt.Errorf("project %d has no active swimlanes", req.ProjectID)
+}
+
+func (h *TaskTransferHandler) userLabel(client *api.Client, user models.KanboardUser) string {
+	_, name := displayUser(h.config, client, strconv.Itoa(user.ID), user.Username, user.Name)
+	return fmt.Sprintf("%q", name)
+}
@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -11,18 +14,27 @@ import (
 
 	"github.com/tech-arch1tect/kan-mcp/internal/api"
 	"github.com/tech-arch1tect/kan-mcp/internal/auth"
+	"github.com/tech-arch1tect/kan-mcp/internal/logging"
 	"github.com/tech-arch1tect/kan-mcp/internal/models"
+	"github.com/tech-arch1tect/kan-mcp/internal/tracing"
+	"github.com/tech-arch1tect/kan-mcp/internal/workpool"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const (
 	MaxResponseSize     = 200 * 1024
 	WarningResponseSize = 150 * 1024
 	MaxTasksHardLimit   = 100
+
+	commentPreviewLength = 200
 )
 
 type TasksHandler struct {
 	authManager *auth.AuthManager
 	config      *models.UserConfig
+	location    *time.Location
+	// userID identifies whose task snapshot closed tasks may be read from.
+	userID string
 }
 
 func NewTasksHandler(authManager *auth.AuthManager, config *models.UserConfig) *TasksHandler {
@@ -39,9 +51,24 @@ type TasksRequest struct {
 	DueDateRange        *DateRange `json:"due_date_range"`
 	IncludeOverdue      bool       `json:"include_overdue"`
 	IncludeTimeTracking bool       `json:"include_time_tracking"`
+	IncludeSubtasks     bool       `json:"include_subtasks"`
+	IncludeComments     bool       `json:"include_comments"`
 	SortBy              string     `json:"sort_by"`
 	Limit               int        `json:"limit"`
 	SummaryMode         bool       `json:"summary_mode"`
+	ModifiedSince       string     `json:"modified_since"`
+	Column              string     `json:"column"`
+	Swimlane            string     `json:"swimlane"`
+	Category            string     `json:"category"`
+	Color               string     `json:"color"`
+	Tag                 string     `json:"tag"`
+	Query               string     `json:"query"`
+	QueryRegex          bool       `json:"query_regex"`
+	Cursor              string     `json:"cursor"`
+	ExportFormat        string     `json:"export_format"`
+	View                string     `json:"view"`
+
+	queryPattern *regexp.Regexp
 }
 
 type DateRange struct {
@@ -50,31 +77,54 @@ type DateRange struct {
 }
 
 type TaskDetail struct {
-	ID           string        `json:"id"`
-	Title        string        `json:"title"`
-	Description  string        `json:"description"`
-	Project      ProjectInfo   `json:"project"`
-	Assignee     *UserInfo     `json:"assignee"`
-	Status       TaskStatus    `json:"status"`
-	Dates        TaskDates     `json:"dates"`
-	TimeTracking *TimeTracking `json:"time_tracking,omitempty"`
-	Priority     string        `json:"priority"`
-	Category     string        `json:"category"`
-	Tags         []string      `json:"tags"`
-	URL          string        `json:"url"`
-	IsOverdue    bool          `json:"is_overdue"`
-	DaysUntilDue *int          `json:"days_until_due"`
+	ID            string           `json:"id"`
+	Title         string           `json:"title"`
+	Description   string           `json:"description"`
+	Project       ProjectInfo      `json:"project"`
+	Assignee      *UserInfo        `json:"assignee"`
+	Status        TaskStatus       `json:"status"`
+	Dates         TaskDates        `json:"dates"`
+	TimeTracking  *TimeTracking    `json:"time_tracking,omitempty"`
+	Recurrence    *RecurrenceInfo  `json:"recurrence,omitempty"`
+	Priority      string           `json:"priority"`
+	Score         int              `json:"score"`
+	Category      string           `json:"category"`
+	Tags          []string         `json:"tags"`
+	URL           string           `json:"url"`
+	IsOverdue     bool             `json:"is_overdue"`
+	DaysUntilDue  *int             `json:"days_until_due"`
+	Subtasks      *SubtaskProgress `json:"subtasks,omitempty"`
+	CommentsCount *int             `json:"comments_count,omitempty"`
+	LatestComment *CommentPreview  `json:"latest_comment,omitempty"`
+
+	// priorityRank is the task's priority as a fraction of its project's
+	// priority range, so priorities compare across projects.
+	priorityRank float64
 }
 
 type TaskSummary struct {
-	ID           string      `json:"id"`
-	Title        string      `json:"title"`
-	Project      ProjectInfo `json:"project"`
-	Assignee     *UserInfo   `json:"assignee,omitempty"`
-	Status       string      `json:"status"`
-	DueDate      string      `json:"due_date,omitempty"`
-	IsOverdue    bool        `json:"is_overdue"`
-	DaysUntilDue *int        `json:"days_until_due,omitempty"`
+	ID            string           `json:"id"`
+	Title         string           `json:"title"`
+	Project       ProjectInfo      `json:"project"`
+	Assignee      *UserInfo        `json:"assignee,omitempty"`
+	Status        string           `json:"status"`
+	DueDate       string           `json:"due_date,omitempty"`
+	IsOverdue     bool             `json:"is_overdue"`
+	DaysUntilDue  *int             `json:"days_until_due,omitempty"`
+	Subtasks      *SubtaskProgress `json:"subtasks,omitempty"`
+	CommentsCount *int             `json:"comments_count,omitempty"`
+}
+
+type SubtaskProgress struct {
+	Total      int `json:"total"`
+	Done       int `json:"done"`
+	InProgress int `json:"in_progress"`
+}
+
+type CommentPreview struct {
+	Author string `json:"author"`
+	Date   string `json:"date"`
+	Text   string `json:"text"`
 }
 
 type ProjectInfo struct {
@@ -85,13 +135,16 @@ type ProjectInfo struct {
 type TaskStatus struct {
 	Column   string `json:"column"`
 	Swimlane string `json:"swimlane"`
+	IsActive bool   `json:"is_active"`
 }
 
 type TaskDates struct {
-	Created  string `json:"created"`
-	Due      string `json:"due"`
-	Modified string `json:"modified"`
-	Started  string `json:"started"`
+	Created   string `json:"created"`
+	Due       string `json:"due"`
+	Modified  string `json:"modified"`
+	Started   string `json:"started"`
+	Moved     string `json:"moved"`
+	Completed string `json:"completed"`
 }
 
 type TimeTracking struct {
@@ -108,23 +161,53 @@ type TasksSummary struct {
 }
 
 type TasksResponse struct {
-	Summary       TasksSummary  `json:"summary"`
-	Tasks         []TaskDetail  `json:"tasks,omitempty"`
-	TaskSummaries []TaskSummary `json:"task_summaries,omitempty"`
-	Truncated     bool          `json:"truncated,omitempty"`
-	TruncatedAt   int           `json:"truncated_at,omitempty"`
-	ResponseSize  int           `json:"response_size_bytes,omitempty"`
+	Summary       TasksSummary     `json:"summary"`
+	Tasks         []TaskDetail     `json:"tasks,omitempty"`
+	TaskSummaries []TaskSummary    `json:"task_summaries,omitempty"`
+	TotalMatching int              `json:"total_matching"`
+	NextCursor    string           `json:"next_cursor,omitempty"`
+	Timezone      string           `json:"timezone"`
+	DueDateRange  *DateRange       `json:"due_date_range,omitempty"`
+	Warnings      []ProjectWarning `json:"warnings,omitempty"`
+	ResponseSize  int              `json:"response_size_bytes,omitempty"`
+}
+
+// ProjectWarning reports a project whose tasks could not be loaded; the
+// response still contains the tasks of every other project.
+type ProjectWarning struct {
+	ProjectID   string `json:"project_id"`
+	ProjectName string `json:"project_name"`
+	Error       string `json:"error"`
 }
 
-func (h *TasksHandler) Handle(params map[string]interface{}, userID string) (*models.MCPResponse, error) {
+func projectWarningMessages(warnings []ProjectWarning) []string {
+	var messages []string
+	for _, warning := range warnings {
+		messages = append(messages, fmt.Sprintf("Project %s (#%s) was skipped because its tasks could not be loaded: %s", warning.ProjectName, warning.ProjectID, warning.Error))
+	}
+	return messages
+}
+
+func (h *TasksHandler) Handle(ctx context.Context, params map[string]interface{}, userID string) (*models.MCPResponse, error) {
 	var req TasksRequest
 	req.StatusFilter = "active"
 	req.IncludeOverdue = false
 	req.IncludeTimeTracking = true
+	req.IncludeSubtasks = true
 	req.SortBy = "due_date"
 	req.Limit = 20
 	req.SummaryMode = true
 
+	// A saved view replaces the defaults; parameters passed alongside it
+	// still take precedence.
+	if name, ok := params["view"].(string); ok && name != "" {
+		view, err := h.authManager.View(userID, name)
+		if err != nil {
+			return nil, err
+		}
+		applyView(&req, view)
+	}
+
 	if params != nil {
 		data, err := json.Marshal(params)
 		if err != nil {
@@ -135,75 +218,107 @@ func (h *TasksHandler) Handle(params map[string]interface{}, userID string) (*mo
 		}
 	}
 
-	if req.Limit > MaxTasksHardLimit {
-		req.Limit = MaxTasksHardLimit
+	if err := validateExportFormat(req.ExportFormat); err != nil {
+		return nil, err
 	}
 
-	if req.SummaryMode && req.Limit > MaxTasksHardLimit*2 {
-		req.Limit = MaxTasksHardLimit * 2
+	if req.Limit < 1 {
+		req.Limit = 1
 	}
 
-	user, err := h.authManager.AuthenticateUser(userID)
-	if err != nil {
-		return nil, fmt.Errorf("authentication failed: %w", err)
+	maxLimit := MaxTasksHardLimit
+	if req.SummaryMode {
+		maxLimit = MaxTasksHardLimit * 2
 	}
-
-	token, err := h.authManager.GetDecryptedToken(user)
-	if err != nil {
-		return nil, fmt.Errorf("failed to decrypt token: %w", err)
+	if req.Limit > maxLimit {
+		req.Limit = maxLimit
 	}
 
-	kanboardURL := user.KanboardURL
-	if kanboardURL == "" {
-		kanboardURL = h.config.DefaultKanboardURL
+	sortKeys, err := prepareTasksRequest(&req)
+	if err != nil {
+		return nil, err
 	}
 
-	client := api.NewClient(kanboardURL, user.KanboardUsername, token)
-
-	projects, err := h.getFilteredProjects(client, req.ProjectIDs)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get projects: %w", err)
+	queryKey := h.queryFingerprint(req)
+	offset := 0
+	if req.Cursor != "" {
+		decoded, err := decodeCursor(req.Cursor, queryKey)
+		if err != nil {
+			return nil, err
+		}
+		offset = decoded
 	}
 
-	tasks, err := h.collectTasks(client, projects, kanboardURL, req.IncludeTimeTracking)
+	set, _, err := NewTaskService(h.authManager, h.config).load(ctx, userID, req, sortKeys)
 	if err != nil {
-		return nil, fmt.Errorf("failed to collect tasks: %w", err)
+		return nil, err
 	}
-
-	filteredTasks := h.filterTasks(tasks, req)
-	sortedTasks := h.sortTasks(filteredTasks, req.SortBy)
+	h.location = set.Location
+	client := set.Client
+	sortedTasks := set.Tasks
 
 	summary := h.calculateTasksSummary(sortedTasks)
 
-	var response TasksResponse
+	// Exports ignore paging and hand back every matching task as a file.
+	if req.ExportFormat != "" {
+		warnings := projectWarningMessages(set.Warnings)
+		exported := sortedTasks
+		if len(exported) > maxExportTasks {
+			exported = exported[:maxExportTasks]
+			warnings = append(warnings, fmt.Sprintf("Only the first %d of %d matching tasks were exported; narrow the filters to export the rest", maxExportTasks, len(sortedTasks)))
+		}
+		return exportResponse(req.ExportFormat, "kanboard-tasks", []exportTable{tasksExportTable(exported)}, summary, warnings)
+	}
+
+	if offset > len(sortedTasks) {
+		offset = len(sortedTasks)
+	}
+	remaining := sortedTasks[offset:]
+
+	response := TasksResponse{
+		Summary:       summary,
+		TotalMatching: len(sortedTasks),
+		Timezone:      h.location.String(),
+		DueDateRange:  set.DueDateRange,
+		Warnings:      set.Warnings,
+	}
 	var responseJSON []byte
 
-	if req.SummaryMode {
+	pageSize := min(req.Limit, len(remaining))
+	if !req.SummaryMode {
+		pageSize = h.pageFittingResponseSize(response, remaining, pageSize)
+	}
 
-		taskSummaries := h.createTaskSummaries(sortedTasks, req.Limit)
-		response = TasksResponse{
-			Summary:       summary,
-			TaskSummaries: taskSummaries,
+	page := remaining[:pageSize]
+	if req.IncludeSubtasks {
+		if err := h.attachSubtaskProgress(ctx, client, page); err != nil {
+			return nil, err
 		}
-	} else {
+	}
 
-		finalTasks, truncated, truncatedAt := h.applyResponseSizeLimits(sortedTasks, req.Limit)
-		response = TasksResponse{
-			Summary:     summary,
-			Tasks:       finalTasks,
-			Truncated:   truncated,
-			TruncatedAt: truncatedAt,
+	if req.IncludeComments {
+		if err := h.attachComments(ctx, client, page); err != nil {
+			return nil, err
 		}
 	}
 
+	if req.SummaryMode {
+		response.TaskSummaries = h.createTaskSummaries(page, pageSize)
+	} else {
+		response.Tasks = page
+	}
+
+	if offset+pageSize < len(sortedTasks) {
+		response.NextCursor = encodeCursor(offset+pageSize, queryKey)
+	}
+
 	responseJSON, err = json.MarshalIndent(response, "", "  ")
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal tasks response: %w", err)
 	}
 
-	response.ResponseSize = len(responseJSON)
-	if response.ResponseSize > WarningResponseSize {
-		responseJSON, _ = json.MarshalIndent(response, "", "  ")
+	if size := len(responseJSON); size > WarningResponseSize {
+		responseJSON = appendResponseSize(responseJSON, size)
 	}
 
 	return &models.MCPResponse{
@@ -217,12 +332,15 @@ func (h *TasksHandler) Handle(params map[string]interface{}, userID string) (*mo
 }
 
 type ProjectData struct {
-	ID   int
-	Name string
+	ID              int
+	Name            string
+	PriorityStart   int
+	PriorityEnd     int
+	PriorityDefault int
 }
 
-func (h *TasksHandler) getFilteredProjects(client *api.Client, projectIDs []string) ([]ProjectData, error) {
-	projectsRaw, err := client.GetMyProjectsRaw()
+func (h *TasksHandler) getFilteredProjects(ctx context.Context, client *api.Client, projectIDs []string) ([]ProjectData, error) {
+	projectsRaw, err := client.GetMyProjectsRaw(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -250,8 +368,11 @@ func (h *TasksHandler) getFilteredProjects(client *api.Client, projectIDs []stri
 		}
 
 		project := ProjectData{
-			ID:   int(rawProject["id"].(float64)),
-			Name: h.getString(rawProject, "name"),
+			ID:              int(rawProject["id"].(float64)),
+			Name:            h.getString(rawProject, "name"),
+			PriorityStart:   h.getInt(rawProject, "priority_start", 0),
+			PriorityEnd:     h.getInt(rawProject, "priority_end", 3),
+			PriorityDefault: h.getInt(rawProject, "priority_default", 0),
 		}
 		projects = append(projects, project)
 	}
@@ -259,90 +380,171 @@ func (h *TasksHandler) getFilteredProjects(client *api.Client, projectIDs []stri
 	return projects, nil
 }
 
-func (h *TasksHandler) collectTasks(client *api.Client, projects []ProjectData, baseURL string, includeTimeTracking bool) ([]TaskDetail, error) {
-	var allTasks []TaskDetail
-	var mu sync.Mutex
-	var wg sync.WaitGroup
-	errors := make([]error, 0)
+// collectTasks loads every project's matching tasks on the shared worker
+// pool, handing each project's tasks to emit as soon as they are loaded so
+// callers need not hold every project's tasks at once. emit is never called
+// concurrently. A project that fails is reported as a warning rather than
+// failing the whole call; only when every project fails is an error returned.
+func (h *TasksHandler) collectTasks(ctx context.Context, client *api.Client, projects []ProjectData, baseURL string, req TasksRequest, emit func([]TaskDetail)) ([]ProjectWarning, error) {
+	var emitMu sync.Mutex
 
-	for _, project := range projects {
-		wg.Add(1)
-		go func(proj ProjectData) {
-			defer wg.Done()
+	errs, stats := h.config.WorkPool.Run(ctx, len(projects), func(ctx context.Context, i int) (err error) {
+		ctx, span := tracing.Start(ctx, "project tasks", attribute.Int("kanboard.project_id", projects[i].ID))
+		defer func() { tracing.End(span, err) }()
 
-			projectTasks, err := h.getProjectTasks(client, proj, baseURL, includeTimeTracking)
-			if err != nil {
-				mu.Lock()
-				errors = append(errors, fmt.Errorf("project %d: %w", proj.ID, err))
-				mu.Unlock()
-				return
-			}
+		tasks, err := h.getProjectTasks(ctx, client, projects[i], baseURL, req)
+		if err != nil {
+			return err
+		}
 
-			mu.Lock()
-			allTasks = append(allTasks, projectTasks...)
-			mu.Unlock()
-		}(project)
+		emitMu.Lock()
+		defer emitMu.Unlock()
+		emit(tasks)
+		return nil
+	})
+	logging.DebugfContext(ctx, "Collected tasks from %d projects (%d failed, max queue wait %s) in %s", stats.Jobs, stats.Failed, stats.MaxQueueWait, stats.Elapsed)
+	if err := workpool.Busy(errs); err != nil {
+		return nil, err
 	}
 
-	wg.Wait()
+	var warnings []ProjectWarning
+	var firstErr error
+
+	for i, project := range projects {
+		if errs[i] != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("project %d: %w", project.ID, errs[i])
+			}
+			warnings = append(warnings, ProjectWarning{
+				ProjectID:   fmt.Sprintf("%d", project.ID),
+				ProjectName: project.Name,
+				Error:       errs[i].Error(),
+			})
+		}
+	}
 
-	if len(errors) > 0 {
-		return nil, errors[0]
+	if len(projects) > 0 && len(warnings) == len(projects) {
+		return nil, firstErr
 	}
 
-	return allTasks, nil
+	return warnings, nil
 }
 
-func (h *TasksHandler) getProjectTasks(client *api.Client, project ProjectData, baseURL string, includeTimeTracking bool) ([]TaskDetail, error) {
-	tasks, err := client.GetTasksByProject(project.ID)
-	if err != nil {
-		return nil, err
+// getProjectTasks converts and filters a project's tasks chunk by chunk so
+// only matching tasks are retained, which keeps memory bounded on projects
+// with a long closed-task history.
+func (h *TasksHandler) getProjectTasks(ctx context.Context, client *api.Client, project ProjectData, baseURL string, req TasksRequest) ([]TaskDetail, error) {
+	// Summaries show neither swimlanes nor categories, so they are only
+	// fetched for summaries that filter on them. Users stay, as summaries
+	// name assignees.
+	parts := api.AllBoardParts
+	if req.SummaryMode {
+		parts.Swimlanes = req.Swimlane != ""
+		parts.Categories = req.Category != ""
 	}
 
-	columns, err := client.GetColumns(project.ID)
+	board, err := client.GetProjectBoard(ctx, project.ID, parts)
 	if err != nil {
 		return nil, err
 	}
 
 	columnMap := make(map[int]string)
-	for _, col := range columns {
+	for _, col := range board.Columns {
 		columnMap[col.ID] = col.Title
 	}
 
-	swimlanes, err := client.GetSwimlanes(project.ID)
-	if err != nil {
-		return nil, err
-	}
-
 	swimlaneMap := make(map[int]string)
-	for _, lane := range swimlanes {
+	for _, lane := range board.Swimlanes {
 		swimlaneMap[lane.ID] = lane.Name
 	}
 
-	users, err := client.GetProjectUsers(project.ID)
-	if err != nil {
-		return nil, err
+	categoryMap := make(map[int]string)
+	for _, category := range board.Categories {
+		categoryMap[category.ID] = category.Name
 	}
 
 	userMap := make(map[int]*UserInfo)
-	for _, user := range users {
+	for _, user := range board.Users {
+		id := fmt.Sprintf("%d", user.ID)
+		username, name := displayUser(h.config, client, id, user.Username, user.Name)
 		userMap[user.ID] = &UserInfo{
-			ID:       fmt.Sprintf("%d", user.ID),
-			Username: user.Username,
-			Name:     user.Name,
+			ID:       id,
+			Username: username,
+			Name:     name,
+		}
+	}
+
+	var tagged map[int]bool
+	if req.Tag != "" {
+		tag, err := h.resolveTag(ctx, client, project.ID, req.Tag)
+		if err != nil {
+			return nil, err
+		}
+		tagged, err = client.TaskIDsWithTag(ctx, project.ID, tag)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch tasks tagged %q: %w", tag, err)
 		}
 	}
 
 	var taskDetails []TaskDetail
-	for _, task := range tasks {
-		detail := h.buildTaskDetail(task, project, columnMap, swimlaneMap, userMap, baseURL, includeTimeTracking)
-		taskDetails = append(taskDetails, detail)
+	appendChunk := func(tasks []models.Task) error {
+		for _, task := range tasks {
+			if tagged != nil && !tagged[task.ID] {
+				continue
+			}
+			if !matchesNameOrID(req.Column, task.ColumnID, columnMap[task.ColumnID]) ||
+				!matchesNameOrID(req.Swimlane, task.SwimlaneID, swimlaneMap[task.SwimlaneID]) ||
+				!matchesNameOrID(req.Category, task.CategoryID, categoryMap[task.CategoryID]) {
+				continue
+			}
+			if req.Color != "" && !strings.EqualFold(task.ColorID, req.Color) {
+				continue
+			}
+			if req.queryPattern != nil && !req.queryPattern.MatchString(task.Title) && !req.queryPattern.MatchString(task.Description) {
+				continue
+			}
+			detail := h.buildTaskDetail(task, project, columnMap, swimlaneMap, categoryMap, userMap, baseURL, req.IncludeTimeTracking)
+			if h.shouldIncludeTask(detail, req) {
+				taskDetails = append(taskDetails, detail)
+			}
+		}
+		return nil
+	}
+
+	if err := appendChunk(board.Tasks); err != nil {
+		return nil, err
+	}
+
+	if req.StatusFilter != "active" {
+		query := api.TaskQuery{}
+		if req.ModifiedSince != "" {
+			since, err := time.Parse("2006-01-02", req.ModifiedSince)
+			if err != nil {
+				return nil, fmt.Errorf("invalid modified_since date: %w", err)
+			}
+			query.ModifiedSince = since
+		}
+
+		fromSnapshot := false
+		if snapshot := h.config.TaskSnapshot; snapshot != nil && h.userID != "" {
+			syncedSince := time.Now().Add(-h.config.TaskSnapshotMaxAge)
+			fromSnapshot, err = snapshot.ClosedTasks(ctx, h.userID, baseURL, project.ID, query.ModifiedSince, syncedSince, appendChunk)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read closed tasks from snapshot: %w", err)
+			}
+		}
+
+		if !fromSnapshot {
+			if err := client.StreamClosedTasks(ctx, project.ID, query, appendChunk); err != nil {
+				return nil, fmt.Errorf("failed to fetch closed tasks: %w", err)
+			}
+		}
 	}
 
 	return taskDetails, nil
 }
 
-func (h *TasksHandler) buildTaskDetail(task models.Task, project ProjectData, columnMap map[int]string, swimlaneMap map[int]string, userMap map[int]*UserInfo, baseURL string, includeTimeTracking bool) TaskDetail {
+func (h *TasksHandler) buildTaskDetail(task models.Task, project ProjectData, columnMap map[int]string, swimlaneMap map[int]string, categoryMap map[int]string, userMap map[int]*UserInfo, baseURL string, includeTimeTracking bool) TaskDetail {
 	detail := TaskDetail{
 		ID:          fmt.Sprintf("%d", task.ID),
 		Title:       task.Title,
@@ -354,27 +556,36 @@ func (h *TasksHandler) buildTaskDetail(task models.Task, project ProjectData, co
 		Status: TaskStatus{
 			Column:   columnMap[task.ColumnID],
 			Swimlane: swimlaneMap[task.SwimlaneID],
+			IsActive: bool(task.IsActive),
 		},
-		Priority: "normal",
-		Category: "",
-		URL:      fmt.Sprintf("%s/?controller=TaskViewController&action=show&task_id=%d&project_id=%d", baseURL, task.ID, project.ID),
+		Priority: h.getPriorityString(task.Priority, project),
+		Score:    task.Score,
+
+		priorityRank: h.getPriorityRank(task.Priority, project),
+		Category:     categoryMap[task.CategoryID],
+		Recurrence:   recurrenceInfo(&task),
+		URL:          fmt.Sprintf("%s/?controller=TaskViewController&action=show&task_id=%d&project_id=%d", baseURL, task.ID, project.ID),
 	}
 
 	if task.OwnerID > 0 {
 		if user, exists := userMap[task.OwnerID]; exists {
 			detail.Assignee = user
+		} else {
+			detail.Assignee = &UserInfo{ID: fmt.Sprintf("%d", task.OwnerID)}
 		}
 	}
 
 	detail.Dates = TaskDates{
-		Created:  h.formatKanboardTime(task.DateCreation),
-		Due:      h.formatKanboardTime(task.DateDue),
-		Modified: h.formatKanboardTime(task.DateModified),
-		Started:  h.formatKanboardTime(task.DateStarted),
+		Created:   h.formatKanboardTime(task.DateCreation),
+		Due:       h.formatKanboardTime(task.DateDue),
+		Modified:  h.formatKanboardTime(task.DateModified),
+		Started:   h.formatKanboardTime(task.DateStarted),
+		Moved:     h.formatKanboardTime(task.DateMoved),
+		Completed: h.formatKanboardTime(task.DateCompleted),
 	}
 
 	if !task.DateDue.Time.IsZero() {
-		detail.IsOverdue, detail.DaysUntilDue = h.calculateDueDateInfo(task.DateDue.Time.Format("2006-01-02T15:04:05Z"))
+		detail.IsOverdue, detail.DaysUntilDue = h.calculateDueDateInfo(task.DateDue.Time)
 	}
 
 	if includeTimeTracking {
@@ -388,17 +599,45 @@ func (h *TasksHandler) buildTaskDetail(task models.Task, project ProjectData, co
 	return detail
 }
 
-func (h *TasksHandler) filterTasks(tasks []TaskDetail, req TasksRequest) []TaskDetail {
-	filtered := make([]TaskDetail, 0, len(tasks))
+// matchesNameOrID reports whether a board entity matches a filter given
+// either as its numeric ID or, case-insensitively, as its name. An empty
+// filter matches everything.
+func matchesNameOrID(filter string, id int, name string) bool {
+	filter = strings.TrimSpace(filter)
+	if filter == "" {
+		return true
+	}
+	if filterID, err := strconv.Atoi(filter); err == nil && filterID == id {
+		return true
+	}
+	return name != "" && strings.EqualFold(name, filter)
+}
 
-	for _, task := range tasks {
-		if !h.shouldIncludeTask(task, req) {
-			continue
-		}
-		filtered = append(filtered, task)
+// resolveTag turns a tag filter into the tag name searchTasks expects. A
+// numeric filter is treated as a tag ID unless the project has a tag with
+// that literal name.
+func (h *TasksHandler) resolveTag(ctx context.Context, client *api.Client, projectID int, tag string) (string, error) {
+	tag = strings.TrimSpace(tag)
+	tagID, err := strconv.Atoi(tag)
+	if err != nil {
+		return tag, nil
+	}
+
+	tags, err := client.GetTagsByProject(ctx, projectID)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch tags: %w", err)
 	}
 
-	return filtered
+	name := tag
+	for _, t := range tags {
+		if strings.EqualFold(t.Name, tag) {
+			return t.Name, nil
+		}
+		if t.ID == tagID {
+			name = t.Name
+		}
+	}
+	return name, nil
 }
 
 func (h *TasksHandler) shouldIncludeTask(task TaskDetail, req TasksRequest) bool {
@@ -439,6 +678,10 @@ func (h *TasksHandler) shouldIncludeTask(task TaskDetail, req TasksRequest) bool
 }
 
 func (h *TasksHandler) isTaskCompleted(task TaskDetail) bool {
+	if !task.Status.IsActive {
+		return true
+	}
+
 	completedColumns := []string{"Done", "Completed", "Closed", "Finished"}
 	for _, col := range completedColumns {
 		if strings.EqualFold(task.Status.Column, col) {
@@ -453,13 +696,13 @@ func (h *TasksHandler) isTaskInDateRange(task TaskDetail, dateRange *DateRange)
 		return false
 	}
 
-	dueDate, err := time.Parse("2006-01-02T15:04:05Z", task.Dates.Due)
+	dueDate, err := time.Parse(time.RFC3339, task.Dates.Due)
 	if err != nil {
 		return false
 	}
 
 	if dateRange.Start != "" {
-		startDate, err := time.Parse("2006-01-02", dateRange.Start)
+		startDate, err := time.ParseInLocation("2006-01-02", dateRange.Start, h.loc())
 		if err != nil {
 			return false
 		}
@@ -469,11 +712,11 @@ func (h *TasksHandler) isTaskInDateRange(task TaskDetail, dateRange *DateRange)
 	}
 
 	if dateRange.End != "" {
-		endDate, err := time.Parse("2006-01-02", dateRange.End)
+		endDate, err := time.ParseInLocation("2006-01-02", dateRange.End, h.loc())
 		if err != nil {
 			return false
 		}
-		if dueDate.After(endDate) {
+		if !dueDate.Before(endDate.AddDate(0, 0, 1)) {
 			return false
 		}
 	}
@@ -481,48 +724,110 @@ func (h *TasksHandler) isTaskInDateRange(task TaskDetail, dateRange *DateRange)
 	return true
 }
 
-func (h *TasksHandler) sortTasks(tasks []TaskDetail, sortBy string) []TaskDetail {
-	sorted := make([]TaskDetail, len(tasks))
-	copy(sorted, tasks)
-
-	switch sortBy {
-	case "due_date":
-		sort.Slice(sorted, func(i, j int) bool {
-			if sorted[i].Dates.Due == "" && sorted[j].Dates.Due == "" {
-				return false
-			}
-			if sorted[i].Dates.Due == "" {
-				return false
-			}
-			if sorted[j].Dates.Due == "" {
-				return true
+type taskComparator func(a, b TaskDetail) int
+
+// sortKeyComparators lists the supported sort keys in their default
+// direction: soonest due first, highest priority first, newest first.
+var sortKeyComparators = map[string]taskComparator{
+	"due_date": func(a, b TaskDetail) int {
+		return strings.Compare(a.Dates.Due, b.Dates.Due)
+	},
+	"priority": func(a, b TaskDetail) int {
+		if a.priorityRank != b.priorityRank {
+			if a.priorityRank > b.priorityRank {
+				return -1
 			}
-			return sorted[i].Dates.Due < sorted[j].Dates.Due
-		})
-	case "priority":
-		sort.Slice(sorted, func(i, j int) bool {
-			return h.getPriorityValue(sorted[i].Priority) > h.getPriorityValue(sorted[j].Priority)
-		})
-	case "created":
-		sort.Slice(sorted, func(i, j int) bool {
-			return sorted[i].Dates.Created > sorted[j].Dates.Created
-		})
-	default:
-		sort.Slice(sorted, func(i, j int) bool {
-			if sorted[i].Dates.Due == "" && sorted[j].Dates.Due == "" {
-				return false
-			}
-			if sorted[i].Dates.Due == "" {
-				return false
-			}
-			if sorted[j].Dates.Due == "" {
-				return true
-			}
-			return sorted[i].Dates.Due < sorted[j].Dates.Due
-		})
+			return 1
+		}
+		return b.Score - a.Score
+	},
+	"created": func(a, b TaskDetail) int {
+		return strings.Compare(b.Dates.Created, a.Dates.Created)
+	},
+	"modified": func(a, b TaskDetail) int {
+		return strings.Compare(b.Dates.Modified, a.Dates.Modified)
+	},
+	"title": func(a, b TaskDetail) int {
+		return strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+	},
+}
+
+// parseSortKeys turns a sort expression such as "priority,-due_date" into
+// comparators. A leading '-' reverses a key's default direction; tasks
+// without a due date always sort after those with one.
+func parseSortKeys(sortBy string) ([]taskComparator, error) {
+	var comparators []taskComparator
+	for _, key := range strings.Split(sortBy, ",") {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+
+		reverse := strings.HasPrefix(key, "-")
+		key = strings.TrimPrefix(key, "-")
+
+		compare, ok := sortKeyComparators[key]
+		if !ok {
+			return nil, fmt.Errorf("invalid sort key %q: use due_date, priority, created, modified or title", key)
+		}
+
+		if reverse {
+			forward := compare
+			compare = func(a, b TaskDetail) int { return forward(b, a) }
+		}
+
+		if key == "due_date" {
+			compare = undatedLast(compare)
+		}
+
+		comparators = append(comparators, compare)
+	}
+
+	if len(comparators) == 0 {
+		comparators = append(comparators, undatedLast(sortKeyComparators["due_date"]))
 	}
 
-	return sorted
+	return comparators, nil
+}
+
+func undatedLast(compare taskComparator) taskComparator {
+	return func(a, b TaskDetail) int {
+		switch {
+		case a.Dates.Due == b.Dates.Due:
+			return 0
+		case a.Dates.Due == "":
+			return 1
+		case b.Dates.Due == "":
+			return -1
+		default:
+			return compare(a, b)
+		}
+	}
+}
+
+// sortTasks orders tasks in place by each comparator in turn and breaks
+// remaining ties by project and task ID, so the same tasks always come back
+// in the same order across pages, whatever order projects loaded in.
+func (h *TasksHandler) sortTasks(tasks []TaskDetail, comparators []taskComparator) {
+	sort.Slice(tasks, func(i, j int) bool {
+		for _, compare := range comparators {
+			if c := compare(tasks[i], tasks[j]); c != 0 {
+				return c < 0
+			}
+		}
+		return h.compareTaskIDs(tasks[i], tasks[j]) < 0
+	})
+}
+
+func (h *TasksHandler) compareTaskIDs(a, b TaskDetail) int {
+	pa, _ := strconv.Atoi(a.Project.ID)
+	pb, _ := strconv.Atoi(b.Project.ID)
+	if pa != pb {
+		return pa - pb
+	}
+	ta, _ := strconv.Atoi(a.ID)
+	tb, _ := strconv.Atoi(b.ID)
+	return ta - tb
 }
 
 func (h *TasksHandler) calculateTasksSummary(tasks []TaskDetail) TasksSummary {
@@ -530,8 +835,8 @@ func (h *TasksHandler) calculateTasksSummary(tasks []TaskDetail) TasksSummary {
 		TotalTasks: len(tasks),
 	}
 
-	now := time.Now()
-	weekFromNow := now.AddDate(0, 0, 7)
+	now := time.Now().In(h.loc())
+	weekFromNow := startOfDay(now).AddDate(0, 0, 8)
 
 	for _, task := range tasks {
 		if task.IsOverdue {
@@ -543,7 +848,7 @@ func (h *TasksHandler) calculateTasksSummary(tasks []TaskDetail) TasksSummary {
 		}
 
 		if task.Dates.Due != "" {
-			dueDate, err := time.Parse("2006-01-02T15:04:05Z", task.Dates.Due)
+			dueDate, err := time.Parse(time.RFC3339, task.Dates.Due)
 			if err == nil && dueDate.Before(weekFromNow) && dueDate.After(now) {
 				summary.DueThisWeek++
 			}
@@ -553,21 +858,15 @@ func (h *TasksHandler) calculateTasksSummary(tasks []TaskDetail) TasksSummary {
 	return summary
 }
 
-func (h *TasksHandler) calculateDueDateInfo(dueDateStr string) (bool, *int) {
-	if dueDateStr == "" {
+// calculateDueDateInfo counts working days until dueDate in the user's
+// timezone, so "due today" follows their calendar day rather than UTC's.
+func (h *TasksHandler) calculateDueDateInfo(dueDate time.Time) (bool, *int) {
+	if dueDate.IsZero() {
 		return false, nil
 	}
 
-	dueDate, err := time.Parse("2006-01-02T15:04:05Z", dueDateStr)
-	if err != nil {
-		dueDate, err = time.Parse("2006-01-02", dueDateStr)
-		if err != nil {
-			return false, nil
-		}
-	}
-
-	now := time.Now()
-	days := int(dueDate.Sub(now).Hours() / 24)
+	now := time.Now().In(h.loc())
+	days := h.config.Calendar.WorkDaysUntil(now, dueDate)
 
 	isOverdue := dueDate.Before(now)
 
@@ -578,7 +877,14 @@ func (h *TasksHandler) formatKanboardTime(kt models.KanboardTime) string {
 	if kt.Time.IsZero() {
 		return ""
 	}
-	return kt.Time.Format("2006-01-02T15:04:05Z")
+	return kt.Time.In(h.loc()).Format(time.RFC3339)
+}
+
+func (h *TasksHandler) loc() *time.Location {
+	if h.location == nil {
+		return defaultLocation(h.config)
+	}
+	return h.location
 }
 
 func (h *TasksHandler) formatDate(timestamp interface{}) string {
@@ -595,57 +901,43 @@ func (h *TasksHandler) formatDate(timestamp interface{}) string {
 		if err != nil {
 			return v
 		}
-		return time.Unix(ts, 0).UTC().Format("2006-01-02T15:04:05Z")
+		return time.Unix(ts, 0).In(h.loc()).Format(time.RFC3339)
 	case float64:
 		if v == 0 {
 			return ""
 		}
-		return time.Unix(int64(v), 0).UTC().Format("2006-01-02T15:04:05Z")
+		return time.Unix(int64(v), 0).In(h.loc()).Format(time.RFC3339)
 	case int64:
 		if v == 0 {
 			return ""
 		}
-		return time.Unix(v, 0).UTC().Format("2006-01-02T15:04:05Z")
+		return time.Unix(v, 0).In(h.loc()).Format(time.RFC3339)
 	default:
 		return ""
 	}
 }
 
-func (h *TasksHandler) getPriorityString(priority interface{}) string {
-	switch v := priority.(type) {
-	case float64:
-		switch int(v) {
-		case 0:
-			return "low"
-		case 1:
-			return "normal"
-		case 2:
-			return "high"
-		case 3:
-			return "urgent"
-		default:
-			return "normal"
-		}
-	case string:
-		return v
-	default:
+// getPriorityString maps a task's numeric priority onto the project's own
+// priority range: the project default is "normal", anything below it "low",
+// the top of the range "urgent" and everything in between "high".
+func (h *TasksHandler) getPriorityString(priority int, project ProjectData) string {
+	switch {
+	case priority < project.PriorityDefault:
+		return "low"
+	case priority == project.PriorityDefault:
 		return "normal"
+	case priority >= project.PriorityEnd:
+		return "urgent"
+	default:
+		return "high"
 	}
 }
 
-func (h *TasksHandler) getPriorityValue(priority string) int {
-	switch priority {
-	case "urgent":
-		return 3
-	case "high":
-		return 2
-	case "normal":
-		return 1
-	case "low":
-		return 0
-	default:
-		return 1
+func (h *TasksHandler) getPriorityRank(priority int, project ProjectData) float64 {
+	if project.PriorityEnd <= project.PriorityStart {
+		return float64(priority)
 	}
+	return float64(priority-project.PriorityStart) / float64(project.PriorityEnd-project.PriorityStart)
 }
 
 func (h *TasksHandler) createTaskSummaries(tasks []TaskDetail, limit int) []TaskSummary {
@@ -665,46 +957,185 @@ func (h *TasksHandler) createTaskSummaries(tasks []TaskDetail, limit int) []Task
 		}
 
 		summaries[i] = TaskSummary{
-			ID:           task.ID,
-			Title:        task.Title,
-			Project:      task.Project,
-			Assignee:     assignee,
-			Status:       task.Status.Column,
-			DueDate:      task.Dates.Due,
-			IsOverdue:    task.IsOverdue,
-			DaysUntilDue: task.DaysUntilDue,
+			ID:            task.ID,
+			Title:         task.Title,
+			Project:       task.Project,
+			Assignee:      assignee,
+			Status:        task.Status.Column,
+			DueDate:       task.Dates.Due,
+			IsOverdue:     task.IsOverdue,
+			DaysUntilDue:  task.DaysUntilDue,
+			Subtasks:      task.Subtasks,
+			CommentsCount: task.CommentsCount,
 		}
 	}
 
 	return summaries
 }
 
-func (h *TasksHandler) applyResponseSizeLimits(tasks []TaskDetail, requestedLimit int) ([]TaskDetail, bool, int) {
-	if len(tasks) > requestedLimit {
-		tasks = tasks[:requestedLimit]
+// attachComments records each task's comment count and a preview of its
+// most recent comment.
+func (h *TasksHandler) attachComments(ctx context.Context, client *api.Client, tasks []TaskDetail) error {
+	if len(tasks) == 0 {
+		return nil
 	}
 
-	for limit := len(tasks); limit > 0; limit-- {
-		testTasks := tasks[:limit]
-		testResponse := TasksResponse{
-			Summary: TasksSummary{},
-			Tasks:   testTasks,
+	taskIDs := make([]int, 0, len(tasks))
+	for _, task := range tasks {
+		if id, err := strconv.Atoi(task.ID); err == nil {
+			taskIDs = append(taskIDs, id)
 		}
+	}
 
-		testJSON, err := json.Marshal(testResponse)
-		if err != nil {
+	comments, err := client.GetComments(ctx, taskIDs)
+	if err != nil {
+		return fmt.Errorf("failed to fetch comments: %w", err)
+	}
+
+	for i := range tasks {
+		id, _ := strconv.Atoi(tasks[i].ID)
+		taskComments := comments[id]
+		count := len(taskComments)
+		tasks[i].CommentsCount = &count
+		if count == 0 {
+			continue
+		}
+
+		latest := taskComments[0]
+		for _, comment := range taskComments[1:] {
+			if comment.DateCreation.Time.After(latest.DateCreation.Time) ||
+				(comment.DateCreation.Time.Equal(latest.DateCreation.Time) && comment.ID > latest.ID) {
+				latest = comment
+			}
+		}
+
+		username, name := displayUser(h.config, client, strconv.Itoa(latest.UserID), latest.Username, latest.Name)
+		author := name
+		if author == "" {
+			author = username
+		}
+		tasks[i].LatestComment = &CommentPreview{
+			Author: author,
+			Date:   h.formatKanboardTime(latest.DateCreation),
+			Text:   truncateRunes(strings.TrimSpace(latest.Comment), commentPreviewLength),
+		}
+	}
+
+	return nil
+}
+
+func truncateRunes(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit]) + "…"
+}
+
+// attachSubtaskProgress fills in the subtask rollup of each task that has
+// subtasks. Only the page being returned is looked up, so the cost scales
+// with the page size rather than the number of matching tasks.
+func (h *TasksHandler) attachSubtaskProgress(ctx context.Context, client *api.Client, tasks []TaskDetail) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	taskIDs := make([]int, 0, len(tasks))
+	for _, task := range tasks {
+		if id, err := strconv.Atoi(task.ID); err == nil {
+			taskIDs = append(taskIDs, id)
+		}
+	}
+
+	subtasks, err := client.GetSubtasks(ctx, taskIDs)
+	if err != nil {
+		return fmt.Errorf("failed to fetch subtasks: %w", err)
+	}
+
+	for i := range tasks {
+		id, _ := strconv.Atoi(tasks[i].ID)
+		taskSubtasks := subtasks[id]
+		if len(taskSubtasks) == 0 {
 			continue
 		}
 
-		if len(testJSON) <= MaxResponseSize {
-			if limit < len(tasks) {
-				return testTasks, true, limit
+		progress := &SubtaskProgress{Total: len(taskSubtasks)}
+		for _, subtask := range taskSubtasks {
+			switch subtask.Status {
+			case models.SubtaskStatusDone:
+				progress.Done++
+			case models.SubtaskStatusInProgress:
+				progress.InProgress++
 			}
-			return testTasks, false, 0
+		}
+		tasks[i].Subtasks = progress
+	}
+
+	return nil
+}
+
+// pageFittingResponseSize returns how many of the first limit tasks fit in
+// MaxResponseSize once added to envelope and encoded the way Handle encodes
+// responses, indented. At least one task is always returned so pagination
+// makes progress.
+func (h *TasksHandler) pageFittingResponseSize(envelope TasksResponse, tasks []TaskDetail, limit int) int {
+	// Each task is encoded once and the sizes summed, rather than encoding
+	// ever shorter pages until one fits. Tasks sit two levels deep, after
+	// a newline and their indent, and the list's brackets add the rest.
+	envelope.Tasks = nil
+	envelopeJSON, err := json.MarshalIndent(envelope, "", "  ")
+	if err != nil {
+		return limit
+	}
+	const taskIndent = "    "
+	size := len(envelopeJSON) + len(",\n  \"tasks\": [\n  ]")
+
+	for i := 0; i < limit; i++ {
+		taskJSON, err := json.MarshalIndent(tasks[i], taskIndent, "  ")
+		if err != nil {
+			return max(i, 1)
+		}
+		size += len("\n"+taskIndent) + len(taskJSON)
+		if i > 0 {
+			size++
+		}
+		if size > MaxResponseSize {
+			return max(i, 1)
 		}
 	}
+	return limit
+}
+
+// appendResponseSize adds response_size_bytes to an indented TasksResponse.
+// It is the struct's last field, so it can be spliced in before the closing
+// brace instead of encoding the whole response again.
+func appendResponseSize(responseJSON []byte, size int) []byte {
+	closing := bytes.LastIndexByte(responseJSON, '}')
+	if closing < 0 {
+		return responseJSON
+	}
+	field := fmt.Sprintf(",\n  \"response_size_bytes\": %d\n}", size)
+	return append(bytes.TrimRight(responseJSON[:closing], "\n"), field...)
+}
 
-	return []TaskDetail{}, true, 0
+// queryFingerprint identifies the filters and ordering of a request so a
+// cursor cannot be replayed against a different query.
+func (h *TasksHandler) queryFingerprint(req TasksRequest) string {
+	req.Cursor = ""
+	req.Limit = 0
+	return fingerprint(req)
+}
+
+func (h *TasksHandler) getInt(data map[string]interface{}, key string, fallback int) int {
+	switch val := data[key].(type) {
+	case float64:
+		return int(val)
+	case string:
+		if parsed, err := strconv.Atoi(val); err == nil {
+			return parsed
+		}
+	}
+	return fallback
 }
 
 func (h *TasksHandler) getString(data map[string]interface{}, key string) string {
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestPageFittingResponseSize(t *testing.T) {
+	tasks := func(n, descriptionSize int) []TaskDetail {
+		details := make([]TaskDetail, n)
+		for i := range details {
+			details[i] = TaskDetail{
+				ID:          fmt.Sprint(i + 1),
+				Title:       fmt.Sprintf("Task %d", i+1),
+				Description: strings.Repeat("x", descriptionSize),
+				Priority:    "P1",
+			}
+		}
+		return details
+	}
+	envelope := TasksResponse{
+		Summary:       TasksSummary{TotalTasks: 500, OverdueTasks: 12},
+		TotalMatching: 500,
+		Timezone:      "Europe/London",
+		Warnings:      []ProjectWarning{{ProjectID: "7", ProjectName: "Archive", Error: "timeout"}},
+	}
+
+	tests := []struct {
+		name  string
+		tasks []TaskDetail
+		limit int
+		want  int // 0 when the page should be capped
+	}{
+		{"small tasks fit", tasks(10, 100), 10, 10},
+		{"limit below the tasks", tasks(10, 100), 4, 4},
+		{"large tasks are capped", tasks(100, 5000), 100, 0},
+		{"capped near the boundary", tasks(500, 1000), 500, 0},
+		{"one oversized task is still returned", tasks(2, MaxResponseSize), 2, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &TasksHandler{}
+			got := h.pageFittingResponseSize(envelope, tt.tasks, tt.limit)
+			if tt.want != 0 {
+				if got != tt.want {
+					t.Errorf("page size = %d, want %d", got, tt.want)
+				}
+				return
+			}
+
+			// The page must be the longest whose indented encoding fits.
+			if size := indentedSize(t, envelope, tt.tasks[:got]); size > MaxResponseSize {
+				t.Errorf("page of %d encodes to %d bytes, over %d", got, size, MaxResponseSize)
+			}
+			if got >= tt.limit {
+				t.Fatalf("page size = %d, want fewer than %d", got, tt.limit)
+			}
+			if size := indentedSize(t, envelope, tt.tasks[:got+1]); size <= MaxResponseSize {
+				t.Errorf("page of %d encodes to %d bytes, which also fits", got+1, size)
+			}
+		})
+	}
+}
+
+func indentedSize(t *testing.T, envelope TasksResponse, page []TaskDetail) int {
+	t.Helper()
+	envelope.Tasks = page
+	data, err := json.MarshalIndent(envelope, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(data)
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/auth"
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+)
+
+// maxLoggedHours caps one entry, so a slip such as minutes given as hours
+// is caught before it skews the analytics.
+const maxLoggedHours = 24
+
+type TimeLogRequest struct {
+	TaskID  int     `json:"task_id"`
+	Subtask string  `json:"subtask"`
+	Hours   float64 `json:"hours"`
+}
+
+type TimeLogResponse struct {
+	TaskID           int      `json:"task_id"`
+	SubtaskID        int      `json:"subtask_id,omitempty"`
+	Change           string   `json:"change"`
+	TimeSpent        float64  `json:"time_spent"`
+	TimeEstimated    float64  `json:"time_estimated"`
+	SubtaskTimeSpent *float64 `json:"subtask_time_spent,omitempty"`
+	Warnings         []string `json:"warnings,omitempty"`
+}
+
+// TimeLogHandler adds hours to the time spent on a task or subtask.
+type TimeLogHandler struct {
+	authManager *auth.AuthManager
+	config      *models.UserConfig
+}
+
+func NewTimeLogHandler(authManager *auth.AuthManager, config *models.UserConfig) *TimeLogHandler {
+	return &TimeLogHandler{
+		authManager: authManager,
+		config:      config,
+	}
+}
+
+func (h *TimeLogHandler) Preview(ctx context.Context, params map[string]interface{}, userID string) (string, error) {
+	return preview(ctx, h, params, userID)
+}
+
+func (h *TimeLogHandler) Handle(ctx context.Context, params map[string]interface{}, userID string) (*models.MCPResponse, error) {
+	return handle(ctx, h, params, userID)
+}
+
+// plan validates the request against the task and its subtasks. Kanboard
+// only stores totals, so the hours are added to the current total; a
+// negative entry corrects an earlier one. The change's Apply fills in its
+// Response.
+func (h *TimeLogHandler) plan(ctx context.Context, params map[string]interface{}, userID string) (*change, error) {
+	var req TimeLogRequest
+	if err := parseWriteRequest(params, &req, "time log"); err != nil {
+		return nil, err
+	}
+	if req.TaskID <= 0 {
+		return nil, fmt.Errorf("task_id is required")
+	}
+	if req.Hours == 0 || math.Abs(req.Hours) > maxLoggedHours {
+		return nil, fmt.Errorf("invalid hours %g: must be between -%d and %d and not 0", req.Hours, maxLoggedHours, maxLoggedHours)
+	}
+
+	client, err := authenticatedClient(h.authManager, h.config, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := client.GetTask(ctx, req.TaskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+	if err := checkProjectAccess(ctx, client, task.ProjectID, false); err != nil {
+		return nil, err
+	}
+
+	subtasks, err := client.GetSubtasks(ctx, []int{task.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subtasks: %w", err)
+	}
+
+	response := &TimeLogResponse{TaskID: task.ID}
+	label := fmt.Sprintf("task #%d %q", task.ID, task.Title)
+	verb := "Log"
+	if req.Hours < 0 {
+		verb = "Remove"
+	}
+
+	var apply func(ctx context.Context) error
+	if strings.TrimSpace(req.Subtask) != "" {
+		subtask, err := findSubtask(subtasks[task.ID], req.Subtask)
+		if err != nil {
+			return nil, err
+		}
+		total := subtask.TimeSpent + req.Hours
+		if total < 0 {
+			return nil, fmt.Errorf("subtask %q only has %gh logged; cannot remove %gh", subtask.Title, subtask.TimeSpent, -req.Hours)
+		}
+		response.SubtaskID = subtask.ID
+		response.Change = fmt.Sprintf("%s %gh on subtask %q of %s, bringing it to %gh", verb, math.Abs(req.Hours), subtask.Title, label, total)
+		apply = func(ctx context.Context) error {
+			return client.SetSubtaskTimeSpent(ctx, task.ProjectID, task.ID, subtask.ID, total)
+		}
+	} else {
+		total := task.TimeSpent + req.Hours
+		if total < 0 {
+			return nil, fmt.Errorf("%s only has %gh logged; cannot remove %gh", label, task.TimeSpent, -req.Hours)
+		}
+		response.Change = fmt.Sprintf("%s %gh on %s, bringing it to %gh", verb, math.Abs(req.Hours), label, total)
+		for _, subtask := range subtasks[task.ID] {
+			if subtask.TimeSpent > 0 {
+				response.Warnings = append(response.Warnings, fmt.Sprintf("Kanboard recalculates the time spent on %s from its subtasks whenever one changes, which will overwrite this entry; log time against a subtask instead", label))
+				break
+			}
+		}
+		apply = func(ctx context.Context) error {
+			return client.SetTaskTimeSpent(ctx, task.ProjectID, task.ID, total)
+		}
+	}
+
+	return &change{
+		Description: response.Change,
+		Warnings:    response.Warnings,
+		Response:    response,
+		Apply: func(ctx context.Context) error {
+			if err := apply(ctx); err != nil {
+				return fmt.Errorf("failed to log time: %w", err)
+			}
+			updated, err := client.GetTask(ctx, task.ID)
+			if err != nil {
+				return fmt.Errorf("time logged but the task could not be read back: %w", err)
+			}
+			response.TimeSpent = updated.TimeSpent
+			response.TimeEstimated = updated.TimeEstimated
+			if response.SubtaskID == 0 {
+				return nil
+			}
+			subtasks, err := client.GetSubtasks(ctx, []int{task.ID})
+			if err != nil {
+				return fmt.Errorf("time logged but the subtask could not be read back: %w", err)
+			}
+			for _, subtask := range subtasks[task.ID] {
+				if subtask.ID == response.SubtaskID {
+					spent := subtask.TimeSpent
+					response.SubtaskTimeSpent = &spent
+				}
+			}
+			return nil
+		},
+	}, nil
+}
+
+// findSubtask looks a subtask up by ID or title.
+func findSubtask(subtasks []models.Subtask, subtask string) (models.Subtask, error) {
+	for _, s := range subtasks {
+		if matchesNameOrID(subtask, s.ID, s.Title) {
+			return s, nil
+		}
+	}
+
+	if len(subtasks) == 0 {
+		return models.Subtask{}, fmt.Errorf("no subtask %q: the task has no subtasks", subtask)
+	}
+	titles := make([]string, len(subtasks))
+	for i, s := range subtasks {
+		titles[i] = s.Title
+	}
+	return models.Subtask{}, fmt.Errorf("no subtask %q in task: subtasks are %s", subtask, strings.Join(titles, ", "))
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/auth"
+	"github.com/tech-arch1tect/kan-mcp/internal/dateparse"
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+)
+
+type SaveViewRequest struct {
+	Name         string   `json:"name"`
+	ProjectIDs   []string `json:"project_ids"`
+	AssigneeIDs  []string `json:"assignee_ids"`
+	StatusFilter string   `json:"status_filter"`
+	SortBy       string   `json:"sort_by"`
+	DueDateStart string   `json:"due_date_start"`
+	DueDateEnd   string   `json:"due_date_end"`
+	Delete       bool     `json:"delete"`
+}
+
+type SaveViewResponse struct {
+	Saved   *models.SavedView  `json:"saved,omitempty"`
+	Deleted string             `json:"deleted,omitempty"`
+	Views   []models.SavedView `json:"views"`
+}
+
+// ViewsHandler saves, deletes and lists the named task filters kanboard_tasks
+// runs with its view parameter.
+type ViewsHandler struct {
+	authManager *auth.AuthManager
+	config      *models.UserConfig
+}
+
+func NewViewsHandler(authManager *auth.AuthManager, config *models.UserConfig) *ViewsHandler {
+	return &ViewsHandler{
+		authManager: authManager,
+		config:      config,
+	}
+}
+
+// Handle saves the view in params, or deletes it when delete is set. Without
+// a name it only lists the user's views. The response always lists every
+// view the user has saved.
+func (h *ViewsHandler) Handle(ctx context.Context, params map[string]interface{}, userID string) (*models.MCPResponse, error) {
+	var req SaveViewRequest
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal params: %w", err)
+		}
+		if err := json.Unmarshal(data, &req); err != nil {
+			return nil, fmt.Errorf("failed to parse view request: %w", err)
+		}
+	}
+
+	if _, err := h.authManager.AuthenticateUser(userID); err != nil {
+		return nil, fmt.Errorf("authentication failed: %w", err)
+	}
+
+	var response SaveViewResponse
+	switch {
+	case req.Delete:
+		if req.Name == "" {
+			return nil, fmt.Errorf("name is required to delete a view")
+		}
+		if err := h.authManager.DeleteView(userID, req.Name); err != nil {
+			return nil, err
+		}
+		response.Deleted = strings.TrimSpace(req.Name)
+	case req.Name != "":
+		view, err := h.view(req)
+		if err != nil {
+			return nil, err
+		}
+		if err := h.authManager.SaveView(userID, view); err != nil {
+			return nil, err
+		}
+		saved, err := h.authManager.View(userID, view.Name)
+		if err != nil {
+			return nil, err
+		}
+		response.Saved = saved
+	}
+
+	views, err := h.authManager.ListViews(userID)
+	if err != nil {
+		return nil, err
+	}
+	response.Views = views
+	if response.Views == nil {
+		response.Views = []models.SavedView{}
+	}
+
+	return jsonResponse(response)
+}
+
+// view checks req's filters the way kanboard_tasks would, so a saved view
+// cannot fail later on a typo. Due-date phrases are only checked to parse;
+// their order depends on the day the view is run.
+func (h *ViewsHandler) view(req SaveViewRequest) (models.SavedView, error) {
+	view := models.SavedView{
+		Name:         req.Name,
+		StatusFilter: req.StatusFilter,
+		SortBy:       req.SortBy,
+		DueDateStart: strings.TrimSpace(req.DueDateStart),
+		DueDateEnd:   strings.TrimSpace(req.DueDateEnd),
+	}
+
+	for _, field := range []struct {
+		name string
+		ids  []string
+		dest *[]string
+	}{
+		{"project_ids", req.ProjectIDs, &view.ProjectIDs},
+		{"assignee_ids", req.AssigneeIDs, &view.AssigneeIDs},
+	} {
+		for _, id := range field.ids {
+			id = strings.TrimSpace(id)
+			if n, err := strconv.Atoi(id); err != nil || n < 0 {
+				return models.SavedView{}, fmt.Errorf("invalid %s entry %q: must be a numeric ID", field.name, id)
+			}
+			*field.dest = append(*field.dest, id)
+		}
+	}
+
+	switch view.StatusFilter {
+	case "", "active", "completed", "all":
+	default:
+		return models.SavedView{}, fmt.Errorf("invalid status_filter %q: must be active, completed or all", view.StatusFilter)
+	}
+
+	if view.SortBy != "" {
+		if _, err := parseSortKeys(view.SortBy); err != nil {
+			return models.SavedView{}, err
+		}
+	}
+
+	now := time.Now().In(defaultLocation(h.config))
+	if view.DueDateStart != "" {
+		if _, err := dateparse.Parse(view.DueDateStart, now, h.config.Calendar); err != nil {
+			return models.SavedView{}, fmt.Errorf("invalid due_date_start: %w", err)
+		}
+	}
+	if view.DueDateEnd != "" {
+		if _, err := dateparse.Parse(view.DueDateEnd, now, h.config.Calendar); err != nil {
+			return models.SavedView{}, fmt.Errorf("invalid due_date_end: %w", err)
+		}
+	}
+
+	return view, nil
+}
+
+// applyView fills req with view's filters. Callers apply explicit parameters
+// afterwards so they override the view's.
+func applyView(req *TasksRequest, view *models.SavedView) {
+	if len(view.ProjectIDs) > 0 {
+		req.ProjectIDs = view.ProjectIDs
+	}
+	if len(view.AssigneeIDs) > 0 {
+		req.AssigneeIDs = view.AssigneeIDs
+	}
+	if view.StatusFilter != "" {
+		req.StatusFilter = view.StatusFilter
+	}
+	if view.SortBy != "" {
+		req.SortBy = view.SortBy
+	}
+	if view.DueDateStart != "" || view.DueDateEnd != "" {
+		req.DueDateRange = &DateRange{Start: view.DueDateStart, End: view.DueDateEnd}
+	}
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/api"
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+)
+
+// ErrPermission is returned when the user's Kanboard role does not allow a
+// change.
+var ErrPermission = errors.New("permission denied")
+
+// change is a validated write a tool is about to make. Description says what
+// it does, for confirmation previews and the tool's response, and Warnings
+// what the user should know before agreeing to it. Apply makes the change
+// and fills in Response, which is what the tool returns.
+type change struct {
+	Description string
+	Warnings    []string
+	Response    interface{}
+	Apply       func(ctx context.Context) error
+}
+
+// planner is implemented by the handlers of write tools. plan validates
+// params against Kanboard's current data and works out the change without
+// making it, so the same plan backs both the preview and the write.
+type planner interface {
+	plan(ctx context.Context, params map[string]interface{}, userID string) (*change, error)
+}
+
+// preview describes the change params ask for without making it.
+func preview(ctx context.Context, p planner, params map[string]interface{}, userID string) (string, error) {
+	c, err := p.plan(ctx, params, userID)
+	if err != nil {
+		return "", err
+	}
+	if len(c.Warnings) > 0 {
+		return c.Description + "\n\nWarning: " + strings.Join(c.Warnings, "\nWarning: "), nil
+	}
+	return c.Description, nil
+}
+
+// handle makes the change params ask for and returns its Response.
+func handle(ctx context.Context, p planner, params map[string]interface{}, userID string) (*models.MCPResponse, error) {
+	c, err := p.plan(ctx, params, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Apply(ctx); err != nil {
+		return nil, err
+	}
+	return jsonResponse(c.Response)
+}
+
+// checkProjectAccess makes sure the user may change projectID. Managing the
+// board needs the project-manager role and editing tasks any role but
+// viewer; Kanboard administrators may do both. With application-token auth
+// Kanboard makes every change as its own API user, so this is the only check
+// the change gets.
+func checkProjectAccess(ctx context.Context, client *api.Client, projectID int, manage bool) error {
+	me, err := client.GetMe(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get user info: %w", err)
+	}
+	if me.Role == "app-admin" {
+		return nil
+	}
+
+	roles, err := client.GetProjectUserRoles(ctx, []int{projectID}, me.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get project role: %w", err)
+	}
+
+	role := roles[projectID]
+	switch {
+	case role == "":
+		return fmt.Errorf("%w: user is not a member of project %d", ErrPermission, projectID)
+	case manage && role != "project-manager":
+		return fmt.Errorf("%w: changing project %d's board needs the project manager role, and the user's role is %s", ErrPermission, projectID, role)
+	case !manage && role == "project-viewer":
+		return fmt.Errorf("%w: the user can only view project %d", ErrPermission, projectID)
+	}
+	return nil
+}
+
+// projectLabel names a project for change descriptions, falling back to its
+// ID when the name cannot be read.
+func projectLabel(ctx context.Context, client *api.Client, projectID int) string {
+	project, err := client.GetProjectByIDRaw(ctx, projectID)
+	if err != nil {
+		return fmt.Sprintf("project %d", projectID)
+	}
+	if name, ok := project["name"].(string); ok && name != "" {
+		return fmt.Sprintf("project %q (ID %d)", name, projectID)
+	}
+	return fmt.Sprintf("project %d", projectID)
+}
+
+// parseWriteRequest decodes a write tool's params into req.
+func parseWriteRequest(params map[string]interface{}, req interface{}, what string) error {
+	if params == nil {
+		return nil
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to marshal params: %w", err)
+	}
+	if err := json.Unmarshal(data, req); err != nil {
+		return fmt.Errorf("failed to parse %s request: %w", what, err)
+	}
+	return nil
+}
+
+func jsonResponse(value interface{}) (*models.MCPResponse, error) {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	return &models.MCPResponse{
+		Content: []models.MCPContent{
+			{
+				Type: "text",
+				Text: string(data),
+			},
+		},
+	}, nil
+}
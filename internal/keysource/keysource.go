@@ -0,0 +1,148 @@
+package keysource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+const defaultField = "encryption_key"
+
+// Fetch returns the hex-encoded key named by source, which is one of
+//
+//	file:<path>
+//	vault:<path>[#field]
+//	aws-sm:<secret id or ARN>[#field]
+//
+// Vault is reached through VAULT_ADDR with VAULT_TOKEN (and VAULT_NAMESPACE
+// if set); both KV version 1 and 2 paths work, e.g.
+// vault:secret/data/kan-mcp. AWS credentials and region come from the
+// standard SDK chain. A Secrets Manager value may be the bare key or a JSON
+// object holding it under field, which defaults to encryption_key.
+func Fetch(ctx context.Context, source string) (string, error) {
+	kind, location, ok := strings.Cut(source, ":")
+	if !ok || location == "" {
+		return "", fmt.Errorf("invalid key source %q: use file:<path>, vault:<path> or aws-sm:<secret id>", source)
+	}
+
+	location, field, _ := strings.Cut(location, "#")
+	if field == "" {
+		field = defaultField
+	}
+
+	var key string
+	var err error
+	switch kind {
+	case "file":
+		key, err = fromFile(location)
+	case "vault":
+		key, err = fromVault(ctx, location, field)
+	case "aws-sm":
+		key, err = fromSecretsManager(ctx, location, field)
+	default:
+		return "", fmt.Errorf("unknown key source %q: use file, vault or aws-sm", kind)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(key), nil
+}
+
+func fromFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read key file: %w", err)
+	}
+	return string(data), nil
+}
+
+func fromVault(ctx context.Context, path, field string) (string, error) {
+	addr := os.Getenv("VAULT_ADDR")
+	token := os.Getenv("VAULT_TOKEN")
+	if addr == "" || token == "" {
+		return "", fmt.Errorf("VAULT_ADDR and VAULT_TOKEN must be set to read the key from Vault")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(addr, "/")+"/v1/"+strings.TrimLeft(path, "/"), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create Vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if namespace := os.Getenv("VAULT_NAMESPACE"); namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to reach Vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("failed to read Vault response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault returned HTTP %d for %s", resp.StatusCode, path)
+	}
+
+	var secret struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return "", fmt.Errorf("failed to parse Vault response: %w", err)
+	}
+
+	// KV version 2 nests the secret's fields one level deeper.
+	data := secret.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		data = nested
+	}
+
+	key, ok := data[field].(string)
+	if !ok {
+		return "", fmt.Errorf("vault secret %s has no string field %q", path, field)
+	}
+	return key, nil
+}
+
+func fromSecretsManager(ctx context.Context, secretID, field string) (string, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+
+	out, err := secretsmanager.NewFromConfig(cfg).GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(secretID),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret %s: %w", secretID, err)
+	}
+
+	value := aws.ToString(out.SecretString)
+	if value == "" {
+		return "", fmt.Errorf("secret %s has no string value", secretID)
+	}
+	if !strings.HasPrefix(strings.TrimSpace(value), "{") {
+		return value, nil
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &fields); err != nil {
+		return "", fmt.Errorf("failed to parse secret %s: %w", secretID, err)
+	}
+	key, ok := fields[field].(string)
+	if !ok {
+		return "", fmt.Errorf("secret %s has no string field %q", secretID, field)
+	}
+	return key, nil
+}
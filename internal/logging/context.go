@@ -0,0 +1,52 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+type requestIDKey struct{}
+
+// NewRequestID returns a random 16-character hex ID for one tool call.
+func NewRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the ID stored by WithRequestID, or "" if there is none.
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// The *fContext variants prefix the message with the request ID in ctx, so
+// every line logged while serving a tool call can be correlated.
+
+func DebugfContext(ctx context.Context, format string, args ...interface{}) {
+	Debugf(withRequestPrefix(ctx, format), args...)
+}
+
+func InfofContext(ctx context.Context, format string, args ...interface{}) {
+	Infof(withRequestPrefix(ctx, format), args...)
+}
+
+func WarnfContext(ctx context.Context, format string, args ...interface{}) {
+	Warnf(withRequestPrefix(ctx, format), args...)
+}
+
+func ErrorfContext(ctx context.Context, format string, args ...interface{}) {
+	Errorf(withRequestPrefix(ctx, format), args...)
+}
+
+func withRequestPrefix(ctx context.Context, format string) string {
+	if requestID := RequestID(ctx); requestID != "" {
+		return "[" + requestID + "] " + format
+	}
+	return format
+}
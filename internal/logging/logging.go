@@ -0,0 +1,70 @@
+package logging
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync/atomic"
+)
+
+type Level int32
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var currentLevel atomic.Int32
+
+func init() {
+	currentLevel.Store(int32(LevelInfo))
+}
+
+func ParseLevel(level string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return LevelDebug, nil
+	case "", "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	default:
+		return LevelInfo, fmt.Errorf("unknown log level: %s", level)
+	}
+}
+
+func SetLevel(level Level) {
+	currentLevel.Store(int32(level))
+}
+
+func Enabled(level Level) bool {
+	return level >= Level(currentLevel.Load())
+}
+
+func Debugf(format string, args ...interface{}) {
+	if Enabled(LevelDebug) {
+		log.Printf("DEBUG: "+format, args...)
+	}
+}
+
+func Infof(format string, args ...interface{}) {
+	if Enabled(LevelInfo) {
+		log.Printf(format, args...)
+	}
+}
+
+func Warnf(format string, args ...interface{}) {
+	if Enabled(LevelWarn) {
+		log.Printf("WARNING: "+format, args...)
+	}
+}
+
+func Errorf(format string, args ...interface{}) {
+	if Enabled(LevelError) {
+		log.Printf("ERROR: "+format, args...)
+	}
+}
@@ -0,0 +1,58 @@
+package logging
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// User ID modes control how UserID renders identifiers in log lines.
+const (
+	UserIDsHash  = "hash"
+	UserIDsPlain = "plain"
+	UserIDsOmit  = "omit"
+)
+
+var userIDMode atomic.Value
+
+func init() {
+	userIDMode.Store(UserIDsHash)
+}
+
+func ParseUserIDMode(mode string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "", UserIDsHash:
+		return UserIDsHash, nil
+	case UserIDsPlain:
+		return UserIDsPlain, nil
+	case UserIDsOmit:
+		return UserIDsOmit, nil
+	default:
+		return UserIDsHash, fmt.Errorf("unknown user ID log mode: %s", mode)
+	}
+}
+
+func SetUserIDMode(mode string) {
+	userIDMode.Store(mode)
+}
+
+// UserID returns the form of a user ID that may appear in logs: a short
+// SHA-256 prefix by default, so lines for one user can still be correlated
+// without the ID, which doubles as a credential, being written out.
+func UserID(userID string) string {
+	if userID == "" {
+		return "-"
+	}
+
+	switch userIDMode.Load().(string) {
+	case UserIDsPlain:
+		return userID
+	case UserIDsOmit:
+		return "[REDACTED]"
+	default:
+		sum := sha256.Sum256([]byte(userID))
+		return "uid:" + hex.EncodeToString(sum[:4])
+	}
+}
@@ -0,0 +1,165 @@
+// Package mailer sends plain-text email through an SMTP server.
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Settings describe the SMTP server. Port 465 uses implicit TLS; on other
+// ports the connection is upgraded with STARTTLS when the server offers it.
+// Credentials are only sent over TLS.
+type Settings struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+type Mailer struct {
+	settings Settings
+	from     *mail.Address
+	timeout  time.Duration
+}
+
+func New(settings Settings) (*Mailer, error) {
+	from, err := mail.ParseAddress(settings.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from address %q: %w", settings.From, err)
+	}
+	return &Mailer{
+		settings: settings,
+		from:     from,
+		timeout:  30 * time.Second,
+	}, nil
+}
+
+// Send delivers one message to a single recipient.
+func (m *Mailer) Send(ctx context.Context, to, subject, body string) error {
+	recipient, err := mail.ParseAddress(to)
+	if err != nil {
+		return fmt.Errorf("invalid recipient %q: %w", to, err)
+	}
+
+	message, err := m.message(recipient, subject, body)
+	if err != nil {
+		return err
+	}
+
+	client, err := m.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if m.settings.Username != "" {
+		auth := smtp.PlainAuth("", m.settings.Username, m.settings.Password, m.settings.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(m.from.Address); err != nil {
+		return fmt.Errorf("SMTP server rejected sender: %w", err)
+	}
+	if err := client.Rcpt(recipient.Address); err != nil {
+		return fmt.Errorf("SMTP server rejected recipient: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP server refused message: %w", err)
+	}
+	if _, err := w.Write(message); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("SMTP server refused message: %w", err)
+	}
+	return client.Quit()
+}
+
+// dial connects and, where possible, switches to TLS. The connection's
+// deadline covers the whole exchange.
+func (m *Mailer) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(m.settings.Host, strconv.Itoa(m.settings.Port))
+	tlsConfig := &tls.Config{ServerName: m.settings.Host}
+
+	deadline := time.Now().Add(m.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+
+	dialer := &net.Dialer{Deadline: deadline}
+	var conn net.Conn
+	var err error
+	if m.settings.Port == 465 {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, m.settings.Host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+
+	if m.settings.Port != 465 {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				client.Close()
+				return nil, fmt.Errorf("STARTTLS failed: %w", err)
+			}
+		} else if m.settings.Username != "" {
+			client.Close()
+			return nil, fmt.Errorf("SMTP server does not offer STARTTLS; refusing to send credentials in plain text")
+		}
+	}
+
+	return client, nil
+}
+
+func (m *Mailer) message(to *mail.Address, subject, body string) ([]byte, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, fmt.Errorf("failed to generate message ID: %w", err)
+	}
+	domain := m.from.Address[strings.LastIndex(m.from.Address, "@")+1:]
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", m.from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", to.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@%s>\r\n", hex.EncodeToString(id), domain)
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n")
+	buf.WriteString("\r\n")
+
+	qp := quotedprintable.NewWriter(&buf)
+	if _, err := qp.Write([]byte(strings.ReplaceAll(body, "\n", "\r\n"))); err != nil {
+		return nil, fmt.Errorf("failed to encode message: %w", err)
+	}
+	if err := qp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode message: %w", err)
+	}
+	return buf.Bytes(), nil
+}
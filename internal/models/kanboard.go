@@ -99,6 +99,7 @@ type Task struct {
 	IsActive            KanboardBool `json:"is_active"`
 	DateCompleted       KanboardTime `json:"date_completed"`
 	Score               int          `json:"score"`
+	Priority            int          `json:"priority"`
 	DateDue             KanboardTime `json:"date_due"`
 	CategoryID          int          `json:"category_id"`
 	CreatorID           int          `json:"creator_id"`
@@ -138,6 +139,20 @@ type Swimlane struct {
 	Description string       `json:"description"`
 }
 
+type Category struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	ProjectID   int    `json:"project_id"`
+	Description string `json:"description"`
+	ColorID     string `json:"color_id"`
+}
+
+type Tag struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	ProjectID int    `json:"project_id"`
+}
+
 type KanboardUser struct {
 	ID                   int            `json:"id"`
 	Username             string         `json:"username"`
@@ -162,3 +177,94 @@ type KanboardUser struct {
 	ApiAccessToken       string         `json:"api_access_token"`
 	AvatarPath           string         `json:"avatar_path"`
 }
+
+type ProjectBoard struct {
+	Tasks      []Task
+	Columns    []Column
+	Swimlanes  []Swimlane
+	Users      []KanboardUser
+	Categories []Category
+}
+
+const (
+	SubtaskStatusTodo       = 0
+	SubtaskStatusInProgress = 1
+	SubtaskStatusDone       = 2
+)
+
+// Recurrence values of a task, as Kanboard numbers them.
+const (
+	RecurrenceStatusNone      = 0
+	RecurrenceStatusPending   = 1
+	RecurrenceStatusProcessed = 2
+
+	RecurrenceTriggerFirstColumn = 0
+	RecurrenceTriggerLastColumn  = 1
+	RecurrenceTriggerClose       = 2
+
+	RecurrenceTimeframeDays   = 0
+	RecurrenceTimeframeMonths = 1
+	RecurrenceTimeframeYears  = 2
+
+	RecurrenceBasedateDueDate    = 0
+	RecurrenceBasedateActionDate = 1
+)
+
+type Subtask struct {
+	ID            int     `json:"id"`
+	Title         string  `json:"title"`
+	Status        int     `json:"status"`
+	TimeEstimated float64 `json:"time_estimated"`
+	TimeSpent     float64 `json:"time_spent"`
+	TaskID        int     `json:"task_id"`
+	UserID        int     `json:"user_id"`
+	Position      int     `json:"position"`
+}
+
+type Comment struct {
+	ID           int          `json:"id"`
+	TaskID       int          `json:"task_id"`
+	UserID       int          `json:"user_id"`
+	DateCreation KanboardTime `json:"date_creation"`
+	Comment      string       `json:"comment"`
+	Username     string       `json:"username"`
+	Name         string       `json:"name"`
+}
+
+type TaskLink struct {
+	ID             int          `json:"id"`
+	TaskID         int          `json:"task_id"`
+	OppositeTaskID int          `json:"opposite_task_id"`
+	LinkID         int          `json:"link_id"`
+	Label          string       `json:"label"`
+	Title          string       `json:"title"`
+	IsActive       KanboardBool `json:"is_active"`
+	ProjectID      int          `json:"project_id"`
+}
+
+// ExternalLink is a URL attached to a task, such as a pull request or a
+// document. LinkType is Kanboard's link provider, weblink or attachment.
+type ExternalLink struct {
+	ID               int          `json:"id"`
+	TaskID           int          `json:"task_id"`
+	LinkType         string       `json:"link_type"`
+	Dependency       string       `json:"dependency"`
+	Title            string       `json:"title"`
+	URL              string       `json:"url"`
+	CreatorID        int          `json:"creator_id"`
+	DateCreation     KanboardTime `json:"date_creation"`
+	DateModification KanboardTime `json:"date_modification"`
+}
+
+// KanboardEvent records a webhook notification received from a Kanboard
+// instance. ProjectID and TaskID are zero when the event does not concern
+// one.
+type KanboardEvent struct {
+	Time        time.Time `json:"time"`
+	KanboardURL string    `json:"kanboard_url"`
+	Name        string    `json:"name"`
+	ProjectID   int       `json:"project_id,omitempty"`
+	TaskID      int       `json:"task_id,omitempty"`
+	TaskTitle   string    `json:"task_title,omitempty"`
+	Author      string    `json:"author,omitempty"`
+}
@@ -5,7 +5,12 @@ type MCPResponse struct {
 	IsError bool         `json:"isError,omitempty"`
 }
 
+// MCPContent is either text or, with Type "resource" or "image", a file
+// named Name whose base64 contents are in Data.
 type MCPContent struct {
-	Type string `json:"type"`
-	Text string `json:"text"`
+	Type     string `json:"type"`
+	Text     string `json:"text"`
+	Name     string `json:"name,omitempty"`
+	MimeType string `json:"mimeType,omitempty"`
+	Data     string `json:"data,omitempty"`
 }
@@ -1,19 +1,136 @@
 package models
 
 import (
+	"context"
+	"crypto/tls"
 	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/calendar"
+	"github.com/tech-arch1tect/kan-mcp/internal/templates"
+	"github.com/tech-arch1tect/kan-mcp/internal/workpool"
+)
+
+const (
+	AuthModeUser = "user"
+	AuthModeApp  = "app"
 )
 
 type User struct {
-	UserID           string    `json:"user_id"`
-	KanboardURL      string    `json:"kanboard_url,omitempty"`
-	KanboardUsername string    `json:"kanboard_username"`
-	KanboardToken    string    `json:"kanboard_token"`
-	CreatedAt        time.Time `json:"created_at"`
-	LastUsed         time.Time `json:"last_used"`
+	UserID           string `json:"user_id"`
+	KanboardURL      string `json:"kanboard_url,omitempty"`
+	KanboardUsername string `json:"kanboard_username"`
+	KanboardToken    string `json:"kanboard_token"`
+	AuthMode         string `json:"auth_mode,omitempty"`
+	SigningSecret    string `json:"signing_secret,omitempty"`
+	// CalendarTokenHash is the hex SHA-256 of the token in the user's iCal
+	// feed URL.
+	CalendarTokenHash string `json:"calendar_token_hash,omitempty"`
+	// Notifications, when set, opts the user in to overdue and due-today
+	// alerts.
+	Notifications *NotificationSettings `json:"notifications,omitempty"`
+	// Views are the user's saved kanboard_tasks filters.
+	Views     []SavedView `json:"views,omitempty"`
+	ExpiresAt time.Time   `json:"expires_at,omitzero"`
+	CreatedAt time.Time   `json:"created_at"`
+	LastUsed  time.Time   `json:"last_used"`
+}
+
+// NotificationSettings says where a user's alerts go: a webhook, an email
+// address or both. LastSent is when alerts were last delivered to the user.
+type NotificationSettings struct {
+	WebhookURL string    `json:"webhook_url,omitempty"`
+	Email      string    `json:"email,omitempty"`
+	LastSent   time.Time `json:"last_sent,omitzero"`
+}
+
+// SavedView is a named set of task filters. Due dates are kept as written, so
+// a phrase such as "this week" is resolved each time the view is run.
+type SavedView struct {
+	Name         string    `json:"name"`
+	ProjectIDs   []string  `json:"project_ids,omitempty"`
+	AssigneeIDs  []string  `json:"assignee_ids,omitempty"`
+	StatusFilter string    `json:"status_filter,omitempty"`
+	SortBy       string    `json:"sort_by,omitempty"`
+	DueDateStart string    `json:"due_date_start,omitempty"`
+	DueDateEnd   string    `json:"due_date_end,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Pseudonymizer maps a Kanboard user, identified by instance URL and user ID,
+// to a stable pseudonym.
+type Pseudonymizer interface {
+	Pseudonym(kanboardURL, kanboardUser string) (string, error)
+}
+
+// TaskSnapshot serves closed tasks from a local copy kept in sync with
+// Kanboard. ClosedTasks reports false when the user's copy of the project was
+// last synced before syncedSince, in which case callers fetch from Kanboard.
+type TaskSnapshot interface {
+	ClosedTasks(ctx context.Context, userID, kanboardURL string, projectID int, modifiedSince, syncedSince time.Time, fn func([]Task) error) (bool, error)
 }
 
 type UserConfig struct {
 	DefaultKanboardURL string
 	EncryptionKey      []byte
+	KanboardAppToken   string
+	KanboardAuthHeader string
+	KanboardTimeout    time.Duration
+	KanboardTLS        *tls.Config
+	KanboardRetry      RetrySettings
+	KanboardRateLimit  RateLimitSettings
+	KanboardCacheTTLs  map[string]time.Duration
+	OverviewCacheTTL   time.Duration
+	KanboardBreaker    BreakerSettings
+	KanboardTransport  TransportSettings
+	KanboardSlowCall   time.Duration
+	ReadOnly           bool
+	Calendar           *calendar.Calendar
+	DefaultLocation    *time.Location
+	WorkPool           *workpool.Pool
+	// Templates renders text reports; nil uses the built-in templates.
+	Templates *templates.Set
+	// Pseudonyms, when set, replaces Kanboard usernames and names in tool
+	// responses.
+	Pseudonyms Pseudonymizer
+	// TaskSnapshot, when set, serves closed tasks synced in the background
+	// unless the snapshot is older than TaskSnapshotMaxAge.
+	TaskSnapshot       TaskSnapshot
+	TaskSnapshotMaxAge time.Duration
+}
+
+type RetrySettings struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}
+
+type RateLimitSettings struct {
+	RequestsPerSecond float64
+	Burst             int
+}
+
+type BreakerSettings struct {
+	FailureThreshold int
+	OpenDuration     time.Duration
+}
+
+type TransportSettings struct {
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+}
+
+// ToolCall records one MCP tool invocation for the audit log and usage
+// statistics. Params holds the call's arguments with secrets redacted and
+// without user_id, which has its own field.
+type ToolCall struct {
+	Time         time.Time              `json:"time"`
+	UserID       string                 `json:"user_id"`
+	Tool         string                 `json:"tool"`
+	Params       map[string]interface{} `json:"params,omitempty"`
+	Error        bool                   `json:"error,omitempty"`
+	ErrorMessage string                 `json:"error_message,omitempty"`
+	RequestID    string                 `json:"request_id,omitempty"`
+	Bytes        int                    `json:"bytes,omitempty"`
+	DurationMs   int64                  `json:"duration_ms"`
 }
@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+)
+
+// AuditStore appends one JSON line per tool call to audit.jsonl in the data
+// directory, so the CLI can report usage and show the audit trail while the
+// server is running. Entries are only ever appended, apart from Prune dropping
+// those older than the retention period.
+type AuditStore struct {
+	path  string
+	mutex sync.Mutex
+}
+
+func NewAuditStore(dataDir string) (*AuditStore, error) {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	return &AuditStore{
+		path: filepath.Join(dataDir, "audit.jsonl"),
+	}, nil
+}
+
+func (as *AuditStore) Record(call models.ToolCall) error {
+	data, err := json.Marshal(call)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tool call: %w", err)
+	}
+
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+
+	file, err := os.OpenFile(as.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+
+	return nil
+}
+
+// Calls returns the recorded tool calls made at or after since, oldest first.
+func (as *AuditStore) Calls(since time.Time) ([]models.ToolCall, error) {
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+
+	return as.read(since)
+}
+
+// Prune rewrites the log without the calls made before cutoff and returns how
+// many were removed.
+func (as *AuditStore) Prune(cutoff time.Time) (int, error) {
+	as.mutex.Lock()
+	defer as.mutex.Unlock()
+
+	all, err := as.read(time.Time{})
+	if err != nil {
+		return 0, err
+	}
+
+	var kept []byte
+	removed := 0
+	for _, call := range all {
+		if call.Time.Before(cutoff) {
+			removed++
+			continue
+		}
+		data, err := json.Marshal(call)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal tool call: %w", err)
+		}
+		kept = append(append(kept, data...), '\n')
+	}
+	if removed == 0 {
+		return 0, nil
+	}
+
+	tempPath := as.path + ".tmp"
+	if err := os.WriteFile(tempPath, kept, 0600); err != nil {
+		return 0, fmt.Errorf("failed to write audit log: %w", err)
+	}
+	if err := os.Rename(tempPath, as.path); err != nil {
+		os.Remove(tempPath)
+		return 0, fmt.Errorf("failed to replace audit log: %w", err)
+	}
+
+	return removed, nil
+}
+
+func (as *AuditStore) read(since time.Time) ([]models.ToolCall, error) {
+	file, err := os.Open(as.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	var calls []models.ToolCall
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var call models.ToolCall
+		if err := json.Unmarshal(scanner.Bytes(), &call); err != nil {
+			continue
+		}
+		if !call.Time.Before(since) {
+			calls = append(calls, call)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	return calls, nil
+}
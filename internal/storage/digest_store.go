@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// StoredDigest is the latest digest of one period for a user.
+type StoredDigest struct {
+	GeneratedAt time.Time       `json:"generated_at"`
+	Digest      json.RawMessage `json:"digest"`
+}
+
+// DigestStore keeps the latest digest of each period for every user in
+// digests.json in the data directory, keyed by user ID and period.
+type DigestStore struct {
+	path    string
+	mutex   sync.Mutex
+	digests map[string]map[string]StoredDigest
+}
+
+func NewDigestStore(dataDir string) (*DigestStore, error) {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	ds := &DigestStore{
+		path:    filepath.Join(dataDir, "digests.json"),
+		digests: make(map[string]map[string]StoredDigest),
+	}
+
+	data, err := os.ReadFile(ds.path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read digests: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &ds.digests); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal digests: %w", err)
+		}
+	}
+
+	return ds, nil
+}
+
+// Latest returns a user's most recent digest for period. ok is false if none
+// has been generated.
+func (ds *DigestStore) Latest(userID, period string) (digest StoredDigest, ok bool) {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+
+	digest, ok = ds.digests[userID][period]
+	return digest, ok
+}
+
+// Save replaces a user's digest for period.
+func (ds *DigestStore) Save(userID, period string, digest StoredDigest) error {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+
+	if ds.digests[userID] == nil {
+		ds.digests[userID] = make(map[string]StoredDigest)
+	}
+	previous, existed := ds.digests[userID][period]
+	ds.digests[userID][period] = digest
+
+	if err := ds.write(); err != nil {
+		if existed {
+			ds.digests[userID][period] = previous
+		} else {
+			delete(ds.digests[userID], period)
+		}
+		return err
+	}
+	return nil
+}
+
+// PruneUsers drops the digests of every user not in userIDs.
+func (ds *DigestStore) PruneUsers(userIDs []string) error {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+
+	removed := false
+	for userID := range ds.digests {
+		if !slices.Contains(userIDs, userID) {
+			delete(ds.digests, userID)
+			removed = true
+		}
+	}
+	if !removed {
+		return nil
+	}
+	return ds.write()
+}
+
+func (ds *DigestStore) write() error {
+	data, err := json.MarshalIndent(ds.digests, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal digests: %w", err)
+	}
+	if err := os.WriteFile(ds.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write digests: %w", err)
+	}
+	return nil
+}
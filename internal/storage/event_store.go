@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+)
+
+// EventStore appends one JSON line per Kanboard webhook event to events.jsonl
+// in the data directory. Like the audit log it is only ever appended to,
+// apart from Prune dropping events older than the retention period.
+type EventStore struct {
+	path  string
+	mutex sync.Mutex
+}
+
+func NewEventStore(dataDir string) (*EventStore, error) {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	return &EventStore{
+		path: filepath.Join(dataDir, "events.jsonl"),
+	}, nil
+}
+
+func (es *EventStore) Record(event models.KanboardEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	es.mutex.Lock()
+	defer es.mutex.Unlock()
+
+	file, err := os.OpenFile(es.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open event log: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write event log: %w", err)
+	}
+
+	return nil
+}
+
+// Events returns the events received at or after since, oldest first.
+func (es *EventStore) Events(since time.Time) ([]models.KanboardEvent, error) {
+	es.mutex.Lock()
+	defer es.mutex.Unlock()
+
+	return es.read(since)
+}
+
+// Prune rewrites the log without the events received before cutoff and
+// returns how many were removed.
+func (es *EventStore) Prune(cutoff time.Time) (int, error) {
+	es.mutex.Lock()
+	defer es.mutex.Unlock()
+
+	all, err := es.read(time.Time{})
+	if err != nil {
+		return 0, err
+	}
+
+	var kept []byte
+	removed := 0
+	for _, event := range all {
+		if event.Time.Before(cutoff) {
+			removed++
+			continue
+		}
+		data, err := json.Marshal(event)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal event: %w", err)
+		}
+		kept = append(append(kept, data...), '\n')
+	}
+	if removed == 0 {
+		return 0, nil
+	}
+
+	tempPath := es.path + ".tmp"
+	if err := os.WriteFile(tempPath, kept, 0600); err != nil {
+		return 0, fmt.Errorf("failed to write event log: %w", err)
+	}
+	if err := os.Rename(tempPath, es.path); err != nil {
+		os.Remove(tempPath)
+		return 0, fmt.Errorf("failed to replace event log: %w", err)
+	}
+
+	return removed, nil
+}
+
+func (es *EventStore) read(since time.Time) ([]models.KanboardEvent, error) {
+	file, err := os.Open(es.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open event log: %w", err)
+	}
+	defer file.Close()
+
+	var events []models.KanboardEvent
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var event models.KanboardEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		if !event.Time.Before(since) {
+			events = append(events, event)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read event log: %w", err)
+	}
+
+	return events, nil
+}
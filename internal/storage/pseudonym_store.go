@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// PseudonymStore hands out stable pseudonyms for Kanboard users so their
+// names need not appear in tool responses. The mapping is kept in
+// pseudonyms.json in the data directory, keyed by Kanboard URL and user ID.
+type PseudonymStore struct {
+	path    string
+	mutex   sync.Mutex
+	mapping map[string]string
+	used    map[string]bool
+}
+
+func NewPseudonymStore(dataDir string) (*PseudonymStore, error) {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	ps := &PseudonymStore{
+		path:    filepath.Join(dataDir, "pseudonyms.json"),
+		mapping: make(map[string]string),
+		used:    make(map[string]bool),
+	}
+
+	data, err := os.ReadFile(ps.path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read pseudonyms: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &ps.mapping); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal pseudonyms: %w", err)
+		}
+	}
+	for _, pseudonym := range ps.mapping {
+		ps.used[pseudonym] = true
+	}
+
+	return ps, nil
+}
+
+// Pseudonym returns the pseudonym for a user of the Kanboard instance at
+// kanboardURL, creating one on first sight. Users are identified by their
+// Kanboard user ID, or by username where the ID is unknown.
+func (ps *PseudonymStore) Pseudonym(kanboardURL, kanboardUser string) (string, error) {
+	key := strings.TrimRight(kanboardURL, "/") + "#" + kanboardUser
+
+	ps.mutex.Lock()
+	defer ps.mutex.Unlock()
+
+	if pseudonym, ok := ps.mapping[key]; ok {
+		return pseudonym, nil
+	}
+
+	var pseudonym string
+	for pseudonym == "" || ps.used[pseudonym] {
+		suffix := make([]byte, 4)
+		if _, err := rand.Read(suffix); err != nil {
+			return "", fmt.Errorf("failed to generate pseudonym: %w", err)
+		}
+		pseudonym = "user-" + hex.EncodeToString(suffix)
+	}
+
+	ps.mapping[key] = pseudonym
+	data, err := json.MarshalIndent(ps.mapping, "", "  ")
+	if err != nil {
+		delete(ps.mapping, key)
+		return "", fmt.Errorf("failed to marshal pseudonyms: %w", err)
+	}
+	if err := os.WriteFile(ps.path, data, 0600); err != nil {
+		delete(ps.mapping, key)
+		return "", fmt.Errorf("failed to write pseudonyms: %w", err)
+	}
+	ps.used[pseudonym] = true
+
+	return pseudonym, nil
+}
+
+// Resolve returns the Kanboard URL and user a pseudonym stands for.
+func (ps *PseudonymStore) Resolve(pseudonym string) (kanboardURL, kanboardUser string, ok bool) {
+	ps.mutex.Lock()
+	defer ps.mutex.Unlock()
+
+	for key, candidate := range ps.mapping {
+		if candidate == pseudonym {
+			i := strings.LastIndex(key, "#")
+			return key[:i], key[i+1:], true
+		}
+	}
+	return "", "", false
+}
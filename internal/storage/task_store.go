@@ -0,0 +1,276 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/models"
+	_ "modernc.org/sqlite"
+)
+
+const taskChunkSize = 500
+
+const taskSchema = `
+CREATE TABLE IF NOT EXISTS tasks (
+	user_id           TEXT    NOT NULL,
+	kanboard_url      TEXT    NOT NULL,
+	task_id           INTEGER NOT NULL,
+	project_id        INTEGER NOT NULL,
+	is_active         INTEGER NOT NULL,
+	date_modification INTEGER NOT NULL,
+	data              BLOB    NOT NULL,
+	PRIMARY KEY (user_id, kanboard_url, task_id)
+);
+CREATE INDEX IF NOT EXISTS tasks_by_project
+	ON tasks (user_id, kanboard_url, project_id, is_active, date_modification);
+CREATE TABLE IF NOT EXISTS sync_state (
+	user_id        TEXT    NOT NULL,
+	kanboard_url   TEXT    NOT NULL,
+	project_id     INTEGER NOT NULL,
+	synced_at      INTEGER NOT NULL,
+	full_synced_at INTEGER NOT NULL,
+	PRIMARY KEY (user_id, kanboard_url, project_id)
+);`
+
+// TaskStore keeps a local snapshot of each user's Kanboard tasks in tasks.db,
+// an SQLite database in the data directory. Snapshots are per user ID, so a
+// user only ever reads back tasks their own credentials could fetch.
+type TaskStore struct {
+	db *sql.DB
+}
+
+// SyncState records when a project's snapshot was last brought up to date,
+// and when it was last rebuilt from scratch.
+type SyncState struct {
+	SyncedAt     time.Time
+	FullSyncedAt time.Time
+}
+
+func NewTaskStore(dataDir string) (*TaskStore, error) {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	path := filepath.Join(dataDir, "tasks.db")
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create task database: %w", err)
+	}
+	file.Close()
+
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open task database: %w", err)
+	}
+
+	if _, err := db.Exec(taskSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create task tables: %w", err)
+	}
+
+	return &TaskStore{db: db}, nil
+}
+
+func (ts *TaskStore) Close() error {
+	return ts.db.Close()
+}
+
+// SyncState returns when a user's snapshot of a project was last synced. Both
+// times are zero if it never was.
+func (ts *TaskStore) SyncState(ctx context.Context, userID, kanboardURL string, projectID int) (SyncState, error) {
+	var syncedAt, fullSyncedAt int64
+	err := ts.db.QueryRowContext(ctx,
+		`SELECT synced_at, full_synced_at FROM sync_state WHERE user_id = ? AND kanboard_url = ? AND project_id = ?`,
+		userID, kanboardURL, projectID,
+	).Scan(&syncedAt, &fullSyncedAt)
+	if err == sql.ErrNoRows {
+		return SyncState{}, nil
+	}
+	if err != nil {
+		return SyncState{}, fmt.Errorf("failed to read sync state: %w", err)
+	}
+
+	return SyncState{SyncedAt: time.Unix(syncedAt, 0), FullSyncedAt: time.Unix(fullSyncedAt, 0)}, nil
+}
+
+// ReplaceProject discards a user's snapshot of a project and stores tasks in
+// its place, marking it fully synced as of syncedAt.
+func (ts *TaskStore) ReplaceProject(ctx context.Context, userID, kanboardURL string, projectID int, tasks []models.Task, syncedAt time.Time) error {
+	return ts.update(ctx, userID, kanboardURL, projectID, tasks, syncedAt, true)
+}
+
+// UpdateProject adds or refreshes tasks in a user's snapshot of a project and
+// marks it synced as of syncedAt.
+func (ts *TaskStore) UpdateProject(ctx context.Context, userID, kanboardURL string, projectID int, tasks []models.Task, syncedAt time.Time) error {
+	return ts.update(ctx, userID, kanboardURL, projectID, tasks, syncedAt, false)
+}
+
+func (ts *TaskStore) update(ctx context.Context, userID, kanboardURL string, projectID int, tasks []models.Task, syncedAt time.Time, full bool) error {
+	tx, err := ts.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin task update: %w", err)
+	}
+	defer tx.Rollback()
+
+	if full {
+		if _, err := tx.ExecContext(ctx,
+			`DELETE FROM tasks WHERE user_id = ? AND kanboard_url = ? AND project_id = ?`,
+			userID, kanboardURL, projectID,
+		); err != nil {
+			return fmt.Errorf("failed to clear project tasks: %w", err)
+		}
+	}
+
+	insert, err := tx.PrepareContext(ctx,
+		`INSERT OR REPLACE INTO tasks (user_id, kanboard_url, task_id, project_id, is_active, date_modification, data)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare task insert: %w", err)
+	}
+	defer insert.Close()
+
+	for _, task := range tasks {
+		data, err := json.Marshal(task)
+		if err != nil {
+			return fmt.Errorf("failed to marshal task %d: %w", task.ID, err)
+		}
+		if _, err := insert.ExecContext(ctx, userID, kanboardURL, task.ID, task.ProjectID, bool(task.IsActive), task.DateModified.Unix(), data); err != nil {
+			return fmt.Errorf("failed to store task %d: %w", task.ID, err)
+		}
+	}
+
+	state := `INSERT INTO sync_state (user_id, kanboard_url, project_id, synced_at, full_synced_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (user_id, kanboard_url, project_id) DO UPDATE SET synced_at = excluded.synced_at`
+	if full {
+		state += `, full_synced_at = excluded.full_synced_at`
+	}
+	if _, err := tx.ExecContext(ctx, state, userID, kanboardURL, projectID, syncedAt.Unix(), syncedAt.Unix()); err != nil {
+		return fmt.Errorf("failed to update sync state: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit task update: %w", err)
+	}
+	return nil
+}
+
+// ClosedTasks delivers a user's closed tasks of a project last modified at or
+// after modifiedSince to fn, a chunk at a time. It returns false without
+// calling fn when the project has not been synced since syncedSince.
+func (ts *TaskStore) ClosedTasks(ctx context.Context, userID, kanboardURL string, projectID int, modifiedSince, syncedSince time.Time, fn func([]models.Task) error) (bool, error) {
+	state, err := ts.SyncState(ctx, userID, kanboardURL, projectID)
+	if err != nil {
+		return false, err
+	}
+	if state.SyncedAt.IsZero() || state.SyncedAt.Before(syncedSince) {
+		return false, nil
+	}
+
+	var since int64
+	if !modifiedSince.IsZero() {
+		since = modifiedSince.Unix()
+	}
+
+	rows, err := ts.db.QueryContext(ctx,
+		`SELECT data FROM tasks
+		WHERE user_id = ? AND kanboard_url = ? AND project_id = ? AND is_active = 0 AND date_modification >= ?
+		ORDER BY task_id`,
+		userID, kanboardURL, projectID, since,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to query tasks: %w", err)
+	}
+	defer rows.Close()
+
+	chunk := make([]models.Task, 0, taskChunkSize)
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return false, fmt.Errorf("failed to read task: %w", err)
+		}
+		var task models.Task
+		if err := json.Unmarshal(data, &task); err != nil {
+			return false, fmt.Errorf("failed to unmarshal task: %w", err)
+		}
+		chunk = append(chunk, task)
+
+		if len(chunk) == taskChunkSize {
+			if err := fn(chunk); err != nil {
+				return false, err
+			}
+			chunk = chunk[:0]
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return false, fmt.Errorf("failed to read tasks: %w", err)
+	}
+
+	if len(chunk) > 0 {
+		if err := fn(chunk); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// PruneProjects drops a user's snapshots of projects not in projectIDs, and
+// any taken from a Kanboard instance other than kanboardURL.
+func (ts *TaskStore) PruneProjects(ctx context.Context, userID, kanboardURL string, projectIDs []int) error {
+	keep := make([]interface{}, 0, len(projectIDs)+2)
+	keep = append(keep, userID, kanboardURL)
+	for _, id := range projectIDs {
+		keep = append(keep, id)
+	}
+
+	condition := `user_id = ? AND (kanboard_url <> ?`
+	if len(projectIDs) > 0 {
+		condition += ` OR project_id NOT IN (` + placeholders(len(projectIDs)) + `)`
+	} else {
+		condition += ` OR 1`
+	}
+	condition += `)`
+
+	return ts.prune(ctx, condition, keep)
+}
+
+// PruneUsers drops the snapshots of every user not in userIDs.
+func (ts *TaskStore) PruneUsers(ctx context.Context, userIDs []string) error {
+	if len(userIDs) == 0 {
+		return ts.prune(ctx, `1`, nil)
+	}
+
+	keep := make([]interface{}, len(userIDs))
+	for i, id := range userIDs {
+		keep[i] = id
+	}
+	return ts.prune(ctx, `user_id NOT IN (`+placeholders(len(userIDs))+`)`, keep)
+}
+
+func (ts *TaskStore) prune(ctx context.Context, condition string, args []interface{}) error {
+	tx, err := ts.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin prune: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, table := range []string{"tasks", "sync_state"} {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM `+table+` WHERE `+condition, args...); err != nil {
+			return fmt.Errorf("failed to prune %s: %w", table, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit prune: %w", err)
+	}
+	return nil
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
@@ -0,0 +1,171 @@
+// Package templates renders the plain-text reports kan-mcp produces, such as
+// standup summaries, digests and alert emails. Each report has a built-in
+// text/template that a deployment can replace by putting a file of the same
+// name in its templates directory.
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/tech-arch1tect/kan-mcp/internal/logging"
+)
+
+const (
+	Standup = "standup"
+	Digest  = "digest"
+	Alert   = "alert"
+)
+
+// Names lists every report template.
+var Names = []string{Standup, Digest, Alert}
+
+//go:embed defaults/*.tmpl
+var defaults embed.FS
+
+// Set holds a template per report: the override from the templates
+// directory where there is one, otherwise the default.
+type Set struct {
+	defaults  map[string]*template.Template
+	overrides map[string]*template.Template
+	dir       string
+}
+
+var (
+	defaultSetOnce sync.Once
+	defaultSet     *Set
+	defaultSetErr  error
+)
+
+// Load parses the default templates and every <name>.tmpl override in dir.
+// A missing dir just means no overrides. Unknown files in dir are ignored.
+func Load(dir string) (*Set, error) {
+	s := &Set{
+		defaults:  make(map[string]*template.Template),
+		overrides: make(map[string]*template.Template),
+		dir:       dir,
+	}
+
+	for _, name := range Names {
+		source, err := Default(name)
+		if err != nil {
+			return nil, err
+		}
+		tmpl, err := parse(name, source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse default %s template: %w", name, err)
+		}
+		s.defaults[name] = tmpl
+
+		if dir == "" {
+			continue
+		}
+		path := filepath.Join(dir, name+".tmpl")
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s template: %w", name, err)
+		}
+		tmpl, err = parse(name, string(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		s.overrides[name] = tmpl
+	}
+
+	return s, nil
+}
+
+// Default returns the source of a built-in template.
+func Default(name string) (string, error) {
+	data, err := defaults.ReadFile("defaults/" + name + ".tmpl")
+	if err != nil {
+		return "", fmt.Errorf("unknown template %q", name)
+	}
+	return string(data), nil
+}
+
+// Overridden reports whether name is replaced by a file in the templates
+// directory, and that file's path.
+func (s *Set) Overridden(name string) (string, bool) {
+	if s == nil {
+		return "", false
+	}
+	_, ok := s.overrides[name]
+	return filepath.Join(s.dir, name+".tmpl"), ok
+}
+
+// Render executes the named template with data. An override that fails is
+// logged and the default used instead, so a broken template never costs a
+// report. A nil Set renders the defaults.
+func (s *Set) Render(name string, data interface{}) (string, error) {
+	if s == nil {
+		defaultSetOnce.Do(func() {
+			defaultSet, defaultSetErr = Load("")
+		})
+		if defaultSetErr != nil {
+			return "", defaultSetErr
+		}
+		s = defaultSet
+	}
+
+	if tmpl, ok := s.overrides[name]; ok {
+		text, err := execute(tmpl, data)
+		if err == nil {
+			return text, nil
+		}
+		logging.Warnf("Template %s.tmpl failed, using the default: %v", name, err)
+	}
+
+	tmpl, ok := s.defaults[name]
+	if !ok {
+		return "", fmt.Errorf("unknown template %q", name)
+	}
+	return execute(tmpl, data)
+}
+
+func parse(name, source string) (*template.Template, error) {
+	return template.New(name).Funcs(funcs).Option("missingkey=error").Parse(source)
+}
+
+func execute(tmpl *template.Template, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+var funcs = template.FuncMap{
+	// date reformats an RFC 3339 timestamp with a Go layout, leaving
+	// anything else as it is.
+	"date": func(layout, value string) string {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return value
+		}
+		return t.Format(layout)
+	},
+	"join": func(items []string, sep string) string {
+		return strings.Join(items, sep)
+	},
+	"sub": func(a, b int) int {
+		return a - b
+	},
+	"plural": func(n int, singular, plural string) string {
+		if n == 1 {
+			return singular
+		}
+		return plural
+	},
+}
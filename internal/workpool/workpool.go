@@ -0,0 +1,198 @@
+package workpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrBusy is wrapped by every error returned because the pool is saturated,
+// so callers can tell overload apart from a failing job.
+var ErrBusy = errors.New("server busy")
+
+var (
+	// ErrQueueFull is returned for jobs submitted while the pool's queue is
+	// at capacity.
+	ErrQueueFull = fmt.Errorf("%w: worker pool queue is full", ErrBusy)
+	// ErrQueueTimeout is returned for jobs that waited longer than the
+	// pool's queue timeout for a worker.
+	ErrQueueTimeout = fmt.Errorf("%w: timed out waiting for a worker", ErrBusy)
+)
+
+// Pool bounds how many jobs run at once across every caller sharing it. Jobs
+// beyond the concurrency limit wait in a queue; when queueSize is positive and
+// that many jobs are already waiting, further jobs fail with ErrQueueFull, and
+// when queueTimeout is positive jobs waiting longer fail with ErrQueueTimeout.
+// A nil Pool runs every job immediately.
+type Pool struct {
+	slots        chan struct{}
+	queueSize    int64
+	queueTimeout time.Duration
+
+	queued    atomic.Int64
+	running   atomic.Int64
+	completed atomic.Int64
+	rejected  atomic.Int64
+	timedOut  atomic.Int64
+}
+
+// Stats is a point-in-time view of a pool shared by all tool calls.
+type Stats struct {
+	Workers   int   `json:"workers"`
+	Running   int64 `json:"running"`
+	Queued    int64 `json:"queued"`
+	Completed int64 `json:"completed"`
+	Rejected  int64 `json:"rejected"`
+	TimedOut  int64 `json:"timed_out"`
+}
+
+// CallStats describes a single Run: how many jobs it submitted, how many
+// failed, how long they waited for a worker and how long the call took.
+type CallStats struct {
+	Jobs         int
+	Failed       int
+	MaxQueueWait time.Duration
+	Elapsed      time.Duration
+}
+
+func New(workers, queueSize int, queueTimeout time.Duration) *Pool {
+	if workers <= 0 {
+		return nil
+	}
+	return &Pool{
+		slots:        make(chan struct{}, workers),
+		queueSize:    int64(queueSize),
+		queueTimeout: queueTimeout,
+	}
+}
+
+// Run calls job for every index in [0, n) and waits for all of them. The
+// returned slice holds each job's error at its index. Once one job is turned
+// away because the pool is saturated, the call's jobs still waiting are
+// cancelled rather than adding to the queue; see Busy.
+func (p *Pool) Run(ctx context.Context, n int, job func(ctx context.Context, i int) error) ([]error, CallStats) {
+	start := time.Now()
+	errs := make([]error, n)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	var maxWait time.Duration
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+
+			waitStart := time.Now()
+			release, err := p.acquire(ctx)
+			if err != nil {
+				if errors.Is(err, ErrBusy) {
+					cancel()
+				}
+				errs[index] = err
+				return
+			}
+			defer release()
+
+			if wait := time.Since(waitStart); wait > 0 {
+				mu.Lock()
+				maxWait = max(maxWait, wait)
+				mu.Unlock()
+			}
+
+			errs[index] = job(ctx, index)
+		}(i)
+	}
+	wg.Wait()
+
+	stats := CallStats{Jobs: n, MaxQueueWait: maxWait, Elapsed: time.Since(start)}
+	for _, err := range errs {
+		if err != nil {
+			stats.Failed++
+		}
+	}
+
+	return errs, stats
+}
+
+func (p *Pool) acquire(ctx context.Context) (func(), error) {
+	if p == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case p.slots <- struct{}{}:
+		return p.started(), nil
+	default:
+	}
+
+	if queued := p.queued.Add(1); p.queueSize > 0 && queued > p.queueSize {
+		p.queued.Add(-1)
+		p.rejected.Add(1)
+		return nil, ErrQueueFull
+	}
+
+	var timeout <-chan time.Time
+	if p.queueTimeout > 0 {
+		timer := time.NewTimer(p.queueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case p.slots <- struct{}{}:
+		p.queued.Add(-1)
+	case <-ctx.Done():
+		p.queued.Add(-1)
+		return nil, ctx.Err()
+	case <-timeout:
+		p.queued.Add(-1)
+		p.timedOut.Add(1)
+		return nil, ErrQueueTimeout
+	}
+
+	return p.started(), nil
+}
+
+// Busy returns the first error in errs caused by the pool being saturated,
+// or nil. Callers should fail the whole call with it instead of returning
+// partial results.
+func Busy(errs []error) error {
+	for _, err := range errs {
+		if errors.Is(err, ErrBusy) {
+			return err
+		}
+	}
+	return nil
+}
+
+// started records a job that holds a worker slot and returns its release
+// function.
+func (p *Pool) started() func() {
+	p.running.Add(1)
+	return func() {
+		p.running.Add(-1)
+		p.completed.Add(1)
+		<-p.slots
+	}
+}
+
+func (p *Pool) Stats() Stats {
+	if p == nil {
+		return Stats{}
+	}
+	return Stats{
+		Workers:   cap(p.slots),
+		Running:   p.running.Load(),
+		Queued:    p.queued.Load(),
+		Completed: p.completed.Load(),
+		Rejected:  p.rejected.Load(),
+		TimedOut:  p.timedOut.Load(),
+	}
+}
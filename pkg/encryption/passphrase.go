@@ -0,0 +1,84 @@
+package encryption
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// KDFParams are the Argon2id settings a passphrase-derived key was created
+// with. They are stored next to the data they protect, since changing any of
+// them changes the key.
+type KDFParams struct {
+	Salt    string `json:"salt"`
+	Time    uint32 `json:"time"`
+	Memory  uint32 `json:"memory_kib"`
+	Threads uint8  `json:"threads"`
+}
+
+// LoadKDFParams reads the parameters stored at path, creating them with a
+// new random salt on first use.
+func LoadKDFParams(path string) (*KDFParams, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		var params KDFParams
+		if err := json.Unmarshal(data, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		return &params, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	params := &KDFParams{
+		Salt:    hex.EncodeToString(salt),
+		Time:    3,
+		Memory:  64 * 1024,
+		Threads: 4,
+	}
+
+	data, err = json.MarshalIndent(params, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode key derivation parameters: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	// O_EXCL keeps two processes starting together from each writing a salt.
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if errors.Is(err, os.ErrExist) {
+		return LoadKDFParams(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer file.Close()
+	if _, err := file.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return params, nil
+}
+
+// DeriveKey stretches passphrase into a 32-byte key with Argon2id.
+func DeriveKey(passphrase string, params *KDFParams) ([]byte, error) {
+	salt, err := hex.DecodeString(params.Salt)
+	if err != nil || len(salt) < 16 {
+		return nil, fmt.Errorf("invalid key derivation salt")
+	}
+	if params.Time == 0 || params.Memory == 0 || params.Threads == 0 {
+		return nil, fmt.Errorf("invalid key derivation parameters")
+	}
+	return argon2.IDKey([]byte(passphrase), salt, params.Time, params.Memory, params.Threads, 32), nil
+}